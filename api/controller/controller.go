@@ -210,3 +210,33 @@ func (c *Client) InitiateModelMigration(spec ModelMigrationSpec) (string, error)
 	}
 	return result.MigrationId, nil
 }
+
+// ApproveMigration approves the held migration of the specified
+// model, recording the given change ticket reference against it.
+func (c *Client) ApproveMigration(modelUUID, ticketRef string) error {
+	args := params.ApproveMigrationArgs{
+		ModelTag:  names.NewModelTag(modelUUID).String(),
+		TicketRef: ticketRef,
+	}
+	return errors.Trace(c.facade.FacadeCall("ApproveMigration", args, nil))
+}
+
+// AbortMigration requests that the active migration of the specified
+// model be aborted. The migration is aborted at the next safe point,
+// unless it has already reached SUCCESS.
+func (c *Client) AbortMigration(modelUUID string) error {
+	args := params.ModelArgs{
+		ModelTag: names.NewModelTag(modelUUID).String(),
+	}
+	return errors.Trace(c.facade.FacadeCall("AbortMigration", args, nil))
+}
+
+// RetryReap requests that the removal of the specified migrated
+// model from the controller be attempted again after it previously
+// failed.
+func (c *Client) RetryReap(modelUUID string) error {
+	args := params.ModelArgs{
+		ModelTag: names.NewModelTag(modelUUID).String(),
+	}
+	return errors.Trace(c.facade.FacadeCall("RetryReap", args, nil))
+}
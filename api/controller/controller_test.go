@@ -220,6 +220,69 @@ func (s *controllerSuite) TestInitiateModelMigrationError(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "unable to read model: .+")
 }
 
+func (s *controllerSuite) TestApproveMigrationError(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	spec := controller.ModelMigrationSpec{
+		ModelUUID:            st.ModelUUID(),
+		TargetControllerUUID: randomUUID(),
+		TargetAddrs:          []string{"1.2.3.4:5"},
+		TargetCACert:         "cert",
+		TargetUser:           "someone",
+		TargetPassword:       "secret",
+	}
+	controller := s.OpenAPI(c)
+	_, err := controller.InitiateModelMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The migration has only just started, so isn't being held.
+	err = controller.ApproveMigration(st.ModelUUID(), "CHG-1234")
+	c.Check(err, gc.ErrorMatches, "migration is not being held \\(phase is QUIESCE\\)")
+}
+
+func (s *controllerSuite) TestAbortMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	spec := controller.ModelMigrationSpec{
+		ModelUUID:            st.ModelUUID(),
+		TargetControllerUUID: randomUUID(),
+		TargetAddrs:          []string{"1.2.3.4:5"},
+		TargetCACert:         "cert",
+		TargetUser:           "someone",
+		TargetPassword:       "secret",
+	}
+	controller := s.OpenAPI(c)
+	_, err := controller.InitiateModelMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = controller.AbortMigration(st.ModelUUID())
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *controllerSuite) TestRetryReapError(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	spec := controller.ModelMigrationSpec{
+		ModelUUID:            st.ModelUUID(),
+		TargetControllerUUID: randomUUID(),
+		TargetAddrs:          []string{"1.2.3.4:5"},
+		TargetCACert:         "cert",
+		TargetUser:           "someone",
+		TargetPassword:       "secret",
+	}
+	controller := s.OpenAPI(c)
+	_, err := controller.InitiateModelMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The migration has only just started, so removal of the model
+	// hasn't failed.
+	err = controller.RetryReap(st.ModelUUID())
+	c.Check(err, gc.ErrorMatches, "migration has not failed to remove the model \\(phase is QUIESCE\\)")
+}
+
 func randomUUID() string {
 	return utils.MustNewUUID().String()
 }
@@ -4,6 +4,10 @@
 package migrationmaster
 
 import (
+	"io"
+	"net/url"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
@@ -78,10 +82,14 @@ func (c *Client) GetMigrationStatus() (migration.MigrationStatus, error) {
 	}
 
 	return migration.MigrationStatus{
-		ModelUUID:        modelTag.Id(),
-		Attempt:          status.Attempt,
-		Phase:            phase,
-		PhaseChangedTime: status.PhaseChangedTime,
+		MigrationId:        status.MigrationId,
+		ModelUUID:          modelTag.Id(),
+		ModelName:          status.ModelName,
+		Attempt:            status.Attempt,
+		Phase:              phase,
+		PhaseChangedTime:   status.PhaseChangedTime,
+		ApprovalRef:        status.ApprovalRef,
+		ReapRetryRequested: status.ReapRetryRequested,
 		TargetInfo: migration.TargetInfo{
 			ControllerTag: controllerTag,
 			Addrs:         target.Addrs,
@@ -100,6 +108,34 @@ func (c *Client) SetPhase(phase migration.Phase) error {
 	return c.caller.FacadeCall("SetPhase", args, nil)
 }
 
+// SetStatusMessage sets a human readable status message for the
+// currently active model migration.
+func (c *Client) SetStatusMessage(message string) error {
+	args := params.SetMigrationStatusMessageArgs{
+		Message: message,
+	}
+	return c.caller.FacadeCall("SetStatusMessage", args, nil)
+}
+
+// ModelInfo returns basic information about the model to be migrated.
+func (c *Client) ModelInfo() (migration.ModelInfo, error) {
+	var info params.MigrationModelInfo
+	err := c.caller.FacadeCall("ModelInfo", nil, &info)
+	if err != nil {
+		return migration.ModelInfo{}, errors.Trace(err)
+	}
+	owner, err := names.ParseUserTag(info.OwnerTag)
+	if err != nil {
+		return migration.ModelInfo{}, errors.Trace(err)
+	}
+	return migration.ModelInfo{
+		UUID:         info.UUID,
+		Name:         info.Name,
+		Owner:        owner,
+		AgentVersion: info.AgentVersion,
+	}, nil
+}
+
 // Export returns a serialized representation of the model associated
 // with the API connection. The charms used by the model are also
 // returned.
@@ -110,20 +146,117 @@ func (c *Client) Export() (migration.SerializedModel, error) {
 		return migration.SerializedModel{}, err
 	}
 
-	// Convert tools info to output map.
+	tools, err := convertTools(serialized.Tools)
+	if err != nil {
+		return migration.SerializedModel{}, errors.Trace(err)
+	}
+
+	return migration.SerializedModel{
+		Bytes:         serialized.Bytes,
+		Charms:        serialized.Charms,
+		CharmOrigins:  convertCharmOrigins(serialized.CharmOrigins),
+		CharmSHA256s:  serialized.CharmSHA256s,
+		Tools:         tools,
+		ToolsSHA256s:  convertToolsSHA256s(serialized.Tools),
+		FirewallRules: convertFirewallRules(serialized.FirewallRules),
+	}, nil
+}
+
+// convertTools converts tools info to a map of tools version to URI.
+func convertTools(in []params.SerializedModelTools) (map[version.Binary]string, error) {
 	tools := make(map[version.Binary]string)
-	for _, toolsInfo := range serialized.Tools {
+	for _, toolsInfo := range in {
 		v, err := version.ParseBinary(toolsInfo.Version)
 		if err != nil {
-			return migration.SerializedModel{}, errors.Annotate(err, "error parsing tools version")
+			return nil, errors.Annotate(err, "error parsing tools version")
 		}
 		tools[v] = toolsInfo.URI
 	}
+	return tools, nil
+}
 
-	return migration.SerializedModel{
-		Bytes:  serialized.Bytes,
-		Charms: serialized.Charms,
-		Tools:  tools,
+// convertToolsSHA256s returns the SHA256 sums of the tools which
+// have them, keyed by version. The versions are already known to
+// parse.
+func convertToolsSHA256s(in []params.SerializedModelTools) map[version.Binary]string {
+	var sums map[version.Binary]string
+	for _, toolsInfo := range in {
+		if toolsInfo.SHA256 == "" {
+			continue
+		}
+		if sums == nil {
+			sums = make(map[version.Binary]string)
+		}
+		sums[version.MustParseBinary(toolsInfo.Version)] = toolsInfo.SHA256
+	}
+	return sums
+}
+
+func convertCharmOrigins(in map[string]string) map[string]migration.CharmOrigin {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]migration.CharmOrigin, len(in))
+	for curl, origin := range in {
+		out[curl] = migration.CharmOrigin(origin)
+	}
+	return out
+}
+
+func convertFirewallRules(in []params.FirewallRule) []migration.FirewallRule {
+	var rules []migration.FirewallRule
+	for _, rule := range in {
+		rules = append(rules, migration.FirewallRule{
+			Ports:       rule.Ports.NetworkPortRange(),
+			SourceCIDRs: rule.SourceCIDRs,
+		})
+	}
+	return rules
+}
+
+// StoragePools returns the storage pools which are in use by the
+// model associated with the API connection.
+func (c *Client) StoragePools() ([]migration.StoragePool, error) {
+	var result params.StoragePoolsResult
+	if err := c.caller.FacadeCall("StoragePools", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	pools := make([]migration.StoragePool, len(result.Result))
+	for i, pool := range result.Result {
+		pools[i] = migration.StoragePool{
+			Name:     pool.Name,
+			Provider: pool.Provider,
+		}
+	}
+	return pools, nil
+}
+
+// ModelCredential returns the cloud credential used by the model
+// associated with the API connection. A zero CloudCredential is
+// returned if the model doesn't use a credential.
+func (c *Client) ModelCredential() (migration.CloudCredential, error) {
+	var empty migration.CloudCredential
+	var in params.MigrationCredential
+	err := c.caller.FacadeCall("ModelCredential", nil, &in)
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	if in.Name == "" {
+		return empty, nil
+	}
+	owner, err := names.ParseUserTag(in.OwnerTag)
+	if err != nil {
+		return empty, errors.Annotate(err, "parsing credential owner tag")
+	}
+	return migration.CloudCredential{
+		Owner:      owner,
+		Cloud:      in.Cloud,
+		Name:       in.Name,
+		AuthType:   in.AuthType,
+		Attributes: in.Attributes,
 	}, nil
 }
 
@@ -133,6 +266,87 @@ func (c *Client) Reap() error {
 	return c.caller.FacadeCall("Reap", nil, nil)
 }
 
+// ReapComplete returns whether all the documents of the model
+// associated with the API connection have been removed by Reap.
+func (c *Client) ReapComplete() (bool, error) {
+	var result params.BoolResult
+	if err := c.caller.FacadeCall("ReapComplete", nil, &result); err != nil {
+		return false, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// LogRecordResult holds a log record streamed by StreamModelLog, or
+// the error which stopped the stream.
+type LogRecordResult struct {
+	Record params.LogRecord
+	Error  error
+}
+
+// StreamModelLog returns a channel which yields the log records of
+// the model associated with the API connection, oldest first, from
+// the given start time onwards. The channel is closed once the
+// records logged before the call have been sent. If the stream fails,
+// the final value sent on the channel holds the error. Closing stop
+// abandons the stream.
+func (c *Client) StreamModelLog(start time.Time, stop <-chan struct{}) (<-chan LogRecordResult, error) {
+	attrs := url.Values{
+		"format":    {"json"},
+		"replay":    {"true"},
+		"noTail":    {"true"},
+		"startTime": {start.Format(time.RFC3339Nano)},
+	}
+	stream, err := c.caller.RawAPICaller().ConnectStream("/log", attrs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to /log")
+	}
+	results := make(chan LogRecordResult)
+	done := make(chan struct{})
+	go func() {
+		// Closing the stream unblocks any pending read.
+		select {
+		case <-stop:
+			stream.Close()
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(results)
+		defer close(done)
+		defer stream.Close()
+		for {
+			var result LogRecordResult
+			err := stream.ReadJSON(&result.Record)
+			if err == io.EOF {
+				// The controller closes the stream once all
+				// the records have been sent.
+				return
+			} else if err != nil {
+				select {
+				case <-stop:
+					// The read failed because the stream
+					// was closed on request.
+					return
+				default:
+				}
+				result.Error = errors.Annotate(err, "reading log record")
+			}
+			select {
+			case results <- result:
+			case <-stop:
+				return
+			}
+			if result.Error != nil {
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
 // WatchMinionReports returns a watcher which reports when a migration
 // minion has made a report for the current migration phase.
 func (c *Client) WatchMinionReports() (watcher.NotifyWatcher, error) {
@@ -147,6 +361,20 @@ func (c *Client) WatchMinionReports() (watcher.NotifyWatcher, error) {
 	return c.newWatcher(c.caller.RawAPICaller(), result), nil
 }
 
+// WatchForAbort returns a watcher which reports when an operator
+// requests that the active migration be aborted.
+func (c *Client) WatchForAbort() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := c.caller.FacadeCall("WatchForAbort", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return c.newWatcher(c.caller.RawAPICaller(), result), nil
+}
+
 // GetMinionReports returns details of the reports made by migration
 // minions to the controller for the current migration phase.
 func (c *Client) GetMinionReports() (migration.MinionReports, error) {
@@ -179,6 +407,93 @@ func (c *Client) GetMinionReports() (migration.MinionReports, error) {
 		return out, errors.Annotate(err, "processing failed agents")
 	}
 
+	if len(in.FailureDetails) > 0 {
+		out.FailureDetails, err = keyByTagId(in.FailureDetails)
+		if err != nil {
+			return out, errors.Annotate(err, "processing failure details")
+		}
+	}
+
+	return out, nil
+}
+
+// SetMinionWaitProgress records the minion reports received so far
+// for the current migration phase, so that the controller can show
+// how many agents have reported while the migrationmaster waits for
+// them.
+func (c *Client) SetMinionWaitProgress(reports migration.MinionReports) error {
+	args := params.MinionReports{
+		MigrationId:   reports.MigrationId,
+		Phase:         reports.Phase.String(),
+		SuccessCount:  reports.SuccessCount,
+		UnknownCount:  reports.UnknownCount,
+		UnknownSample: agentTags(reports.SomeUnknownMachines, reports.SomeUnknownUnits),
+		Failed:        agentTags(reports.FailedMachines, reports.FailedUnits),
+	}
+	if len(reports.FailureDetails) > 0 {
+		args.FailureDetails = make(map[string]string)
+		for id, detail := range reports.FailureDetails {
+			args.FailureDetails[agentTag(id).String()] = detail
+		}
+	}
+	return c.caller.FacadeCall("SetMinionWaitProgress", args, nil)
+}
+
+// agentTags returns the tags of the given machines and units, as
+// strings.
+func agentTags(machineIds, unitNames []string) []string {
+	var tags []string
+	for _, id := range machineIds {
+		tags = append(tags, names.NewMachineTag(id).String())
+	}
+	for _, name := range unitNames {
+		tags = append(tags, names.NewUnitTag(name).String())
+	}
+	return tags
+}
+
+// agentTag returns the tag of the agent with the given machine id or
+// unit name.
+func agentTag(id string) names.Tag {
+	if names.IsValidMachine(id) {
+		return names.NewMachineTag(id)
+	}
+	return names.NewUnitTag(id)
+}
+
+// GetMinionFailureDetails returns the failure descriptions reported
+// by the migration minions which failed the given phase of the
+// current migration, keyed by machine id or unit name.
+func (c *Client) GetMinionFailureDetails(phase migration.Phase) (map[string]string, error) {
+	args := params.MinionFailureDetailsArgs{Phase: phase.String()}
+	var result params.MinionFailureDetails
+	err := c.caller.FacadeCall("GetMinionFailureDetails", args, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	details, err := keyByTagId(result.Details)
+	if err != nil {
+		return nil, errors.Annotate(err, "processing failure details")
+	}
+	return details, nil
+}
+
+// keyByTagId converts a map keyed by agent tag into one keyed by
+// machine id or unit name.
+func keyByTagId(byTag map[string]string) (map[string]string, error) {
+	out := make(map[string]string)
+	for tagStr, value := range byTag {
+		tag, err := names.ParseTag(tagStr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch tag.(type) {
+		case names.MachineTag, names.UnitTag:
+			out[tag.Id()] = value
+		default:
+			return nil, errors.Errorf("unsupported tag: %q", tag)
+		}
+	}
 	return out, nil
 }
 
@@ -4,6 +4,9 @@
 package migrationmaster_test
 
 import (
+	"io"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -19,6 +22,8 @@ import (
 	"github.com/juju/juju/api/migrationmaster"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/migration"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/watcher"
 )
 
@@ -77,9 +82,13 @@ func (s *ClientSuite) TestGetMigrationStatus(c *gc.C) {
 					Password:      "secret",
 				},
 			},
-			Attempt:          3,
-			Phase:            "READONLY",
-			PhaseChangedTime: timestamp,
+			MigrationId:        "id",
+			ModelName:          "mymodel",
+			Attempt:            3,
+			Phase:              "READONLY",
+			PhaseChangedTime:   timestamp,
+			ApprovalRef:        "CHG-1234",
+			ReapRetryRequested: true,
 		}
 		return nil
 	})
@@ -88,10 +97,14 @@ func (s *ClientSuite) TestGetMigrationStatus(c *gc.C) {
 	status, err := client.GetMigrationStatus()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(status, gc.DeepEquals, migration.MigrationStatus{
-		ModelUUID:        modelUUID,
-		Attempt:          3,
-		Phase:            migration.READONLY,
-		PhaseChangedTime: timestamp,
+		MigrationId:        "id",
+		ModelUUID:          modelUUID,
+		ModelName:          "mymodel",
+		Attempt:            3,
+		Phase:              migration.READONLY,
+		PhaseChangedTime:   timestamp,
+		ApprovalRef:        "CHG-1234",
+		ReapRetryRequested: true,
 		TargetInfo: migration.TargetInfo{
 			ControllerTag: names.NewModelTag(controllerUUID),
 			Addrs:         []string{"2.2.2.2:2"},
@@ -126,17 +139,84 @@ func (s *ClientSuite) TestSetPhaseError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "boom")
 }
 
+func (s *ClientSuite) TestSetStatusMessage(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	err := client.SetStatusMessage("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	expectedArg := params.SetMigrationStatusMessageArgs{Message: "foo"}
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMaster.SetStatusMessage", []interface{}{"", expectedArg}},
+	})
+}
+
+func (s *ClientSuite) TestSetStatusMessageError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("boom")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	err := client.SetStatusMessage("foo")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestModelInfo(c *gc.C) {
+	var stub jujutesting.Stub
+	owner := names.NewUserTag("owner")
+	apiCaller := apitesting.APICallerFunc(func(objType string, v int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.MigrationModelInfo)) = params.MigrationModelInfo{
+			UUID:         "uuid",
+			Name:         "name",
+			OwnerTag:     owner.String(),
+			AgentVersion: version.MustParse("1.2.3"),
+		}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	model, err := client.ModelInfo()
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMaster.ModelInfo", []interface{}{"", nil}},
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(model, jc.DeepEquals, migration.ModelInfo{
+		UUID:         "uuid",
+		Name:         "name",
+		Owner:        owner,
+		AgentVersion: version.MustParse("1.2.3"),
+	})
+}
+
+func (s *ClientSuite) TestModelInfoError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("blam")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.ModelInfo()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
 func (s *ClientSuite) TestExport(c *gc.C) {
 	var stub jujutesting.Stub
 	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		stub.AddCall(objType+"."+request, id, arg)
 		out := result.(*params.SerializedModel)
 		*out = params.SerializedModel{
-			Bytes:  []byte("foo"),
-			Charms: []string{"cs:foo-1"},
+			Bytes:        []byte("foo"),
+			Charms:       []string{"cs:foo-1", "local:trusty/bar-2"},
+			CharmOrigins: map[string]string{"cs:foo-1": "charmstore", "local:trusty/bar-2": "local"},
+			CharmSHA256s: map[string]string{"cs:foo-1": "foo-sha256", "local:trusty/bar-2": "bar-sha256"},
 			Tools: []params.SerializedModelTools{{
 				Version: "2.0.0-trusty-amd64",
 				URI:     "/tools/0",
+				SHA256:  "tools-sha256",
+			}},
+			FirewallRules: []params.FirewallRule{{
+				Ports:       params.PortRange{FromPort: 80, ToPort: 81, Protocol: "tcp"},
+				SourceCIDRs: []string{"10.0.0.0/8"},
 			}},
 		}
 		return nil
@@ -149,10 +229,25 @@ func (s *ClientSuite) TestExport(c *gc.C) {
 	})
 	c.Assert(out, gc.DeepEquals, migration.SerializedModel{
 		Bytes:  []byte("foo"),
-		Charms: []string{"cs:foo-1"},
+		Charms: []string{"cs:foo-1", "local:trusty/bar-2"},
+		CharmOrigins: map[string]migration.CharmOrigin{
+			"cs:foo-1":           migration.CharmStoreOrigin,
+			"local:trusty/bar-2": migration.LocalOrigin,
+		},
+		CharmSHA256s: map[string]string{
+			"cs:foo-1":           "foo-sha256",
+			"local:trusty/bar-2": "bar-sha256",
+		},
 		Tools: map[version.Binary]string{
 			version.MustParseBinary("2.0.0-trusty-amd64"): "/tools/0",
 		},
+		ToolsSHA256s: map[version.Binary]string{
+			version.MustParseBinary("2.0.0-trusty-amd64"): "tools-sha256",
+		},
+		FirewallRules: []migration.FirewallRule{{
+			Ports:       network.MustParsePortRange("80-81/tcp"),
+			SourceCIDRs: []string{"10.0.0.0/8"},
+		}},
 	})
 }
 
@@ -165,6 +260,99 @@ func (s *ClientSuite) TestExportError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "blam")
 }
 
+func (s *ClientSuite) TestModelCredential(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		out := result.(*params.MigrationCredential)
+		*out = params.MigrationCredential{
+			OwnerTag:   "user-bob",
+			Cloud:      "aws",
+			Name:       "default",
+			AuthType:   "access-key",
+			Attributes: map[string]string{"secret-key": "sekrit"},
+		}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	out, err := client.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMaster.ModelCredential", []interface{}{"", nil}},
+	})
+	c.Assert(out, jc.DeepEquals, migration.CloudCredential{
+		Owner:      names.NewUserTag("bob"),
+		Cloud:      "aws",
+		Name:       "default",
+		AuthType:   "access-key",
+		Attributes: map[string]string{"secret-key": "sekrit"},
+	})
+}
+
+func (s *ClientSuite) TestModelCredentialNone(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	out, err := client.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.IsZero(), jc.IsTrue)
+}
+
+func (s *ClientSuite) TestModelCredentialError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("blam")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.ModelCredential()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *ClientSuite) TestStoragePools(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		out := result.(*params.StoragePoolsResult)
+		*out = params.StoragePoolsResult{
+			Result: []params.StoragePool{
+				{Name: "ebs-ssd", Provider: "ebs"},
+				{Name: "rootfs", Provider: "rootfs"},
+			},
+		}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	pools, err := client.StoragePools()
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMaster.StoragePools", []interface{}{"", nil}},
+	})
+	c.Assert(pools, jc.DeepEquals, []migration.StoragePool{
+		{Name: "ebs-ssd", Provider: "ebs"},
+		{Name: "rootfs", Provider: "rootfs"},
+	})
+}
+
+func (s *ClientSuite) TestStoragePoolsResultError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(_ string, _ int, _, _ string, _, result interface{}) error {
+		out := result.(*params.StoragePoolsResult)
+		out.Error = &params.Error{Message: "blam"}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.StoragePools()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *ClientSuite) TestStoragePoolsError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("blam")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.StoragePools()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
 func (s *ClientSuite) TestReap(c *gc.C) {
 	var stub jujutesting.Stub
 	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -188,6 +376,147 @@ func (s *ClientSuite) TestReapError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "blam")
 }
 
+func (s *ClientSuite) TestReapComplete(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.BoolResult)) = params.BoolResult{Result: true}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	complete, err := client.ReapComplete()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(complete, jc.IsTrue)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMaster.ReapComplete", []interface{}{"", nil}},
+	})
+}
+
+func (s *ClientSuite) TestReapCompleteResultError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(_ string, _ int, _, _ string, _, result interface{}) error {
+		*(result.(*params.BoolResult)) = params.BoolResult{
+			Error: &params.Error{Message: "boom"},
+		}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.ReapComplete()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestReapCompleteError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("blam")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.ReapComplete()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *ClientSuite) TestStreamModelLog(c *gc.C) {
+	t1 := time.Date(2016, 12, 1, 10, 31, 0, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+	stream := newFakeStream(
+		params.LogRecord{Time: t1, Module: "juju.worker", Message: "one", Entity: "machine-0"},
+		params.LogRecord{Time: t2, Module: "juju.worker", Message: "two", Entity: "unit-mysql-0"},
+	)
+	var stub jujutesting.Stub
+	caller := streamCaller{
+		connectStream: func(path string, attrs url.Values) (base.Stream, error) {
+			stub.AddCall("ConnectStream", path, attrs)
+			return stream, nil
+		},
+	}
+	client := migrationmaster.NewClient(caller, nil)
+
+	results, err := client.StreamModelLog(t1, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var got []params.LogRecord
+	for result := range results {
+		c.Assert(result.Error, jc.ErrorIsNil)
+		got = append(got, result.Record)
+	}
+	c.Check(got, jc.DeepEquals, []params.LogRecord{
+		{Time: t1, Module: "juju.worker", Message: "one", Entity: "machine-0"},
+		{Time: t2, Module: "juju.worker", Message: "two", Entity: "unit-mysql-0"},
+	})
+	c.Check(stream.isClosed(), jc.IsTrue)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"ConnectStream", []interface{}{"/log", url.Values{
+			"format":    {"json"},
+			"replay":    {"true"},
+			"noTail":    {"true"},
+			"startTime": {"2016-12-01T10:31:00Z"},
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestStreamModelLogError(c *gc.C) {
+	caller := streamCaller{
+		connectStream: func(string, url.Values) (base.Stream, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	client := migrationmaster.NewClient(caller, nil)
+	_, err := client.StreamModelLog(time.Time{}, nil)
+	c.Assert(err, gc.ErrorMatches, "cannot connect to /log: boom")
+}
+
+func (s *ClientSuite) TestStreamModelLogReadError(c *gc.C) {
+	stream := newFakeStream(params.LogRecord{Message: "one"})
+	stream.err = errors.New("boom")
+	caller := streamCaller{
+		connectStream: func(string, url.Values) (base.Stream, error) {
+			return stream, nil
+		},
+	}
+	client := migrationmaster.NewClient(caller, nil)
+
+	results, err := client.StreamModelLog(time.Time{}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var got []migrationmaster.LogRecordResult
+	for result := range results {
+		got = append(got, result)
+	}
+	c.Assert(got, gc.HasLen, 2)
+	c.Check(got[0].Record.Message, gc.Equals, "one")
+	c.Check(got[0].Error, jc.ErrorIsNil)
+	c.Check(got[1].Error, gc.ErrorMatches, "reading log record: boom")
+	c.Check(stream.isClosed(), jc.IsTrue)
+}
+
+func (s *ClientSuite) TestStreamModelLogStop(c *gc.C) {
+	stream := newFakeStream(params.LogRecord{Message: "one"})
+	stream.blockAtEnd = true
+	caller := streamCaller{
+		connectStream: func(string, url.Values) (base.Stream, error) {
+			return stream, nil
+		},
+	}
+	client := migrationmaster.NewClient(caller, nil)
+
+	stop := make(chan struct{})
+	results, err := client.StreamModelLog(time.Time{}, stop)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case result := <-results:
+		c.Check(result.Record.Message, gc.Equals, "one")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log record")
+	}
+
+	// The stream is blocked waiting for more records; stopping
+	// closes it and ends the stream without an error.
+	close(stop)
+	select {
+	case result, ok := <-results:
+		c.Check(ok, jc.IsFalse, gc.Commentf("unexpected result %#v", result))
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for stream to stop")
+	}
+	c.Check(stream.isClosed(), jc.IsTrue)
+}
+
 func (s *ClientSuite) TestWatchMinionReports(c *gc.C) {
 	var stub jujutesting.Stub
 	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -221,6 +550,93 @@ func (s *ClientSuite) TestWatchMinionReportsError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "boom")
 }
 
+func (s *ClientSuite) TestWatchForAbort(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.NotifyWatchResult)) = params.NotifyWatchResult{
+			NotifyWatcherId: "123",
+		}
+		return nil
+	})
+
+	expectWatch := &struct{ watcher.NotifyWatcher }{}
+	newWatcher := func(caller base.APICaller, result params.NotifyWatchResult) watcher.NotifyWatcher {
+		c.Check(caller, gc.NotNil)
+		c.Check(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "123"})
+		return expectWatch
+	}
+	client := migrationmaster.NewClient(apiCaller, newWatcher)
+
+	w, err := client.WatchForAbort()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(w, gc.Equals, expectWatch)
+	stub.CheckCalls(c, []jujutesting.StubCall{{"MigrationMaster.WatchForAbort", []interface{}{"", nil}}})
+}
+
+func (s *ClientSuite) TestWatchForAbortError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.WatchForAbort()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestSetMinionWaitProgress(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	err := client.SetMinionWaitProgress(migration.MinionReports{
+		MigrationId:         "id",
+		Phase:               migration.READONLY,
+		SuccessCount:        4,
+		UnknownCount:        3,
+		SomeUnknownMachines: []string{"3", "4"},
+		SomeUnknownUnits:    []string{"foo/0"},
+		FailedMachines:      []string{"5"},
+		FailedUnits:         []string{"foo/1"},
+		FailureDetails: map[string]string{
+			"5":     "disk full",
+			"foo/1": "hook failed",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMaster.SetMinionWaitProgress", []interface{}{"", params.MinionReports{
+			MigrationId:  "id",
+			Phase:        "READONLY",
+			SuccessCount: 4,
+			UnknownCount: 3,
+			UnknownSample: []string{
+				names.NewMachineTag("3").String(),
+				names.NewMachineTag("4").String(),
+				names.NewUnitTag("foo/0").String(),
+			},
+			Failed: []string{
+				names.NewMachineTag("5").String(),
+				names.NewUnitTag("foo/1").String(),
+			},
+			FailureDetails: map[string]string{
+				names.NewMachineTag("5").String():  "disk full",
+				names.NewUnitTag("foo/1").String(): "hook failed",
+			},
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestSetMinionWaitProgressError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("boom")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	err := client.SetMinionWaitProgress(migration.MinionReports{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
 func (s *ClientSuite) TestGetMinionReports(c *gc.C) {
 	var stub jujutesting.Stub
 	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -241,6 +657,10 @@ func (s *ClientSuite) TestGetMinionReports(c *gc.C) {
 				names.NewUnitTag("foo/1").String(),
 				names.NewUnitTag("foo/2").String(),
 			},
+			FailureDetails: map[string]string{
+				names.NewMachineTag("5").String():  "disk full",
+				names.NewUnitTag("foo/1").String(): "hook failed",
+			},
 		}
 		return nil
 	})
@@ -259,9 +679,62 @@ func (s *ClientSuite) TestGetMinionReports(c *gc.C) {
 		SomeUnknownUnits:    []string{"foo/0"},
 		FailedMachines:      []string{"5"},
 		FailedUnits:         []string{"foo/1", "foo/2"},
+		FailureDetails: map[string]string{
+			"5":     "disk full",
+			"foo/1": "hook failed",
+		},
+	})
+}
+
+func (s *ClientSuite) TestGetMinionFailureDetails(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		out := result.(*params.MinionFailureDetails)
+		*out = params.MinionFailureDetails{
+			Details: map[string]string{
+				names.NewMachineTag("5").String():  "disk full",
+				names.NewUnitTag("foo/1").String(): "",
+			},
+		}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	details, err := client.GetMinionFailureDetails(migration.QUIESCE)
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMaster.GetMinionFailureDetails", []interface{}{"", params.MinionFailureDetailsArgs{
+			Phase: "QUIESCE",
+		}}},
+	})
+	c.Check(details, jc.DeepEquals, map[string]string{
+		"5":     "disk full",
+		"foo/1": "",
 	})
 }
 
+func (s *ClientSuite) TestGetMinionFailureDetailsError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("blam")
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.GetMinionFailureDetails(migration.QUIESCE)
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *ClientSuite) TestGetMinionFailureDetailsBadTag(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(_ string, _ int, _ string, _ string, _ interface{}, result interface{}) error {
+		out := result.(*params.MinionFailureDetails)
+		*out = params.MinionFailureDetails{
+			Details: map[string]string{"erin": "boom"},
+		}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	_, err := client.GetMinionFailureDetails(migration.QUIESCE)
+	c.Assert(err, gc.ErrorMatches, `processing failure details: "erin" is not a valid tag`)
+}
+
 func (s *ClientSuite) TestGetMinionReportsFailedCall(c *gc.C) {
 	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
 		return errors.New("blam")
@@ -311,3 +784,64 @@ func (s *ClientSuite) TestGetMinionReportsBadFailedTag(c *gc.C) {
 	_, err := client.GetMinionReports()
 	c.Assert(err, gc.ErrorMatches, `processing failed agents: "dave" is not a valid tag`)
 }
+
+// streamCaller is an APICaller which delegates stream connections to
+// a function.
+type streamCaller struct {
+	apitesting.APICallerFunc
+	connectStream func(string, url.Values) (base.Stream, error)
+}
+
+func (c streamCaller) ConnectStream(path string, attrs url.Values) (base.Stream, error) {
+	return c.connectStream(path, attrs)
+}
+
+// fakeStream is a base.Stream which yields the given records and
+// then reports the end of the stream, or err if it's set. If
+// blockAtEnd is set, it instead waits until the stream is closed.
+type fakeStream struct {
+	base.Stream
+	records    []params.LogRecord
+	err        error
+	blockAtEnd bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newFakeStream(records ...params.LogRecord) *fakeStream {
+	return &fakeStream{
+		records: records,
+		closed:  make(chan struct{}),
+	}
+}
+
+func (s *fakeStream) ReadJSON(v interface{}) error {
+	if len(s.records) == 0 {
+		switch {
+		case s.err != nil:
+			return s.err
+		case s.blockAtEnd:
+			<-s.closed
+			return errors.New("use of closed connection")
+		}
+		return io.EOF
+	}
+	*(v.(*params.LogRecord)) = s.records[0]
+	s.records = s.records[1:]
+	return nil
+}
+
+func (s *fakeStream) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
+
+func (s *fakeStream) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
@@ -48,3 +48,17 @@ func (c *Client) Report(migrationId string, phase migration.Phase, success bool)
 	err := c.caller.FacadeCall("Report", args, nil)
 	return errors.Trace(err)
 }
+
+// ReportFailure allows a migration minion to report that it failed to
+// complete its activities for a given migration phase, along with a
+// description of the failure.
+func (c *Client) ReportFailure(migrationId string, phase migration.Phase, message string) error {
+	args := params.MinionReport{
+		MigrationId: migrationId,
+		Phase:       phase.String(),
+		Success:     false,
+		Error:       message,
+	}
+	err := c.caller.FacadeCall("Report", args, nil)
+	return errors.Trace(err)
+}
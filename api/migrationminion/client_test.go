@@ -112,3 +112,24 @@ func (s *ClientSuite) TestReportError(c *gc.C) {
 	err := client.Report("id", migration.IMPORT, true)
 	c.Assert(err, gc.ErrorMatches, "boom")
 }
+
+func (s *ClientSuite) TestReportFailure(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, arg)
+		return nil
+	})
+
+	client := migrationminion.NewClient(apiCaller)
+	err := client.ReportFailure("id", migration.IMPORT, "disk full")
+	c.Assert(err, jc.ErrorIsNil)
+
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationMinion.Report", []interface{}{params.MinionReport{
+			MigrationId: "id",
+			Phase:       "IMPORT",
+			Success:     false,
+			Error:       "disk full",
+		}}},
+	})
+}
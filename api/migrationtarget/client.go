@@ -4,26 +4,85 @@
 package migrationtarget
 
 import (
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	coremigration "github.com/juju/juju/core/migration"
 )
 
 // Client describes the client side API for the MigrationTarget
 // facade. It is called by the migration master worker to talk to the
 // target controller during a migration.
 type Client interface {
+	// Prechecks checks that the target controller is able to accept
+	// the given model, for example that it runs a compatible version
+	// and doesn't already have a model with the same name. The
+	// reason for any failure is returned as an error.
+	Prechecks(coremigration.ModelInfo) error
+
 	// Import takes a serialized model and imports it into the target
 	// controller.
 	Import([]byte) error
 
+	// Export returns the serialized form of the target controller's
+	// copy of the given model, so that it may be compared with the
+	// model that was imported.
+	Export(string) ([]byte, error)
+
 	// Abort removes all data relating to a previously imported
 	// model.
 	Abort(string) error
 
 	// Activate marks a migrated model as being ready to use.
 	Activate(string) error
+
+	// CredentialHash returns the hash of the content of the target
+	// controller's copy of the given credential. Only the owner,
+	// cloud and name of the credential are used to identify it. An
+	// error satisfying params.IsCodeNotFound is returned if the
+	// target controller doesn't have the credential.
+	CredentialHash(coremigration.CloudCredential) (string, error)
+
+	// UploadCredential sends a cloud credential to the target
+	// controller. The target verifies the credential against the
+	// hash sent with it before storing it.
+	UploadCredential(coremigration.CloudCredential) error
+
+	// ApplyFirewallRules asks the target controller to recreate the
+	// given firewall rules for a migrated model. The rules which the
+	// target controller's provider couldn't recreate are returned.
+	ApplyFirewallRules(string, []coremigration.FirewallRule) ([]coremigration.UntranslatedFirewallRule, error)
+
+	// VerifyBinaries asks the target controller to confirm that the
+	// charms and tools it stores for the given model have the given
+	// SHA256 sums, keyed by charm URL and tools version. An error
+	// describing any mismatch is returned.
+	VerifyBinaries(string, map[string]string, map[version.Binary]string) error
+
+	// StorageProviderTypes returns the storage provider types
+	// supported by the target controller's cloud.
+	StorageProviderTypes() ([]string, error)
+
+	// AgentStreams returns the agent streams the target controller
+	// can serve tools from.
+	AgentStreams() ([]string, error)
+
+	// LatestLogTime returns the time of the most recent log record
+	// the target controller holds for the given model, or the zero
+	// time if it has none. It is used to resume an interrupted log
+	// transfer.
+	LatestLogTime(string) (time.Time, error)
+
+	// OpenLogTransferStream opens a stream to which the given
+	// model's log records may be written, as JSON arrays of
+	// params.LogRecord, to be stored by the target controller.
+	OpenLogTransferStream(string) (base.Stream, error)
 }
 
 // NewClient returns a new Client based on an existing API connection.
@@ -36,12 +95,33 @@ type client struct {
 	caller base.FacadeCaller
 }
 
+// Prechecks implements Client.
+func (c *client) Prechecks(model coremigration.ModelInfo) error {
+	args := params.MigrationModelInfo{
+		UUID:         model.UUID,
+		Name:         model.Name,
+		OwnerTag:     model.Owner.String(),
+		AgentVersion: model.AgentVersion,
+	}
+	return c.caller.FacadeCall("Prechecks", args, nil)
+}
+
 // Import implements Client.
 func (c *client) Import(bytes []byte) error {
 	serialized := params.SerializedModel{Bytes: bytes}
 	return c.caller.FacadeCall("Import", serialized, nil)
 }
 
+// Export implements Client.
+func (c *client) Export(modelUUID string) ([]byte, error) {
+	args := params.ModelArgs{ModelTag: names.NewModelTag(modelUUID).String()}
+	var serialized params.SerializedModel
+	if err := c.caller.FacadeCall("Export", args, &serialized); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return serialized.Bytes, nil
+}
+
 // Abort implements Client.
 func (c *client) Abort(modelUUID string) error {
 	args := params.ModelArgs{ModelTag: names.NewModelTag(modelUUID).String()}
@@ -53,3 +133,125 @@ func (c *client) Activate(modelUUID string) error {
 	args := params.ModelArgs{ModelTag: names.NewModelTag(modelUUID).String()}
 	return c.caller.FacadeCall("Activate", args, nil)
 }
+
+// CredentialHash implements Client.
+func (c *client) CredentialHash(cred coremigration.CloudCredential) (string, error) {
+	args := params.MigrationCredential{
+		OwnerTag: cred.Owner.String(),
+		Cloud:    cred.Cloud,
+		Name:     cred.Name,
+	}
+	var result params.StringResult
+	if err := c.caller.FacadeCall("CredentialHash", args, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// UploadCredential implements Client.
+func (c *client) UploadCredential(cred coremigration.CloudCredential) error {
+	args := params.MigrationCredential{
+		OwnerTag:   cred.Owner.String(),
+		Cloud:      cred.Cloud,
+		Name:       cred.Name,
+		AuthType:   cred.AuthType,
+		Attributes: cred.Attributes,
+		Hash:       cred.Hash(),
+	}
+	return c.caller.FacadeCall("UploadCredential", args, nil)
+}
+
+// ApplyFirewallRules implements Client.
+func (c *client) ApplyFirewallRules(
+	modelUUID string, rules []coremigration.FirewallRule,
+) ([]coremigration.UntranslatedFirewallRule, error) {
+	args := params.ApplyFirewallRulesArgs{
+		ModelTag: names.NewModelTag(modelUUID).String(),
+		Rules:    make([]params.FirewallRule, len(rules)),
+	}
+	for i, rule := range rules {
+		args.Rules[i] = params.FirewallRule{
+			Ports:       params.FromNetworkPortRange(rule.Ports),
+			SourceCIDRs: rule.SourceCIDRs,
+		}
+	}
+	var result params.ApplyFirewallRulesResult
+	if err := c.caller.FacadeCall("ApplyFirewallRules", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var untranslated []coremigration.UntranslatedFirewallRule
+	for _, u := range result.Untranslated {
+		untranslated = append(untranslated, coremigration.UntranslatedFirewallRule{
+			Rule: coremigration.FirewallRule{
+				Ports:       u.Rule.Ports.NetworkPortRange(),
+				SourceCIDRs: u.Rule.SourceCIDRs,
+			},
+			Reason: u.Reason,
+		})
+	}
+	return untranslated, nil
+}
+
+// VerifyBinaries implements Client.
+func (c *client) VerifyBinaries(
+	modelUUID string, charms map[string]string, tools map[version.Binary]string,
+) error {
+	args := params.VerifyBinariesArgs{
+		ModelTag: names.NewModelTag(modelUUID).String(),
+		Charms:   charms,
+	}
+	for v, sha256 := range tools {
+		args.Tools = append(args.Tools, params.SerializedModelTools{
+			Version: v.String(),
+			SHA256:  sha256,
+		})
+	}
+	return c.caller.FacadeCall("VerifyBinaries", args, nil)
+}
+
+// StorageProviderTypes implements Client.
+func (c *client) StorageProviderTypes() ([]string, error) {
+	var result params.StringsResult
+	if err := c.caller.FacadeCall("StorageProviderTypes", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// AgentStreams implements Client.
+func (c *client) AgentStreams() ([]string, error) {
+	var result params.StringsResult
+	if err := c.caller.FacadeCall("AgentStreams", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// LatestLogTime implements Client.
+func (c *client) LatestLogTime(modelUUID string) (time.Time, error) {
+	args := params.ModelArgs{ModelTag: names.NewModelTag(modelUUID).String()}
+	var result time.Time
+	if err := c.caller.FacadeCall("LatestLogTime", args, &result); err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// OpenLogTransferStream implements Client.
+func (c *client) OpenLogTransferStream(modelUUID string) (base.Stream, error) {
+	attrs := url.Values{"model-uuid": {modelUUID}}
+	stream, err := c.caller.RawAPICaller().ConnectStream("/migrate/logtransfer", attrs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to /migrate/logtransfer")
+	}
+	return stream, nil
+}
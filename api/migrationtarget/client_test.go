@@ -4,14 +4,22 @@
 package migrationtarget_test
 
 import (
+	"net/url"
+	"time"
+
 	"github.com/juju/errors"
 	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/api/base"
 	apitesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/migrationtarget"
 	"github.com/juju/juju/apiserver/params"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/network"
 )
 
 type ClientSuite struct {
@@ -42,6 +50,68 @@ func (s *ClientSuite) TestImport(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "boom")
 }
 
+func (s *ClientSuite) TestExport(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.SerializedModel)) = params.SerializedModel{Bytes: []byte("model")}
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	bytes, err := client.Export("fake")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(bytes), gc.Equals, "model")
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.Export", []interface{}{"", params.ModelArgs{
+			ModelTag: names.NewModelTag("fake").String(),
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestExportError(c *gc.C) {
+	client, _ := s.getClientAndStub(c)
+	_, err := client.Export("fake")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestPrechecks(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	ownerTag := names.NewUserTag("owner")
+	vers := version.MustParse("1.2.3")
+
+	err := client.Prechecks(coremigration.ModelInfo{
+		UUID:         "uuid",
+		Owner:        ownerTag,
+		Name:         "name",
+		AgentVersion: vers,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.Prechecks", []interface{}{"", params.MigrationModelInfo{
+			UUID:         "uuid",
+			Name:         "name",
+			OwnerTag:     ownerTag.String(),
+			AgentVersion: vers,
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestPrechecksError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("blam")
+	})
+	client := migrationtarget.NewClient(apiCaller)
+	err := client.Prechecks(coremigration.ModelInfo{})
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
 func (s *ClientSuite) TestAbort(c *gc.C) {
 	client, stub := s.getClientAndStub(c)
 
@@ -58,6 +128,266 @@ func (s *ClientSuite) TestActivate(c *gc.C) {
 	s.AssertModelCall(c, stub, names.NewModelTag(uuid), "Activate", err)
 }
 
+func (s *ClientSuite) TestCredentialHash(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.StringResult)) = params.StringResult{Result: "abc"}
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	hash, err := client.CredentialHash(testCredential)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(hash, gc.Equals, "abc")
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.CredentialHash", []interface{}{"", params.MigrationCredential{
+			OwnerTag: "user-bob",
+			Cloud:    "aws",
+			Name:     "default",
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestCredentialHashResultError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StringResult)) = params.StringResult{
+			Error: &params.Error{Code: params.CodeNotFound, Message: "nope"},
+		}
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	_, err := client.CredentialHash(testCredential)
+	c.Assert(err, gc.ErrorMatches, "nope")
+	c.Assert(params.IsCodeNotFound(err), jc.IsTrue)
+}
+
+func (s *ClientSuite) TestCredentialHashError(c *gc.C) {
+	client, _ := s.getClientAndStub(c)
+	_, err := client.CredentialHash(testCredential)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestUploadCredential(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+
+	err := client.UploadCredential(testCredential)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.UploadCredential", []interface{}{"", params.MigrationCredential{
+			OwnerTag:   "user-bob",
+			Cloud:      "aws",
+			Name:       "default",
+			AuthType:   "access-key",
+			Attributes: map[string]string{"secret-key": "sekrit"},
+			Hash:       testCredential.Hash(),
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestApplyFirewallRules(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.ApplyFirewallRulesResult)) = params.ApplyFirewallRulesResult{
+			Untranslated: []params.UntranslatedFirewallRule{{
+				Rule: params.FirewallRule{
+					Ports: params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+				},
+				Reason: "udp not supported",
+			}},
+		}
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	rules := []coremigration.FirewallRule{{
+		Ports:       network.MustParsePortRange("80/tcp"),
+		SourceCIDRs: []string{"10.0.0.0/8"},
+	}, {
+		Ports: network.MustParsePortRange("53/udp"),
+	}}
+	untranslated, err := client.ApplyFirewallRules("uuid", rules)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(untranslated, jc.DeepEquals, []coremigration.UntranslatedFirewallRule{{
+		Rule:   coremigration.FirewallRule{Ports: network.MustParsePortRange("53/udp")},
+		Reason: "udp not supported",
+	}})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.ApplyFirewallRules", []interface{}{"", params.ApplyFirewallRulesArgs{
+			ModelTag: names.NewModelTag("uuid").String(),
+			Rules: []params.FirewallRule{{
+				Ports:       params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+				SourceCIDRs: []string{"10.0.0.0/8"},
+			}, {
+				Ports: params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+			}},
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestApplyFirewallRulesError(c *gc.C) {
+	client, _ := s.getClientAndStub(c)
+	_, err := client.ApplyFirewallRules("uuid", nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestVerifyBinaries(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+
+	err := client.VerifyBinaries(
+		"uuid",
+		map[string]string{"cs:foo-1": "foo-sha256"},
+		map[version.Binary]string{
+			version.MustParseBinary("2.0.0-trusty-amd64"): "tools-sha256",
+		},
+	)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.VerifyBinaries", []interface{}{"", params.VerifyBinariesArgs{
+			ModelTag: names.NewModelTag("uuid").String(),
+			Charms:   map[string]string{"cs:foo-1": "foo-sha256"},
+			Tools: []params.SerializedModelTools{{
+				Version: "2.0.0-trusty-amd64",
+				SHA256:  "tools-sha256",
+			}},
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestStorageProviderTypes(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.StringsResult)) = params.StringsResult{
+			Result: []string{"ebs", "loop"},
+		}
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	types, err := client.StorageProviderTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(types, jc.DeepEquals, []string{"ebs", "loop"})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.StorageProviderTypes", []interface{}{"", nil}},
+	})
+}
+
+func (s *ClientSuite) TestStorageProviderTypesError(c *gc.C) {
+	client, _ := s.getClientAndStub(c)
+	_, err := client.StorageProviderTypes()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestAgentStreams(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*params.StringsResult)) = params.StringsResult{
+			Result: []string{"released", "proposed"},
+		}
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	streams, err := client.AgentStreams()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(streams, jc.DeepEquals, []string{"released", "proposed"})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.AgentStreams", []interface{}{"", nil}},
+	})
+}
+
+func (s *ClientSuite) TestAgentStreamsError(c *gc.C) {
+	client, _ := s.getClientAndStub(c)
+	_, err := client.AgentStreams()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ClientSuite) TestLatestLogTime(c *gc.C) {
+	var stub jujutesting.Stub
+	t1 := time.Date(2016, 12, 1, 10, 31, 0, 0, time.UTC)
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		stub.AddCall(objType+"."+request, id, arg)
+		*(result.(*time.Time)) = t1
+		return nil
+	})
+	client := migrationtarget.NewClient(apiCaller)
+
+	latest, err := client.LatestLogTime("fake")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(latest, gc.Equals, t1)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.LatestLogTime", []interface{}{"", params.ModelArgs{
+			ModelTag: names.NewModelTag("fake").String(),
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestLatestLogTimeError(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+	_, err := client.LatestLogTime("fake")
+	s.AssertModelCall(c, stub, names.NewModelTag("fake"), "LatestLogTime", err)
+}
+
+func (s *ClientSuite) TestOpenLogTransferStream(c *gc.C) {
+	var stub jujutesting.Stub
+	stream := fakeStream{}
+	caller := streamCaller{
+		connectStream: func(path string, attrs url.Values) (base.Stream, error) {
+			stub.AddCall("ConnectStream", path, attrs)
+			return stream, nil
+		},
+	}
+	client := migrationtarget.NewClient(caller)
+
+	result, err := client.OpenLogTransferStream("fake")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, gc.Equals, stream)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"ConnectStream", []interface{}{
+			"/migrate/logtransfer",
+			url.Values{"model-uuid": {"fake"}},
+		}},
+	})
+}
+
+func (s *ClientSuite) TestOpenLogTransferStreamError(c *gc.C) {
+	caller := streamCaller{
+		connectStream: func(string, url.Values) (base.Stream, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	client := migrationtarget.NewClient(caller)
+	_, err := client.OpenLogTransferStream("fake")
+	c.Assert(err, gc.ErrorMatches, "cannot connect to /migrate/logtransfer: boom")
+}
+
+// streamCaller is an APICaller which delegates stream connections to
+// a function.
+type streamCaller struct {
+	apitesting.APICallerFunc
+	connectStream func(string, url.Values) (base.Stream, error)
+}
+
+func (c streamCaller) ConnectStream(path string, attrs url.Values) (base.Stream, error) {
+	return c.connectStream(path, attrs)
+}
+
+type fakeStream struct {
+	base.Stream
+}
+
+var testCredential = coremigration.CloudCredential{
+	Owner:      names.NewUserTag("bob"),
+	Cloud:      "aws",
+	Name:       "default",
+	AuthType:   "access-key",
+	Attributes: map[string]string{"secret-key": "sekrit"},
+}
+
 func (s *ClientSuite) AssertModelCall(c *gc.C, stub *jujutesting.Stub, tag names.ModelTag, call string, err error) {
 	expectedArg := params.ModelArgs{ModelTag: tag.String()}
 	stub.CheckCalls(c, []jujutesting.StubCall{
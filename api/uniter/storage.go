@@ -4,12 +4,15 @@
 package uniter
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/watcher"
 )
 
@@ -46,6 +49,73 @@ func (sa *StorageAccessor) UnitStorageAttachments(unitTag names.UnitTag) ([]para
 	return result.Result.Ids, nil
 }
 
+// UnitsStorageAttachments returns the IDs of the storage attachments
+// of each of the specified units, fetched in a single call. A unit's
+// result holds an error if its attachments could not be fetched.
+func (sa *StorageAccessor) UnitsStorageAttachments(unitTags []names.UnitTag) (map[names.UnitTag]params.StorageAttachmentIdsResult, error) {
+	if sa.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("UnitsStorageAttachments() (need V2+)")
+	}
+	if len(unitTags) == 0 {
+		return map[names.UnitTag]params.StorageAttachmentIdsResult{}, nil
+	}
+	args := params.Entities{
+		Entities: make([]params.Entity, len(unitTags)),
+	}
+	for i, unitTag := range unitTags {
+		args.Entities[i] = params.Entity{Tag: unitTag.String()}
+	}
+	var results params.StorageAttachmentIdsResults
+	err := sa.facade.FacadeCall("UnitStorageAttachments", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(unitTags) {
+		return nil, errors.Errorf("expected %d results, got %d", len(unitTags), len(results.Results))
+	}
+	attachments := make(map[names.UnitTag]params.StorageAttachmentIdsResult)
+	for i, unitTag := range unitTags {
+		attachments[unitTag] = results.Results[i]
+	}
+	return attachments, nil
+}
+
+// AddUnitStorage adds storage instances to the specified unit, with
+// the given constraints keyed by storage name. Counts and sizes, if
+// specified, must be non-zero.
+func (sa *StorageAccessor) AddUnitStorage(unitTag names.UnitTag, all map[string]params.StorageConstraints) error {
+	if sa.facade.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("AddUnitStorage() (need V2+)")
+	}
+	storageNames := make([]string, 0, len(all))
+	for name, cons := range all {
+		if cons.Count != nil && *cons.Count == 0 {
+			return errors.NotValidf("zero count for storage %q", name)
+		}
+		if cons.Size != nil && *cons.Size == 0 {
+			return errors.NotValidf("zero size for storage %q", name)
+		}
+		storageNames = append(storageNames, name)
+	}
+	sort.Strings(storageNames)
+	args := params.StoragesAddParams{
+		Storages: make([]params.StorageAddParams, len(storageNames)),
+	}
+	for i, name := range storageNames {
+		args.Storages[i] = params.StorageAddParams{
+			UnitTag:     unitTag.String(),
+			StorageName: name,
+			Constraints: all[name],
+		}
+	}
+	var results params.ErrorResults
+	err := sa.facade.FacadeCall("AddUnitStorage", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
 // DestroyUnitStorageAttachments ensures that the specified unit's storage
 // attachments will be removed at some point in the future.
 func (sa *StorageAccessor) DestroyUnitStorageAttachments(unitTag names.UnitTag) error {
@@ -120,6 +190,31 @@ func (sa *StorageAccessor) StorageAttachment(storageTag names.StorageTag, unitTa
 	return result.Result, nil
 }
 
+// StorageAttachmentInfo holds the kind and location of a storage
+// attachment.
+type StorageAttachmentInfo struct {
+	// Kind is the kind of the attached storage: block device or
+	// filesystem.
+	Kind storage.StorageKind
+
+	// Location is the path at which the storage is attached to the
+	// unit's machine, which is empty until it has been provisioned.
+	Location string
+}
+
+// AttachmentInfo returns the kind and location of the storage
+// attachment with the specified unit and storage tags.
+func (sa *StorageAccessor) AttachmentInfo(storageTag names.StorageTag, unitTag names.UnitTag) (StorageAttachmentInfo, error) {
+	attachment, err := sa.StorageAttachment(storageTag, unitTag)
+	if err != nil {
+		return StorageAttachmentInfo{}, errors.Trace(err)
+	}
+	return StorageAttachmentInfo{
+		Kind:     storage.StorageKind(attachment.Kind),
+		Location: attachment.Location,
+	}, nil
+}
+
 // StorageAttachmentLife returns the lifecycle state of the storage attachments
 // with the specified IDs.
 func (sa *StorageAccessor) StorageAttachmentLife(ids []params.StorageAttachmentId) ([]params.LifeResult, error) {
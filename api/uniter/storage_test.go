@@ -9,9 +9,11 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -53,6 +55,137 @@ func (s *storageSuite) TestUnitStorageAttachments(c *gc.C) {
 	c.Assert(attachmentIds, gc.DeepEquals, storageAttachmentIds)
 }
 
+func (s *storageSuite) TestUnitsStorageAttachments(c *gc.C) {
+	storageAttachmentIds := []params.StorageAttachmentId{{
+		StorageTag: "storage-whatever-0",
+		UnitTag:    "unit-mysql-0",
+	}}
+
+	var calls int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Uniter")
+		c.Check(version, gc.Equals, 4)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "UnitStorageAttachments")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "unit-mysql-0"}, {Tag: "unit-mysql-1"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StorageAttachmentIdsResults{})
+		*(result.(*params.StorageAttachmentIdsResults)) = params.StorageAttachmentIdsResults{
+			Results: []params.StorageAttachmentIdsResult{{
+				Result: params.StorageAttachmentIds{storageAttachmentIds},
+			}, {
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		calls++
+		return nil
+	})
+
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	mysql0, mysql1 := names.NewUnitTag("mysql/0"), names.NewUnitTag("mysql/1")
+	results, err := st.UnitsStorageAttachments([]names.UnitTag{mysql0, mysql1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(calls, gc.Equals, 1)
+	c.Assert(results, gc.HasLen, 2)
+	c.Check(results[mysql0].Error, gc.IsNil)
+	c.Check(results[mysql0].Result.Ids, gc.DeepEquals, storageAttachmentIds)
+	c.Check(results[mysql1].Error, gc.ErrorMatches, "permission denied")
+	c.Check(results[mysql1].Error, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *storageSuite) TestUnitsStorageAttachmentsNoUnits(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	results, err := st.UnitsStorageAttachments(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 0)
+}
+
+func (s *storageSuite) TestUnitsStorageAttachmentsResultCountMismatch(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StorageAttachmentIdsResults)) = params.StorageAttachmentIdsResults{
+			[]params.StorageAttachmentIdsResult{{}},
+		}
+		return nil
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	_, err := st.UnitsStorageAttachments([]names.UnitTag{
+		names.NewUnitTag("mysql/0"), names.NewUnitTag("mysql/1"),
+	})
+	c.Assert(err, gc.ErrorMatches, "expected 2 results, got 1")
+}
+
+func (s *storageSuite) TestAddUnitStorage(c *gc.C) {
+	count, size := uint64(2), uint64(1024)
+	var called bool
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Uniter")
+		c.Check(version, gc.Equals, 4)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "AddUnitStorage")
+		c.Check(arg, jc.DeepEquals, params.StoragesAddParams{
+			Storages: []params.StorageAddParams{{
+				UnitTag:     "unit-mysql-0",
+				StorageName: "data",
+				Constraints: params.StorageConstraints{Count: &count},
+			}, {
+				UnitTag:     "unit-mysql-0",
+				StorageName: "logs",
+				Constraints: params.StorageConstraints{Pool: "ebs", Size: &size},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		called = true
+		return nil
+	})
+
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	err := st.AddUnitStorage(names.NewUnitTag("mysql/0"), map[string]params.StorageConstraints{
+		"data": {Count: &count},
+		"logs": {Pool: "ebs", Size: &size},
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *storageSuite) TestAddUnitStorageZeroCount(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	zero := uint64(0)
+	err := st.AddUnitStorage(names.NewUnitTag("mysql/0"), map[string]params.StorageConstraints{
+		"data": {Count: &zero},
+	})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `zero count for storage "data" not valid`)
+	err = st.AddUnitStorage(names.NewUnitTag("mysql/0"), map[string]params.StorageConstraints{
+		"data": {Size: &zero},
+	})
+	c.Check(err, gc.ErrorMatches, `zero size for storage "data" not valid`)
+}
+
+func (s *storageSuite) TestAddUnitStorageNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+
+	facade := base.NewFacadeCallerForVersion(apiCaller, "Uniter", 1)
+	sa := uniter.NewStorageAccessor(facade)
+	err := sa.AddUnitStorage(names.NewUnitTag("mysql/0"), nil)
+	c.Check(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *storageSuite) TestDestroyUnitStorageAttachments(c *gc.C) {
 	var called bool
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -77,6 +210,37 @@ func (s *storageSuite) TestDestroyUnitStorageAttachments(c *gc.C) {
 	c.Check(called, jc.IsTrue)
 }
 
+func (s *storageSuite) TestDestroyUnitStorageAttachmentsError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "DestroyUnitStorageAttachments")
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "yoink", Code: params.CodeNotFound},
+			}},
+		}
+		return nil
+	})
+
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	err := st.DestroyUnitStorageAttachments(names.NewUnitTag("mysql/0"))
+	c.Check(err, gc.ErrorMatches, "yoink")
+	c.Check(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *storageSuite) TestDestroyUnitStorageAttachmentsNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+
+	// Servers older than V2 don't support the call.
+	facade := base.NewFacadeCallerForVersion(apiCaller, "Uniter", 1)
+	sa := uniter.NewStorageAccessor(facade)
+	err := sa.DestroyUnitStorageAttachments(names.NewUnitTag("mysql/0"))
+	c.Check(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Check(err, gc.ErrorMatches, `DestroyUnitStorageAttachments\(\) \(need V2\+\) not implemented`)
+}
+
 func (s *storageSuite) TestStorageAttachmentResultCountMismatch(c *gc.C) {
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		*(result.(*params.StorageAttachmentIdsResults)) = params.StorageAttachmentIdsResults{
@@ -192,6 +356,70 @@ func (s *storageSuite) TestStorageAttachments(c *gc.C) {
 	c.Assert(attachment, gc.DeepEquals, storageAttachment)
 }
 
+func (s *storageSuite) TestAttachmentInfo(c *gc.C) {
+	for _, test := range []struct {
+		attachment params.StorageAttachment
+		expect     uniter.StorageAttachmentInfo
+	}{{
+		attachment: params.StorageAttachment{
+			StorageTag: "storage-data-0",
+			UnitTag:    "unit-mysql-0",
+			Kind:       params.StorageKindBlock,
+			Location:   "/dev/sdb",
+		},
+		expect: uniter.StorageAttachmentInfo{
+			Kind:     storage.StorageKindBlock,
+			Location: "/dev/sdb",
+		},
+	}, {
+		attachment: params.StorageAttachment{
+			StorageTag: "storage-data-0",
+			UnitTag:    "unit-mysql-0",
+			Kind:       params.StorageKindFilesystem,
+			Location:   "/srv/data",
+		},
+		expect: uniter.StorageAttachmentInfo{
+			Kind:     storage.StorageKindFilesystem,
+			Location: "/srv/data",
+		},
+	}} {
+		apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "StorageAttachments")
+			c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{
+				Ids: []params.StorageAttachmentId{{
+					StorageTag: "storage-data-0",
+					UnitTag:    "unit-mysql-0",
+				}},
+			})
+			*(result.(*params.StorageAttachmentResults)) = params.StorageAttachmentResults{
+				Results: []params.StorageAttachmentResult{{Result: test.attachment}},
+			}
+			return nil
+		})
+
+		st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+		info, err := st.AttachmentInfo(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(info, jc.DeepEquals, test.expect)
+	}
+}
+
+func (s *storageSuite) TestAttachmentInfoError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StorageAttachmentResults)) = params.StorageAttachmentResults{
+			Results: []params.StorageAttachmentResult{{
+				Error: &params.Error{Message: "not found", Code: params.CodeNotFound},
+			}},
+		}
+		return nil
+	})
+
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	_, err := st.AttachmentInfo(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
+	c.Check(err, gc.ErrorMatches, "not found")
+	c.Check(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
 func (s *storageSuite) TestStorageAttachmentLife(c *gc.C) {
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
@@ -222,6 +450,43 @@ func (s *storageSuite) TestStorageAttachmentLife(c *gc.C) {
 	c.Assert(results, jc.DeepEquals, []params.LifeResult{{Life: params.Dying}})
 }
 
+func (s *storageSuite) TestStorageAttachmentLifeBatch(c *gc.C) {
+	ids := []params.StorageAttachmentId{{
+		StorageTag: "storage-data-0",
+		UnitTag:    "unit-mysql-0",
+	}, {
+		StorageTag: "storage-data-1",
+		UnitTag:    "unit-mysql-0",
+	}, {
+		StorageTag: "storage-data-2",
+		UnitTag:    "unit-mysql-0",
+	}, {
+		StorageTag: "storage-data-3",
+		UnitTag:    "unit-mysql-0",
+	}}
+	expect := []params.LifeResult{
+		{Life: params.Alive},
+		{Life: params.Dying},
+		{Life: params.Dead},
+		{Error: &params.Error{Message: "not found", Code: params.CodeNotFound}},
+	}
+	var calls int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "StorageAttachmentLife")
+		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{Ids: ids})
+		*(result.(*params.LifeResults)) = params.LifeResults{Results: expect}
+		calls++
+		return nil
+	})
+
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	results, err := st.StorageAttachmentLife(ids)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(calls, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, expect)
+	c.Assert(results[3].Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
 func (s *storageSuite) TestRemoveStorageAttachment(c *gc.C) {
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
@@ -0,0 +1,183 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+// StorageAttachmentsWatcher reports changes to a unit's storage
+// attachments, along with the details of the changed attachments.
+type StorageAttachmentsWatcher interface {
+	watcher.CoreWatcher
+
+	// Changes returns a channel on which the details of changed
+	// storage attachments are sent. The first change holds all of
+	// the unit's storage attachments. An attachment which has been
+	// removed is reported as Dead.
+	Changes() <-chan []params.StorageAttachment
+}
+
+// WatchUnitStorageAttachmentsDetailed starts a watcher for changes to
+// the storage attachments of the specified unit. Unlike
+// WatchUnitStorageAttachments, the watcher reports the details of the
+// changed attachments, which are fetched in a single call per change,
+// so there is no need to watch each attachment.
+//
+// Servers older than V2 cannot report storage attachment details, so
+// the caller must fall back to WatchUnitStorageAttachments.
+func (sa *StorageAccessor) WatchUnitStorageAttachmentsDetailed(unitTag names.UnitTag) (StorageAttachmentsWatcher, error) {
+	if sa.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotImplementedf("WatchUnitStorageAttachmentsDetailed() (need V2+)")
+	}
+	source, err := sa.WatchUnitStorageAttachments(unitTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &storageAttachmentsWatcher{
+		accessor: sa,
+		unitTag:  unitTag,
+		source:   source,
+		out:      make(chan []params.StorageAttachment),
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+		source.Kill()
+		w.tomb.Kill(source.Wait())
+	}()
+	go func() {
+		// The source watcher's channel isn't closed when it
+		// stops, so its death is reported separately.
+		w.tomb.Kill(source.Wait())
+	}()
+	return w, nil
+}
+
+// storageAttachmentsWatcher implements StorageAttachmentsWatcher by
+// fetching the details of the attachments reported by a watcher of a
+// unit's storage attachment IDs.
+type storageAttachmentsWatcher struct {
+	tomb     tomb.Tomb
+	accessor *StorageAccessor
+	unitTag  names.UnitTag
+	source   watcher.StringsWatcher
+	out      chan []params.StorageAttachment
+}
+
+// Changes is part of the StorageAttachmentsWatcher interface.
+func (w *storageAttachmentsWatcher) Changes() <-chan []params.StorageAttachment {
+	return w.out
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *storageAttachmentsWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *storageAttachmentsWatcher) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *storageAttachmentsWatcher) loop() error {
+	// pending holds the changed attachments which have yet to be
+	// sent, keyed by storage tag; an attachment that changes again
+	// before it has been sent is only sent once, with its latest
+	// details.
+	var pending map[string]params.StorageAttachment
+	var changes []params.StorageAttachment
+	var out chan<- []params.StorageAttachment
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case ids := <-w.source.Changes():
+			attachments, err := w.attachments(ids)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if pending == nil {
+				pending = make(map[string]params.StorageAttachment)
+			}
+			for _, attachment := range attachments {
+				pending[attachment.StorageTag] = attachment
+			}
+			changes = pendingAttachments(pending)
+			out = w.out
+		case out <- changes:
+			pending = nil
+			out = nil
+		}
+	}
+}
+
+// attachments returns the details of the unit's attachments of the
+// storage instances with the given IDs, in a single call.
+func (w *storageAttachmentsWatcher) attachments(storageIds []string) ([]params.StorageAttachment, error) {
+	if len(storageIds) == 0 {
+		return nil, nil
+	}
+	args := params.StorageAttachmentIds{
+		Ids: make([]params.StorageAttachmentId, len(storageIds)),
+	}
+	for i, storageId := range storageIds {
+		if !names.IsValidStorage(storageId) {
+			return nil, errors.NotValidf("storage ID %q", storageId)
+		}
+		args.Ids[i] = params.StorageAttachmentId{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			UnitTag:    w.unitTag.String(),
+		}
+	}
+	var results params.StorageAttachmentResults
+	err := w.accessor.facade.FacadeCall("StorageAttachments", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(args.Ids) {
+		return nil, errors.Errorf("expected %d results, got %d", len(args.Ids), len(results.Results))
+	}
+	attachments := make([]params.StorageAttachment, len(results.Results))
+	for i, result := range results.Results {
+		switch {
+		case result.Error == nil:
+			attachments[i] = result.Result
+		case params.IsCodeNotFound(result.Error):
+			// The attachment has been removed since the
+			// change was reported.
+			attachments[i] = params.StorageAttachment{
+				StorageTag: args.Ids[i].StorageTag,
+				UnitTag:    args.Ids[i].UnitTag,
+				Life:       params.Dead,
+			}
+		default:
+			return nil, errors.Annotatef(result.Error, "getting attachment of %s", args.Ids[i].StorageTag)
+		}
+	}
+	return attachments, nil
+}
+
+// pendingAttachments returns the attachments in pending, ordered by
+// storage tag. The result is empty rather than nil if there are none,
+// as for the first change of a unit with no storage.
+func pendingAttachments(pending map[string]params.StorageAttachment) []params.StorageAttachment {
+	storageTags := make([]string, 0, len(pending))
+	for storageTag := range pending {
+		storageTags = append(storageTags, storageTag)
+	}
+	sort.Strings(storageTags)
+	attachments := make([]params.StorageAttachment, len(storageTags))
+	for i, storageTag := range storageTags {
+		attachments[i] = pending[storageTag]
+	}
+	return attachments
+}
@@ -0,0 +1,215 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&storageWatcherSuite{})
+
+type storageWatcherSuite struct {
+	coretesting.BaseSuite
+
+	// next delivers the changes returned by the StringsWatcher's Next.
+	next chan []string
+	// fetched receives the storage tags of each StorageAttachments call.
+	fetched chan []string
+	// stopped is closed when the StringsWatcher is stopped.
+	stopped  chan struct{}
+	stopOnce sync.Once
+	// removed holds the storage tags of removed attachments.
+	removed map[string]bool
+	// fetchErr, if set, is returned by StorageAttachments.
+	fetchErr error
+}
+
+func (s *storageWatcherSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.next = make(chan []string)
+	s.fetched = make(chan []string, 10)
+	s.stopped = make(chan struct{})
+	s.stopOnce = sync.Once{}
+	s.removed = make(map[string]bool)
+	s.fetchErr = nil
+}
+
+func (s *storageWatcherSuite) apiCaller(c *gc.C) testing.APICallerFunc {
+	return testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		switch objType + "." + request {
+		case "Uniter.WatchUnitStorageAttachments":
+			c.Check(arg, gc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "unit-mysql-0"}},
+			})
+			*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+				Results: []params.StringsWatchResult{{
+					StringsWatcherId: "1",
+					Changes:          []string{"data/1", "data/0"},
+				}},
+			}
+		case "Uniter.StorageAttachments":
+			if s.fetchErr != nil {
+				return s.fetchErr
+			}
+			ids := arg.(params.StorageAttachmentIds).Ids
+			results := make([]params.StorageAttachmentResult, len(ids))
+			var storageTags []string
+			for i, id := range ids {
+				c.Check(id.UnitTag, gc.Equals, "unit-mysql-0")
+				storageTags = append(storageTags, id.StorageTag)
+				if s.removed[id.StorageTag] {
+					results[i].Error = &params.Error{Code: params.CodeNotFound, Message: "gone"}
+					continue
+				}
+				results[i].Result = params.StorageAttachment{
+					StorageTag: id.StorageTag,
+					UnitTag:    id.UnitTag,
+					Location:   "/srv/" + id.StorageTag,
+					Life:       params.Alive,
+				}
+			}
+			*(result.(*params.StorageAttachmentResults)) = params.StorageAttachmentResults{results}
+			s.fetched <- storageTags
+		case "StringsWatcher.Next":
+			c.Check(id, gc.Equals, "1")
+			select {
+			case changes := <-s.next:
+				(*result.(*interface{})).(*params.StringsWatchResult).Changes = changes
+				return nil
+			case <-s.stopped:
+				return &params.Error{Code: params.CodeStopped, Message: "watcher was stopped"}
+			}
+		case "StringsWatcher.Stop":
+			c.Check(id, gc.Equals, "1")
+			s.stopOnce.Do(func() { close(s.stopped) })
+		default:
+			c.Errorf("unexpected API call %s.%s", objType, request)
+		}
+		return nil
+	})
+}
+
+func (s *storageWatcherSuite) startWatcher(c *gc.C) uniter.StorageAttachmentsWatcher {
+	st := uniter.NewState(s.apiCaller(c), names.NewUnitTag("mysql/0"))
+	w, err := st.WatchUnitStorageAttachmentsDetailed(names.NewUnitTag("mysql/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *storageWatcherSuite) waitFetched(c *gc.C, expect ...string) {
+	select {
+	case storageTags := <-s.fetched:
+		c.Assert(storageTags, jc.SameContents, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for storage attachments to be fetched")
+	}
+}
+
+func (s *storageWatcherSuite) assertChange(c *gc.C, w uniter.StorageAttachmentsWatcher, expect []params.StorageAttachment) {
+	select {
+	case changes := <-w.Changes():
+		c.Assert(changes, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for change")
+	}
+}
+
+func (s *storageWatcherSuite) assertSourceStopped(c *gc.C) {
+	select {
+	case <-s.stopped:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for source watcher to be stopped")
+	}
+}
+
+func attachment(storageTag string, life params.Life) params.StorageAttachment {
+	a := params.StorageAttachment{
+		StorageTag: storageTag,
+		UnitTag:    "unit-mysql-0",
+		Life:       life,
+	}
+	if life != params.Dead {
+		a.Location = "/srv/" + storageTag
+	}
+	return a
+}
+
+func (s *storageWatcherSuite) TestInitialChange(c *gc.C) {
+	w := s.startWatcher(c)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	s.waitFetched(c, "storage-data-0", "storage-data-1")
+	s.assertChange(c, w, []params.StorageAttachment{
+		attachment("storage-data-0", params.Alive),
+		attachment("storage-data-1", params.Alive),
+	})
+}
+
+func (s *storageWatcherSuite) TestBatchedChanges(c *gc.C) {
+	w := s.startWatcher(c)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+	s.waitFetched(c, "storage-data-0", "storage-data-1")
+	s.assertChange(c, w, []params.StorageAttachment{
+		attachment("storage-data-0", params.Alive),
+		attachment("storage-data-1", params.Alive),
+	})
+
+	// Changes that arrive before the previous change has been
+	// received are delivered together, with the latest details.
+	s.next <- []string{"data/2"}
+	s.waitFetched(c, "storage-data-2")
+	s.removed["storage-data-1"] = true
+	s.next <- []string{"data/1", "data/2"}
+	s.waitFetched(c, "storage-data-1", "storage-data-2")
+	s.assertChange(c, w, []params.StorageAttachment{
+		attachment("storage-data-1", params.Dead),
+		attachment("storage-data-2", params.Alive),
+	})
+}
+
+func (s *storageWatcherSuite) TestStopStopsSourceWatcher(c *gc.C) {
+	w := s.startWatcher(c)
+	s.waitFetched(c, "storage-data-0", "storage-data-1")
+
+	w.Kill()
+	c.Assert(w.Wait(), jc.ErrorIsNil)
+	s.assertSourceStopped(c)
+}
+
+func (s *storageWatcherSuite) TestFetchError(c *gc.C) {
+	s.fetchErr = errors.New("splat")
+	w := s.startWatcher(c)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "splat")
+	s.assertSourceStopped(c)
+}
+
+func (s *storageWatcherSuite) TestNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+	facade := base.NewFacadeCallerForVersion(apiCaller, "Uniter", 1)
+	sa := uniter.NewStorageAccessor(facade)
+	_, err := sa.WatchUnitStorageAttachmentsDetailed(names.NewUnitTag("mysql/0"))
+	c.Check(err, jc.Satisfies, errors.IsNotImplemented)
+}
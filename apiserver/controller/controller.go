@@ -336,6 +336,70 @@ func (c *ControllerAPI) initiateOneModelMigration(spec params.ModelMigrationSpec
 	return mig.Id(), nil
 }
 
+// ApproveMigration approves a model migration which is being held
+// awaiting approval, recording the given change ticket reference
+// against the migration.
+func (c *ControllerAPI) ApproveMigration(args params.ApproveMigrationArgs) error {
+	modelTag, err := names.ParseModelTag(args.ModelTag)
+	if err != nil {
+		return errors.Annotate(err, "model tag")
+	}
+	hostedState, err := c.state.ForModel(modelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer hostedState.Close()
+
+	mig, err := hostedState.LatestModelMigration()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(mig.Approve(args.TicketRef))
+}
+
+// AbortMigration requests that the active migration of a model be
+// aborted. The model's migrationmaster aborts the migration at the
+// next safe point, unless it has already reached SUCCESS.
+func (c *ControllerAPI) AbortMigration(args params.ModelArgs) error {
+	modelTag, err := names.ParseModelTag(args.ModelTag)
+	if err != nil {
+		return errors.Annotate(err, "model tag")
+	}
+	hostedState, err := c.state.ForModel(modelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer hostedState.Close()
+
+	mig, err := hostedState.LatestModelMigration()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(mig.RequestAbort())
+}
+
+// RetryReap requests that the removal of a migrated model from this
+// controller be attempted again after it previously failed. The
+// model's migrationmaster picks up the request and returns the
+// migration to the REAP phase.
+func (c *ControllerAPI) RetryReap(args params.ModelArgs) error {
+	modelTag, err := names.ParseModelTag(args.ModelTag)
+	if err != nil {
+		return errors.Annotate(err, "model tag")
+	}
+	hostedState, err := c.state.ForModel(modelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer hostedState.Close()
+
+	mig, err := hostedState.LatestModelMigration()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(mig.RequestReapRetry())
+}
+
 func (c *ControllerAPI) environStatus(tag string) (params.ModelStatus, error) {
 	var status params.ModelStatus
 	modelTag, err := names.ParseModelTag(tag)
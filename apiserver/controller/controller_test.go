@@ -18,9 +18,11 @@ import (
 	"github.com/juju/juju/apiserver/facade/facadetest"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/migration"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
@@ -415,6 +417,147 @@ func (s *controllerSuite) TestInitiateModelMigrationPartialFailure(c *gc.C) {
 	c.Check(out.Results[1].Error, gc.ErrorMatches, "unable to read model: .+")
 }
 
+func (s *controllerSuite) TestApproveMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	mig := s.makeHeldMigration(c, st)
+
+	err := s.controller.ApproveMigration(params.ApproveMigrationArgs{
+		ModelTag:  st.ModelTag().String(),
+		TicketRef: "CHG-1234",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(mig.Refresh(), jc.ErrorIsNil)
+	c.Check(mig.ApprovalRef(), gc.Equals, "CHG-1234")
+}
+
+func (s *controllerSuite) TestApproveMigrationNotHeld(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	_, err := st.CreateModelMigration(state.ModelMigrationSpec{
+		InitiatedBy: names.NewUserTag("admin"),
+		TargetInfo:  heldMigrationTargetInfo,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.controller.ApproveMigration(params.ApproveMigrationArgs{
+		ModelTag:  st.ModelTag().String(),
+		TicketRef: "CHG-1234",
+	})
+	c.Assert(err, gc.ErrorMatches, "migration is not being held \\(phase is QUIESCE\\)")
+}
+
+func (s *controllerSuite) TestApproveMigrationBadModelTag(c *gc.C) {
+	err := s.controller.ApproveMigration(params.ApproveMigrationArgs{
+		ModelTag:  "bad",
+		TicketRef: "CHG-1234",
+	})
+	c.Assert(err, gc.ErrorMatches, "model tag: .+")
+}
+
+func (s *controllerSuite) TestAbortMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	mig, err := st.CreateModelMigration(state.ModelMigrationSpec{
+		InitiatedBy: names.NewUserTag("admin"),
+		TargetInfo:  heldMigrationTargetInfo,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	w, err := mig.WatchForAbort()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, st, w)
+	wc.AssertOneChange() // initial event
+
+	err = s.controller.AbortMigration(params.ModelArgs{
+		ModelTag: st.ModelTag().String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *controllerSuite) TestAbortMigrationNoMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	err := s.controller.AbortMigration(params.ModelArgs{
+		ModelTag: st.ModelTag().String(),
+	})
+	c.Assert(err, gc.ErrorMatches, "migration not found")
+}
+
+func (s *controllerSuite) TestAbortMigrationBadModelTag(c *gc.C) {
+	err := s.controller.AbortMigration(params.ModelArgs{ModelTag: "bad"})
+	c.Assert(err, gc.ErrorMatches, "model tag: .+")
+}
+
+func (s *controllerSuite) TestRetryReap(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	mig := s.makeHeldMigration(c, st)
+	for _, phase := range []migration.Phase{
+		migration.SUCCESS,
+		migration.LOGTRANSFER,
+		migration.REAP,
+		migration.REAPFAILED,
+	} {
+		c.Assert(mig.SetPhase(phase), jc.ErrorIsNil)
+	}
+
+	err := s.controller.RetryReap(params.ModelArgs{
+		ModelTag: st.ModelTag().String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(mig.Refresh(), jc.ErrorIsNil)
+	c.Check(mig.ReapRetryRequested(), jc.IsTrue)
+}
+
+func (s *controllerSuite) TestRetryReapNotFailed(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	s.makeHeldMigration(c, st)
+
+	err := s.controller.RetryReap(params.ModelArgs{
+		ModelTag: st.ModelTag().String(),
+	})
+	c.Assert(err, gc.ErrorMatches, "migration has not failed to remove the model \\(phase is HOLD\\)")
+}
+
+func (s *controllerSuite) TestRetryReapBadModelTag(c *gc.C) {
+	err := s.controller.RetryReap(params.ModelArgs{ModelTag: "bad"})
+	c.Assert(err, gc.ErrorMatches, "model tag: .+")
+}
+
+var heldMigrationTargetInfo = migration.TargetInfo{
+	ControllerTag: names.NewModelTag(utils.MustNewUUID().String()),
+	Addrs:         []string{"1.1.1.1:1111"},
+	CACert:        "cert",
+	AuthTag:       names.NewUserTag("admin"),
+	Password:      "secret",
+}
+
+// makeHeldMigration creates a migration for the given model and
+// moves it through to the HOLD phase.
+func (s *controllerSuite) makeHeldMigration(c *gc.C, st *state.State) state.ModelMigration {
+	mig, err := st.CreateModelMigration(state.ModelMigrationSpec{
+		InitiatedBy: names.NewUserTag("admin"),
+		TargetInfo:  heldMigrationTargetInfo,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	for _, phase := range []migration.Phase{
+		migration.READONLY,
+		migration.PRECHECK,
+		migration.IMPORT,
+		migration.VALIDATION,
+		migration.HOLD,
+	} {
+		c.Assert(mig.SetPhase(phase), jc.ErrorIsNil)
+	}
+	return mig
+}
+
 func randomModelTag() string {
 	uuid := utils.MustNewUUID().String()
 	return names.NewModelTag(uuid).String()
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -67,6 +68,10 @@ func newDebugLogHandler(
 //   replay -> string - one of [true, false], if true, start the file from the start
 //   noTail -> string - one of [true, false], if true, existing logs are sent back,
 //      - but the command does not wait for new ones.
+//   startTime -> string - an RFC3339 timestamp; only lines logged at or
+//      - after this time are sent
+//   format -> string one of [text, json], if json, each log line is sent as
+//      - a JSON-encoded params.LogRecord rather than as formatted text
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(conn *websocket.Conn) {
@@ -144,6 +149,8 @@ type debugLogParams struct {
 	maxLines      uint
 	fromTheStart  bool
 	noTail        bool
+	startTime     time.Time
+	jsonFormat    bool
 	backlog       uint
 	filterLevel   loggo.Level
 	includeEntity []string
@@ -179,6 +186,22 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 		params.noTail = noTail
 	}
 
+	if value := queryMap.Get("startTime"); value != "" {
+		startTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Errorf("startTime value %q is not a valid time", value)
+		}
+		params.startTime = startTime
+	}
+
+	switch value := queryMap.Get("format"); value {
+	case "", "text":
+	case "json":
+		params.jsonFormat = true
+	default:
+		return nil, errors.Errorf("format value %q is not one of %q, %q", value, "text", "json")
+	}
+
 	if value := queryMap.Get("backlog"); value != "" {
 		num, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
@@ -4,12 +4,14 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
 
@@ -43,8 +45,16 @@ func handleDebugLogDBRequest(
 				return errors.Annotate(tailer.Err(), "tailer stopped")
 			}
 
-			line := formatLogRecord(rec)
-			_, err := socket.Write([]byte(line))
+			var line []byte
+			if reqParams.jsonFormat {
+				line, err = formatLogRecordJSON(rec)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			} else {
+				line = []byte(formatLogRecord(rec))
+			}
+			_, err = socket.Write(line)
 			if err != nil {
 				return errors.Annotate(err, "sending failed")
 			}
@@ -61,6 +71,7 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 	params := &state.LogTailerParams{
 		MinLevel:      reqParams.filterLevel,
 		NoTail:        reqParams.noTail,
+		StartTime:     reqParams.startTime,
 		InitialLines:  int(reqParams.backlog),
 		IncludeEntity: reqParams.includeEntity,
 		ExcludeEntity: reqParams.excludeEntity,
@@ -84,6 +95,24 @@ func formatLogRecord(r *state.LogRecord) string {
 	)
 }
 
+// formatLogRecordJSON returns the given log record as a JSON-encoded
+// params.LogRecord, for clients which process the log records rather
+// than display them.
+func formatLogRecordJSON(r *state.LogRecord) ([]byte, error) {
+	line, err := json.Marshal(params.LogRecord{
+		Time:     r.Time,
+		Module:   r.Module,
+		Location: r.Location,
+		Level:    r.Level.String(),
+		Message:  r.Message,
+		Entity:   r.Entity.String(),
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "encoding log record")
+	}
+	return append(line, '\n'), nil
+}
+
 func formatTime(t time.Time) string {
 	return t.In(time.UTC).Format("2006-01-02 15:04:05")
 }
@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/juju/loggo"
@@ -29,9 +30,11 @@ func (s *debugLogDBIntSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
+	startTime := time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC)
 	reqParams := &debugLogParams{
 		fromTheStart:  false,
 		noTail:        true,
+		startTime:     startTime,
 		backlog:       11,
 		filterLevel:   loggo.INFO,
 		includeEntity: []string{"foo"},
@@ -44,9 +47,7 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params *state.LogTailerParams) (state.LogTailer, error) {
 		called = true
 
-		// Start time will be used once the client is extended to send
-		// time range arguments.
-		c.Assert(params.StartTime.IsZero(), jc.IsTrue)
+		c.Assert(params.StartTime, gc.Equals, startTime)
 		c.Assert(params.NoTail, jc.IsTrue)
 		c.Assert(params.MinLevel, gc.Equals, loggo.INFO)
 		c.Assert(params.InitialLines, gc.Equals, 11)
@@ -125,6 +126,53 @@ func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestJSONFormat(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:   names.NewMachineTag("99"),
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff happened",
+	}
+	close(tailer.logsCh)
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params *state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+
+	done := s.runRequest(&debugLogParams{jsonFormat: true}, nil)
+
+	s.assertOutput(c, []string{
+		"ok",
+		`{"t":"2015-06-19T15:34:37Z","m":"some.where","l":"code.go:42","v":"INFO","x":"stuff happened","e":"machine-99"}` + "\n",
+	})
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestReadParams(c *gc.C) {
+	params, err := readDebugLogParams(url.Values{
+		"startTime": {"2015-06-19T15:34:37.5Z"},
+		"format":    {"json"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(params.startTime, gc.Equals, time.Date(2015, 6, 19, 15, 34, 37, 500000000, time.UTC))
+	c.Check(params.jsonFormat, jc.IsTrue)
+
+	params, err = readDebugLogParams(url.Values{"format": {"text"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(params.jsonFormat, jc.IsFalse)
+	c.Check(params.startTime.IsZero(), jc.IsTrue)
+}
+
+func (s *debugLogDBIntSuite) TestReadParamsInvalid(c *gc.C) {
+	_, err := readDebugLogParams(url.Values{"startTime": {"yesterday"}})
+	c.Check(err, gc.ErrorMatches, `startTime value "yesterday" is not a valid time`)
+
+	_, err = readDebugLogParams(url.Values{"format": {"xml"}})
+	c.Check(err, gc.ErrorMatches, `format value "xml" is not one of "text", "json"`)
+}
+
 func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 	tailer := newFakeLogTailer()
 	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params *state.LogTailerParams) (state.LogTailer, error) {
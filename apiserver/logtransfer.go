@@ -0,0 +1,147 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"golang.org/x/net/websocket"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// logTransferHandler receives the log records of a model being
+// migrated to this controller, as sent by the source controller. The
+// records are sent as JSON arrays of params.LogRecord.
+type logTransferHandler struct {
+	ctxt httpContext
+}
+
+func newLogTransferHandler(ctxt httpContext) http.Handler {
+	return &logTransferHandler{ctxt: ctxt}
+}
+
+// ServeHTTP implements the http.Handler interface. The model being
+// imported is identified by the request's "model-uuid" parameter.
+func (h *logTransferHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	server := websocket.Server{
+		Handler: func(socket *websocket.Conn) {
+			defer socket.Close()
+
+			st, err := h.modelState(req)
+			if err != nil {
+				h.sendError(socket, req, err)
+				return
+			}
+			cfg, err := st.ModelConfig()
+			if err != nil {
+				h.sendError(socket, req, err)
+				return
+			}
+			ver, _ := cfg.AgentVersion()
+			dbLogger := state.NewDbLogger(st, st.ModelTag(), ver)
+			defer dbLogger.Close()
+
+			// If we get to here, no more errors to report, so we
+			// report a nil error.
+			h.sendError(socket, req, nil)
+
+			batches := h.receiveBatches(socket)
+			for {
+				select {
+				case <-h.ctxt.stop():
+					return
+				case batch, ok := <-batches:
+					if !ok {
+						return
+					}
+					for _, m := range batch {
+						level, _ := loggo.ParseLevel(m.Level)
+						err := dbLogger.LogFor(m.Entity, m.Time, m.Module, m.Location, level, m.Message)
+						if err != nil {
+							logger.Errorf("storing transferred log record failed: %v", err)
+							return
+						}
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(w, req)
+}
+
+// modelState authenticates the request as coming from a controller
+// administrator and returns the state for the model being imported.
+func (h *logTransferHandler) modelState(req *http.Request) (*state.State, error) {
+	st, entity, err := h.ctxt.stateForRequestAuthenticatedUser(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	userTag := entity.Tag().(names.UserTag)
+	if isAdmin, err := st.IsControllerAdministrator(userTag); err != nil {
+		return nil, errors.Trace(err)
+	} else if !isAdmin {
+		return nil, common.ErrPerm
+	}
+
+	modelUUID := req.URL.Query().Get("model-uuid")
+	if !names.IsValidModel(modelUUID) {
+		return nil, errors.NotValidf("model UUID %q", modelUUID)
+	}
+	model, err := st.GetModel(names.NewModelTag(modelUUID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if model.MigrationMode() != state.MigrationModeImporting {
+		return nil, errors.New("migration mode for the model is not importing")
+	}
+	return h.ctxt.srv.statePool.Get(modelUUID)
+}
+
+// receiveBatches returns a channel which yields the batches of log
+// records read from the socket. The channel is closed when the socket
+// is closed or can't be read.
+func (h *logTransferHandler) receiveBatches(socket *websocket.Conn) <-chan []params.LogRecord {
+	batches := make(chan []params.LogRecord)
+
+	go func() {
+		defer close(batches)
+		for {
+			// Receive() blocks until data arrives but will also be
+			// unblocked when the API handler calls socket.Close as it
+			// finishes.
+			var batch []params.LogRecord
+			if err := websocket.JSON.Receive(socket, &batch); err != nil {
+				if err != io.EOF {
+					logger.Debugf("log transfer receive error: %v", err)
+				}
+				return
+			}
+
+			select {
+			case <-h.ctxt.stop():
+				return
+			case batches <- batch:
+			}
+		}
+	}()
+
+	return batches
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *logTransferHandler) sendError(w io.Writer, req *http.Request, err error) {
+	if err != nil {
+		logger.Errorf("returning error from %s %s: %s", req.Method, req.URL.Path, errors.Details(err))
+	}
+	sendJSON(w, &params.ErrorResult{
+		Error: common.ServerError(err),
+	})
+}
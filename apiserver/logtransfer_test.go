@@ -0,0 +1,136 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	"golang.org/x/net/websocket"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type logTransferSuite struct {
+	authHttpSuite
+	importing *state.State
+}
+
+var _ = gc.Suite(&logTransferSuite{})
+
+func (s *logTransferSuite) SetUpTest(c *gc.C) {
+	s.authHttpSuite.SetUpTest(c)
+	s.importing = s.Factory.MakeModel(c, nil)
+	s.AddCleanup(func(*gc.C) { s.importing.Close() })
+	model, err := s.importing.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.SetMigrationMode(state.MigrationModeImporting)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *logTransferSuite) logTransferURL(c *gc.C, modelUUID string) string {
+	return s.makeURL(c, "wss", "/migrate/logtransfer", url.Values{
+		"model-uuid": {modelUUID},
+	}).String()
+}
+
+func (s *logTransferSuite) adminHeader(c *gc.C) http.Header {
+	return utils.BasicAuthHeader(s.AdminUserTag(c).String(), "dummy-secret")
+}
+
+func (s *logTransferSuite) TestTransfer(c *gc.C) {
+	conn := s.dialWebsocketFromURL(c, s.logTransferURL(c, s.importing.ModelUUID()), s.adminHeader(c))
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	// Read back the nil error, indicating that all is well.
+	errResult := readJSONErrorLine(c, reader)
+	c.Assert(errResult.Error, gc.IsNil)
+
+	t0 := time.Date(2015, time.June, 1, 23, 2, 1, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	err := websocket.JSON.Send(conn, []params.LogRecord{{
+		Time:     t0,
+		Module:   "some.where",
+		Location: "foo.go:42",
+		Level:    loggo.INFO.String(),
+		Message:  "all is well",
+		Entity:   "machine-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = websocket.JSON.Send(conn, []params.LogRecord{{
+		Time:     t1,
+		Module:   "else.where",
+		Location: "bar.go:99",
+		Level:    loggo.ERROR.String(),
+		Message:  "oh noes",
+		Entity:   "unit-mysql-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Wait for the log documents to be written to the DB.
+	logsColl := s.State.MongoSession().DB("logs").C("logs")
+	var docs []bson.M
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := logsColl.Find(bson.M{"e": s.importing.ModelUUID()}).Sort("t").All(&docs)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(docs) >= 2 {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for log writes")
+		}
+	}
+	c.Assert(docs, gc.HasLen, 2)
+
+	c.Check(docs[0]["t"], gc.Equals, t0.UnixNano())
+	c.Check(docs[0]["n"], gc.Equals, "machine-0")
+	c.Check(docs[0]["m"], gc.Equals, "some.where")
+	c.Check(docs[0]["v"], gc.Equals, int(loggo.INFO))
+	c.Check(docs[0]["x"], gc.Equals, "all is well")
+
+	c.Check(docs[1]["t"], gc.Equals, t1.UnixNano())
+	c.Check(docs[1]["n"], gc.Equals, "unit-mysql-0")
+	c.Check(docs[1]["m"], gc.Equals, "else.where")
+	c.Check(docs[1]["v"], gc.Equals, int(loggo.ERROR))
+	c.Check(docs[1]["x"], gc.Equals, "oh noes")
+
+	latest, err := state.LatestLogTime(s.importing)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(latest, gc.Equals, t1)
+}
+
+func (s *logTransferSuite) TestRequiresControllerAdmin(c *gc.C) {
+	header := utils.BasicAuthHeader(s.userTag.String(), s.password)
+	conn := s.dialWebsocketFromURL(c, s.logTransferURL(c, s.importing.ModelUUID()), header)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	assertJSONError(c, reader, "permission denied")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *logTransferSuite) TestRejectsModelNotImporting(c *gc.C) {
+	conn := s.dialWebsocketFromURL(c, s.logTransferURL(c, s.State.ModelUUID()), s.adminHeader(c))
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	assertJSONError(c, reader, "migration mode for the model is not importing")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *logTransferSuite) TestRejectsBadModelUUID(c *gc.C) {
+	conn := s.dialWebsocketFromURL(c, s.logTransferURL(c, "bad"), s.adminHeader(c))
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	assertJSONError(c, reader, `model UUID "bad" not valid`)
+	s.assertWebsocketClosed(c, reader)
+}
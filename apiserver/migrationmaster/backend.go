@@ -4,6 +4,11 @@
 package migrationmaster
 
 import (
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/state"
 )
@@ -16,4 +21,33 @@ type Backend interface {
 	WatchForModelMigration() state.NotifyWatcher
 	LatestModelMigration() (state.ModelMigration, error)
 	RemoveExportingModelDocs() error
+
+	// ModelUUID returns the UUID of the model being migrated.
+	ModelUUID() string
+
+	// ModelName returns the name of the model being migrated.
+	ModelName() (string, error)
+
+	// ModelOwner returns the owner of the model being migrated.
+	ModelOwner() (names.UserTag, error)
+
+	// AgentVersion returns the agent version of the model being
+	// migrated.
+	AgentVersion() (version.Number, error)
+
+	// StoragePools returns the storage pools used by the model's
+	// volumes and filesystems.
+	StoragePools() ([]coremigration.StoragePool, error)
+
+	// ModelCredential returns the cloud credential used by the model
+	// being migrated, or a zero CloudCredential if it doesn't use one.
+	ModelCredential() (coremigration.CloudCredential, error)
+
+	// ModelRemoved returns whether the documents of the model being
+	// migrated have been removed.
+	ModelRemoved() (bool, error)
+
+	// CharmSHA256 returns the SHA256 sum of the archive stored for
+	// the charm with the given URL.
+	CharmSHA256(*charm.URL) (string, error)
 }
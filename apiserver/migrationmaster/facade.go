@@ -8,6 +8,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
 	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -15,6 +16,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/description"
 	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
 )
 
@@ -87,6 +90,11 @@ func (api *API) GetMigrationStatus() (params.FullMigrationStatus, error) {
 		return empty, errors.Annotate(err, "retrieving phase")
 	}
 
+	modelName, err := api.backend.ModelName()
+	if err != nil {
+		return empty, errors.Annotate(err, "retrieving model name")
+	}
+
 	return params.FullMigrationStatus{
 		Spec: params.ModelMigrationSpec{
 			ModelTag: names.NewModelTag(mig.ModelUUID()).String(),
@@ -98,9 +106,13 @@ func (api *API) GetMigrationStatus() (params.FullMigrationStatus, error) {
 				Password:      target.Password,
 			},
 		},
-		Attempt:          attempt,
-		Phase:            phase.String(),
-		PhaseChangedTime: mig.PhaseChangedTime(),
+		MigrationId:        mig.Id(),
+		ModelName:          modelName,
+		Attempt:            attempt,
+		Phase:              phase.String(),
+		PhaseChangedTime:   mig.PhaseChangedTime(),
+		ApprovalRef:        mig.ApprovalRef(),
+		ReapRetryRequested: mig.ReapRetryRequested(),
 	}, nil
 }
 
@@ -122,6 +134,66 @@ func (api *API) SetPhase(args params.SetMigrationPhaseArgs) error {
 	return errors.Annotate(err, "failed to set phase")
 }
 
+// SetStatusMessage sets a human readable status message for the
+// active model migration.
+func (api *API) SetStatusMessage(args params.SetMigrationStatusMessageArgs) error {
+	mig, err := api.backend.LatestModelMigration()
+	if err != nil {
+		return errors.Annotate(err, "could not get migration")
+	}
+	err = mig.SetStatusMessage(args.Message)
+	return errors.Annotate(err, "failed to set status message")
+}
+
+// SetMinionWaitProgress records the counts of migration minions which
+// have reported so far for the current migration phase, so that
+// clients can show how many agents have reported.
+func (api *API) SetMinionWaitProgress(args params.MinionReports) error {
+	phase, ok := coremigration.ParsePhase(args.Phase)
+	if !ok {
+		return errors.Errorf("invalid phase: %q", args.Phase)
+	}
+	mig, err := api.backend.LatestModelMigration()
+	if err != nil {
+		return errors.Annotate(err, "could not get migration")
+	}
+	if args.MigrationId != mig.Id() {
+		return errors.Errorf("minion reports are for migration %q, not %q", args.MigrationId, mig.Id())
+	}
+	err = mig.SetMinionWaitProgress(state.MinionWaitProgress{
+		Phase:        phase,
+		SuccessCount: args.SuccessCount,
+		FailedCount:  len(args.Failed),
+		UnknownCount: args.UnknownCount,
+	})
+	return errors.Trace(err)
+}
+
+// ModelInfo returns essential information about the model to be
+// migrated.
+func (api *API) ModelInfo() (params.MigrationModelInfo, error) {
+	empty := params.MigrationModelInfo{}
+
+	name, err := api.backend.ModelName()
+	if err != nil {
+		return empty, errors.Annotate(err, "retrieving model name")
+	}
+	owner, err := api.backend.ModelOwner()
+	if err != nil {
+		return empty, errors.Annotate(err, "retrieving model owner")
+	}
+	vers, err := api.backend.AgentVersion()
+	if err != nil {
+		return empty, errors.Annotate(err, "retrieving agent version")
+	}
+	return params.MigrationModelInfo{
+		UUID:         api.backend.ModelUUID(),
+		Name:         name,
+		OwnerTag:     owner.String(),
+		AgentVersion: vers,
+	}, nil
+}
+
 // Export serializes the model associated with the API connection.
 func (api *API) Export() (params.SerializedModel, error) {
 	var serialized params.SerializedModel
@@ -137,16 +209,72 @@ func (api *API) Export() (params.SerializedModel, error) {
 	}
 	serialized.Bytes = bytes
 	serialized.Charms = getUsedCharms(model)
+	serialized.CharmOrigins = getCharmOrigins(serialized.Charms)
+	serialized.CharmSHA256s, err = api.getCharmSHA256s(serialized.Charms)
+	if err != nil {
+		return serialized, errors.Trace(err)
+	}
 	serialized.Tools = getUsedTools(model)
+	serialized.FirewallRules = getFirewallRules(model)
 	return serialized, nil
 }
 
+// StoragePools returns the storage pools used by the model
+// associated with the API connection, along with their provider
+// types.
+func (api *API) StoragePools() params.StoragePoolsResult {
+	pools, err := api.backend.StoragePools()
+	if err != nil {
+		return params.StoragePoolsResult{Error: common.ServerError(err)}
+	}
+	result := params.StoragePoolsResult{
+		Result: make([]params.StoragePool, len(pools)),
+	}
+	for i, pool := range pools {
+		result.Result[i] = params.StoragePool{
+			Name:     pool.Name,
+			Provider: pool.Provider,
+		}
+	}
+	return result
+}
+
+// ModelCredential returns the cloud credential used by the model
+// being migrated. An empty Name is returned if the model doesn't use
+// a credential.
+func (api *API) ModelCredential() (params.MigrationCredential, error) {
+	cred, err := api.backend.ModelCredential()
+	if err != nil {
+		return params.MigrationCredential{}, errors.Annotate(err, "retrieving model credential")
+	}
+	if cred.IsZero() {
+		return params.MigrationCredential{}, nil
+	}
+	return params.MigrationCredential{
+		OwnerTag:   cred.Owner.String(),
+		Cloud:      cred.Cloud,
+		Name:       cred.Name,
+		AuthType:   cred.AuthType,
+		Attributes: cred.Attributes,
+	}, nil
+}
+
 // Reap removes all documents for the model associated with the API
 // connection.
 func (api *API) Reap() error {
 	return api.backend.RemoveExportingModelDocs()
 }
 
+// ReapComplete returns whether all the documents for the model
+// associated with the API connection have been removed.
+func (api *API) ReapComplete() params.BoolResult {
+	removed, err := api.backend.ModelRemoved()
+	if err != nil {
+		return params.BoolResult{Error: common.ServerError(err)}
+	}
+	return params.BoolResult{Result: removed}
+}
+
 // WatchMinionReports sets up a watcher which reports when a report
 // for a migration minion has arrived.
 func (api *API) WatchMinionReports() params.NotifyWatchResult {
@@ -170,6 +298,29 @@ func (api *API) WatchMinionReports() params.NotifyWatchResult {
 	}
 }
 
+// WatchForAbort sets up a watcher which reports when an operator
+// requests that the active migration be aborted.
+func (api *API) WatchForAbort() params.NotifyWatchResult {
+	mig, err := api.backend.LatestModelMigration()
+	if err != nil {
+		return params.NotifyWatchResult{Error: common.ServerError(err)}
+	}
+
+	watch, err := mig.WatchForAbort()
+	if err != nil {
+		return params.NotifyWatchResult{Error: common.ServerError(err)}
+	}
+
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}
+	}
+	return params.NotifyWatchResult{
+		Error: common.ServerError(watcher.EnsureErr(watch)),
+	}
+}
+
 // GetMinionReports returns details of the reports made by migration
 // minions to the controller for the current migration phase.
 func (api *API) GetMinionReports() (params.MinionReports, error) {
@@ -215,6 +366,40 @@ func (api *API) GetMinionReports() (params.MinionReports, error) {
 	}
 	out.UnknownSample = unknown[:numSamples]
 
+	if len(reports.FailureDetails) > 0 {
+		out.FailureDetails = make(map[string]string)
+		for tag, message := range reports.FailureDetails {
+			out.FailureDetails[tag.String()] = message
+		}
+	}
+
+	return out, nil
+}
+
+// GetMinionFailureDetails returns the failure descriptions reported
+// by the migration minions which failed the given phase of the
+// current migration.
+func (api *API) GetMinionFailureDetails(args params.MinionFailureDetailsArgs) (params.MinionFailureDetails, error) {
+	var out params.MinionFailureDetails
+
+	phase, ok := coremigration.ParsePhase(args.Phase)
+	if !ok {
+		return out, errors.Errorf("invalid phase: %q", args.Phase)
+	}
+
+	mig, err := api.backend.LatestModelMigration()
+	if err != nil {
+		return out, errors.Trace(err)
+	}
+
+	details, err := mig.MinionFailureDetails(phase)
+	if err != nil {
+		return out, errors.Trace(err)
+	}
+	out.Details = make(map[string]string)
+	for tag, message := range details {
+		out.Details[tag.String()] = message
+	}
 	return out, nil
 }
 
@@ -226,9 +411,42 @@ func getUsedCharms(model description.Model) []string {
 	return result.Values()
 }
 
+// getCharmOrigins returns the origins of the given charms, keyed by
+// charm URL. Charms whose origin isn't known are left out, so that
+// the migration master refuses to migrate them.
+func getCharmOrigins(charms []string) map[string]string {
+	origins := make(map[string]string)
+	for _, curl := range charms {
+		if origin := coremigration.CharmOriginForURL(curl); origin != "" {
+			origins[curl] = string(origin)
+		}
+	}
+	return origins
+}
+
+// getCharmSHA256s returns the SHA256 sums of the stored archives of
+// the given charms, keyed by charm URL, so that the migration master
+// can check they reach the target intact.
+func (api *API) getCharmSHA256s(charms []string) (map[string]string, error) {
+	sums := make(map[string]string)
+	for _, curlStr := range charms {
+		curl, err := charm.ParseURL(curlStr)
+		if err != nil {
+			return nil, errors.Annotate(err, "bad charm URL")
+		}
+		sum, err := api.backend.CharmSHA256(curl)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get SHA256 of charm %q", curlStr)
+		}
+		sums[curlStr] = sum
+	}
+	return sums, nil
+}
+
 func getUsedTools(model description.Model) []params.SerializedModelTools {
 	// Iterate through the model for all tools, and make a map of them.
-	usedVersions := make(map[version.Binary]bool)
+	// The SHA256 sum of each version's tarball is recorded too.
+	usedVersions := make(map[version.Binary]string)
 	// It is most likely that the preconditions will limit the number of
 	// tools versions in use, but that is not relied on here.
 	for _, machine := range model.Machines() {
@@ -238,23 +456,63 @@ func getUsedTools(model description.Model) []params.SerializedModelTools {
 	for _, application := range model.Applications() {
 		for _, unit := range application.Units() {
 			tools := unit.Tools()
-			usedVersions[tools.Version()] = true
+			usedVersions[tools.Version()] = tools.SHA256()
 		}
 	}
 
 	out := make([]params.SerializedModelTools, 0, len(usedVersions))
-	for v := range usedVersions {
+	for v, sha256 := range usedVersions {
 		out = append(out, params.SerializedModelTools{
 			Version: v.String(),
 			URI:     common.ToolsURL("", v),
+			SHA256:  sha256,
 		})
 	}
 	return out
 }
 
-func addToolsVersionForMachine(machine description.Machine, usedVersions map[version.Binary]bool) {
+// getFirewallRules returns the distinct set of port ranges opened
+// across the model's machines, as firewall rules which the target
+// controller will need to recreate.
+func getFirewallRules(model description.Model) []params.FirewallRule {
+	seen := make(map[network.PortRange]bool)
+	var ranges []network.PortRange
+	for _, machine := range model.Machines() {
+		addPortRangesForMachine(machine, seen, &ranges)
+	}
+	network.SortPortRanges(ranges)
+
+	var out []params.FirewallRule
+	for _, portRange := range ranges {
+		out = append(out, params.FirewallRule{
+			Ports: params.FromNetworkPortRange(portRange),
+		})
+	}
+	return out
+}
+
+func addPortRangesForMachine(machine description.Machine, seen map[network.PortRange]bool, ranges *[]network.PortRange) {
+	for _, opened := range machine.OpenedPorts() {
+		for _, p := range opened.OpenPorts() {
+			portRange := network.PortRange{
+				FromPort: p.FromPort(),
+				ToPort:   p.ToPort(),
+				Protocol: p.Protocol(),
+			}
+			if !seen[portRange] {
+				seen[portRange] = true
+				*ranges = append(*ranges, portRange)
+			}
+		}
+	}
+	for _, container := range machine.Containers() {
+		addPortRangesForMachine(container, seen, ranges)
+	}
+}
+
+func addToolsVersionForMachine(machine description.Machine, usedVersions map[version.Binary]string) {
 	tools := machine.Tools()
-	usedVersions[tools.Version()] = true
+	usedVersions[tools.Version()] = tools.SHA256()
 	for _, container := range machine.Containers() {
 		addToolsVersionForMachine(container, usedVersions)
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -102,9 +103,13 @@ func (s *Suite) TestGetMigrationStatus(c *gc.C) {
 				Password:      "secret",
 			},
 		},
-		Attempt:          1,
-		Phase:            "READONLY",
-		PhaseChangedTime: s.backend.migration.PhaseChangedTime(),
+		MigrationId:        "id",
+		ModelName:          "mymodel",
+		Attempt:            1,
+		Phase:              "READONLY",
+		PhaseChangedTime:   s.backend.migration.PhaseChangedTime(),
+		ApprovalRef:        "CHG-1234",
+		ReapRetryRequested: true,
 	})
 }
 
@@ -140,6 +145,81 @@ func (s *Suite) TestSetPhaseError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "failed to set phase: blam")
 }
 
+func (s *Suite) TestSetStatusMessage(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	err := api.SetStatusMessage(params.SetMigrationStatusMessageArgs{Message: "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.backend.migration.messageSet, gc.Equals, "foo")
+}
+
+func (s *Suite) TestSetStatusMessageNoMigration(c *gc.C) {
+	s.backend.getErr = errors.New("boom")
+	api := s.mustMakeAPI(c)
+
+	err := api.SetStatusMessage(params.SetMigrationStatusMessageArgs{Message: "foo"})
+	c.Check(err, gc.ErrorMatches, "could not get migration: boom")
+}
+
+func (s *Suite) TestSetStatusMessageError(c *gc.C) {
+	s.backend.migration.setMessageErr = errors.New("blam")
+	api := s.mustMakeAPI(c)
+
+	err := api.SetStatusMessage(params.SetMigrationStatusMessageArgs{Message: "foo"})
+	c.Assert(err, gc.ErrorMatches, "failed to set status message: blam")
+}
+
+func (s *Suite) TestSetMinionWaitProgress(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	err := api.SetMinionWaitProgress(params.MinionReports{
+		MigrationId:   "id",
+		Phase:         "READONLY",
+		SuccessCount:  3,
+		UnknownCount:  2,
+		UnknownSample: []string{"machine-3", "unit-foo-0"},
+		Failed:        []string{"machine-1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.backend.migration.progressSet, gc.Equals, state.MinionWaitProgress{
+		Phase:        coremigration.READONLY,
+		SuccessCount: 3,
+		FailedCount:  1,
+		UnknownCount: 2,
+	})
+}
+
+func (s *Suite) TestSetMinionWaitProgressInvalidPhase(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	err := api.SetMinionWaitProgress(params.MinionReports{
+		MigrationId: "id",
+		Phase:       "foo",
+	})
+	c.Check(err, gc.ErrorMatches, `invalid phase: "foo"`)
+}
+
+func (s *Suite) TestSetMinionWaitProgressWrongMigration(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	err := api.SetMinionWaitProgress(params.MinionReports{
+		MigrationId: "other",
+		Phase:       "READONLY",
+	})
+	c.Check(err, gc.ErrorMatches, `minion reports are for migration "other", not "id"`)
+}
+
+func (s *Suite) TestSetMinionWaitProgressError(c *gc.C) {
+	s.backend.migration.setProgressErr = errors.New("blam")
+	api := s.mustMakeAPI(c)
+
+	err := api.SetMinionWaitProgress(params.MinionReports{
+		MigrationId: "id",
+		Phase:       "READONLY",
+	})
+	c.Check(err, gc.ErrorMatches, "blam")
+}
+
 func (s *Suite) TestExport(c *gc.C) {
 	s.model.AddApplication(description.ApplicationArgs{
 		Tag:      names.NewApplicationTag("foo"),
@@ -149,6 +229,29 @@ func (s *Suite) TestExport(c *gc.C) {
 	m := s.model.AddMachine(description.MachineArgs{Id: names.NewMachineTag("9")})
 	m.SetTools(description.AgentToolsArgs{
 		Version: version.MustParseBinary(tools),
+		SHA256:  "tools-sha256",
+	})
+	m.AddOpenedPorts(description.OpenedPortsArgs{
+		OpenedPorts: []description.PortRangeArgs{{
+			UnitName: "foo/0",
+			FromPort: 8080,
+			ToPort:   8080,
+			Protocol: "tcp",
+		}, {
+			UnitName: "foo/0",
+			FromPort: 80,
+			ToPort:   81,
+			Protocol: "tcp",
+		}},
+	})
+	m.AddOpenedPorts(description.OpenedPortsArgs{
+		SubnetID: "10.0.0.0/24",
+		OpenedPorts: []description.PortRangeArgs{{
+			UnitName: "foo/1",
+			FromPort: 80,
+			ToPort:   81,
+			Protocol: "tcp",
+		}},
 	})
 	api := s.mustMakeAPI(c)
 
@@ -160,9 +263,27 @@ func (s *Suite) TestExport(c *gc.C) {
 	// is in the serialised output.
 	c.Assert(string(serialized.Bytes), jc.Contains, jujuversion.Current.String())
 	c.Assert(serialized.Charms, gc.DeepEquals, []string{"cs:foo-0"})
+	c.Assert(serialized.CharmOrigins, gc.DeepEquals, map[string]string{"cs:foo-0": "charmstore"})
+	c.Assert(serialized.CharmSHA256s, gc.DeepEquals, map[string]string{"cs:foo-0": "cs:foo-0 sha256"})
 	c.Assert(serialized.Tools, gc.DeepEquals, []params.SerializedModelTools{
-		{tools, "/tools/" + tools},
+		{tools, "/tools/" + tools, "tools-sha256"},
+	})
+	c.Assert(serialized.FirewallRules, gc.DeepEquals, []params.FirewallRule{
+		{Ports: params.PortRange{FromPort: 80, ToPort: 81, Protocol: "tcp"}},
+		{Ports: params.PortRange{FromPort: 8080, ToPort: 8080, Protocol: "tcp"}},
+	})
+}
+
+func (s *Suite) TestExportCharmSHA256Error(c *gc.C) {
+	s.model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("foo"),
+		CharmURL: "cs:foo-0",
 	})
+	s.backend.charmErr = errors.New("boom")
+	api := s.mustMakeAPI(c)
+
+	_, err := api.Export()
+	c.Assert(err, gc.ErrorMatches, `cannot get SHA256 of charm "cs:foo-0": boom`)
 }
 
 func (s *Suite) TestReap(c *gc.C) {
@@ -183,6 +304,32 @@ func (s *Suite) TestReapError(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "boom")
 }
 
+func (s *Suite) TestReapComplete(c *gc.C) {
+	s.backend.modelRemoved = true
+	api := s.mustMakeAPI(c)
+
+	result := api.ReapComplete()
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Result, jc.IsTrue)
+	s.backend.stub.CheckCallNames(c, "ModelRemoved")
+}
+
+func (s *Suite) TestReapCompleteNotRemoved(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	result := api.ReapComplete()
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Result, jc.IsFalse)
+}
+
+func (s *Suite) TestReapCompleteError(c *gc.C) {
+	s.backend.modelRemovedErr = errors.New("boom")
+	api := s.mustMakeAPI(c)
+
+	result := api.ReapComplete()
+	c.Check(result.Error, gc.ErrorMatches, "boom")
+}
+
 func (s *Suite) TestWatchMinionReports(c *gc.C) {
 	api := s.mustMakeAPI(c)
 
@@ -205,6 +352,118 @@ func (s *Suite) TestWatchMinionReports(c *gc.C) {
 	}
 }
 
+func (s *Suite) TestModelInfo(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	info, err := api.ModelInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info, gc.Equals, params.MigrationModelInfo{
+		UUID:         modelUUID,
+		Name:         "mymodel",
+		OwnerTag:     names.NewUserTag("owner").String(),
+		AgentVersion: version.MustParse("2.1.9"),
+	})
+	s.stub.CheckCallNames(c, "ModelName", "ModelOwner", "AgentVersion")
+}
+
+func (s *Suite) TestModelInfoError(c *gc.C) {
+	s.backend.agentVersionErr = errors.New("boom")
+	api := s.mustMakeAPI(c)
+
+	_, err := api.ModelInfo()
+	c.Assert(err, gc.ErrorMatches, "retrieving agent version: boom")
+}
+
+func (s *Suite) TestStoragePools(c *gc.C) {
+	s.backend.storagePools = []coremigration.StoragePool{
+		{Name: "ebs-ssd", Provider: "ebs"},
+		{Name: "loop", Provider: "loop"},
+	}
+	api := s.mustMakeAPI(c)
+
+	result := api.StoragePools()
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Result, jc.DeepEquals, []params.StoragePool{
+		{Name: "ebs-ssd", Provider: "ebs"},
+		{Name: "loop", Provider: "loop"},
+	})
+}
+
+func (s *Suite) TestStoragePoolsError(c *gc.C) {
+	s.backend.storagePoolsErr = errors.New("boom")
+	api := s.mustMakeAPI(c)
+
+	result := api.StoragePools()
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestModelCredential(c *gc.C) {
+	s.backend.credential = coremigration.CloudCredential{
+		Owner:      names.NewUserTag("owner"),
+		Cloud:      "dummy",
+		Name:       "cred",
+		AuthType:   "userpass",
+		Attributes: map[string]string{"username": "bob"},
+	}
+	api := s.mustMakeAPI(c)
+
+	cred, err := api.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cred, jc.DeepEquals, params.MigrationCredential{
+		OwnerTag:   "user-owner",
+		Cloud:      "dummy",
+		Name:       "cred",
+		AuthType:   "userpass",
+		Attributes: map[string]string{"username": "bob"},
+	})
+}
+
+func (s *Suite) TestModelCredentialNone(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	cred, err := api.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cred, jc.DeepEquals, params.MigrationCredential{})
+}
+
+func (s *Suite) TestModelCredentialError(c *gc.C) {
+	s.backend.credentialErr = errors.New("boom")
+	api := s.mustMakeAPI(c)
+
+	_, err := api.ModelCredential()
+	c.Assert(err, gc.ErrorMatches, "retrieving model credential: boom")
+}
+
+func (s *Suite) TestWatchForAbort(c *gc.C) {
+	api := s.mustMakeAPI(c)
+
+	result := api.WatchForAbort()
+	c.Assert(result.Error, gc.IsNil)
+
+	s.stub.CheckCallNames(c,
+		"LatestModelMigration",
+		"ModelMigration.WatchForAbort",
+	)
+
+	resource := s.resources.Get(result.NotifyWatcherId)
+	watcher, _ := resource.(state.NotifyWatcher)
+	c.Assert(watcher, gc.NotNil)
+
+	select {
+	case <-watcher.Changes():
+		c.Fatalf("initial event not consumed")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *Suite) TestWatchForAbortNoMigration(c *gc.C) {
+	s.backend.getErr = errors.NotFoundf("migration")
+	api := s.mustMakeAPI(c)
+
+	result := api.WatchForAbort()
+	c.Assert(result.Error, gc.ErrorMatches, "migration not found")
+}
+
 func (s *Suite) TestGetMinionReports(c *gc.C) {
 	// Report 16 unknowns. These are in reverse order in order to test
 	// sorting.
@@ -223,6 +482,10 @@ func (s *Suite) TestGetMinionReports(c *gc.C) {
 		Succeeded: []names.Tag{m50, m51, u0},
 		Failed:    []names.Tag{u1, m52, m50c1, m50c0},
 		Unknown:   unknown,
+		FailureDetails: map[names.Tag]string{
+			u1:  "hook failed",
+			m52: "disk full",
+		},
 	}
 
 	api := s.mustMakeAPI(c)
@@ -248,9 +511,42 @@ func (s *Suite) TestGetMinionReports(c *gc.C) {
 			m52.String(),
 			u1.String(),
 		},
+		FailureDetails: map[string]string{
+			"unit-foo-1": "hook failed",
+			"machine-52": "disk full",
+		},
+	})
+}
+
+func (s *Suite) TestGetMinionFailureDetails(c *gc.C) {
+	s.backend.migration.failureDetails = map[names.Tag]string{
+		names.NewMachineTag("1"):       "disk full",
+		names.NewUnitTag("foo/0"):      "hook failed",
+		names.NewMachineTag("2/lxd/0"): "",
+	}
+	api := s.mustMakeAPI(c)
+	details, err := api.GetMinionFailureDetails(params.MinionFailureDetailsArgs{Phase: "QUIESCE"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details, jc.DeepEquals, params.MinionFailureDetails{
+		Details: map[string]string{
+			"machine-1":       "disk full",
+			"unit-foo-0":      "hook failed",
+			"machine-2-lxd-0": "",
+		},
+	})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"LatestModelMigration", nil},
+		{"ModelMigration.MinionFailureDetails", []interface{}{coremigration.QUIESCE}},
 	})
 }
 
+func (s *Suite) TestGetMinionFailureDetailsInvalidPhase(c *gc.C) {
+	api := s.mustMakeAPI(c)
+	_, err := api.GetMinionFailureDetails(params.MinionFailureDetailsArgs{Phase: "WTF"})
+	c.Assert(err, gc.ErrorMatches, `invalid phase: "WTF"`)
+	s.stub.CheckNoCalls(c)
+}
+
 func (s *Suite) makeAPI() (*migrationmaster.API, error) {
 	return migrationmaster.NewAPI(s.backend, s.resources, s.authorizer)
 }
@@ -267,8 +563,17 @@ type stubBackend struct {
 	stub      *testing.Stub
 	getErr    error
 	removeErr error
+	charmErr  error
 	migration *stubMigration
 	model     description.Model
+
+	agentVersionErr error
+	storagePools    []coremigration.StoragePool
+	storagePoolsErr error
+	credential      coremigration.CloudCredential
+	credentialErr   error
+	modelRemoved    bool
+	modelRemovedErr error
 }
 
 func (b *stubBackend) WatchForModelMigration() state.NotifyWatcher {
@@ -289,6 +594,51 @@ func (b *stubBackend) RemoveExportingModelDocs() error {
 	return b.removeErr
 }
 
+func (b *stubBackend) ModelName() (string, error) {
+	b.stub.AddCall("ModelName")
+	return "mymodel", nil
+}
+
+func (b *stubBackend) ModelUUID() string {
+	return modelUUID
+}
+
+func (b *stubBackend) ModelOwner() (names.UserTag, error) {
+	b.stub.AddCall("ModelOwner")
+	return names.NewUserTag("owner"), nil
+}
+
+func (b *stubBackend) AgentVersion() (version.Number, error) {
+	b.stub.AddCall("AgentVersion")
+	if b.agentVersionErr != nil {
+		return version.Zero, b.agentVersionErr
+	}
+	return version.MustParse("2.1.9"), nil
+}
+
+func (b *stubBackend) StoragePools() ([]coremigration.StoragePool, error) {
+	b.stub.AddCall("StoragePools")
+	return b.storagePools, b.storagePoolsErr
+}
+
+func (b *stubBackend) ModelCredential() (coremigration.CloudCredential, error) {
+	b.stub.AddCall("ModelCredential")
+	return b.credential, b.credentialErr
+}
+
+func (b *stubBackend) ModelRemoved() (bool, error) {
+	b.stub.AddCall("ModelRemoved")
+	return b.modelRemoved, b.modelRemovedErr
+}
+
+func (b *stubBackend) CharmSHA256(curl *charm.URL) (string, error) {
+	b.stub.AddCall("CharmSHA256", curl)
+	if b.charmErr != nil {
+		return "", b.charmErr
+	}
+	return curl.String() + " sha256", nil
+}
+
 func (b *stubBackend) Export() (description.Model, error) {
 	b.stub.AddCall("Export")
 	return b.model, nil
@@ -300,7 +650,14 @@ type stubMigration struct {
 	stub          *testing.Stub
 	setPhaseErr   error
 	phaseSet      coremigration.Phase
+	setMessageErr error
+	messageSet    string
 	minionReports *state.MinionReports
+
+	setProgressErr error
+	progressSet    state.MinionWaitProgress
+
+	failureDetails map[names.Tag]string
 }
 
 func (m *stubMigration) Id() string {
@@ -315,6 +672,14 @@ func (m *stubMigration) PhaseChangedTime() time.Time {
 	return time.Date(2016, 6, 22, 16, 38, 0, 0, time.UTC)
 }
 
+func (m *stubMigration) ApprovalRef() string {
+	return "CHG-1234"
+}
+
+func (m *stubMigration) ReapRetryRequested() bool {
+	return true
+}
+
 func (m *stubMigration) Attempt() (int, error) {
 	return 1, nil
 }
@@ -341,15 +706,41 @@ func (m *stubMigration) SetPhase(phase coremigration.Phase) error {
 	return nil
 }
 
+func (m *stubMigration) SetStatusMessage(message string) error {
+	if m.setMessageErr != nil {
+		return m.setMessageErr
+	}
+	m.messageSet = message
+	return nil
+}
+
+func (m *stubMigration) SetMinionWaitProgress(progress state.MinionWaitProgress) error {
+	if m.setProgressErr != nil {
+		return m.setProgressErr
+	}
+	m.progressSet = progress
+	return nil
+}
+
 func (m *stubMigration) WatchMinionReports() (state.NotifyWatcher, error) {
 	m.stub.AddCall("ModelMigration.WatchMinionReports")
 	return apiservertesting.NewFakeNotifyWatcher(), nil
 }
 
+func (m *stubMigration) WatchForAbort() (state.NotifyWatcher, error) {
+	m.stub.AddCall("ModelMigration.WatchForAbort")
+	return apiservertesting.NewFakeNotifyWatcher(), nil
+}
+
 func (m *stubMigration) GetMinionReports() (*state.MinionReports, error) {
 	return m.minionReports, nil
 }
 
+func (m *stubMigration) MinionFailureDetails(phase coremigration.Phase) (map[names.Tag]string, error) {
+	m.stub.AddCall("ModelMigration.MinionFailureDetails", phase)
+	return m.failureDetails, nil
+}
+
 var modelUUID string
 var controllerUUID string
 
@@ -4,8 +4,16 @@
 package migrationmaster
 
 import (
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
 	"github.com/juju/juju/apiserver/facade"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/poolmanager"
 )
 
 // newAPIForRegistration exists to provide the required signature for
@@ -15,5 +23,141 @@ func newAPIForRegistration(
 	resources facade.Resources,
 	authorizer facade.Authorizer,
 ) (*API, error) {
-	return NewAPI(st, resources, authorizer)
+	return NewAPI(backendShim{st}, resources, authorizer)
+}
+
+// backendShim adds the methods required by Backend which
+// *state.State doesn't provide directly.
+type backendShim struct {
+	*state.State
+}
+
+// ModelName implements Backend.
+func (s backendShim) ModelName() (string, error) {
+	model, err := s.Model()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return model.Name(), nil
+}
+
+// ModelOwner implements Backend.
+func (s backendShim) ModelOwner() (names.UserTag, error) {
+	model, err := s.Model()
+	if err != nil {
+		return names.UserTag{}, errors.Trace(err)
+	}
+	return model.Owner(), nil
+}
+
+// AgentVersion implements Backend.
+func (s backendShim) AgentVersion() (version.Number, error) {
+	cfg, err := s.ModelConfig()
+	if err != nil {
+		return version.Zero, errors.Trace(err)
+	}
+	vers, ok := cfg.AgentVersion()
+	if !ok {
+		return version.Zero, errors.New("no agent version")
+	}
+	return vers, nil
+}
+
+// StoragePools implements Backend.
+func (s backendShim) StoragePools() ([]coremigration.StoragePool, error) {
+	var poolNames []string
+	volumes, err := s.AllVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, v := range volumes {
+		if info, err := v.Info(); err == nil {
+			poolNames = append(poolNames, info.Pool)
+		} else if volumeParams, ok := v.Params(); ok {
+			poolNames = append(poolNames, volumeParams.Pool)
+		}
+	}
+	filesystems, err := s.AllFilesystems()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, f := range filesystems {
+		if info, err := f.Info(); err == nil {
+			poolNames = append(poolNames, info.Pool)
+		} else if filesystemParams, ok := f.Params(); ok {
+			poolNames = append(poolNames, filesystemParams.Pool)
+		}
+	}
+
+	poolManager := poolmanager.New(state.NewStateSettings(s.State))
+	seen := make(map[string]bool)
+	var pools []coremigration.StoragePool
+	for _, name := range poolNames {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		// A pool that doesn't exist is a provider type which
+		// has been specified directly.
+		providerType := storage.ProviderType(name)
+		pool, err := poolManager.Get(name)
+		if err == nil {
+			providerType = pool.Provider()
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		pools = append(pools, coremigration.StoragePool{
+			Name:     name,
+			Provider: string(providerType),
+		})
+	}
+	return pools, nil
+}
+
+// ModelCredential implements Backend.
+func (s backendShim) ModelCredential() (coremigration.CloudCredential, error) {
+	var empty coremigration.CloudCredential
+	model, err := s.Model()
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	name := model.CloudCredential()
+	if name == "" {
+		return empty, nil
+	}
+	credentials, err := s.CloudCredentials(model.Owner(), model.Cloud())
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	credential, ok := credentials[name]
+	if !ok {
+		return empty, errors.NotFoundf("cloud credential %q", name)
+	}
+	return coremigration.CloudCredential{
+		Owner:      model.Owner(),
+		Cloud:      model.Cloud(),
+		Name:       name,
+		AuthType:   string(credential.AuthType()),
+		Attributes: credential.Attributes(),
+	}, nil
+}
+
+// ModelRemoved implements Backend.
+func (s backendShim) ModelRemoved() (bool, error) {
+	_, err := s.Model()
+	if errors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return false, nil
+}
+
+// CharmSHA256 implements Backend.
+func (s backendShim) CharmSHA256(curl *charm.URL) (string, error) {
+	ch, err := s.Charm(curl)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return ch.BundleSha256(), nil
 }
@@ -64,6 +64,11 @@ func (api *API) Report(info params.MinionReport) error {
 		return errors.Trace(err)
 	}
 
-	err = mig.MinionReport(api.authorizer.GetAuthTag(), phase, info.Success)
+	tag := api.authorizer.GetAuthTag()
+	if !info.Success && info.Error != "" {
+		err = mig.MinionReportFailure(tag, phase, info.Error)
+	} else {
+		err = mig.MinionReport(tag, phase, info.Success)
+	}
 	return errors.Trace(err)
 }
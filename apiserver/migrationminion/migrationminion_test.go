@@ -84,6 +84,21 @@ func (s *Suite) TestReport(c *gc.C) {
 	})
 }
 
+func (s *Suite) TestReportFailure(c *gc.C) {
+	api := s.mustMakeAPI(c)
+	err := api.Report(params.MinionReport{
+		MigrationId: "id",
+		Phase:       "READONLY",
+		Success:     false,
+		Error:       "disk full",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"ModelMigration", []interface{}{"id"}},
+		{"ReportFailure", []interface{}{s.authorizer.Tag, migration.READONLY, "disk full"}},
+	})
+}
+
 func (s *Suite) TestReportInvalidPhase(c *gc.C) {
 	api := s.mustMakeAPI(c)
 	err := api.Report(params.MinionReport{
@@ -144,3 +159,8 @@ func (m *stubModelMigration) MinionReport(tag names.Tag, phase migration.Phase,
 	m.stub.AddCall("Report", tag, phase, success)
 	return nil
 }
+
+func (m *stubModelMigration) MinionReportFailure(tag names.Tag, phase migration.Phase, message string) error {
+	m.stub.AddCall("ReportFailure", tag, phase, message)
+	return nil
+}
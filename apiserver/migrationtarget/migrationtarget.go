@@ -4,14 +4,31 @@
 package migrationtarget
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/description"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/migration"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/storage/provider/registry"
 )
 
 func init() {
@@ -58,6 +75,144 @@ func checkAuth(authorizer facade.Authorizer, st *state.State) error {
 	return nil
 }
 
+// Prechecks ensures that the target controller is ready to accept a
+// model migration: that it's running at least the model's agent
+// version, and that it doesn't already have the model, or a model
+// with the same name and owner.
+func (api *API) Prechecks(model params.MigrationModelInfo) error {
+	ownerTag, err := names.ParseUserTag(model.OwnerTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	controllerVersion, err := api.controllerVersion()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if model.AgentVersion.Compare(controllerVersion) > 0 {
+		return errors.Errorf("model has higher version than target controller (%s > %s)",
+			model.AgentVersion, controllerVersion)
+	}
+
+	models, err := api.state.AllModels()
+	if err != nil {
+		return errors.Annotate(err, "retrieving models")
+	}
+	for _, m := range models {
+		if m.UUID() == model.UUID {
+			return errors.Errorf("model with same UUID already exists (%s)", model.UUID)
+		}
+		if m.Name() == model.Name && m.Owner().Canonical() == ownerTag.Canonical() {
+			return errors.Errorf("model named %q already exists", model.Name)
+		}
+	}
+	return nil
+}
+
+// controllerVersion returns the agent version of the controller.
+func (api *API) controllerVersion() (version.Number, error) {
+	cfg, err := api.state.ModelConfig()
+	if err != nil {
+		return version.Zero, errors.Annotate(err, "retrieving controller config")
+	}
+	vers, ok := cfg.AgentVersion()
+	if !ok {
+		return version.Zero, errors.New("no controller agent version")
+	}
+	return vers, nil
+}
+
+// StorageProviderTypes returns the storage provider types supported
+// by the controller's cloud, including the providers common to all
+// clouds.
+func (api *API) StorageProviderTypes() params.StringsResult {
+	cfg, err := api.state.ModelConfig()
+	if err != nil {
+		return params.StringsResult{Error: common.ServerError(err)}
+	}
+	supported := set.NewStrings()
+	providerTypes, _ := registry.EnvironStorageProviders(cfg.Type())
+	for _, providerType := range providerTypes {
+		supported.Add(string(providerType))
+	}
+	for providerType := range provider.CommonProviders() {
+		supported.Add(string(providerType))
+	}
+	return params.StringsResult{Result: supported.SortedValues()}
+}
+
+// CredentialHash returns the hash of the target controller's copy of
+// the specified cloud credential. A NotFound error is returned if the
+// controller doesn't have the credential.
+func (api *API) CredentialHash(args params.MigrationCredential) params.StringResult {
+	cred, err := api.credential(args)
+	if err != nil {
+		return params.StringResult{Error: common.ServerError(err)}
+	}
+	return params.StringResult{Result: cred.Hash()}
+}
+
+func (api *API) credential(args params.MigrationCredential) (coremigration.CloudCredential, error) {
+	var empty coremigration.CloudCredential
+	owner, err := names.ParseUserTag(args.OwnerTag)
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	credentials, err := api.state.CloudCredentials(owner, args.Cloud)
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	credential, ok := credentials[args.Name]
+	if !ok {
+		return empty, errors.NotFoundf("cloud credential %q", args.Name)
+	}
+	return coremigration.CloudCredential{
+		Owner:      owner,
+		Cloud:      args.Cloud,
+		Name:       args.Name,
+		AuthType:   string(credential.AuthType()),
+		Attributes: credential.Attributes(),
+	}, nil
+}
+
+// UploadCredential adds the cloud credential used by a migrated model
+// to the controller. The credential is rejected if its content doesn't
+// match the hash computed by the sending controller.
+func (api *API) UploadCredential(args params.MigrationCredential) error {
+	owner, err := names.ParseUserTag(args.OwnerTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cred := coremigration.CloudCredential{
+		Owner:      owner,
+		Cloud:      args.Cloud,
+		Name:       args.Name,
+		AuthType:   args.AuthType,
+		Attributes: args.Attributes,
+	}
+	if cred.Hash() != args.Hash {
+		return errors.Errorf("cloud credential %q failed hash verification", args.Name)
+	}
+	return api.state.UpdateCloudCredentials(owner, args.Cloud, map[string]cloud.Credential{
+		args.Name: cloud.NewCredential(cloud.AuthType(args.AuthType), args.Attributes),
+	})
+}
+
+// AgentStreams returns the agent streams which the controller can
+// serve tools from: the stream it's configured to find tools in.
+func (api *API) AgentStreams() params.StringsResult {
+	cfg, err := api.state.ModelConfig()
+	if err != nil {
+		return params.StringsResult{Error: common.ServerError(err)}
+	}
+	vers, err := api.controllerVersion()
+	if err != nil {
+		return params.StringsResult{Error: common.ServerError(err)}
+	}
+	stream := tools.PreferredStream(&vers, cfg.Development(), cfg.AgentStream())
+	return params.StringsResult{Result: []string{stream}}
+}
+
 // Import takes a serialized Juju model, deserializes it, and
 // recreates it in the receiving controller.
 func (api *API) Import(serialized params.SerializedModel) error {
@@ -70,6 +225,32 @@ func (api *API) Import(serialized params.SerializedModel) error {
 	return err
 }
 
+// Export serializes a model which is being imported, so that the
+// source controller can verify that the import was complete.
+func (api *API) Export(args params.ModelArgs) (params.SerializedModel, error) {
+	var serialized params.SerializedModel
+	model, err := api.getModel(args)
+	if err != nil {
+		return serialized, errors.Trace(err)
+	}
+	st, err := api.state.ForModel(model.ModelTag())
+	if err != nil {
+		return serialized, errors.Trace(err)
+	}
+	defer st.Close()
+
+	exported, err := st.Export()
+	if err != nil {
+		return serialized, errors.Trace(err)
+	}
+	bytes, err := description.Serialize(exported)
+	if err != nil {
+		return serialized, errors.Trace(err)
+	}
+	serialized.Bytes = bytes
+	return serialized, nil
+}
+
 func (api *API) getModel(args params.ModelArgs) (*state.Model, error) {
 	tag, err := names.ParseModelTag(args.ModelTag)
 	if err != nil {
@@ -102,6 +283,149 @@ func (api *API) Abort(args params.ModelArgs) error {
 	return st.RemoveImportingModelDocs()
 }
 
+// VerifyBinaries checks that the charms and tools stored for a model
+// being imported have the SHA256 sums that the source controller
+// expects. An error describing every mismatch is returned.
+func (api *API) VerifyBinaries(args params.VerifyBinariesArgs) error {
+	model, err := api.getModel(params.ModelArgs{ModelTag: args.ModelTag})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	st, err := api.state.ForModel(model.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+
+	var problems []string
+	for curlStr, expected := range args.Charms {
+		curl, err := charm.ParseURL(curlStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ch, err := st.Charm(curl)
+		if err != nil {
+			return errors.Annotatef(err, "retrieving charm %q", curlStr)
+		}
+		if actual := ch.BundleSha256(); actual != expected {
+			problems = append(problems, fmt.Sprintf(
+				"charm %q has SHA256 %s, expected %s", curlStr, actual, expected))
+		}
+	}
+
+	if len(args.Tools) > 0 {
+		storage, err := st.ToolsStorage()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer storage.Close()
+		for _, tools := range args.Tools {
+			metadata, err := storage.Metadata(tools.Version)
+			if err != nil {
+				return errors.Annotatef(err, "retrieving tools %s", tools.Version)
+			}
+			if metadata.SHA256 != tools.SHA256 {
+				problems = append(problems, fmt.Sprintf(
+					"tools %s have SHA256 %s, expected %s", tools.Version, metadata.SHA256, tools.SHA256))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+var newEnviron = environs.New
+
+// ApplyFirewallRules recreates the firewall rules of a model being
+// imported. The rules only need to be applied directly when the model
+// uses a global firewall; otherwise the model's firewaller opens the
+// ports on each machine once the model is active. Rules which the
+// controller's provider can't recreate are returned.
+func (api *API) ApplyFirewallRules(args params.ApplyFirewallRulesArgs) (params.ApplyFirewallRulesResult, error) {
+	var result params.ApplyFirewallRulesResult
+	model, err := api.getModel(params.ModelArgs{ModelTag: args.ModelTag})
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	st, err := api.state.ForModel(model.ModelTag())
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer st.Close()
+
+	cfg, err := st.ModelConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if cfg.FirewallMode() != config.FwGlobal {
+		return result, nil
+	}
+	env, err := newEnviron(cfg)
+	if err != nil {
+		return result, errors.Annotate(err, "opening model environ")
+	}
+	firewaller, ok := env.(environs.Firewaller)
+	if !ok {
+		for _, rule := range args.Rules {
+			result.Untranslated = append(result.Untranslated, params.UntranslatedFirewallRule{
+				Rule:   rule,
+				Reason: "global firewall not supported by target provider",
+			})
+		}
+		return result, nil
+	}
+
+	var ports []network.PortRange
+	for _, rule := range args.Rules {
+		if !allowsAnySource(rule.SourceCIDRs) {
+			result.Untranslated = append(result.Untranslated, params.UntranslatedFirewallRule{
+				Rule:   rule,
+				Reason: "source CIDRs not supported by target provider",
+			})
+			continue
+		}
+		ports = append(ports, rule.Ports.NetworkPortRange())
+	}
+	if len(ports) > 0 {
+		if err := firewaller.OpenPorts(ports); err != nil {
+			return result, errors.Annotate(err, "opening ports")
+		}
+	}
+	return result, nil
+}
+
+// allowsAnySource returns whether a firewall rule with the given
+// source CIDRs allows ingress from anywhere.
+func allowsAnySource(cidrs []string) bool {
+	for _, cidr := range cidrs {
+		if cidr != "0.0.0.0/0" {
+			return false
+		}
+	}
+	return true
+}
+
+// LatestLogTime returns the time of the most recent log record
+// received by the target controller for the model being imported, or
+// the zero time if there are none. The source controller uses it to
+// resume an interrupted log transfer.
+func (api *API) LatestLogTime(args params.ModelArgs) (time.Time, error) {
+	model, err := api.getModel(args)
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	st, err := api.state.ForModel(model.ModelTag())
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	defer st.Close()
+	return state.LatestLogTime(st)
+}
+
 // Activate sets the migration mode of the model to "active". It is an error to
 // attempt to Abort a model that has a migration mode other than importing.
 func (api *API) Activate(args params.ModelArgs) error {
@@ -4,7 +4,12 @@
 package migrationtarget_test
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -15,14 +20,22 @@ import (
 	"github.com/juju/juju/apiserver/migrationtarget"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/description"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/binarystorage"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	jujuversion "github.com/juju/juju/version"
 )
 
 type Suite struct {
@@ -87,8 +100,153 @@ func (s *Suite) TestNotControllerAdmin(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
 }
 
+func (s *Suite) TestPrechecks(c *gc.C) {
+	api := s.mustNewAPI(c)
+	err := api.Prechecks(s.modelInfo(c))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) TestPrechecksNewerVersion(c *gc.C) {
+	api := s.mustNewAPI(c)
+	info := s.modelInfo(c)
+	controllerVersion := info.AgentVersion
+	info.AgentVersion.Minor++
+	err := api.Prechecks(info)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(
+		`model has higher version than target controller \(%s > %s\)`,
+		info.AgentVersion, controllerVersion))
+}
+
+func (s *Suite) TestPrechecksModelExists(c *gc.C) {
+	api := s.mustNewAPI(c)
+	info := s.modelInfo(c)
+	info.UUID = s.State.ModelUUID()
+	err := api.Prechecks(info)
+	c.Assert(err, gc.ErrorMatches, `model with same UUID already exists \(.+\)`)
+}
+
+func (s *Suite) TestPrechecksModelNameExists(c *gc.C) {
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		Name:  "some-model",
+		Owner: s.Owner,
+	})
+	defer st.Close()
+
+	api := s.mustNewAPI(c)
+	err := api.Prechecks(s.modelInfo(c))
+	c.Assert(err, gc.ErrorMatches, `model named "some-model" already exists`)
+}
+
+func (s *Suite) TestPrechecksBadOwner(c *gc.C) {
+	api := s.mustNewAPI(c)
+	info := s.modelInfo(c)
+	info.OwnerTag = "not-a-tag"
+	err := api.Prechecks(info)
+	c.Assert(err, gc.ErrorMatches, `"not-a-tag" is not a valid tag`)
+}
+
+func (s *Suite) TestStorageProviderTypes(c *gc.C) {
+	api := s.mustNewAPI(c)
+	result := api.StorageProviderTypes()
+	c.Assert(result.Error, gc.IsNil)
+	// The dummy provider registers a "dummy" storage provider.
+	c.Check(result.Result, jc.DeepEquals, []string{
+		"dummy", "loop", "rootfs", "tmpfs",
+	})
+}
+
+func (s *Suite) TestAgentStreams(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"agent-stream": "proposed",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.mustNewAPI(c)
+	result := api.AgentStreams()
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Result, jc.DeepEquals, []string{"proposed"})
+}
+
+func (s *Suite) TestUploadCredential(c *gc.C) {
+	api := s.mustNewAPI(c)
+	cred := makeCredential()
+	err := api.UploadCredential(credentialArgs(cred))
+	c.Assert(err, jc.ErrorIsNil)
+
+	credentials, err := s.State.CloudCredentials(cred.Owner, "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials, gc.HasLen, 1)
+	c.Check(credentials["cred"].AuthType(), gc.Equals, cloud.EmptyAuthType)
+	c.Check(credentials["cred"].Attributes(), jc.DeepEquals, cred.Attributes)
+
+	result := api.CredentialHash(params.MigrationCredential{
+		OwnerTag: cred.Owner.String(),
+		Cloud:    "dummy",
+		Name:     "cred",
+	})
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Result, gc.Equals, cred.Hash())
+}
+
+func (s *Suite) TestUploadCredentialBadHash(c *gc.C) {
+	api := s.mustNewAPI(c)
+	cred := makeCredential()
+	args := credentialArgs(cred)
+	args.Attributes = map[string]string{"foo": "tampered"}
+	err := api.UploadCredential(args)
+	c.Assert(err, gc.ErrorMatches, `cloud credential "cred" failed hash verification`)
+
+	credentials, err := s.State.CloudCredentials(cred.Owner, "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(credentials, gc.HasLen, 0)
+}
+
+func (s *Suite) TestUploadCredentialBadOwner(c *gc.C) {
+	api := s.mustNewAPI(c)
+	args := credentialArgs(makeCredential())
+	args.OwnerTag = "machine-0"
+	err := api.UploadCredential(args)
+	c.Assert(err, gc.ErrorMatches, `"machine-0" is not a valid user tag`)
+}
+
+func (s *Suite) TestCredentialHashNotFound(c *gc.C) {
+	api := s.mustNewAPI(c)
+	result := api.CredentialHash(params.MigrationCredential{
+		OwnerTag: names.NewUserTag("bob").String(),
+		Cloud:    "dummy",
+		Name:     "cred",
+	})
+	c.Assert(result.Error, gc.ErrorMatches, `cloud credential "cred" not found`)
+	c.Check(result.Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func makeCredential() coremigration.CloudCredential {
+	return coremigration.CloudCredential{
+		Owner:      names.NewUserTag("bob"),
+		Cloud:      "dummy",
+		Name:       "cred",
+		AuthType:   string(cloud.EmptyAuthType),
+		Attributes: map[string]string{"foo": "bar"},
+	}
+}
+
+func credentialArgs(cred coremigration.CloudCredential) params.MigrationCredential {
+	return params.MigrationCredential{
+		OwnerTag:   cred.Owner.String(),
+		Cloud:      cred.Cloud,
+		Name:       cred.Name,
+		AuthType:   cred.AuthType,
+		Attributes: cred.Attributes,
+		Hash:       cred.Hash(),
+	}
+}
+
 func (s *Suite) importModel(c *gc.C, api *migrationtarget.API) names.ModelTag {
-	uuid, bytes := s.makeExportedModel(c)
+	return s.importModelWithConfig(c, api, nil)
+}
+
+func (s *Suite) importModelWithConfig(c *gc.C, api *migrationtarget.API, attrs map[string]interface{}) names.ModelTag {
+	uuid, bytes := s.makeExportedModel(c, attrs)
 	err := api.Import(params.SerializedModel{Bytes: bytes})
 	c.Assert(err, jc.ErrorIsNil)
 	return names.NewModelTag(uuid)
@@ -104,6 +262,29 @@ func (s *Suite) TestImport(c *gc.C) {
 	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeImporting)
 }
 
+func (s *Suite) TestExport(c *gc.C) {
+	api := s.mustNewAPI(c)
+	tag := s.importModel(c, api)
+
+	serialized, err := api.Export(params.ModelArgs{ModelTag: tag.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	model, err := description.Deserialize(serialized.Bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.Tag(), gc.Equals, tag)
+	c.Check(model.Config()["name"], gc.Equals, "some-model")
+}
+
+func (s *Suite) TestExportNotImportingEnv(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.mustNewAPI(c)
+	_, err = api.Export(params.ModelArgs{ModelTag: model.ModelTag().String()})
+	c.Assert(err, gc.ErrorMatches, `migration mode for the model is not importing`)
+}
+
 func (s *Suite) TestAbort(c *gc.C) {
 	api := s.mustNewAPI(c)
 	tag := s.importModel(c, api)
@@ -177,6 +358,203 @@ func (s *Suite) TestActivateNotImportingEnv(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `migration mode for the model is not importing`)
 }
 
+func (s *Suite) TestApplyFirewallRules(c *gc.C) {
+	env := &fakeFirewallEnviron{}
+	s.PatchValue(migrationtarget.NewEnviron, func(*config.Config) (environs.Environ, error) {
+		return env, nil
+	})
+	api := s.mustNewAPI(c)
+	tag := s.importModelWithConfig(c, api, map[string]interface{}{
+		"firewall-mode": config.FwGlobal,
+	})
+
+	restricted := params.FirewallRule{
+		Ports:       params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+		SourceCIDRs: []string{"10.0.0.0/8"},
+	}
+	result, err := api.ApplyFirewallRules(params.ApplyFirewallRulesArgs{
+		ModelTag: tag.String(),
+		Rules: []params.FirewallRule{{
+			Ports: params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		}, {
+			Ports:       params.PortRange{FromPort: 8000, ToPort: 8080, Protocol: "tcp"},
+			SourceCIDRs: []string{"0.0.0.0/0"},
+		}, restricted},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Untranslated, jc.DeepEquals, []params.UntranslatedFirewallRule{{
+		Rule:   restricted,
+		Reason: "source CIDRs not supported by target provider",
+	}})
+	c.Check(env.opened, jc.DeepEquals, []network.PortRange{
+		network.MustParsePortRange("80/tcp"),
+		network.MustParsePortRange("8000-8080/tcp"),
+	})
+}
+
+func (s *Suite) TestApplyFirewallRulesInstanceMode(c *gc.C) {
+	// Without a global firewall, the model's firewaller opens the
+	// ports on each machine once the model is active.
+	s.PatchValue(migrationtarget.NewEnviron, func(*config.Config) (environs.Environ, error) {
+		c.Fatalf("unexpected environ")
+		return nil, nil
+	})
+	api := s.mustNewAPI(c)
+	tag := s.importModelWithConfig(c, api, map[string]interface{}{
+		"firewall-mode": config.FwInstance,
+	})
+
+	result, err := api.ApplyFirewallRules(params.ApplyFirewallRulesArgs{
+		ModelTag: tag.String(),
+		Rules: []params.FirewallRule{{
+			Ports: params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Untranslated, gc.HasLen, 0)
+}
+
+func (s *Suite) TestApplyFirewallRulesNotSupported(c *gc.C) {
+	s.PatchValue(migrationtarget.NewEnviron, func(*config.Config) (environs.Environ, error) {
+		return &struct{ environs.Environ }{}, nil
+	})
+	api := s.mustNewAPI(c)
+	tag := s.importModelWithConfig(c, api, map[string]interface{}{
+		"firewall-mode": config.FwGlobal,
+	})
+
+	rule := params.FirewallRule{
+		Ports: params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+	}
+	result, err := api.ApplyFirewallRules(params.ApplyFirewallRulesArgs{
+		ModelTag: tag.String(),
+		Rules:    []params.FirewallRule{rule},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Untranslated, jc.DeepEquals, []params.UntranslatedFirewallRule{{
+		Rule:   rule,
+		Reason: "global firewall not supported by target provider",
+	}})
+}
+
+func (s *Suite) TestApplyFirewallRulesNotImportingEnv(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.mustNewAPI(c)
+	_, err = api.ApplyFirewallRules(params.ApplyFirewallRulesArgs{ModelTag: model.ModelTag().String()})
+	c.Assert(err, gc.ErrorMatches, `migration mode for the model is not importing`)
+}
+
+func (s *Suite) TestLatestLogTime(c *gc.C) {
+	api := s.mustNewAPI(c)
+	tag := s.importModel(c, api)
+	st, err := s.State.ForModel(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	t0 := time.Date(2016, 11, 30, 10, 1, 0, 0, time.UTC)
+	dbLogger := state.NewDbLogger(st, names.NewMachineTag("0"), jujuversion.Current)
+	defer dbLogger.Close()
+	for _, t := range []time.Time{t0, t0.Add(time.Second), t0.Add(-time.Second)} {
+		err := dbLogger.Log(t, "module", "loc", loggo.INFO, "message")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	latest, err := api.LatestLogTime(params.ModelArgs{ModelTag: tag.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(latest, gc.Equals, t0.Add(time.Second))
+}
+
+func (s *Suite) TestLatestLogTimeNoLogs(c *gc.C) {
+	api := s.mustNewAPI(c)
+	tag := s.importModel(c, api)
+
+	latest, err := api.LatestLogTime(params.ModelArgs{ModelTag: tag.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(latest.IsZero(), jc.IsTrue)
+}
+
+func (s *Suite) TestLatestLogTimeNotImportingEnv(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.mustNewAPI(c)
+	_, err = api.LatestLogTime(params.ModelArgs{ModelTag: model.ModelTag().String()})
+	c.Assert(err, gc.ErrorMatches, `migration mode for the model is not importing`)
+}
+
+func (s *Suite) TestVerifyBinaries(c *gc.C) {
+	api := s.mustNewAPI(c)
+	tag := s.importModel(c, api)
+	ch, toolsVersion := s.addBinaries(c, tag)
+
+	err := api.VerifyBinaries(params.VerifyBinariesArgs{
+		ModelTag: tag.String(),
+		Charms:   map[string]string{ch.URL().String(): ch.BundleSha256()},
+		Tools: []params.SerializedModelTools{{
+			Version: toolsVersion,
+			SHA256:  "tools-sha256",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) TestVerifyBinariesMismatch(c *gc.C) {
+	api := s.mustNewAPI(c)
+	tag := s.importModel(c, api)
+	ch, toolsVersion := s.addBinaries(c, tag)
+
+	err := api.VerifyBinaries(params.VerifyBinariesArgs{
+		ModelTag: tag.String(),
+		Charms:   map[string]string{ch.URL().String(): "wrong"},
+		Tools: []params.SerializedModelTools{{
+			Version: toolsVersion,
+			SHA256:  "also-wrong",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(
+		`charm %q has SHA256 .*, expected wrong; tools %s have SHA256 tools-sha256, expected also-wrong`,
+		ch.URL().String(), toolsVersion))
+}
+
+func (s *Suite) TestVerifyBinariesNotImportingEnv(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.mustNewAPI(c)
+	err = api.VerifyBinaries(params.VerifyBinariesArgs{ModelTag: model.ModelTag().String()})
+	c.Assert(err, gc.ErrorMatches, `migration mode for the model is not importing`)
+}
+
+// addBinaries adds a charm and tools to the given model, returning
+// the charm and tools version.
+func (s *Suite) addBinaries(c *gc.C, tag names.ModelTag) (*state.Charm, string) {
+	st, err := s.State.ForModel(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	ch := factory.NewFactory(st).MakeCharm(c, nil)
+
+	storage, err := st.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	toolsVersion := "2.1.0-xenial-amd64"
+	err = storage.Add(strings.NewReader("tools"), binarystorage.Metadata{
+		Version: toolsVersion,
+		Size:    5,
+		SHA256:  "tools-sha256",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return ch, toolsVersion
+}
+
 func (s *Suite) newAPI() (*migrationtarget.API, error) {
 	return migrationtarget.NewAPI(s.State, s.resources, s.authorizer)
 }
@@ -187,7 +565,22 @@ func (s *Suite) mustNewAPI(c *gc.C) *migrationtarget.API {
 	return api
 }
 
-func (s *Suite) makeExportedModel(c *gc.C) (string, []byte) {
+// modelInfo returns the details of a model which the target
+// controller should be able to accept.
+func (s *Suite) modelInfo(c *gc.C) params.MigrationModelInfo {
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	vers, ok := cfg.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	return params.MigrationModelInfo{
+		UUID:         utils.MustNewUUID().String(),
+		Name:         "some-model",
+		OwnerTag:     s.Owner.String(),
+		AgentVersion: vers,
+	}
+}
+
+func (s *Suite) makeExportedModel(c *gc.C, attrs map[string]interface{}) (string, []byte) {
 	model, err := s.State.Export()
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -196,8 +589,21 @@ func (s *Suite) makeExportedModel(c *gc.C) (string, []byte) {
 		"name": "some-model",
 		"uuid": newUUID,
 	})
+	model.UpdateConfig(attrs)
 
 	bytes, err := description.Serialize(model)
 	c.Assert(err, jc.ErrorIsNil)
 	return newUUID, bytes
 }
+
+// fakeFirewallEnviron is an environs.Environ which records the ports
+// opened in its global firewall.
+type fakeFirewallEnviron struct {
+	environs.Environ
+	opened []network.PortRange
+}
+
+func (e *fakeFirewallEnviron) OpenPorts(ports []network.PortRange) error {
+	e.opened = append(e.opened, ports...)
+	return nil
+}
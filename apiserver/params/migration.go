@@ -3,7 +3,11 @@
 
 package params
 
-import "time"
+import (
+	"time"
+
+	"github.com/juju/version"
+)
 
 // InitiateModelMigrationArgs holds the details required to start one
 // or more model migrations.
@@ -48,12 +52,21 @@ type SetMigrationPhaseArgs struct {
 	Phase string `json:"phase"`
 }
 
+// SetMigrationStatusMessageArgs provides a migration status message
+// to the migrationmaster.SetStatusMessage API method.
+type SetMigrationStatusMessageArgs struct {
+	Message string `json:"message"`
+}
+
 // SerializedModel wraps a buffer contain a serialised Juju model. It
 // also contains lists of the charms and tools used in the model.
 type SerializedModel struct {
-	Bytes  []byte                 `json:"bytes"`
-	Charms []string               `json:"charms"`
-	Tools  []SerializedModelTools `json:"tools"`
+	Bytes         []byte                 `json:"bytes"`
+	Charms        []string               `json:"charms"`
+	CharmOrigins  map[string]string      `json:"charm-origins,omitempty"`
+	CharmSHA256s  map[string]string      `json:"charm-sha256s,omitempty"`
+	Tools         []SerializedModelTools `json:"tools"`
+	FirewallRules []FirewallRule         `json:"firewall-rules,omitempty"`
 }
 
 // SerializedModelTools holds the version and URI for a given tools
@@ -66,6 +79,76 @@ type SerializedModelTools struct {
 	// with the API server scheme, address and model prefix before it
 	// can be used.
 	URI string `json:"uri"`
+
+	// SHA256 holds the SHA256 sum of the tools tarball, if known.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// VerifyBinariesArgs holds the SHA256 sums which the charms and tools
+// uploaded to a target controller for a migrated model are expected
+// to have.
+type VerifyBinariesArgs struct {
+	ModelTag string                 `json:"model-tag"`
+	Charms   map[string]string      `json:"charms,omitempty"`
+	Tools    []SerializedModelTools `json:"tools,omitempty"`
+}
+
+// MigrationCredential holds the details of a cloud credential used by
+// a model being migrated.
+type MigrationCredential struct {
+	OwnerTag   string            `json:"owner-tag"`
+	Cloud      string            `json:"cloud"`
+	Name       string            `json:"name"`
+	AuthType   string            `json:"auth-type,omitempty"`
+	Attributes map[string]string `json:"attrs,omitempty"`
+
+	// Hash holds the SHA256 hash of the credential content. It is
+	// used by the receiving controller to verify that the credential
+	// arrived intact.
+	Hash string `json:"hash,omitempty"`
+}
+
+// FirewallRule describes a firewall rule in use by a model being
+// migrated.
+type FirewallRule struct {
+	Ports       PortRange `json:"ports"`
+	SourceCIDRs []string  `json:"source-cidrs,omitempty"`
+}
+
+// ApplyFirewallRulesArgs holds the firewall rules which the target
+// controller should recreate for a migrated model.
+type ApplyFirewallRulesArgs struct {
+	ModelTag string         `json:"model-tag"`
+	Rules    []FirewallRule `json:"rules"`
+}
+
+// ApplyFirewallRulesResult holds the firewall rules which the target
+// controller was unable to recreate for a migrated model.
+type ApplyFirewallRulesResult struct {
+	Untranslated []UntranslatedFirewallRule `json:"untranslated,omitempty"`
+}
+
+// UntranslatedFirewallRule holds a firewall rule which couldn't be
+// recreated by the target controller, and the reason why.
+type UntranslatedFirewallRule struct {
+	Rule   FirewallRule `json:"rule"`
+	Reason string       `json:"reason"`
+}
+
+// ApproveMigrationArgs holds the details needed to approve a model
+// migration which is being held awaiting approval.
+type ApproveMigrationArgs struct {
+	ModelTag  string `json:"model-tag"`
+	TicketRef string `json:"ticket-ref"`
+}
+
+// MigrationModelInfo is used to report basic model information to the
+// migrationmaster worker, and from it to the target controller.
+type MigrationModelInfo struct {
+	UUID         string         `json:"uuid"`
+	Name         string         `json:"name"`
+	OwnerTag     string         `json:"owner-tag"`
+	AgentVersion version.Number `json:"agent-version"`
 }
 
 // ModelArgs wraps a simple model tag.
@@ -91,10 +174,14 @@ type MigrationStatus struct {
 // migration, including authentication details for the remote
 // controller.
 type FullMigrationStatus struct {
-	Spec             ModelMigrationSpec `json:"spec"`
-	Attempt          int                `json:"attempt"`
-	Phase            string             `json:"phase"`
-	PhaseChangedTime time.Time          `json:"phase-changed-time"`
+	Spec               ModelMigrationSpec `json:"spec"`
+	MigrationId        string             `json:"migration-id"`
+	ModelName          string             `json:"model-name,omitempty"`
+	Attempt            int                `json:"attempt"`
+	Phase              string             `json:"phase"`
+	PhaseChangedTime   time.Time          `json:"phase-changed-time"`
+	ApprovalRef        string             `json:"approval-ref,omitempty"`
+	ReapRetryRequested bool               `json:"reap-retry-requested,omitempty"`
 }
 
 // PhasesResults holds the phase of one or more model migrations.
@@ -123,6 +210,11 @@ type MinionReport struct {
 	// Success is true if the agent successfully completed its actions
 	// for the migration phase, false otherwise.
 	Success bool `json:"success"`
+
+	// Error optionally holds a description of why the agent failed
+	// to complete the migration phase. It is only used when Success
+	// is false.
+	Error string `json:"error,omitempty"`
 }
 
 // MinionReports holds the details of whether a migration minion
@@ -150,4 +242,25 @@ type MinionReports struct {
 	// Failed contains the tags of all agents which have reported a
 	// failed to complete a given migration phase.
 	Failed []string `json:"failed"`
+
+	// FailureDetails holds the error reported by each failed agent
+	// which gave one, keyed by agent tag.
+	FailureDetails map[string]string `json:"failure-details,omitempty"`
+}
+
+// MinionFailureDetailsArgs holds the arguments for the
+// MigrationMaster.GetMinionFailureDetails API call.
+type MinionFailureDetailsArgs struct {
+	// Phase holds the migration phase to retrieve failure details
+	// for.
+	Phase string `json:"phase"`
+}
+
+// MinionFailureDetails holds the errors reported by migration
+// minions which failed a migration phase.
+type MinionFailureDetails struct {
+	// Details holds the error reported by each failed agent, keyed
+	// by agent tag. Agents which failed without giving a reason map
+	// to an empty string.
+	Details map[string]string `json:"details"`
 }
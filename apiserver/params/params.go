@@ -773,6 +773,7 @@ type LogRecord struct {
 	Location string    `json:"l"`
 	Level    string    `json:"v"`
 	Message  string    `json:"x"`
+	Entity   string    `json:"e,omitempty"`
 }
 
 // GetBundleChangesParams holds parameters for making GetBundleChanges calls.
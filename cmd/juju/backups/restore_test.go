@@ -61,7 +61,7 @@ func (s *restoreSuite) SetUpTest(c *gc.C) {
 	s.store.CurrentControllerName = "testing"
 	s.store.Models["testing"] = &jujuclient.ControllerModels{
 		Models: map[string]jujuclient.ModelDetails{
-			"admin": {"test1-uuid"},
+			"admin": {ModelUUID: "test1-uuid"},
 		},
 		CurrentModel: "admin",
 	}
@@ -42,66 +42,66 @@ func (s *listCredentialsSuite) SetUpSuite(c *gc.C) {
 
 func (s *listCredentialsSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
-	s.store = &jujuclienttesting.MemStore{
-		Credentials: map[string]jujucloud.CloudCredential{
-			"aws": {
-				DefaultRegion:     "ap-southeast-2",
-				DefaultCredential: "down",
-				AuthCredentials: map[string]jujucloud.Credential{
-					"bob": jujucloud.NewCredential(
-						jujucloud.AccessKeyAuthType,
-						map[string]string{
-							"access-key": "key",
-							"secret-key": "secret",
-						},
-					),
-					"down": jujucloud.NewCredential(
-						jujucloud.UserPassAuthType,
-						map[string]string{
-							"username": "user",
-							"password": "password",
-						},
-					),
-				},
+	store := jujuclienttesting.NewMemStore()
+	store.Credentials = map[string]jujucloud.CloudCredential{
+		"aws": {
+			DefaultRegion:     "ap-southeast-2",
+			DefaultCredential: "down",
+			AuthCredentials: map[string]jujucloud.Credential{
+				"bob": jujucloud.NewCredential(
+					jujucloud.AccessKeyAuthType,
+					map[string]string{
+						"access-key": "key",
+						"secret-key": "secret",
+					},
+				),
+				"down": jujucloud.NewCredential(
+					jujucloud.UserPassAuthType,
+					map[string]string{
+						"username": "user",
+						"password": "password",
+					},
+				),
 			},
-			"google": {
-				AuthCredentials: map[string]jujucloud.Credential{
-					"default": jujucloud.NewCredential(
-						jujucloud.OAuth2AuthType,
-						map[string]string{
-							"client-id":    "id",
-							"client-email": "email",
-							"private-key":  "key",
-						},
-					),
-				},
+		},
+		"google": {
+			AuthCredentials: map[string]jujucloud.Credential{
+				"default": jujucloud.NewCredential(
+					jujucloud.OAuth2AuthType,
+					map[string]string{
+						"client-id":    "id",
+						"client-email": "email",
+						"private-key":  "key",
+					},
+				),
 			},
-			"azure": {
-				AuthCredentials: map[string]jujucloud.Credential{
-					"azhja": jujucloud.NewCredential(
-						jujucloud.UserPassAuthType,
-						map[string]string{
-							"application-id":       "app-id",
-							"application-password": "app-secret",
-							"subscription-id":      "subscription-id",
-							"tenant-id":            "tenant-id",
-						},
-					),
-				},
+		},
+		"azure": {
+			AuthCredentials: map[string]jujucloud.Credential{
+				"azhja": jujucloud.NewCredential(
+					jujucloud.UserPassAuthType,
+					map[string]string{
+						"application-id":       "app-id",
+						"application-password": "app-secret",
+						"subscription-id":      "subscription-id",
+						"tenant-id":            "tenant-id",
+					},
+				),
 			},
-			"mycloud": {
-				AuthCredentials: map[string]jujucloud.Credential{
-					"me": jujucloud.NewCredential(
-						jujucloud.AccessKeyAuthType,
-						map[string]string{
-							"access-key": "key",
-							"secret-key": "secret",
-						},
-					),
-				},
+		},
+		"mycloud": {
+			AuthCredentials: map[string]jujucloud.Credential{
+				"me": jujucloud.NewCredential(
+					jujucloud.AccessKeyAuthType,
+					map[string]string{
+						"access-key": "key",
+						"secret-key": "secret",
+					},
+				),
 			},
 		},
 	}
+	s.store = store
 }
 
 func (s *listCredentialsSuite) TestListCredentialsTabular(c *gc.C) {
@@ -30,12 +30,11 @@ func (s *removeCredentialSuite) TestBadArgs(c *gc.C) {
 }
 
 func (s *removeCredentialSuite) TestMissingCredential(c *gc.C) {
-	store := &jujuclienttesting.MemStore{
-		Credentials: map[string]jujucloud.CloudCredential{
-			"aws": {
-				AuthCredentials: map[string]jujucloud.Credential{
-					"my-credential": jujucloud.NewCredential(jujucloud.AccessKeyAuthType, nil),
-				},
+	store := jujuclienttesting.NewMemStore()
+	store.Credentials = map[string]jujucloud.CloudCredential{
+		"aws": {
+			AuthCredentials: map[string]jujucloud.Credential{
+				"my-credential": jujucloud.NewCredential(jujucloud.AccessKeyAuthType, nil),
 			},
 		},
 	}
@@ -57,13 +56,12 @@ func (s *removeCredentialSuite) TestBadCloudName(c *gc.C) {
 }
 
 func (s *removeCredentialSuite) TestRemove(c *gc.C) {
-	store := &jujuclienttesting.MemStore{
-		Credentials: map[string]jujucloud.CloudCredential{
-			"aws": {
-				AuthCredentials: map[string]jujucloud.Credential{
-					"my-credential":      jujucloud.NewCredential(jujucloud.AccessKeyAuthType, nil),
-					"another-credential": jujucloud.NewCredential(jujucloud.AccessKeyAuthType, nil),
-				},
+	store := jujuclienttesting.NewMemStore()
+	store.Credentials = map[string]jujucloud.CloudCredential{
+		"aws": {
+			AuthCredentials: map[string]jujucloud.Credential{
+				"my-credential":      jujucloud.NewCredential(jujucloud.AccessKeyAuthType, nil),
+				"another-credential": jujucloud.NewCredential(jujucloud.AccessKeyAuthType, nil),
 			},
 		},
 	}
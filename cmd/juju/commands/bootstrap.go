@@ -13,6 +13,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/featureflag"
 	"github.com/juju/version"
+	"golang.org/x/net/context"
 	"gopkg.in/juju/charm.v6-unstable"
 	"launchpad.net/gnuflag"
 
@@ -520,7 +521,7 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 
 	// Set the current model to the initial hosted model.
 	if err := store.UpdateModel(c.controllerName, c.hostedModelName, jujuclient.ModelDetails{
-		hostedModelUUID.String(),
+		ModelUUID: hostedModelUUID.String(),
 	}); err != nil {
 		return errors.Trace(err)
 	}
@@ -561,8 +562,11 @@ to clean up the model.`[1:])
 		}
 	}()
 
-	// Block interruption during bootstrap. Providers may also
-	// register for interrupt notification so they can exit early.
+	// Block interruption during bootstrap. Instead, an interrupt
+	// cancels the bootstrap context, so that providers can exit
+	// early and clean up after themselves.
+	bootstrapCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	interrupted := make(chan os.Signal, 1)
 	defer close(interrupted)
 	ctx.InterruptNotify(interrupted)
@@ -570,6 +574,7 @@ to clean up the model.`[1:])
 	go func() {
 		for _ = range interrupted {
 			ctx.Infof("Interrupt signalled: waiting for bootstrap to exit")
+			cancel()
 		}
 	}()
 
@@ -637,7 +642,7 @@ to clean up the model.`[1:])
 		credentialName = detectedCredentialName
 	}
 
-	err = bootstrapFuncs.Bootstrap(modelcmd.BootstrapContext(ctx), environ, bootstrap.BootstrapParams{
+	err = bootstrapFuncs.Bootstrap(modelcmd.CancellableBootstrapContext(bootstrapCtx, ctx), environ, bootstrap.BootstrapParams{
 		ModelConstraints:          c.Constraints,
 		BootstrapConstraints:      bootstrapConstraints,
 		BootstrapSeries:           c.BootstrapSeries,
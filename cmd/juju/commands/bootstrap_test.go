@@ -566,7 +566,7 @@ func (s *BootstrapSuite) TestBootstrapFailToPrepareDiesGracefully(c *gc.C) {
 func (s *BootstrapSuite) writeControllerModelAccountInfo(c *gc.C, controller, model, user string) {
 	err := s.store.UpdateController(controller, jujuclient.ControllerDetails{
 		CACert:         "x",
-		ControllerUUID: "y",
+		ControllerUUID: "6b3a2c1d-8e7f-4a5b-9c0d-1e2f3a4b5c6d",
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	err = s.store.SetCurrentController(controller)
@@ -1231,7 +1231,7 @@ func (s *BootstrapSuite) TestBootstrapSetsControllerOnBase(c *gc.C) {
 	// bootstrap. Changing the current controller shouldn't affect the
 	// bootstrap process.
 	c.Assert(s.store.UpdateController("another", jujuclient.ControllerDetails{
-		ControllerUUID: "uuid",
+		ControllerUUID: "7c4b3d2e-9f8a-4b6c-8d1e-2f3a4b5c6d7e",
 		CACert:         "cert",
 	}), jc.ErrorIsNil)
 	c.Assert(s.store.SetCurrentController("another"), jc.ErrorIsNil)
@@ -202,7 +202,7 @@ func (c *addModelCommand) Run(ctx *cmd.Context) error {
 	if modelOwner == accountDetails.User {
 		controllerName := c.ControllerName()
 		if err := store.UpdateModel(controllerName, c.Name, jujuclient.ModelDetails{
-			model.UUID,
+			ModelUUID: model.UUID,
 		}); err != nil {
 			return errors.Trace(err)
 		}
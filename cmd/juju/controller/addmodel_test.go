@@ -146,7 +146,7 @@ func (s *addSuite) TestAddExistingName(c *gc.C) {
 	// means we'll replace any stale details from an previously existing
 	// model with the same name.
 	err := s.store.UpdateModel("test-master", "test", jujuclient.ModelDetails{
-		"stale-uuid",
+		ModelUUID: "stale-uuid",
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -155,7 +155,7 @@ func (s *addSuite) TestAddExistingName(c *gc.C) {
 
 	details, err := s.store.ModelByName("test-master", "test")
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(details, jc.DeepEquals, &jujuclient.ModelDetails{"fake-model-uuid"})
+	c.Assert(details.ModelUUID, gc.Equals, "fake-model-uuid")
 }
 
 func (s *addSuite) TestCredentialsPassedThrough(c *gc.C) {
@@ -285,7 +285,7 @@ func (s *addSuite) TestAddStoresValues(c *gc.C) {
 
 	model, err := s.store.ModelByName("test-master", "test")
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(model, jc.DeepEquals, &jujuclient.ModelDetails{"fake-model-uuid"})
+	c.Assert(model.ModelUUID, gc.Equals, "fake-model-uuid")
 }
 
 func (s *addSuite) TestNoEnvCacheOtherUser(c *gc.C) {
@@ -176,7 +176,7 @@ func (s *baseDestroySuite) SetUpTest(c *gc.C) {
 		controllerName, modelName := modelcmd.SplitModelName(model.name)
 		s.store.UpdateController(controllerName, jujuclient.ControllerDetails{
 			ControllerUUID: model.serverUUID,
-			APIEndpoints:   []string{"localhost"},
+			APIEndpoints:   []string{"localhost:17070"},
 			CACert:         testing.CACert,
 		})
 		s.store.UpdateModel(controllerName, modelName, jujuclient.ModelDetails{
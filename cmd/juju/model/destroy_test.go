@@ -52,8 +52,8 @@ func (s *DestroySuite) SetUpTest(c *gc.C) {
 	s.store.Controllers["test1"] = jujuclient.ControllerDetails{ControllerUUID: "test1-uuid"}
 	s.store.Models["test1"] = &jujuclient.ControllerModels{
 		Models: map[string]jujuclient.ModelDetails{
-			"test1": {"test1-uuid"},
-			"test2": {"test2-uuid"},
+			"test1": {ModelUUID: "test1-uuid"},
+			"test2": {ModelUUID: "test2-uuid"},
 		},
 	}
 	s.store.Accounts["test1"] = jujuclient.AccountDetails{
@@ -133,8 +133,8 @@ func (s *DestroySuite) TestFailedDestroyModel(c *gc.C) {
 func (s *DestroySuite) resetModel(c *gc.C) {
 	s.store.Models["test1"] = &jujuclient.ControllerModels{
 		Models: map[string]jujuclient.ModelDetails{
-			"test1": {"test1-uuid"},
-			"test2": {"test2-uuid"},
+			"test1": {ModelUUID: "test1-uuid"},
+			"test2": {ModelUUID: "test2-uuid"},
 		},
 	}
 }
@@ -47,11 +47,11 @@ func (s *grantRevokeSuite) SetUpTest(c *gc.C) {
 	s.store.Models = map[string]*jujuclient.ControllerModels{
 		controllerName: {
 			Models: map[string]jujuclient.ModelDetails{
-				"foo":    jujuclient.ModelDetails{fooModelUUID},
-				"bar":    jujuclient.ModelDetails{barModelUUID},
-				"baz":    jujuclient.ModelDetails{bazModelUUID},
-				"model1": jujuclient.ModelDetails{model1ModelUUID},
-				"model2": jujuclient.ModelDetails{model2ModelUUID},
+				"foo":    jujuclient.ModelDetails{ModelUUID: fooModelUUID},
+				"bar":    jujuclient.ModelDetails{ModelUUID: barModelUUID},
+				"baz":    jujuclient.ModelDetails{ModelUUID: bazModelUUID},
+				"model1": jujuclient.ModelDetails{ModelUUID: model1ModelUUID},
+				"model2": jujuclient.ModelDetails{ModelUUID: model2ModelUUID},
 			},
 		},
 	}
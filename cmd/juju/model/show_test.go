@@ -121,7 +121,7 @@ func (s *ShowCommandSuite) SetUpTest(c *gc.C) {
 		User: "admin@local",
 	}
 	err := s.store.UpdateModel("testing", "mymodel", jujuclient.ModelDetails{
-		testing.ModelTag.Id(),
+		ModelUUID: testing.ModelTag.Id(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.store.Models["testing"].CurrentModel = "mymodel"
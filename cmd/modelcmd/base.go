@@ -182,7 +182,7 @@ func (c *JujuCommandBase) RefreshModels(store jujuclient.ClientStore, controller
 		return errors.Trace(err)
 	}
 	for _, model := range models {
-		modelDetails := jujuclient.ModelDetails{model.UUID}
+		modelDetails := jujuclient.ModelDetails{ModelUUID: model.UUID}
 		if err := store.UpdateModel(controllerName, model.Name, modelDetails); err != nil {
 			return errors.Trace(err)
 		}
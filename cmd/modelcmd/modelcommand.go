@@ -11,6 +11,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"golang.org/x/net/context"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
@@ -304,6 +305,7 @@ func (w *modelCommandWrapper) Init(args []string) error {
 
 type bootstrapContext struct {
 	*cmd.Context
+	ctx               context.Context
 	verifyCredentials bool
 }
 
@@ -312,10 +314,22 @@ func (ctx *bootstrapContext) ShouldVerifyCredentials() bool {
 	return ctx.verifyCredentials
 }
 
+// StdContext implements BootstrapContext.StdContext
+func (ctx *bootstrapContext) StdContext() context.Context {
+	return ctx.ctx
+}
+
 // BootstrapContext returns a new BootstrapContext constructed from a command Context.
 func BootstrapContext(cmdContext *cmd.Context) environs.BootstrapContext {
+	return CancellableBootstrapContext(context.Background(), cmdContext)
+}
+
+// CancellableBootstrapContext returns a new BootstrapContext constructed from
+// a command Context, which is cancelled when the supplied context is.
+func CancellableBootstrapContext(ctx context.Context, cmdContext *cmd.Context) environs.BootstrapContext {
 	return &bootstrapContext{
 		Context:           cmdContext,
+		ctx:               ctx,
 		verifyCredentials: true,
 	}
 }
@@ -325,6 +339,7 @@ func BootstrapContext(cmdContext *cmd.Context) environs.BootstrapContext {
 func BootstrapContextNoVerify(cmdContext *cmd.Context) environs.BootstrapContext {
 	return &bootstrapContext{
 		Context:           cmdContext,
+		ctx:               context.Background(),
 		verifyCredentials: false,
 	}
 }
@@ -54,7 +54,7 @@ func (s *ModelCommandSuite) TestGetCurrentModelCurrentControllerNoCurrentModel(c
 }
 
 func (s *ModelCommandSuite) TestGetCurrentModelCurrentControllerModel(c *gc.C) {
-	err := s.store.UpdateModel("foo", "mymodel", jujuclient.ModelDetails{"uuid"})
+	err := s.store.UpdateModel("foo", "mymodel", jujuclient.ModelDetails{ModelUUID: "uuid"})
 	c.Assert(err, jc.ErrorIsNil)
 	err = s.store.SetCurrentModel("foo", "mymodel")
 	c.Assert(err, jc.ErrorIsNil)
@@ -74,7 +74,7 @@ func (s *ModelCommandSuite) TestGetCurrentModelJujuEnvSet(c *gc.C) {
 func (s *ModelCommandSuite) TestGetCurrentModelBothSet(c *gc.C) {
 	os.Setenv(osenv.JujuModelEnvKey, "magic")
 
-	err := s.store.UpdateModel("foo", "mymodel", jujuclient.ModelDetails{"uuid"})
+	err := s.store.UpdateModel("foo", "mymodel", jujuclient.ModelDetails{ModelUUID: "uuid"})
 	c.Assert(err, jc.ErrorIsNil)
 	err = s.store.SetCurrentModel("foo", "mymodel")
 	c.Assert(err, jc.ErrorIsNil)
@@ -95,7 +95,7 @@ func (s *ModelCommandSuite) TestModelCommandInitExplicitLongForm(c *gc.C) {
 }
 
 func (s *ModelCommandSuite) TestModelCommandInitEnvFile(c *gc.C) {
-	err := s.store.UpdateModel("foo", "mymodel", jujuclient.ModelDetails{"uuid"})
+	err := s.store.UpdateModel("foo", "mymodel", jujuclient.ModelDetails{ModelUUID: "uuid"})
 	c.Assert(err, jc.ErrorIsNil)
 	err = s.store.SetCurrentModel("foo", "mymodel")
 	c.Assert(err, jc.ErrorIsNil)
@@ -207,7 +207,7 @@ func (s *macaroonLoginSuite) SetUpTest(c *gc.C) {
 	}
 	s.store.Models[s.controllerName] = &jujuclient.ControllerModels{
 		Models: map[string]jujuclient.ModelDetails{
-			s.modelName: {modelTag.Id()},
+			s.modelName: {ModelUUID: modelTag.Id()},
 		},
 	}
 }
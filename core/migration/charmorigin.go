@@ -0,0 +1,62 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// CharmOrigin identifies where a charm used by a migrated model came
+// from, so that the target controller can resolve it correctly.
+type CharmOrigin string
+
+const (
+	// CharmStoreOrigin is the origin of charms from the charm store,
+	// with "cs:" URLs.
+	CharmStoreOrigin CharmOrigin = "charmstore"
+
+	// LocalOrigin is the origin of charms deployed from the client's
+	// filesystem, with "local:" URLs.
+	LocalOrigin CharmOrigin = "local"
+
+	// CharmHubOrigin is the origin of charms from charmhub, with
+	// "ch:" URLs.
+	CharmHubOrigin CharmOrigin = "charmhub"
+)
+
+// CharmOriginForURL returns the origin of the charm with the given
+// URL, based on its schema. It returns "" if the origin isn't known.
+func CharmOriginForURL(curl string) CharmOrigin {
+	schema := curl
+	if i := strings.Index(curl, ":"); i >= 0 {
+		schema = curl[:i]
+	}
+	switch schema {
+	case "cs":
+		return CharmStoreOrigin
+	case "local":
+		return LocalOrigin
+	case "ch":
+		return CharmHubOrigin
+	}
+	return ""
+}
+
+// CheckCharmOrigins returns an error if any of the given charms has
+// no recorded origin, or an origin which doesn't agree with its URL.
+// Such charms can't be resolved correctly by the target controller.
+func CheckCharmOrigins(charms []string, origins map[string]CharmOrigin) error {
+	for _, curl := range charms {
+		origin, ok := origins[curl]
+		if !ok || origin == "" {
+			return errors.NotValidf("charm %q with unknown origin", curl)
+		}
+		if expect := CharmOriginForURL(curl); origin != expect {
+			return errors.NotValidf("charm %q with origin %q", curl, origin)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/migration"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CharmOriginSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(new(CharmOriginSuite))
+
+func (s *CharmOriginSuite) TestCharmOriginForURL(c *gc.C) {
+	for curl, expect := range map[string]migration.CharmOrigin{
+		"cs:trusty/mysql-42":  migration.CharmStoreOrigin,
+		"cs:~bob/xenial/foo":  migration.CharmStoreOrigin,
+		"local:trusty/magic":  migration.LocalOrigin,
+		"ch:amd64/xenial/foo": migration.CharmHubOrigin,
+		"http:example.com":    "",
+		"mysql":               "",
+	} {
+		c.Check(migration.CharmOriginForURL(curl), gc.Equals, expect, gc.Commentf("%s", curl))
+	}
+}
+
+func (s *CharmOriginSuite) TestCheckCharmOrigins(c *gc.C) {
+	err := migration.CheckCharmOrigins(
+		[]string{"cs:trusty/mysql-42", "local:trusty/magic", "ch:amd64/xenial/foo"},
+		map[string]migration.CharmOrigin{
+			"cs:trusty/mysql-42":  migration.CharmStoreOrigin,
+			"local:trusty/magic":  migration.LocalOrigin,
+			"ch:amd64/xenial/foo": migration.CharmHubOrigin,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmOriginSuite) TestCheckCharmOriginsMissing(c *gc.C) {
+	err := migration.CheckCharmOrigins(
+		[]string{"cs:trusty/mysql-42", "local:trusty/magic"},
+		map[string]migration.CharmOrigin{
+			"cs:trusty/mysql-42": migration.CharmStoreOrigin,
+		},
+	)
+	c.Assert(err, gc.ErrorMatches, `charm "local:trusty/magic" with unknown origin not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *CharmOriginSuite) TestCheckCharmOriginsMismatch(c *gc.C) {
+	err := migration.CheckCharmOrigins(
+		[]string{"local:trusty/magic"},
+		map[string]migration.CharmOrigin{
+			"local:trusty/magic": migration.CharmStoreOrigin,
+		},
+	)
+	c.Assert(err, gc.ErrorMatches, `charm "local:trusty/magic" with origin "charmstore" not valid`)
+}
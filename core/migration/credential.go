@@ -0,0 +1,55 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"gopkg.in/juju/names.v2"
+)
+
+// CloudCredential holds the details of the cloud credential used by
+// a model being migrated.
+type CloudCredential struct {
+	// Owner holds the tag of the user who owns the credential.
+	Owner names.UserTag
+
+	// Cloud holds the name of the cloud the credential is for.
+	Cloud string
+
+	// Name holds the name of the credential.
+	Name string
+
+	// AuthType holds the authentication type of the credential.
+	AuthType string
+
+	// Attributes holds the credential's (possibly secret) attributes.
+	Attributes map[string]string
+}
+
+// IsZero returns true if the CloudCredential hasn't been set. Models
+// which don't use a credential will have a zero CloudCredential.
+func (c *CloudCredential) IsZero() bool {
+	return c.Name == ""
+}
+
+// Hash returns a hex encoded SHA256 hash of the credential's
+// content. The hash is independent of attribute ordering and can be
+// used to determine whether two credentials are identical without
+// comparing their secret attributes directly.
+func (c *CloudCredential) Hash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", c.Owner.Id(), c.Cloud, c.Name, c.AuthType)
+	keys := make([]string, 0, len(c.Attributes))
+	for k := range c.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\x00", k, c.Attributes[k])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
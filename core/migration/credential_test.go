@@ -0,0 +1,64 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/core/migration"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CloudCredentialSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(new(CloudCredentialSuite))
+
+func makeCredential() migration.CloudCredential {
+	return migration.CloudCredential{
+		Owner:    names.NewUserTag("bob"),
+		Cloud:    "aws",
+		Name:     "default",
+		AuthType: "access-key",
+		Attributes: map[string]string{
+			"access-key": "key",
+			"secret-key": "secret",
+		},
+	}
+}
+
+func (s *CloudCredentialSuite) TestIsZero(c *gc.C) {
+	var cred migration.CloudCredential
+	c.Check(cred.IsZero(), jc.IsTrue)
+	cred = makeCredential()
+	c.Check(cred.IsZero(), jc.IsFalse)
+}
+
+func (s *CloudCredentialSuite) TestHashStable(c *gc.C) {
+	cred0 := makeCredential()
+	cred1 := makeCredential()
+	c.Check(cred0.Hash(), gc.Equals, cred1.Hash())
+	c.Check(cred0.Hash(), gc.HasLen, 64)
+}
+
+func (s *CloudCredentialSuite) TestHashChanges(c *gc.C) {
+	base := makeCredential()
+	tweaks := []func(*migration.CloudCredential){
+		func(cred *migration.CloudCredential) { cred.Owner = names.NewUserTag("mary") },
+		func(cred *migration.CloudCredential) { cred.Cloud = "gce" },
+		func(cred *migration.CloudCredential) { cred.Name = "other" },
+		func(cred *migration.CloudCredential) { cred.AuthType = "userpass" },
+		func(cred *migration.CloudCredential) { cred.Attributes["secret-key"] = "changed" },
+		func(cred *migration.CloudCredential) { cred.Attributes["extra"] = "" },
+	}
+	for i, tweak := range tweaks {
+		c.Logf("test %d", i)
+		cred := makeCredential()
+		tweak(&cred)
+		c.Check(cred.Hash(), gc.Not(gc.Equals), base.Hash())
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/juju/network"
+)
+
+// FirewallRule describes a firewall rule in use by a model which
+// needs to be recreated by the target controller's provider.
+type FirewallRule struct {
+	// Ports holds the port range the rule allows ingress to.
+	Ports network.PortRange
+
+	// SourceCIDRs holds the CIDRs ingress is allowed from. An empty
+	// slice means ingress is allowed from anywhere.
+	SourceCIDRs []string
+}
+
+// String returns a human readable representation of the rule.
+func (r FirewallRule) String() string {
+	if len(r.SourceCIDRs) == 0 {
+		return r.Ports.String()
+	}
+	return fmt.Sprintf("%s from %s", r.Ports, strings.Join(r.SourceCIDRs, ","))
+}
+
+// UntranslatedFirewallRule describes a firewall rule which the target
+// controller's provider was unable to recreate.
+type UntranslatedFirewallRule struct {
+	// Rule holds the rule which couldn't be translated.
+	Rule FirewallRule
+
+	// Reason explains why the rule couldn't be translated.
+	Reason string
+}
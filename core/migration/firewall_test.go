@@ -0,0 +1,28 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/migration"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type FirewallRuleSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(new(FirewallRuleSuite))
+
+func (s *FirewallRuleSuite) TestString(c *gc.C) {
+	rule := migration.FirewallRule{
+		Ports: network.MustParsePortRange("80-90/tcp"),
+	}
+	c.Check(rule.String(), gc.Equals, "80-90/tcp")
+
+	rule.SourceCIDRs = []string{"10.0.0.0/8", "192.168.0.0/16"}
+	c.Check(rule.String(), gc.Equals, "80-90/tcp from 10.0.0.0/8,192.168.0.0/16")
+}
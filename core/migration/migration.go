@@ -7,14 +7,22 @@ import (
 	"time"
 
 	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
 )
 
 // MigrationStatus returns the details for a migration as needed by
 // the migration master worker.
 type MigrationStatus struct {
+	// MigrationId holds the unique id of the migration. Minion
+	// reports carry the id of the migration they relate to.
+	MigrationId string
+
 	// ModelUUID holds the UUID of the model being migrated.
 	ModelUUID string
 
+	// ModelName holds the name of the model being migrated.
+	ModelName string
+
 	// Attempt specifies the migration attempt number. This is
 	// incremeted for each attempt to migrate a model.
 	Attempt int
@@ -29,6 +37,25 @@ type MigrationStatus struct {
 	// TargetInfo contains the details of how to connect to the target
 	// controller.
 	TargetInfo TargetInfo
+
+	// ApprovalRef holds the external change ticket reference recorded
+	// when the migration was approved while in the HOLD phase. It is
+	// empty if the migration hasn't been approved.
+	ApprovalRef string
+
+	// ReapRetryRequested indicates that an operator has asked for
+	// the removal of the migrated model to be retried while the
+	// migration is in the REAPFAILED phase.
+	ReapRetryRequested bool
+}
+
+// ModelInfo is used to report basic details about a model, so that
+// the target controller can check that it's able to accept it.
+type ModelInfo struct {
+	UUID         string
+	Owner        names.UserTag
+	Name         string
+	AgentVersion version.Number
 }
 
 // SerializedModel wraps a buffer contain a serialised Juju model as
@@ -41,8 +68,25 @@ type SerializedModel struct {
 	// Charms lists the charm URLs in use in the model.
 	Charms []string
 
+	// CharmOrigins records where each of the model's charms came
+	// from, keyed by charm URL.
+	CharmOrigins map[string]CharmOrigin
+
+	// CharmSHA256s holds the SHA256 sums of the model's charm
+	// archives, keyed by charm URL, so that they can be checked once
+	// uploaded to the target controller.
+	CharmSHA256s map[string]string
+
 	// Tools lists the tools versions in use with the model along with
 	// their URIs. The URIs can be used to download the tools from the
 	// source controller.
 	Tools map[version.Binary]string // version -> tools URI
+
+	// ToolsSHA256s holds the SHA256 sums of the tools tarballs in use
+	// with the model, where known.
+	ToolsSHA256s map[version.Binary]string
+
+	// FirewallRules lists the firewall rules which need to be
+	// recreated for the model by the target controller.
+	FirewallRules []FirewallRule
 }
@@ -35,6 +35,11 @@ type MinionReports struct {
 	// FailedUnits holds the names of units which have failed to
 	// complete the migration phase.
 	FailedUnits []string
+
+	// FailureDetails holds the failure descriptions reported by
+	// failed agents, keyed by machine id or unit name. Agents which
+	// failed without giving a reason are not included.
+	FailureDetails map[string]string
 }
 
 // IsZero returns true if the MinionReports instance hasn't been set.
@@ -22,6 +22,8 @@ const (
 	DONE
 	ABORT
 	ABORTDONE
+	HOLD
+	DRYRUNDONE
 )
 
 var phaseNames = []string{
@@ -39,6 +41,8 @@ var phaseNames = []string{
 	"DONE",
 	"ABORT",
 	"ABORTDONE",
+	"HOLD",
+	"DRYRUNDONE", // A dry run passed PRECHECK; nothing was imported.
 }
 
 // String returns the name of an model migration phase constant.
@@ -85,7 +89,7 @@ func (p Phase) IsRunning() bool {
 		return false
 	}
 	switch p {
-	case QUIESCE, READONLY, PRECHECK, IMPORT, VALIDATION, SUCCESS:
+	case QUIESCE, READONLY, PRECHECK, IMPORT, VALIDATION, HOLD, SUCCESS:
 		return true
 	default:
 		return false
@@ -99,12 +103,14 @@ func (p Phase) IsRunning() bool {
 var validTransitions = map[Phase][]Phase{
 	QUIESCE:     {READONLY, ABORT},
 	READONLY:    {PRECHECK, ABORT},
-	PRECHECK:    {IMPORT, ABORT},
+	PRECHECK:    {IMPORT, ABORT, DRYRUNDONE},
 	IMPORT:      {VALIDATION, ABORT},
-	VALIDATION:  {SUCCESS, ABORT},
+	VALIDATION:  {SUCCESS, HOLD, ABORT},
+	HOLD:        {SUCCESS, ABORT},
 	SUCCESS:     {LOGTRANSFER},
 	LOGTRANSFER: {REAP},
 	REAP:        {DONE, REAPFAILED},
+	REAPFAILED:  {REAP},
 	ABORT:       {ABORTDONE},
 }
 
@@ -51,8 +51,9 @@ func (s *PhaseSuite) TestIsTerminal(c *gc.C) {
 	c.Check(migration.SUCCESS.IsTerminal(), jc.IsFalse)
 	c.Check(migration.ABORT.IsTerminal(), jc.IsFalse)
 	c.Check(migration.ABORTDONE.IsTerminal(), jc.IsTrue)
-	c.Check(migration.REAPFAILED.IsTerminal(), jc.IsTrue)
+	c.Check(migration.REAPFAILED.IsTerminal(), jc.IsFalse)
 	c.Check(migration.DONE.IsTerminal(), jc.IsTrue)
+	c.Check(migration.DRYRUNDONE.IsTerminal(), jc.IsTrue)
 }
 
 func (s *PhaseSuite) TestIsRunning(c *gc.C) {
@@ -61,6 +62,7 @@ func (s *PhaseSuite) TestIsRunning(c *gc.C) {
 
 	c.Check(migration.QUIESCE.IsRunning(), jc.IsTrue)
 	c.Check(migration.IMPORT.IsRunning(), jc.IsTrue)
+	c.Check(migration.HOLD.IsRunning(), jc.IsTrue)
 	c.Check(migration.SUCCESS.IsRunning(), jc.IsTrue)
 
 	c.Check(migration.LOGTRANSFER.IsRunning(), jc.IsFalse)
@@ -69,6 +71,7 @@ func (s *PhaseSuite) TestIsRunning(c *gc.C) {
 	c.Check(migration.DONE.IsRunning(), jc.IsFalse)
 	c.Check(migration.ABORT.IsRunning(), jc.IsFalse)
 	c.Check(migration.ABORTDONE.IsRunning(), jc.IsFalse)
+	c.Check(migration.DRYRUNDONE.IsRunning(), jc.IsFalse)
 }
 
 func (s *PhaseSuite) TestCanTransitionTo(c *gc.C) {
@@ -78,4 +81,12 @@ func (s *PhaseSuite) TestCanTransitionTo(c *gc.C) {
 	c.Check(migration.QUIESCE.CanTransitionTo(migration.Phase(-1)), jc.IsFalse)
 
 	c.Check(migration.ABORT.CanTransitionTo(migration.QUIESCE), jc.IsFalse)
+
+	c.Check(migration.VALIDATION.CanTransitionTo(migration.HOLD), jc.IsTrue)
+	c.Check(migration.HOLD.CanTransitionTo(migration.SUCCESS), jc.IsTrue)
+	c.Check(migration.HOLD.CanTransitionTo(migration.ABORT), jc.IsTrue)
+	c.Check(migration.HOLD.CanTransitionTo(migration.VALIDATION), jc.IsFalse)
+
+	c.Check(migration.PRECHECK.CanTransitionTo(migration.DRYRUNDONE), jc.IsTrue)
+	c.Check(migration.VALIDATION.CanTransitionTo(migration.DRYRUNDONE), jc.IsFalse)
 }
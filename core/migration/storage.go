@@ -0,0 +1,22 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import "fmt"
+
+// StoragePool describes a storage pool which is in use by a model
+// being migrated.
+type StoragePool struct {
+	// Name holds the name of the pool.
+	Name string
+
+	// Provider holds the type of the storage provider backing the
+	// pool, e.g. "ebs" or "rootfs".
+	Provider string
+}
+
+// String implements fmt.Stringer.
+func (p StoragePool) String() string {
+	return fmt.Sprintf("%s (%s)", p.Name, p.Provider)
+}
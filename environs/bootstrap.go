@@ -8,6 +8,8 @@ import (
 	"os"
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
@@ -114,4 +116,10 @@ type BootstrapContext interface {
 	// ShouldVerifyCredentials indicates whether the caller's cloud
 	// credentials should be verified.
 	ShouldVerifyCredentials() bool
+
+	// StdContext returns the context in which the bootstrap runs.
+	// When it is cancelled, providers should abort the bootstrap,
+	// stop any instances they have started, and return the context's
+	// error.
+	StdContext() context.Context
 }
@@ -4,6 +4,8 @@
 package bootstrap
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/cloud"
@@ -212,6 +214,7 @@ func prepare(
 	details.CloudEndpoint = args.CloudEndpoint
 	details.CloudStorageEndpoint = args.CloudStorageEndpoint
 	details.Credential = args.CredentialName
+	details.BootstrapTime = time.Now().UTC()
 
 	return env, details, nil
 }
@@ -4,6 +4,8 @@
 package bootstrap_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -75,6 +77,8 @@ func (*PrepareSuite) TestPrepare(c *gc.C) {
 	// Check that bootstrap config was written
 	bootstrapCfg, err := controllerStore.BootstrapConfigForController(cfg.Name())
 	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bootstrapCfg.BootstrapTime.IsZero(), jc.IsFalse)
+	bootstrapCfg.BootstrapTime = time.Time{}
 	c.Assert(bootstrapCfg, jc.DeepEquals, &jujuclient.BootstrapConfig{
 		ControllerConfig: controller.Config{
 			controller.ApiPort:                 17777,
@@ -0,0 +1,48 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+// ProviderCapabilities describes the optional features supported by
+// a provider, so that callers need not special-case providers by type.
+// The zero value describes a provider with none of the features.
+type ProviderCapabilities struct {
+	// Spaces reports whether the provider supports network spaces.
+	Spaces bool
+
+	// SpaceDiscovery reports whether the provider can report the
+	// spaces defined in the cloud.
+	SpaceDiscovery bool
+
+	// AvailabilityZones reports whether instances may be placed in
+	// availability zones.
+	AvailabilityZones bool
+
+	// AvailabilitySets reports whether the provider groups the
+	// instances of an application into availability sets, for
+	// fault tolerance in clouds without availability zones.
+	AvailabilitySets bool
+
+	// ManagedDisks reports whether the provider can use disks that
+	// are managed by the cloud, rather than stored in storage
+	// accounts created by Juju.
+	ManagedDisks bool
+}
+
+// CapabilitiesProvider is implemented by providers which can describe
+// the optional features that they support.
+type CapabilitiesProvider interface {
+	// Capabilities returns the optional features supported by
+	// the provider.
+	Capabilities() ProviderCapabilities
+}
+
+// Capabilities returns the optional features supported by the given
+// provider. If the provider doesn't describe its features, the zero
+// ProviderCapabilities is returned.
+func Capabilities(p EnvironProvider) ProviderCapabilities {
+	if cp, ok := p.(CapabilitiesProvider); ok {
+		return cp.Capabilities()
+	}
+	return ProviderCapabilities{}
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CapabilitiesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&CapabilitiesSuite{})
+
+// capableProvider is an environs.CapabilitiesProvider reporting
+// fixed capabilities.
+type capableProvider struct {
+	environs.EnvironProvider
+	capabilities environs.ProviderCapabilities
+}
+
+func (p capableProvider) Capabilities() environs.ProviderCapabilities {
+	return p.capabilities
+}
+
+func (s *CapabilitiesSuite) TestCapabilities(c *gc.C) {
+	capabilities := environs.ProviderCapabilities{
+		Spaces:            true,
+		AvailabilityZones: true,
+	}
+	p := capableProvider{capabilities: capabilities}
+	c.Assert(environs.Capabilities(p), gc.Equals, capabilities)
+}
+
+func (s *CapabilitiesSuite) TestCapabilitiesNotDescribed(c *gc.C) {
+	var p struct{ environs.EnvironProvider }
+	c.Assert(environs.Capabilities(p), gc.Equals, environs.ProviderCapabilities{})
+}
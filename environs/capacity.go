@@ -0,0 +1,59 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+)
+
+// CapacityReserver is implemented by environments which can reserve
+// capacity for instances ahead of time, so that a later burst of
+// StartInstance calls is guaranteed to be satisfied.
+type CapacityReserver interface {
+	// ReserveCapacity reserves capacity for count instances of the
+	// specified instance type in the specified availability zone. If
+	// zone is empty, the cloud chooses the zone. The returned
+	// reservation ID identifies the reservation in the cloud, and
+	// remains valid after the Environ is closed, so it may be
+	// recorded and passed to ReleaseCapacity later.
+	ReserveCapacity(instanceType string, count int, zone string) (reservationID string, _ error)
+
+	// ReleaseCapacity releases the reservation with the specified ID.
+	// If there is no such reservation, an error satisfying
+	// errors.IsNotFound is returned.
+	ReleaseCapacity(reservationID string) error
+}
+
+// ReserveCapacity reserves capacity for instances in the environment,
+// as described by CapacityReserver. If the environment doesn't
+// support reserving capacity, an error satisfying
+// errors.IsNotSupported is returned.
+func ReserveCapacity(env Environ, instanceType string, count int, zone string) (string, error) {
+	reserver, ok := env.(CapacityReserver)
+	if !ok {
+		return "", errors.NotSupportedf("reserving capacity")
+	}
+	if instanceType == "" {
+		return "", errors.NotValidf("empty instance type")
+	}
+	if count <= 0 {
+		return "", errors.NotValidf("instance count %d", count)
+	}
+	reservationID, err := reserver.ReserveCapacity(instanceType, count, zone)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return reservationID, nil
+}
+
+// ReleaseCapacity releases a reservation made with ReserveCapacity.
+// If the environment doesn't support reserving capacity, an error
+// satisfying errors.IsNotSupported is returned.
+func ReleaseCapacity(env Environ, reservationID string) error {
+	reserver, ok := env.(CapacityReserver)
+	if !ok {
+		return errors.NotSupportedf("reserving capacity")
+	}
+	return errors.Trace(reserver.ReleaseCapacity(reservationID))
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CapacitySuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&CapacitySuite{})
+
+func (s *CapacitySuite) TestReserveAndRelease(c *gc.C) {
+	env := newStubEnviron()
+	id, err := environs.ReserveCapacity(env, "Standard_D1", 10, "zone-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "reservation-1")
+	c.Assert(env.reservations, jc.DeepEquals, map[string]int{"reservation-1": 10})
+
+	err = environs.ReleaseCapacity(env, id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.reservations, gc.HasLen, 0)
+	env.CheckCalls(c, []jujutesting.StubCall{
+		{"ReserveCapacity", []interface{}{"Standard_D1", 10, "zone-1"}},
+		{"ReleaseCapacity", []interface{}{"reservation-1"}},
+	})
+}
+
+func (s *CapacitySuite) TestReserveCapacityError(c *gc.C) {
+	env := newStubEnviron()
+	env.SetErrors(errors.New("quota exceeded"))
+	_, err := environs.ReserveCapacity(env, "Standard_D1", 10, "")
+	c.Assert(err, gc.ErrorMatches, "quota exceeded")
+	c.Assert(env.reservations, gc.HasLen, 0)
+}
+
+func (s *CapacitySuite) TestReserveCapacityInvalid(c *gc.C) {
+	env := newStubEnviron()
+	_, err := environs.ReserveCapacity(env, "", 10, "")
+	c.Assert(err, gc.ErrorMatches, "empty instance type not valid")
+	_, err = environs.ReserveCapacity(env, "Standard_D1", 0, "")
+	c.Assert(err, gc.ErrorMatches, "instance count 0 not valid")
+	env.CheckNoCalls(c)
+}
+
+func (s *CapacitySuite) TestReleaseCapacityNotFound(c *gc.C) {
+	env := newStubEnviron()
+	err := environs.ReleaseCapacity(env, "reservation-42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CapacitySuite) TestNotSupported(c *gc.C) {
+	env := struct{ environs.Environ }{}
+	_, err := environs.ReserveCapacity(env, "Standard_D1", 10, "")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "reserving capacity not supported")
+	err = environs.ReleaseCapacity(env, "reservation-1")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,38 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+)
+
+// ConsoleLogger is implemented by environments which can retrieve the
+// console or serial output of an instance. This is most useful when
+// an instance never becomes reachable, for example because cloud-init
+// failed.
+type ConsoleLogger interface {
+	// InstanceConsoleLog returns the console output recorded for the
+	// instance with the specified ID. If there is no such instance,
+	// or no output has been recorded for it, an error satisfying
+	// errors.IsNotFound is returned.
+	InstanceConsoleLog(id instance.Id) ([]byte, error)
+}
+
+// InstanceConsoleLog returns the console output of the instance with
+// the specified ID in the environment. If the environment doesn't
+// support retrieving console output, an error satisfying
+// errors.IsNotSupported is returned.
+func InstanceConsoleLog(env Environ, id instance.Id) ([]byte, error) {
+	consoleLogger, ok := env.(ConsoleLogger)
+	if !ok {
+		return nil, errors.NotSupportedf("retrieving console logs")
+	}
+	output, err := consoleLogger.InstanceConsoleLog(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return output, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConsoleLogSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ConsoleLogSuite{})
+
+const sampleConsoleLog = `[    0.000000] Linux version 4.4.0-45-generic
+Cloud-init v. 0.7.8 running 'init' at Mon, 14 Nov 2016 10:01:02 +0000.
+2016-11-14 10:01:05,123 - util.py[WARNING]: Failed running /var/lib/cloud/instance/scripts/runcmd [1]
+`
+
+func (s *ConsoleLogSuite) TestInstanceConsoleLog(c *gc.C) {
+	env := newStubEnviron()
+	env.consoleLog = []byte(sampleConsoleLog)
+	output, err := environs.InstanceConsoleLog(env, "inst-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(output), gc.Equals, sampleConsoleLog)
+	env.CheckCalls(c, []jujutesting.StubCall{
+		{"InstanceConsoleLog", []interface{}{instance.Id("inst-0")}},
+	})
+}
+
+func (s *ConsoleLogSuite) TestInstanceConsoleLogError(c *gc.C) {
+	env := newStubEnviron()
+	env.SetErrors(errors.NotFoundf(`console log for instance "inst-0"`))
+	_, err := environs.InstanceConsoleLog(env, "inst-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `console log for instance "inst-0" not found`)
+}
+
+func (s *ConsoleLogSuite) TestInstanceConsoleLogNotSupported(c *gc.C) {
+	_, err := environs.InstanceConsoleLog(struct{ environs.Environ }{}, "inst-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "retrieving console logs not supported")
+}
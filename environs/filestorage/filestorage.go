@@ -89,7 +89,13 @@ func (f *fileStorageReader) List(prefix string) ([]string, error) {
 	if isInternalPath(prefix) {
 		return names, nil
 	}
+	// filepath.Join drops any trailing slash, which must be kept
+	// so that e.g. "a/" doesn't match "aa".
+	trailingSlash := strings.HasSuffix(prefix, "/")
 	prefix = filepath.Join(f.path, prefix)
+	if trailingSlash {
+		prefix += string(filepath.Separator)
+	}
 	dir := filepath.Dir(prefix)
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -177,6 +183,17 @@ func (f *fileStorageWriter) Remove(name string) error {
 	return err
 }
 
+// RemoveIfExists implements storage.IfExistsRemover.RemoveIfExists.
+func (f *fileStorageWriter) RemoveIfExists(name string) (bool, error) {
+	err := os.Remove(f.fullPath(name))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (f *fileStorageWriter) RemoveAll() error {
 	return storage.RemoveAll(f)
 }
@@ -73,6 +73,8 @@ func (s *filestorageSuite) TestList(c *gc.C) {
 	for i, test := range []test{
 		{"a", []string{"a/b/c", "a/bb", "a/c", "aa"}},
 		{"a/b", []string{"a/b/c", "a/bb"}},
+		{"a/", []string{"a/b/c", "a/bb", "a/c"}},
+		{"a/b/", []string{"a/b/c"}},
 		{"a/b/c", []string{"a/b/c"}},
 		{"", names},
 	} {
@@ -188,6 +190,22 @@ func (s *filestorageSuite) TestRemove(c *gc.C) {
 	c.Assert(err, gc.Not(gc.IsNil))
 }
 
+func (s *filestorageSuite) TestRemoveIfExists(c *gc.C) {
+	expectedpath, _ := s.createFile(c, "test-file")
+	_, file := filepath.Split(expectedpath)
+	stor, err := filestorage.NewFileStorageWriter(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	removed, err := storage.RemoveIfExists(stor, file)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.IsTrue)
+	_, err = os.Stat(expectedpath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	removed, err = storage.RemoveIfExists(stor, file)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.IsFalse)
+}
+
 func (s *filestorageSuite) TestRemoveAll(c *gc.C) {
 	expectedpath, _ := s.createFile(c, "test-file")
 	err := s.writer.RemoveAll()
@@ -0,0 +1,61 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+)
+
+// HealthStatus is a normalized indication of the health of the cloud
+// hosting an environment.
+type HealthStatus string
+
+const (
+	// HealthHealthy indicates that the cloud reports no problems.
+	HealthHealthy HealthStatus = "healthy"
+
+	// HealthDegraded indicates that the cloud is reachable, but is
+	// reporting problems which may affect the environment.
+	HealthDegraded HealthStatus = "degraded"
+
+	// HealthUnavailable indicates that the cloud could not be
+	// reached.
+	HealthUnavailable HealthStatus = "unavailable"
+)
+
+// ProviderHealth describes the health of the cloud hosting an
+// environment, as reported by its provider.
+type ProviderHealth struct {
+	// Status is the overall health of the cloud.
+	Status HealthStatus
+
+	// Messages holds any provider-specific details explaining the
+	// status.
+	Messages []string
+}
+
+// ProviderStatusReporter is implemented by environments which can
+// report the health of the cloud hosting them, so that cloud problems
+// can be distinguished from Juju problems.
+type ProviderStatusReporter interface {
+	// ProviderStatus queries the cloud's health signals, and returns
+	// the cloud's health. A cloud which can't be reached is reported
+	// as unavailable rather than as an error.
+	ProviderStatus() (ProviderHealth, error)
+}
+
+// ProviderStatus returns the health of the cloud hosting the
+// environment. If the environment can't report its cloud's health, an
+// error satisfying errors.IsNotSupported is returned.
+func ProviderStatus(env Environ) (ProviderHealth, error) {
+	reporter, ok := env.(ProviderStatusReporter)
+	if !ok {
+		return ProviderHealth{}, errors.NotSupportedf("provider status")
+	}
+	health, err := reporter.ProviderStatus()
+	if err != nil {
+		return ProviderHealth{}, errors.Trace(err)
+	}
+	return health, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type HealthSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&HealthSuite{})
+
+type healthReportingEnviron struct {
+	environs.Environ
+	health environs.ProviderHealth
+	err    error
+}
+
+func (e *healthReportingEnviron) ProviderStatus() (environs.ProviderHealth, error) {
+	return e.health, e.err
+}
+
+func (s *HealthSuite) TestProviderStatusHealthy(c *gc.C) {
+	env := &healthReportingEnviron{
+		health: environs.ProviderHealth{Status: environs.HealthHealthy},
+	}
+	health, err := environs.ProviderStatus(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, environs.ProviderHealth{Status: environs.HealthHealthy})
+}
+
+func (s *HealthSuite) TestProviderStatusDegraded(c *gc.C) {
+	degraded := environs.ProviderHealth{
+		Status:   environs.HealthDegraded,
+		Messages: []string{"region is experiencing issues"},
+	}
+	env := &healthReportingEnviron{health: degraded}
+	health, err := environs.ProviderStatus(env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, degraded)
+}
+
+func (s *HealthSuite) TestProviderStatusError(c *gc.C) {
+	env := &healthReportingEnviron{err: errors.New("boom")}
+	_, err := environs.ProviderStatus(env)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *HealthSuite) TestProviderStatusNotSupported(c *gc.C) {
+	env := struct{ environs.Environ }{}
+	_, err := environs.ProviderStatus(env)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,30 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+)
+
+// ImageCacher is implemented by environments which can prepare the
+// images used to start instances ahead of time, so that the first
+// instance started for a series isn't held up waiting for its image.
+type ImageCacher interface {
+	// CacheImages prepares the images for the specified series and
+	// architecture. Depending on the cloud, this may fetch the
+	// images or just check that they are available.
+	CacheImages(series []string, arch string) error
+}
+
+// CacheImages prepares the images for the specified series and
+// architecture in the environment. If the environment doesn't
+// support caching images, an error satisfying errors.IsNotSupported
+// is returned.
+func CacheImages(env Environ, series []string, arch string) error {
+	cacher, ok := env.(ImageCacher)
+	if !ok {
+		return errors.NotSupportedf("caching images")
+	}
+	return errors.Trace(cacher.CacheImages(series, arch))
+}
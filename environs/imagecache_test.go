@@ -0,0 +1,42 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ImageCacheSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ImageCacheSuite{})
+
+func (s *ImageCacheSuite) TestCacheImages(c *gc.C) {
+	env := newStubEnviron()
+	err := environs.CacheImages(env, []string{"trusty", "xenial"}, "amd64")
+	c.Assert(err, jc.ErrorIsNil)
+	env.CheckCalls(c, []jujutesting.StubCall{
+		{"CacheImages", []interface{}{[]string{"trusty", "xenial"}, "amd64"}},
+	})
+}
+
+func (s *ImageCacheSuite) TestCacheImagesError(c *gc.C) {
+	env := newStubEnviron()
+	env.SetErrors(errors.New("boom"))
+	err := environs.CacheImages(env, []string{"xenial"}, "amd64")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ImageCacheSuite) TestCacheImagesNotSupported(c *gc.C) {
+	err := environs.CacheImages(struct{ environs.Environ }{}, []string{"xenial"}, "amd64")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "caching images not supported")
+}
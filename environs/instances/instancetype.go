@@ -134,6 +134,16 @@ func MatchingInstanceTypes(allInstanceTypes []InstanceType, region string, cons
 	return nil, fmt.Errorf("no instance types in %s matching constraints %q", region, origCons)
 }
 
+// FilterInstanceTypes returns the instance types in allInstanceTypes
+// which match cons, sorted by increasing cost (if known). Unlike
+// MatchingInstanceTypes, no default memory constraint is applied,
+// and it is not an error for no instance types to match.
+func FilterInstanceTypes(allInstanceTypes []InstanceType, cons constraints.Value) []InstanceType {
+	itypes := matchingTypesForConstraint(allInstanceTypes, cons)
+	sort.Sort(byCost(itypes))
+	return itypes
+}
+
 // tagsMatch returns if the tags in wanted all exist in have.
 // Note that duplicates of tags are disregarded in both lists
 func tagsMatch(wanted, have []string) bool {
@@ -271,6 +271,22 @@ func (s *instanceTypeSuite) TestGetMatchingInstanceTypesErrors(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `no instance types in test matching constraints "instance-type=dep.medium mem=8192M"`)
 }
 
+func (s *instanceTypeSuite) TestFilterInstanceTypes(c *gc.C) {
+	itypes := FilterInstanceTypes(instanceTypes, constraints.MustParse("mem=8G"))
+	names := make([]string, len(itypes))
+	for i, itype := range itypes {
+		names[i] = itype.Name
+	}
+	c.Check(names, gc.DeepEquals, []string{"m1.xlarge", "cc1.4xlarge", "cc2.8xlarge"})
+
+	// Unlike MatchingInstanceTypes, no default memory constraint is
+	// applied, and matching nothing is not an error.
+	itypes = FilterInstanceTypes(instanceTypes, constraints.MustParse("cpu-power=20"))
+	c.Check(itypes, gc.HasLen, 9)
+	c.Check(itypes[0].Name, gc.Equals, "t1.micro")
+	c.Check(FilterInstanceTypes(instanceTypes, constraints.MustParse("mem=90000M")), gc.HasLen, 0)
+}
+
 var instanceTypeMatchTests = []struct {
 	cons   string
 	itype  string
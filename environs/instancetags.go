@@ -0,0 +1,31 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+)
+
+// TagInstances tags each of the instances with the given IDs with the
+// specified tags, as described by InstanceTagger, e.g. so the cloud's
+// cost allocation reports can be broken down by owner or cost centre.
+// If the environment doesn't support tagging instances, an error
+// satisfying errors.IsNotSupported is returned.
+func TagInstances(env Environ, ids []instance.Id, tags map[string]string) error {
+	tagger, ok := env.(InstanceTagger)
+	if !ok {
+		return errors.NotSupportedf("tagging instances")
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	for _, id := range ids {
+		if err := tagger.TagInstance(id, tags); err != nil {
+			return errors.Annotatef(err, "tagging instance %q", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type InstanceTagsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&InstanceTagsSuite{})
+
+func (s *InstanceTagsSuite) TestTagInstances(c *gc.C) {
+	env := newStubEnviron()
+	err := environs.TagInstances(env, []instance.Id{"inst-0", "inst-1"}, map[string]string{
+		"owner":       "finance",
+		"cost-center": "42",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = environs.TagInstances(env, []instance.Id{"inst-1"}, map[string]string{
+		"owner": "marketing",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.tags, jc.DeepEquals, map[instance.Id]map[string]string{
+		"inst-0": {"owner": "finance", "cost-center": "42"},
+		"inst-1": {"owner": "marketing", "cost-center": "42"},
+	})
+}
+
+func (s *InstanceTagsSuite) TestTagInstancesNoTags(c *gc.C) {
+	env := newStubEnviron()
+	err := environs.TagInstances(env, []instance.Id{"inst-0"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	env.CheckNoCalls(c)
+}
+
+func (s *InstanceTagsSuite) TestTagInstancesError(c *gc.C) {
+	env := newStubEnviron()
+	env.SetErrors(nil, errors.New("boom"))
+	err := environs.TagInstances(env, []instance.Id{"inst-0", "inst-1", "inst-2"}, map[string]string{
+		"owner": "finance",
+	})
+	c.Assert(err, gc.ErrorMatches, `tagging instance "inst-1": boom`)
+	c.Assert(env.tags, jc.DeepEquals, map[instance.Id]map[string]string{
+		"inst-0": {"owner": "finance"},
+	})
+	env.CheckCallNames(c, "TagInstance", "TagInstance")
+}
+
+func (s *InstanceTagsSuite) TestNotSupported(c *gc.C) {
+	env := struct{ environs.Environ }{}
+	err := environs.TagInstances(env, []instance.Id{"inst-0"}, map[string]string{
+		"owner": "finance",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "tagging instances not supported")
+}
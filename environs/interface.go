@@ -9,6 +9,7 @@ import (
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -197,6 +198,12 @@ type Environ interface {
 	// is used to validate and merge constraints.
 	ConstraintsValidator() (constraints.Validator, error)
 
+	// InstanceTypes returns the instance types offered by the cloud
+	// which match the given constraints, ordered by increasing cost.
+	// Providers that cannot enumerate their instance types return an
+	// error satisfying errors.IsNotSupported.
+	InstanceTypes(constraints.Value) ([]instances.InstanceType, error)
+
 	// SetConfig updates the Environ's configuration.
 	//
 	// Calls to SetConfig do not affect the configuration of
@@ -270,3 +277,11 @@ type InstanceTagger interface {
 	// same names, but other existing tags will be left alone.
 	TagInstance(id instance.Id, tags map[string]string) error
 }
+
+// InstanceTagReader is an interface that can be used for reporting
+// the tags of existing instances.
+type InstanceTagReader interface {
+	// InstanceTags returns the tags of the given instance, including
+	// those managed by Juju.
+	InstanceTags(id instance.Id) (map[string]string, error)
+}
@@ -4,6 +4,10 @@
 package environs
 
 import (
+	"fmt"
+	"net"
+
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/instance"
@@ -61,3 +65,38 @@ func supportsNetworking(environ Environ) (NetworkingEnviron, bool) {
 	ne, ok := environ.(NetworkingEnviron)
 	return ne, ok
 }
+
+// NetworkAvailabilityChecker is implemented by environments which can
+// check whether a network CIDR is free for use, so that a deployment
+// into an existing network doesn't collide with subnets already there.
+type NetworkAvailabilityChecker interface {
+	// CheckNetworkAvailability returns nil if no subnet or address
+	// range known to the provider overlaps the given CIDR. Otherwise
+	// it returns an error satisfying errors.IsAlreadyExists; see
+	// NewNetworkConflictError.
+	CheckNetworkAvailability(cidr string) error
+}
+
+// CheckNetworkAvailability checks that the given CIDR is free for use
+// in the environment. If the environment can't tell, for example
+// because the cloud manages networking opaquely, an error satisfying
+// errors.IsNotSupported is returned.
+func CheckNetworkAvailability(env Environ, cidr string) error {
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return errors.NotValidf("CIDR %q", cidr)
+	}
+	checker, ok := env.(NetworkAvailabilityChecker)
+	if !ok {
+		return errors.NotSupportedf("checking network availability")
+	}
+	return errors.Trace(checker.CheckNetworkAvailability(cidr))
+}
+
+// NewNetworkConflictError returns an error satisfying
+// errors.IsAlreadyExists, reporting that the requested CIDR overlaps
+// the existing CIDR.
+func NewNetworkConflictError(cidr, existing string) error {
+	return errors.NewAlreadyExists(nil, fmt.Sprintf(
+		"CIDR %q conflicts with existing network %q", cidr, existing,
+	))
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type NetworkingSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&NetworkingSuite{})
+
+type availabilityCheckingEnviron struct {
+	environs.Environ
+	err error
+}
+
+func (e *availabilityCheckingEnviron) CheckNetworkAvailability(cidr string) error {
+	return e.err
+}
+
+func (s *NetworkingSuite) TestCheckNetworkAvailability(c *gc.C) {
+	env := &availabilityCheckingEnviron{}
+	err := environs.CheckNetworkAvailability(env, "10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+
+	env.err = environs.NewNetworkConflictError("10.0.0.0/24", "10.0.0.0/16")
+	err = environs.CheckNetworkAvailability(env, "10.0.0.0/24")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `CIDR "10.0.0.0/24" conflicts with existing network "10.0.0.0/16"`)
+}
+
+func (s *NetworkingSuite) TestCheckNetworkAvailabilityNotSupported(c *gc.C) {
+	env := struct{ environs.Environ }{}
+	err := environs.CheckNetworkAvailability(env, "10.0.0.0/24")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *NetworkingSuite) TestCheckNetworkAvailabilityInvalidCIDR(c *gc.C) {
+	env := &availabilityCheckingEnviron{}
+	err := environs.CheckNetworkAvailability(env, "10.0.0.0")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
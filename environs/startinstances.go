@@ -0,0 +1,79 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// InstancesStarter is implemented by environments which can start
+// several instances at once, e.g. by provisioning them in parallel.
+type InstancesStarter interface {
+	// StartInstances starts an instance for each of the given
+	// requests. The results correspond to the requests by index. If
+	// some of the instances can't be started, the results of those
+	// that were started are still returned, along with a
+	// *StartInstancesError describing the failures.
+	StartInstances(reqs []StartInstanceParams) ([]StartInstanceResult, error)
+}
+
+// StartInstancesError is returned by StartInstances when some of the
+// requested instances could not be started.
+type StartInstancesError struct {
+	// Errors holds the error of each request, indexed like the
+	// requests; the errors of requests that succeeded are nil.
+	Errors []error
+}
+
+// Error is part of the error interface.
+func (e *StartInstancesError) Error() string {
+	var failed int
+	var first error
+	for _, err := range e.Errors {
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		failed++
+	}
+	return fmt.Sprintf("cannot start %d of %d instances: %v", failed, len(e.Errors), first)
+}
+
+// StartInstances starts an instance for each of the given requests, as
+// described by InstancesStarter. If the environment doesn't implement
+// InstancesStarter, the instances are started one at a time with
+// StartInstance.
+func StartInstances(env Environ, reqs []StartInstanceParams) ([]StartInstanceResult, error) {
+	if starter, ok := env.(InstancesStarter); ok {
+		results, err := starter.StartInstances(reqs)
+		if err != nil {
+			return results, err
+		}
+		if len(results) != len(reqs) {
+			return nil, errors.Errorf("expected %d results, got %d", len(reqs), len(results))
+		}
+		return results, nil
+	}
+
+	results := make([]StartInstanceResult, len(reqs))
+	errs := make([]error, len(reqs))
+	var failed bool
+	for i, req := range reqs {
+		result, err := env.StartInstance(req)
+		if err != nil {
+			errs[i] = err
+			failed = true
+			continue
+		}
+		results[i] = *result
+	}
+	if failed {
+		return results, &StartInstancesError{Errors: errs}
+	}
+	return results, nil
+}
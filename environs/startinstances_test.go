@@ -0,0 +1,125 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type StartInstancesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&StartInstancesSuite{})
+
+// bulkStartingEnviron is a stubEnviron which also implements
+// environs.InstancesStarter.
+type bulkStartingEnviron struct {
+	*stubEnviron
+	results []environs.StartInstanceResult
+}
+
+func (e *bulkStartingEnviron) StartInstances(reqs []environs.StartInstanceParams) ([]environs.StartInstanceResult, error) {
+	placements := make([]string, len(reqs))
+	for i, req := range reqs {
+		placements[i] = req.Placement
+	}
+	e.MethodCall(e, "StartInstances", placements)
+	return e.results, e.NextErr()
+}
+
+func startInstanceParams(placements ...string) []environs.StartInstanceParams {
+	reqs := make([]environs.StartInstanceParams, len(placements))
+	for i, placement := range placements {
+		reqs[i].Placement = placement
+	}
+	return reqs
+}
+
+func instanceIds(results []environs.StartInstanceResult) []instance.Id {
+	ids := make([]instance.Id, len(results))
+	for i, result := range results {
+		if result.Instance != nil {
+			ids[i] = result.Instance.Id()
+		}
+	}
+	return ids
+}
+
+func (s *StartInstancesSuite) TestStartInstances(c *gc.C) {
+	env := &bulkStartingEnviron{
+		stubEnviron: newStubEnviron(),
+		results: []environs.StartInstanceResult{
+			{Instance: fakeInstance{id: "a"}},
+			{Instance: fakeInstance{id: "b"}},
+		},
+	}
+	results, err := environs.StartInstances(env, startInstanceParams("a", "b"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceIds(results), jc.DeepEquals, []instance.Id{"a", "b"})
+	env.CheckCalls(c, []jujutesting.StubCall{
+		{"StartInstances", []interface{}{[]string{"a", "b"}}},
+	})
+}
+
+func (s *StartInstancesSuite) TestStartInstancesPartialFailure(c *gc.C) {
+	env := &bulkStartingEnviron{
+		stubEnviron: newStubEnviron(),
+		results: []environs.StartInstanceResult{
+			{Instance: fakeInstance{id: "a"}},
+			{},
+		},
+	}
+	env.SetErrors(&environs.StartInstancesError{
+		Errors: []error{nil, errors.New("no capacity")},
+	})
+	results, err := environs.StartInstances(env, startInstanceParams("a", "b"))
+	c.Assert(err, gc.ErrorMatches, "cannot start 1 of 2 instances: no capacity")
+	c.Assert(instanceIds(results), jc.DeepEquals, []instance.Id{"a", ""})
+}
+
+func (s *StartInstancesSuite) TestStartInstancesWrongResultCount(c *gc.C) {
+	env := &bulkStartingEnviron{
+		stubEnviron: newStubEnviron(),
+		results: []environs.StartInstanceResult{
+			{Instance: fakeInstance{id: "a"}},
+		},
+	}
+	_, err := environs.StartInstances(env, startInstanceParams("a", "b"))
+	c.Assert(err, gc.ErrorMatches, "expected 2 results, got 1")
+}
+
+func (s *StartInstancesSuite) TestFallback(c *gc.C) {
+	env := newStubEnviron()
+	results, err := environs.StartInstances(env, startInstanceParams("a", "b", "c"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceIds(results), jc.DeepEquals, []instance.Id{"a", "b", "c"})
+	env.CheckCalls(c, []jujutesting.StubCall{
+		{"StartInstance", []interface{}{"a"}},
+		{"StartInstance", []interface{}{"b"}},
+		{"StartInstance", []interface{}{"c"}},
+	})
+}
+
+func (s *StartInstancesSuite) TestFallbackPartialFailure(c *gc.C) {
+	env := newStubEnviron()
+	env.SetErrors(nil, errors.New("no capacity"), nil)
+	results, err := environs.StartInstances(env, startInstanceParams("a", "b", "c"))
+	c.Assert(err, gc.ErrorMatches, "cannot start 1 of 3 instances: no capacity")
+	c.Assert(err, gc.FitsTypeOf, &environs.StartInstancesError{})
+	errs := err.(*environs.StartInstancesError).Errors
+	c.Assert(errs, gc.HasLen, 3)
+	c.Check(errs[0], gc.IsNil)
+	c.Check(errs[1], gc.ErrorMatches, "no capacity")
+	c.Check(errs[2], gc.IsNil)
+	c.Assert(instanceIds(results), jc.DeepEquals, []instance.Id{"a", "", "c"})
+	env.CheckCallNames(c, "StartInstance", "StartInstance", "StartInstance")
+}
@@ -61,6 +61,16 @@ type StorageWriter interface {
 	RemoveAll() error
 }
 
+// IfExistsRemover is implemented by storage which can report whether
+// removing a file actually removed anything.
+type IfExistsRemover interface {
+	// RemoveIfExists removes the given file from the storage,
+	// reporting whether it existed. Unlike Remove, errors such as
+	// lack of permission are always reported; only the absence of
+	// the file is not an error.
+	RemoveIfExists(name string) (removed bool, err error)
+}
+
 // Storage represents storage that can be both
 // read and written.
 type Storage interface {
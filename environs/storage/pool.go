@@ -0,0 +1,115 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// PoolStorage is implemented by storage with several pools, such as
+// those of providers with multiple storage classes. The methods of
+// the PoolStorage itself operate on its default pool.
+type PoolStorage interface {
+	Storage
+
+	// StorageWithPool returns a Storage whose files are kept in the
+	// named pool, separately from those of the default pool and of
+	// any other pool.
+	StorageWithPool(pool string) (Storage, error)
+}
+
+// BasePoolsPath is the container where the files of the named pools of
+// storage which doesn't implement PoolStorage are found.
+var BasePoolsPath = "pools"
+
+var validPoolName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// StorageWithPool returns a Storage whose Get, Put and Remove methods
+// operate on files in the named pool of stor. If stor implements
+// PoolStorage, it is used to obtain the pool; otherwise the pool's
+// files are kept under BasePoolsPath in stor, so that they are
+// isolated from the files of other pools, but not hidden from the
+// default pool.
+func StorageWithPool(stor Storage, pool string) (Storage, error) {
+	if !validPoolName.MatchString(pool) {
+		return nil, errors.NotValidf("storage pool name %q", pool)
+	}
+	if poolStor, ok := stor.(PoolStorage); ok {
+		return poolStor.StorageWithPool(pool)
+	}
+	return &prefixedStorage{
+		stor:   stor,
+		prefix: BasePoolsPath + "/" + pool + "/",
+	}, nil
+}
+
+// prefixedStorage is a Storage whose files are those of another
+// Storage with the given prefix.
+type prefixedStorage struct {
+	stor   Storage
+	prefix string
+}
+
+// Get is specified in the StorageReader interface.
+func (s *prefixedStorage) Get(name string) (io.ReadCloser, error) {
+	return s.stor.Get(s.prefix + name)
+}
+
+// List is specified in the StorageReader interface.
+func (s *prefixedStorage) List(prefix string) ([]string, error) {
+	names, err := s.stor.List(s.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	// Storage may match prefixes by path, listing names which don't
+	// have the prefix; those must be left out.
+	var result []string
+	for _, name := range names {
+		if strings.HasPrefix(name, s.prefix+prefix) {
+			result = append(result, name[len(s.prefix):])
+		}
+	}
+	return result, nil
+}
+
+// URL is specified in the StorageReader interface.
+func (s *prefixedStorage) URL(name string) (string, error) {
+	return s.stor.URL(s.prefix + name)
+}
+
+// DefaultConsistencyStrategy is specified in the StorageReader interface.
+func (s *prefixedStorage) DefaultConsistencyStrategy() utils.AttemptStrategy {
+	return s.stor.DefaultConsistencyStrategy()
+}
+
+// ShouldRetry is specified in the StorageReader interface.
+func (s *prefixedStorage) ShouldRetry(err error) bool {
+	return s.stor.ShouldRetry(err)
+}
+
+// Put is specified in the StorageWriter interface.
+func (s *prefixedStorage) Put(name string, r io.Reader, length int64) error {
+	return s.stor.Put(s.prefix+name, r, length)
+}
+
+// Remove is specified in the StorageWriter interface.
+func (s *prefixedStorage) Remove(name string) error {
+	return s.stor.Remove(s.prefix + name)
+}
+
+// RemoveIfExists is specified in the IfExistsRemover interface.
+func (s *prefixedStorage) RemoveIfExists(name string) (bool, error) {
+	return RemoveIfExists(s.stor, s.prefix+name)
+}
+
+// RemoveAll is specified in the StorageWriter interface. Only the files
+// with the storage's prefix are removed.
+func (s *prefixedStorage) RemoveAll() error {
+	return RemoveAll(s)
+}
@@ -0,0 +1,151 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&poolSuite{})
+
+type poolSuite struct {
+	testing.BaseSuite
+	stor storage.Storage
+}
+
+func (s *poolSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.stor = stor
+}
+
+func (s *poolSuite) storageWithPool(c *gc.C, pool string) storage.Storage {
+	stor, err := storage.StorageWithPool(s.stor, pool)
+	c.Assert(err, jc.ErrorIsNil)
+	return stor
+}
+
+func put(c *gc.C, stor storage.Storage, name, content string) {
+	err := stor.Put(name, strings.NewReader(content), int64(len(content)))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func get(c *gc.C, stor storage.Storage, name string) string {
+	r, err := storage.Get(stor, name)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *poolSuite) TestPoolsAreIsolated(c *gc.C) {
+	fast := s.storageWithPool(c, "fast")
+	slow := s.storageWithPool(c, "slow")
+	put(c, fast, "data", "fast data")
+	put(c, slow, "data", "slow data")
+	put(c, s.stor, "data", "default data")
+
+	c.Assert(get(c, fast, "data"), gc.Equals, "fast data")
+	c.Assert(get(c, slow, "data"), gc.Equals, "slow data")
+	c.Assert(get(c, s.stor, "data"), gc.Equals, "default data")
+
+	names, err := storage.List(fast, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"data"})
+}
+
+func (s *poolSuite) TestPoolsWithCommonPrefixAreIsolated(c *gc.C) {
+	fast := s.storageWithPool(c, "fast")
+	faster := s.storageWithPool(c, "faster")
+	put(c, fast, "a", "fast a")
+	put(c, faster, "b", "faster b")
+
+	names, err := storage.List(fast, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"a"})
+}
+
+func (s *poolSuite) TestListFiltersByPrefixInOrder(c *gc.C) {
+	fast := s.storageWithPool(c, "fast")
+	for _, name := range []string{"tools/c", "images/a", "tools/a", "tools/b/x", "toolsy"} {
+		put(c, fast, name, name)
+	}
+	put(c, s.stor, "tools/d", "default")
+
+	names, err := storage.List(fast, "tools/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"tools/a", "tools/b/x", "tools/c"})
+
+	names, err = storage.List(fast, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"images/a", "tools/a", "tools/b/x", "tools/c", "toolsy"})
+}
+
+func (s *poolSuite) TestRemoveFromOnePool(c *gc.C) {
+	fast := s.storageWithPool(c, "fast")
+	slow := s.storageWithPool(c, "slow")
+	put(c, fast, "data", "fast data")
+	put(c, slow, "data", "slow data")
+	put(c, s.stor, "data", "default data")
+
+	err := fast.Remove("data")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = fast.Get("data")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(get(c, slow, "data"), gc.Equals, "slow data")
+	c.Assert(get(c, s.stor, "data"), gc.Equals, "default data")
+}
+
+func (s *poolSuite) TestRemoveAllFromOnePool(c *gc.C) {
+	fast := s.storageWithPool(c, "fast")
+	slow := s.storageWithPool(c, "slow")
+	put(c, fast, "a", "fast a")
+	put(c, fast, "dir/b", "fast b")
+	put(c, slow, "a", "slow a")
+
+	err := fast.RemoveAll()
+	c.Assert(err, jc.ErrorIsNil)
+	names, err := storage.List(fast, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+	c.Assert(get(c, slow, "a"), gc.Equals, "slow a")
+}
+
+func (s *poolSuite) TestInvalidPoolName(c *gc.C) {
+	for _, pool := range []string{"", "../fast", "fast/slow", "Fast"} {
+		_, err := storage.StorageWithPool(s.stor, pool)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+// poolingStorage is a storage.PoolStorage which records the pools
+// requested of it.
+type poolingStorage struct {
+	storage.Storage
+	pools []string
+}
+
+func (s *poolingStorage) StorageWithPool(pool string) (storage.Storage, error) {
+	s.pools = append(s.pools, pool)
+	return s.Storage, nil
+}
+
+func (s *poolSuite) TestPoolStorage(c *gc.C) {
+	stor := &poolingStorage{Storage: s.stor}
+	poolStor, err := storage.StorageWithPool(stor, "fast")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(poolStor, gc.Equals, s.stor)
+	c.Assert(stor.pools, jc.DeepEquals, []string{"fast"})
+}
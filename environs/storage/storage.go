@@ -8,6 +8,7 @@ import (
 	"io"
 	"path"
 
+	"github.com/juju/errors"
 	"github.com/juju/utils"
 
 	"github.com/juju/juju/environs/simplestreams"
@@ -33,6 +34,28 @@ func RemoveAll(stor Storage) error {
 	return err
 }
 
+// RemoveIfExists removes the named file from stor, reporting whether
+// there was such a file, as described by IfExistsRemover. If stor
+// doesn't implement IfExistsRemover, the file's existence is checked
+// with Get before it is removed, so a file which is removed by someone
+// else in the meantime may be reported as removed.
+func RemoveIfExists(stor Storage, name string) (bool, error) {
+	if remover, ok := stor.(IfExistsRemover); ok {
+		return remover.RemoveIfExists(name)
+	}
+	r, err := stor.Get(name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "checking for %q", name)
+	}
+	r.Close()
+	if err := stor.Remove(name); err != nil {
+		return false, errors.Annotatef(err, "removing %q", name)
+	}
+	return true, nil
+}
+
 // Get gets the named file from stor using the stor's default consistency strategy.
 func Get(stor StorageReader, name string) (io.ReadCloser, error) {
 	return GetWithRetry(stor, name, stor.DefaultConsistencyStrategy())
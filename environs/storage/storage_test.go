@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	stdtesting "testing"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -186,3 +187,85 @@ func (s *storageSuite) TestListNoRetryAllowed(c *gc.C) {
 	c.Assert(stor.listPrefix, gc.Equals, "foo")
 	c.Assert(stor.invokeCount, gc.Equals, 1)
 }
+
+// removingStorage is a storage.Storage holding files in memory, whose
+// Get and Remove methods fail with the configured errors.
+type removingStorage struct {
+	storage.Storage
+	files     map[string]string
+	getErr    error
+	removeErr error
+}
+
+func (s *removingStorage) Get(name string) (io.ReadCloser, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	content, ok := s.files[name]
+	if !ok {
+		return nil, errors.NotFoundf("file %q", name)
+	}
+	return ioutil.NopCloser(bytes.NewReader([]byte(content))), nil
+}
+
+func (s *removingStorage) Remove(name string) error {
+	if s.removeErr != nil {
+		return s.removeErr
+	}
+	delete(s.files, name)
+	return nil
+}
+
+func (s *storageSuite) TestRemoveIfExistsPresent(c *gc.C) {
+	stor := &removingStorage{files: map[string]string{"foo": "data"}}
+	removed, err := storage.RemoveIfExists(stor, "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.IsTrue)
+	c.Assert(stor.files, gc.HasLen, 0)
+}
+
+func (s *storageSuite) TestRemoveIfExistsAbsent(c *gc.C) {
+	stor := &removingStorage{files: map[string]string{"bar": "data"}}
+	removed, err := storage.RemoveIfExists(stor, "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.IsFalse)
+	c.Assert(stor.files, gc.HasLen, 1)
+}
+
+func (s *storageSuite) TestRemoveIfExistsPermissionDenied(c *gc.C) {
+	stor := &removingStorage{
+		files:     map[string]string{"foo": "data"},
+		removeErr: fmt.Errorf("permission denied"),
+	}
+	removed, err := storage.RemoveIfExists(stor, "foo")
+	c.Assert(err, gc.ErrorMatches, `removing "foo": permission denied`)
+	c.Assert(removed, jc.IsFalse)
+	c.Assert(stor.files, gc.HasLen, 1)
+}
+
+func (s *storageSuite) TestRemoveIfExistsGetError(c *gc.C) {
+	stor := &removingStorage{getErr: fmt.Errorf("connection refused")}
+	removed, err := storage.RemoveIfExists(stor, "foo")
+	c.Assert(err, gc.ErrorMatches, `checking for "foo": connection refused`)
+	c.Assert(removed, jc.IsFalse)
+}
+
+// ifExistsRemovingStorage is a removingStorage which implements
+// storage.IfExistsRemover.
+type ifExistsRemovingStorage struct {
+	removingStorage
+	removed []string
+}
+
+func (s *ifExistsRemovingStorage) RemoveIfExists(name string) (bool, error) {
+	s.removed = append(s.removed, name)
+	return true, nil
+}
+
+func (s *storageSuite) TestRemoveIfExistsUsesIfExistsRemover(c *gc.C) {
+	stor := &ifExistsRemovingStorage{}
+	removed, err := storage.RemoveIfExists(stor, "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.IsTrue)
+	c.Assert(stor.removed, jc.DeepEquals, []string{"foo"})
+}
@@ -0,0 +1,125 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+// stubEnviron is an environs.Environ which implements the optional
+// environ interfaces exercised by the tests in this package. Calls
+// are recorded, and errors injected, through the embedded Stub.
+type stubEnviron struct {
+	environs.Environ
+	jujutesting.Stub
+
+	consoleLog []byte
+
+	nextReservationID int
+	reservations      map[string]int
+
+	tags map[instance.Id]map[string]string
+}
+
+func newStubEnviron() *stubEnviron {
+	return &stubEnviron{
+		reservations: make(map[string]int),
+		tags:         make(map[instance.Id]map[string]string),
+	}
+}
+
+// ReserveCapacity implements environs.CapacityReserver.
+func (e *stubEnviron) ReserveCapacity(instanceType string, count int, zone string) (string, error) {
+	e.MethodCall(e, "ReserveCapacity", instanceType, count, zone)
+	if err := e.NextErr(); err != nil {
+		return "", err
+	}
+	e.nextReservationID++
+	id := fmt.Sprintf("reservation-%d", e.nextReservationID)
+	e.reservations[id] = count
+	return id, nil
+}
+
+// ReleaseCapacity implements environs.CapacityReserver.
+func (e *stubEnviron) ReleaseCapacity(reservationID string) error {
+	e.MethodCall(e, "ReleaseCapacity", reservationID)
+	if err := e.NextErr(); err != nil {
+		return err
+	}
+	if _, ok := e.reservations[reservationID]; !ok {
+		return errors.NotFoundf("reservation %s", reservationID)
+	}
+	delete(e.reservations, reservationID)
+	return nil
+}
+
+// InstanceConsoleLog implements environs.ConsoleLogger.
+func (e *stubEnviron) InstanceConsoleLog(id instance.Id) ([]byte, error) {
+	e.MethodCall(e, "InstanceConsoleLog", id)
+	if err := e.NextErr(); err != nil {
+		return nil, err
+	}
+	return e.consoleLog, nil
+}
+
+// CacheImages implements environs.ImageCacher.
+func (e *stubEnviron) CacheImages(series []string, arch string) error {
+	e.MethodCall(e, "CacheImages", series, arch)
+	return e.NextErr()
+}
+
+// TagInstance implements environs.InstanceTagger, recording the tags
+// applied to each instance.
+func (e *stubEnviron) TagInstance(id instance.Id, tags map[string]string) error {
+	e.MethodCall(e, "TagInstance", id, tags)
+	if err := e.NextErr(); err != nil {
+		return err
+	}
+	if e.tags[id] == nil {
+		e.tags[id] = make(map[string]string)
+	}
+	for k, v := range tags {
+		e.tags[id][k] = v
+	}
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker, naming each
+// instance after the placement of its request.
+func (e *stubEnviron) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	e.MethodCall(e, "StartInstance", args.Placement)
+	if err := e.NextErr(); err != nil {
+		return nil, err
+	}
+	return &environs.StartInstanceResult{
+		Instance: fakeInstance{id: instance.Id(args.Placement)},
+	}, nil
+}
+
+// SuspendInstances implements environs.InstanceSuspender.
+func (e *stubEnviron) SuspendInstances(ids ...instance.Id) error {
+	e.MethodCall(e, "SuspendInstances", ids)
+	return e.NextErr()
+}
+
+// ResumeInstances implements environs.InstanceSuspender.
+func (e *stubEnviron) ResumeInstances(ids ...instance.Id) ([]instance.Instance, error) {
+	e.MethodCall(e, "ResumeInstances", ids)
+	return nil, e.NextErr()
+}
+
+type fakeInstance struct {
+	instance.Instance
+	id instance.Id
+}
+
+func (inst fakeInstance) Id() instance.Id {
+	return inst.id
+}
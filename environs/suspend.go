@@ -0,0 +1,51 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+)
+
+// InstanceSuspender is implemented by environments which can stop
+// instances without destroying them, and start them again later.
+// Suspended instances keep their disks and identity, so that idle
+// models can be paused to save cost.
+type InstanceSuspender interface {
+	// SuspendInstances stops the specified instances, releasing
+	// their compute resources but keeping their disks.
+	SuspendInstances(ids ...instance.Id) error
+
+	// ResumeInstances starts the specified suspended instances, and
+	// returns them.
+	ResumeInstances(ids ...instance.Id) ([]instance.Instance, error)
+}
+
+// SuspendInstances suspends the specified instances in the
+// environment. If the environment can't suspend instances, for example
+// because they are bare metal machines, an error satisfying
+// errors.IsNotSupported is returned.
+func SuspendInstances(env Environ, ids ...instance.Id) error {
+	suspender, ok := env.(InstanceSuspender)
+	if !ok {
+		return errors.NotSupportedf("suspending instances")
+	}
+	return errors.Trace(suspender.SuspendInstances(ids...))
+}
+
+// ResumeInstances resumes the specified suspended instances in the
+// environment. If the environment can't suspend instances, an error
+// satisfying errors.IsNotSupported is returned.
+func ResumeInstances(env Environ, ids ...instance.Id) ([]instance.Instance, error) {
+	suspender, ok := env.(InstanceSuspender)
+	if !ok {
+		return nil, errors.NotSupportedf("resuming instances")
+	}
+	instances, err := suspender.ResumeInstances(ids...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return instances, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SuspendSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&SuspendSuite{})
+
+func (s *SuspendSuite) TestSuspendResume(c *gc.C) {
+	env := newStubEnviron()
+	err := environs.SuspendInstances(env, "inst-0", "inst-1")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = environs.ResumeInstances(env, "inst-0", "inst-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ids := []instance.Id{"inst-0", "inst-1"}
+	env.CheckCallNames(c, "SuspendInstances", "ResumeInstances")
+	env.CheckCall(c, 0, "SuspendInstances", ids)
+	env.CheckCall(c, 1, "ResumeInstances", ids)
+}
+
+func (s *SuspendSuite) TestSuspendError(c *gc.C) {
+	env := newStubEnviron()
+	env.SetErrors(errors.New("boom"))
+	err := environs.SuspendInstances(env, "inst-0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *SuspendSuite) TestNotSupported(c *gc.C) {
+	env := struct{ environs.Environ }{}
+	err := environs.SuspendInstances(env, "inst-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = environs.ResumeInstances(env, "inst-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
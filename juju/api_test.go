@@ -261,7 +261,7 @@ func newClientStore(c *gc.C, controllerName string) *jujuclienttesting.MemStore
 	c.Assert(err, jc.ErrorIsNil)
 
 	err = store.UpdateModel(controllerName, "admin", jujuclient.ModelDetails{
-		fakeUUID,
+		ModelUUID: fakeUUID,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
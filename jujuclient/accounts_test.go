@@ -4,6 +4,7 @@
 package jujuclient_test
 
 import (
+	"io/ioutil"
 	"os"
 
 	jc "github.com/juju/testing/checkers"
@@ -47,6 +48,67 @@ func (s *AccountsSuite) TestAccountDetails(c *gc.C) {
 	c.Assert(*details, jc.DeepEquals, kontrollBobRemoteAccountDetails)
 }
 
+func (s *AccountsSuite) TestEncryptedStore(c *gc.C) {
+	details := jujuclient.AccountDetails{
+		User:     "bob@local",
+		Password: "hunter2",
+	}
+	store := jujuclient.NewEncryptedFileStore("passphrase")
+	err := store.UpdateAccount("ctrl", details)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Not(jc.Contains), "hunter2")
+	c.Assert(string(data), jc.Contains, "password: aes-gcm:")
+	c.Assert(string(data), jc.Contains, "user: bob@local")
+
+	store = jujuclient.NewEncryptedFileStore("passphrase")
+	found, err := store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, details)
+
+	// Accounts recorded before the store was encrypted are still
+	// readable.
+	found, err = store.AccountDetails("kontroll")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, kontrollBobRemoteAccountDetails)
+}
+
+func (s *AccountsSuite) TestEncryptedStoreUpdateUnchanged(c *gc.C) {
+	details := jujuclient.AccountDetails{
+		User:     "bob@local",
+		Password: "hunter2",
+	}
+	store := jujuclient.NewEncryptedFileStore("passphrase")
+	err := store.UpdateAccount("ctrl", details)
+	c.Assert(err, jc.ErrorIsNil)
+	before, err := ioutil.ReadFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = store.UpdateAccount("ctrl", details)
+	c.Assert(err, jc.ErrorIsNil)
+	after, err := ioutil.ReadFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(after), gc.Equals, string(before))
+}
+
+func (s *AccountsSuite) TestEncryptedStoreIncorrectPassphrase(c *gc.C) {
+	store := jujuclient.NewEncryptedFileStore("passphrase")
+	err := store.UpdateAccount("ctrl", jujuclient.AccountDetails{
+		User:     "bob@local",
+		Password: "hunter2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	store = jujuclient.NewEncryptedFileStore("not the passphrase")
+	_, err = store.AccountDetails("ctrl")
+	c.Assert(err, gc.ErrorMatches, "cannot decrypt password for controller ctrl: incorrect passphrase")
+
+	_, err = s.store.AccountDetails("ctrl")
+	c.Assert(err, gc.ErrorMatches, "password for controller ctrl is encrypted, and no passphrase was supplied")
+}
+
 /*
 func (s *AccountsSuite) TestAllAccountsNoFile(c *gc.C) {
 	err := os.Remove(jujuclient.JujuAccountsPath())
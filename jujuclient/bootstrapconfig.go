@@ -6,6 +6,7 @@ package jujuclient
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -61,3 +62,40 @@ func ParseBootstrapConfig(data []byte) (map[string]BootstrapConfig, error) {
 type bootstrapConfigCollection struct {
 	ControllerBootstrapConfig map[string]BootstrapConfig `yaml:"controllers"`
 }
+
+// bootstrapConfigFields has the same fields as BootstrapConfig, but
+// none of its methods, so that it may be marshalled as a part of
+// BootstrapConfig without recursing.
+type bootstrapConfigFields BootstrapConfig
+
+// bootstrapConfigDoc is the serialized form of a BootstrapConfig.
+type bootstrapConfigDoc struct {
+	Fields        bootstrapConfigFields `yaml:",inline"`
+	BootstrapTime string                `yaml:"bootstrap-time,omitempty"`
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (c BootstrapConfig) MarshalYAML() (interface{}, error) {
+	doc := bootstrapConfigDoc{Fields: bootstrapConfigFields(c)}
+	if !c.BootstrapTime.IsZero() {
+		doc.BootstrapTime = c.BootstrapTime.UTC().Format(time.RFC3339)
+	}
+	return doc, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *BootstrapConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var doc bootstrapConfigDoc
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	*c = BootstrapConfig(doc.Fields)
+	if doc.BootstrapTime != "" {
+		t, err := time.Parse(time.RFC3339, doc.BootstrapTime)
+		if err != nil {
+			return errors.Annotate(err, "cannot parse bootstrap-time")
+		}
+		c.BootstrapTime = t
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package jujuclient_test
 
 import (
 	"io/ioutil"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -124,3 +125,31 @@ func (s *BootstrapConfigFileSuite) TestParseControllerMetadataError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot unmarshal bootstrap config: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `fail me...` into jujuclient.bootstrapConfigCollection")
 	c.Assert(controllers, gc.IsNil)
 }
+
+func (s *BootstrapConfigFileSuite) TestBootstrapTimeRoundTrip(c *gc.C) {
+	configs := parseBootstrapConfig(c)
+	cfg := configs["mallards"]
+	cfg.BootstrapTime = time.Date(2016, 9, 1, 15, 4, 5, 0, time.FixedZone("NZST", 12*60*60))
+	configs["mallards"] = cfg
+	err := jujuclient.WriteBootstrapConfigFile(configs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(osenv.JujuXDGDataHomePath("bootstrap-config.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "    bootstrap-time: \"2016-09-01T03:04:05Z\"\n")
+
+	read, err := jujuclient.ReadBootstrapConfigFile(jujuclient.JujuBootstrapConfigPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read["mallards"].BootstrapTime.Equal(cfg.BootstrapTime), jc.IsTrue)
+	c.Assert(read["aws-test"].BootstrapTime.IsZero(), jc.IsTrue)
+}
+
+func (s *BootstrapConfigFileSuite) TestBootstrapTimeInvalid(c *gc.C) {
+	_, err := jujuclient.ParseBootstrapConfig([]byte(`
+controllers:
+  mallards:
+    cloud: maas
+    bootstrap-time: yesterday
+`))
+	c.Assert(err, gc.ErrorMatches, `cannot unmarshal bootstrap config: cannot parse bootstrap-time: .*`)
+}
@@ -0,0 +1,762 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/mutex"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+// clientStoreSuite holds behavioural tests which every ClientStore
+// implementation must pass.
+type clientStoreSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	store jujuclient.ClientStore
+}
+
+type fileClientStoreSuite struct {
+	clientStoreSuite
+}
+
+var _ = gc.Suite(&fileClientStoreSuite{})
+
+func (s *fileClientStoreSuite) SetUpTest(c *gc.C) {
+	s.clientStoreSuite.SetUpTest(c)
+	s.store = jujuclient.NewFileClientStore()
+}
+
+// storeFiles returns the contents of the file store's files, keyed
+// by path. Missing files are recorded as such.
+func storeFiles(c *gc.C) map[string]string {
+	files := make(map[string]string)
+	for _, path := range []string{
+		jujuclient.JujuControllersPath(),
+		jujuclient.JujuModelsPath(),
+		jujuclient.JujuAccountsPath(),
+		jujuclient.JujuCredentialsPath(),
+		jujuclient.JujuBootstrapConfigPath(),
+	} {
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			files[path] = "<missing>"
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		files[path] = string(data)
+	}
+	return files
+}
+
+func (s *fileClientStoreSuite) TestDoErrorModifiesNoFiles(c *gc.C) {
+	s.addController(c, "ctrl")
+	before := storeFiles(c)
+
+	err := s.store.(jujuclient.Transactor).Do(func(tx jujuclient.ClientStore) error {
+		err := tx.UpdateController("other", storeController)
+		c.Assert(err, jc.ErrorIsNil)
+		err = tx.UpdateAccount("other", storeAccount)
+		c.Assert(err, jc.ErrorIsNil)
+		err = tx.RemoveModel("ctrl", "admin")
+		c.Assert(err, jc.ErrorIsNil)
+		return errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(storeFiles(c), jc.DeepEquals, before)
+}
+
+func (s *fileClientStoreSuite) TestDoWriteErrorRestoresFiles(c *gc.C) {
+	s.addController(c, "ctrl")
+	before := storeFiles(c)
+
+	err := s.store.(jujuclient.Transactor).Do(func(tx jujuclient.ClientStore) error {
+		err := tx.UpdateController("other", storeController)
+		c.Assert(err, jc.ErrorIsNil)
+		err = tx.UpdateAccount("other", storeAccount)
+		c.Assert(err, jc.ErrorIsNil)
+		err = tx.RemoveModel("ctrl", "admin")
+		c.Assert(err, jc.ErrorIsNil)
+
+		// Replace the controllers file, which is written last, with
+		// a directory so that it can't be written.
+		path := jujuclient.JujuControllersPath()
+		c.Assert(os.Remove(path), jc.ErrorIsNil)
+		c.Assert(os.Mkdir(path, 0700), jc.ErrorIsNil)
+		return ioutil.WriteFile(filepath.Join(path, "file"), nil, 0600)
+	})
+	c.Assert(err, gc.NotNil)
+
+	for _, path := range []string{jujuclient.JujuAccountsPath(), jujuclient.JujuModelsPath()} {
+		data, err := ioutil.ReadFile(path)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, before[path])
+	}
+}
+
+func (s *fileClientStoreSuite) TestDoWritesOnlyChangedFiles(c *gc.C) {
+	err := s.store.(jujuclient.Transactor).Do(func(tx jujuclient.ClientStore) error {
+		return tx.UpdateAccount("ctrl", storeAccount)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	files := storeFiles(c)
+	c.Assert(files[jujuclient.JujuAccountsPath()], gc.Not(gc.Equals), "<missing>")
+	c.Assert(files[jujuclient.JujuControllersPath()], gc.Equals, "<missing>")
+	c.Assert(files[jujuclient.JujuModelsPath()], gc.Equals, "<missing>")
+	c.Assert(files[jujuclient.JujuCredentialsPath()], gc.Equals, "<missing>")
+	c.Assert(files[jujuclient.JujuBootstrapConfigPath()], gc.Equals, "<missing>")
+}
+
+func (s *fileClientStoreSuite) TestConcurrentUpdateModel(c *gc.C) {
+	const count = 20
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each goroutine has its own store, as each juju
+			// command would.
+			store := jujuclient.NewFileClientStore()
+			name := fmt.Sprintf("model%d", i)
+			details := jujuclient.ModelDetails{ModelUUID: fmt.Sprintf("uuid%d", i)}
+			c.Check(store.UpdateModel("ctrl", name, details), jc.ErrorIsNil)
+		}(i)
+	}
+	wg.Wait()
+
+	// No update was lost.
+	models, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, gc.HasLen, count)
+}
+
+func (s *fileClientStoreSuite) TestLockTimeout(c *gc.C) {
+	s.PatchValue(jujuclient.LockTimeout, 50*time.Millisecond)
+	releaser, err := mutex.Acquire(mutex.Spec{
+		Name:    jujuclient.LockName,
+		Clock:   clock.WallClock,
+		Delay:   10 * time.Millisecond,
+		Timeout: time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer releaser.Release()
+
+	err = s.store.UpdateModel("ctrl", "admin", storeModel)
+	c.Assert(err, gc.ErrorMatches,
+		"timed out after 50ms waiting for the client store lock; another juju command may be using it")
+}
+
+type memClientStoreSuite struct {
+	clientStoreSuite
+}
+
+var _ = gc.Suite(&memClientStoreSuite{})
+
+func (s *memClientStoreSuite) SetUpTest(c *gc.C) {
+	s.clientStoreSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+}
+
+func (s *memClientStoreSuite) TestConcurrentUpdates(c *gc.C) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("ctrl%d", i)
+			details := jujuclient.ControllerDetails{
+				ControllerUUID: fmt.Sprintf("%08x-0000-4000-8000-000000000000", i),
+				CACert:         "cert",
+			}
+			c.Check(s.store.UpdateController(name, details), jc.ErrorIsNil)
+			c.Check(s.store.UpdateModel(name, "admin", jujuclient.ModelDetails{ModelUUID: "model-uuid"}), jc.ErrorIsNil)
+			_, err := s.store.AllControllers()
+			c.Check(err, jc.ErrorIsNil)
+		}(i)
+	}
+	wg.Wait()
+	controllers, err := s.store.AllControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllers, gc.HasLen, 10)
+}
+
+const otherControllerUUID = "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"
+
+var (
+	storeController = jujuclient.ControllerDetails{
+		ControllerUUID: "2e8b7c6d-1a0f-4e3d-9c2b-5a4f3e2d1c0b",
+		APIEndpoints:   []string{"10.0.0.1:17070"},
+		CACert:         "test.ca.cert",
+		Cloud:          "aws",
+		CloudRegion:    "southeastasia",
+	}
+	storeAccount = jujuclient.AccountDetails{
+		User:     "bob@local",
+		Password: "secret",
+	}
+	storeModel = jujuclient.ModelDetails{ModelUUID: "test.model.uuid"}
+)
+
+func (s *clientStoreSuite) addController(c *gc.C, name string) {
+	err := s.store.UpdateController(name, storeController)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateAccount(name, storeAccount)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateModel(name, "admin", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientStoreSuite) TestControllerNotFound(c *gc.C) {
+	all, err := s.store.AllControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+
+	_, err = s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "controller ctrl not found")
+	_, err = s.store.CurrentController()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.store.SetCurrentController("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.store.SetControllerLabels("ctrl", map[string]string{"team": "a"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.store.UpdateControllerEndpoints("ctrl", []string{"10.0.0.9:17070"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "controller ctrl not found")
+	err = s.store.RecordControllerConnection("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestUpdateController(c *gc.C) {
+	err := s.store.UpdateController("ctrl", storeController)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, storeController)
+
+	names, err := s.store.FindControllersByUUID(storeController.ControllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"ctrl"})
+}
+
+func (s *clientStoreSuite) TestUpdateControllerEndpoints(c *gc.C) {
+	details := storeController
+	details.CACerts = []string{"rotation.cert.1"}
+	details.Labels = map[string]string{"team": "a"}
+	details.UnresolvedAPIEndpoints = []string{"ctrl.example.com:17070"}
+	err := s.store.UpdateController("ctrl", details)
+	c.Assert(err, jc.ErrorIsNil)
+
+	endpoints := []string{"10.0.0.2:17070", "10.0.0.3:17070", "[fd00::4]:17070"}
+	err = s.store.UpdateControllerEndpoints("ctrl", endpoints)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	// The stale unresolved endpoints are replaced too, so that
+	// they aren't compared against later API addresses.
+	c.Check(found.UnresolvedAPIEndpoints, jc.DeepEquals, endpoints)
+	details.APIEndpoints = endpoints
+	details.UnresolvedAPIEndpoints = endpoints
+	c.Assert(*found, jc.DeepEquals, details)
+}
+
+func (s *clientStoreSuite) TestUpdateControllerEndpointsInvalid(c *gc.C) {
+	err := s.store.UpdateController("ctrl", storeController)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.UpdateControllerEndpoints("ctrl", []string{"10.0.0.2"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `api-endpoints: endpoint "10.0.0.2": .*missing port in address.*`)
+	found, err := s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, storeController)
+}
+
+func (s *clientStoreSuite) TestControllerCACertsRoundTrip(c *gc.C) {
+	details := storeController
+	details.CACerts = []string{"rotation.cert.1", "rotation.cert.2", "rotation.cert.3"}
+	err := s.store.UpdateController("ctrl", details)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, details)
+	c.Assert(found.TrustedCACerts(), jc.DeepEquals, []string{
+		"test.ca.cert", "rotation.cert.1", "rotation.cert.2", "rotation.cert.3",
+	})
+}
+
+func (s *clientStoreSuite) TestUpdateControllerPreservesCACerts(c *gc.C) {
+	details := storeController
+	details.CACerts = []string{"rotation.cert.1", "rotation.cert.2"}
+	err := s.store.UpdateController("ctrl", details)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Updating without specifying additional certs keeps them.
+	updated := storeController
+	updated.CACert = "new.ca.cert"
+	err = s.store.UpdateController("ctrl", updated)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.CACert, gc.Equals, "new.ca.cert")
+	c.Assert(found.CACerts, jc.DeepEquals, []string{"rotation.cert.1", "rotation.cert.2"})
+
+	// They can be replaced...
+	updated.CACerts = []string{"rotation.cert.3"}
+	err = s.store.UpdateController("ctrl", updated)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err = s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.CACerts, jc.DeepEquals, []string{"rotation.cert.3"})
+
+	// ...or removed.
+	updated.CACerts = []string{}
+	err = s.store.UpdateController("ctrl", updated)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err = s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.CACerts, gc.HasLen, 0)
+}
+
+func (s *clientStoreSuite) TestUpdateControllerInvalid(c *gc.C) {
+	err := s.store.UpdateController("ctrl", jujuclient.ControllerDetails{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *clientStoreSuite) TestSetCurrentController(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.SetCurrentController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	current, err := s.store.CurrentController()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "ctrl")
+}
+
+func (s *clientStoreSuite) TestSetControllerLabels(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.SetControllerLabels("ctrl", map[string]string{"team": "a"})
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ControllersByLabel("team", "a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 1)
+	c.Assert(found["ctrl"].Labels, jc.DeepEquals, map[string]string{"team": "a"})
+}
+
+func (s *clientStoreSuite) TestRecordControllerConnection(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.RecordControllerConnection("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.LastConnection.IsZero(), jc.IsFalse)
+}
+
+func (s *clientStoreSuite) TestSwitchTo(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.SwitchTo("ctrl", "bob@local", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	current, err := s.store.CurrentController()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "ctrl")
+	model, err := s.store.CurrentModel("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model, gc.Equals, "admin")
+}
+
+func (s *clientStoreSuite) TestSwitchToNotFound(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.SwitchTo("ctrl", "mary@local", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.store.SwitchTo("ctrl", "bob@local", "default")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Nothing was changed.
+	_, err = s.store.CurrentController()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.store.CurrentModel("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestRemoveController(c *gc.C) {
+	s.addController(c, "ctrl")
+	s.addController(c, "ctrl-copy")
+	err := s.store.UpdateBootstrapConfig("ctrl", jujuclient.BootstrapConfig{
+		Cloud:  "aws",
+		Config: map[string]interface{}{"name": "admin"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetCurrentController("ctrl-copy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Controllers with the same UUID are removed along with
+	// everything related to them.
+	err = s.store.RemoveController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range []string{"ctrl", "ctrl-copy"} {
+		_, err = s.store.ControllerByName(name)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+		_, err = s.store.AccountDetails(name)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+		_, err = s.store.AllModels(name)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+		_, err = s.store.BootstrapConfigForController(name)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+	}
+	_, err = s.store.CurrentController()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestRemoveControllerNotFound(c *gc.C) {
+	err := s.store.RemoveController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientStoreSuite) TestSetControllerAlias(c *gc.C) {
+	s.addController(c, "a-controller-with-a-long-name")
+	err := s.store.SetControllerAlias("short", "a-controller-with-a-long-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	found, err := s.store.ControllerByName("short")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, storeController)
+	all, err := s.store.AllControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, map[string]jujuclient.ControllerDetails{
+		"a-controller-with-a-long-name": storeController,
+	})
+
+	// An alias may be given in place of the controller's name.
+	err = s.store.SetControllerAlias("shorter", "short")
+	c.Assert(err, jc.ErrorIsNil)
+	found, err = s.store.ControllerByName("shorter")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, storeController)
+}
+
+func (s *clientStoreSuite) TestSetControllerAliasNotFound(c *gc.C) {
+	err := s.store.SetControllerAlias("short", "ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "controller ctrl not found")
+	_, err = s.store.ControllerByName("short")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "controller short not found")
+}
+
+func (s *clientStoreSuite) TestSetControllerAliasCollision(c *gc.C) {
+	s.addController(c, "ctrl")
+	other := storeController
+	other.ControllerUUID = otherControllerUUID
+	err := s.store.UpdateController("other", other)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.SetControllerAlias("other", "ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, "controller other already exists")
+	found, err := s.store.ControllerByName("other")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, other)
+}
+
+func (s *clientStoreSuite) TestUpdateControllerReplacesAlias(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.SetControllerAlias("other", "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+
+	other := storeController
+	other.ControllerUUID = otherControllerUUID
+	err = s.store.UpdateController("other", other)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ControllerByName("other")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, other)
+
+	// Removing the new controller doesn't bring back the alias.
+	err = s.store.RemoveController("other")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.ControllerByName("other")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestRemoveControllerRemovesAliases(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.SetControllerAlias("short", "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.RemoveController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.ControllerByName("short")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// A new controller with the same name doesn't inherit the alias.
+	s.addController(c, "ctrl")
+	_, err = s.store.ControllerByName("short")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestModelNotFound(c *gc.C) {
+	_, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "models for controller ctrl not found")
+	_, err = s.store.CurrentModel("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "current model for controller ctrl not found")
+	_, err = s.store.ModelByName("ctrl", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.store.SetCurrentModel("ctrl", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "model ctrl:admin not found")
+	err = s.store.RemoveModel("ctrl", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "model ctrl:admin not found")
+
+	err = s.store.UpdateModel("ctrl", "admin", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.ModelByName("ctrl", "default")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "model ctrl:default not found")
+	_, err = s.store.CurrentModel("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestUpdateModel(c *gc.C) {
+	err := s.store.UpdateModel("ctrl", "admin", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ModelByName("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, storeModel)
+	all, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, map[string]jujuclient.ModelDetails{"admin": storeModel})
+}
+
+func (s *clientStoreSuite) TestSetCurrentModelRecordsLastUsed(c *gc.C) {
+	err := s.store.UpdateModel("ctrl", "admin", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+	before := time.Now().UTC().Truncate(time.Second)
+	err = s.store.SetCurrentModel("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	all, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	lastUsed := all["admin"].LastUsed
+	c.Assert(lastUsed.Before(before), jc.IsFalse)
+
+	// Updating the model's details doesn't forget when it was used.
+	err = s.store.UpdateModel("ctrl", "admin", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.ModelByName("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.LastUsed, gc.Equals, lastUsed)
+}
+
+func (s *clientStoreSuite) TestRemoveCurrentModel(c *gc.C) {
+	err := s.store.UpdateModel("ctrl", "admin", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetCurrentModel("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.RemoveModel("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.ModelByName("ctrl", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.store.CurrentModel("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestModelByUUID(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.UpdateModel("ctrl", "default", jujuclient.ModelDetails{ModelUUID: "other.uuid"})
+	c.Assert(err, jc.ErrorIsNil)
+	name, details, err := s.store.ModelByUUID("ctrl", "bob@local", "test.model.uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "admin")
+	c.Assert(*details, jc.DeepEquals, storeModel)
+}
+
+func (s *clientStoreSuite) TestModelByUUIDNotFound(c *gc.C) {
+	_, _, err := s.store.ModelByUUID("ctrl", "bob@local", "test.model.uuid")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.addController(c, "ctrl")
+	_, _, err = s.store.ModelByUUID("ctrl", "bob@local", "unknown.uuid")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "model with UUID unknown.uuid on controller ctrl not found")
+}
+
+func (s *clientStoreSuite) TestModelByUUIDWrongAccount(c *gc.C) {
+	s.addController(c, "ctrl")
+	_, _, err := s.store.ModelByUUID("ctrl", "mary@local", "test.model.uuid")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "account mary@local for controller ctrl not found")
+}
+
+func (s *clientStoreSuite) TestModelByUUIDAmbiguous(c *gc.C) {
+	// The same model may be cached under more than one name, for
+	// example if it was renamed or is shared with another user.
+	s.addController(c, "ctrl")
+	err := s.store.UpdateModel("ctrl", "admin-old", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.store.ModelByUUID("ctrl", "bob@local", "test.model.uuid")
+	c.Assert(err, gc.ErrorMatches,
+		"model UUID test.model.uuid is ambiguous on controller ctrl: cached as admin, admin-old")
+	c.Assert(err, gc.Not(jc.Satisfies), errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestDo(c *gc.C) {
+	err := s.store.(jujuclient.Transactor).Do(func(tx jujuclient.ClientStore) error {
+		if err := tx.UpdateController("ctrl", storeController); err != nil {
+			return err
+		}
+		if err := tx.UpdateAccount("ctrl", storeAccount); err != nil {
+			return err
+		}
+		if err := tx.UpdateModel("ctrl", "admin", storeModel); err != nil {
+			return err
+		}
+		return tx.SwitchTo("ctrl", "bob@local", "admin")
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	current, err := s.store.CurrentController()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "ctrl")
+	account, err := s.store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*account, jc.DeepEquals, storeAccount)
+	model, err := s.store.CurrentModel("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model, gc.Equals, "admin")
+}
+
+func (s *clientStoreSuite) TestDoError(c *gc.C) {
+	s.addController(c, "ctrl")
+	err := s.store.(jujuclient.Transactor).Do(func(tx jujuclient.ClientStore) error {
+		err := tx.UpdateController("other", storeController)
+		c.Assert(err, jc.ErrorIsNil)
+		err = tx.UpdateModel("ctrl", "default", storeModel)
+		c.Assert(err, jc.ErrorIsNil)
+		err = tx.RemoveAccount("ctrl")
+		c.Assert(err, jc.ErrorIsNil)
+		return errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	// None of the changes were made.
+	_, err = s.store.ControllerByName("other")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	models, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, map[string]jujuclient.ModelDetails{"admin": storeModel})
+	_, err = s.store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientStoreSuite) TestAccounts(c *gc.C) {
+	_, err := s.store.AccountDetails("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "account details for controller ctrl not found")
+	err = s.store.RemoveAccount("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.store.UpdateAccount("ctrl", storeAccount)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, storeAccount)
+
+	err = s.store.RemoveAccount("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.AccountDetails("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestUpdateAccountInvalid(c *gc.C) {
+	err := s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "bob"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *clientStoreSuite) TestCredentials(c *gc.C) {
+	_, err := s.store.CredentialForCloud("aws")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "credentials for cloud aws not found")
+
+	cred := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": "bob",
+		"password": "secret",
+	})
+	err = s.store.UpdateCredential("aws", cloud.CloudCredential{
+		DefaultCredential: "one",
+		AuthCredentials:   map[string]cloud.Credential{"one": cred},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Replacing the default credential clears it.
+	err = s.store.UpdateCredential("aws", cloud.CloudCredential{
+		DefaultCredential: "one",
+		AuthCredentials:   map[string]cloud.Credential{"two": cred},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.CredentialForCloud("aws")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.DefaultCredential, gc.Equals, "")
+	c.Assert(found.AuthCredentials, gc.HasLen, 1)
+
+	err = s.store.MoveCredentials("aws", "amazon", false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.CredentialForCloud("aws")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	all, err := s.store.AllCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all["amazon"].AuthCredentials, gc.HasLen, 1)
+}
+
+func (s *clientStoreSuite) TestMoveCredentialsNotFound(c *gc.C) {
+	err := s.store.MoveCredentials("aws", "amazon", false)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestCloudProxy(c *gc.C) {
+	_, err := s.store.CloudProxy("aws")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "proxy for cloud aws not found")
+
+	proxy := jujuclient.ProxyConfig{HTTP: "http://proxy.example.com:3128"}
+	err = s.store.SetCloudProxy("aws", proxy)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.CloudProxy("aws")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, proxy)
+
+	err = s.store.SetCloudProxy("aws", jujuclient.ProxyConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.CloudProxy("aws")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientStoreSuite) TestBootstrapConfig(c *gc.C) {
+	_, err := s.store.BootstrapConfigForController("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "bootstrap config for controller ctrl not found")
+
+	err = s.store.UpdateBootstrapConfig("ctrl", jujuclient.BootstrapConfig{
+		Cloud:  "aws",
+		Config: map[string]interface{}{"name": "admin"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.BootstrapConfigForController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Cloud, gc.Equals, "aws")
+	c.Assert(found.Config, jc.DeepEquals, map[string]interface{}{"name": "admin"})
+}
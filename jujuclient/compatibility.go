@@ -0,0 +1,37 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/version"
+)
+
+// CheckVersionCompatibility returns an error if a client with the
+// given version cannot talk to the controller with the given name and
+// details. Clients and controllers are compatible if they share a
+// major version. If the controller's agent version is not known, no
+// error is returned.
+func CheckVersionCompatibility(controllerName string, details ControllerDetails, clientVersion version.Number) error {
+	if details.AgentVersion == "" {
+		return nil
+	}
+	controllerVersion, err := version.Parse(details.AgentVersion)
+	if err != nil {
+		return errors.Annotatef(err, "cannot parse agent version for controller %q", controllerName)
+	}
+	switch {
+	case clientVersion.Major < controllerVersion.Major:
+		return errors.Errorf(
+			"client version %s too old for controller %q (version %s)",
+			clientVersion, controllerName, controllerVersion,
+		)
+	case clientVersion.Major > controllerVersion.Major:
+		return errors.Errorf(
+			"client version %s too new for controller %q (version %s)",
+			clientVersion, controllerName, controllerVersion,
+		)
+	}
+	return nil
+}
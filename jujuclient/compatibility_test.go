@@ -0,0 +1,86 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type CompatibilitySuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	store jujuclient.ClientStore
+}
+
+var _ = gc.Suite(&CompatibilitySuite{})
+
+func (s *CompatibilitySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewFileClientStore()
+}
+
+func (s *CompatibilitySuite) TestCheckClientCompatibility(c *gc.C) {
+	for i, test := range []struct {
+		agentVersion  string
+		clientVersion string
+		expect        string
+	}{{
+		agentVersion:  "",
+		clientVersion: "2.0.0",
+	}, {
+		agentVersion:  "2.0.0",
+		clientVersion: "2.0.0",
+	}, {
+		agentVersion:  "2.0.1",
+		clientVersion: "2.0.0",
+	}, {
+		agentVersion:  "2.1-beta1",
+		clientVersion: "2.0.2",
+	}, {
+		agentVersion:  "2.0.0",
+		clientVersion: "2.3.1",
+	}, {
+		agentVersion:  "3.0.0",
+		clientVersion: "2.9.9",
+		expect:        `client version 2.9.9 too old for controller "ctrl" \(version 3.0.0\)`,
+	}, {
+		agentVersion:  "1.25.6",
+		clientVersion: "2.0.0",
+		expect:        `client version 2.0.0 too new for controller "ctrl" \(version 1.25.6\)`,
+	}} {
+		c.Logf("test %d: controller %q, client %s", i, test.agentVersion, test.clientVersion)
+		err := s.store.UpdateController("ctrl", jujuclient.ControllerDetails{
+			ControllerUUID: "4b5c6d7e-8f9a-4b0c-9d1e-2f3a4b5c6d7e",
+			CACert:         "ca-cert",
+			AgentVersion:   test.agentVersion,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+
+		err = s.store.CheckClientCompatibility("ctrl", version.MustParse(test.clientVersion))
+		if test.expect == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expect)
+		}
+	}
+}
+
+func (s *CompatibilitySuite) TestCheckClientCompatibilityNotFound(c *gc.C) {
+	err := s.store.CheckClientCompatibility("ctrl", version.MustParse("2.0.0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CompatibilitySuite) TestInvalidAgentVersion(c *gc.C) {
+	err := s.store.UpdateController("ctrl", jujuclient.ControllerDetails{
+		ControllerUUID: "4b5c6d7e-8f9a-4b0c-9d1e-2f3a4b5c6d7e",
+		CACert:         "ca-cert",
+		AgentVersion:   "two",
+	})
+	c.Assert(err, gc.ErrorMatches, `agent-version "two", controller details not valid`)
+}
@@ -6,6 +6,8 @@ package jujuclient
 import (
 	"io/ioutil"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -64,4 +66,140 @@ type Controllers struct {
 
 	// CurrentController is the name of the active controller.
 	CurrentController string `yaml:"current-controller,omitempty"`
+
+	// Aliases maps alternative names for controllers to the
+	// controllers' names.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// FilterControllersByLabel returns the controllers which have a label
+// with the specified key and value.
+func FilterControllersByLabel(controllers map[string]ControllerDetails, key, value string) map[string]ControllerDetails {
+	result := make(map[string]ControllerDetails)
+	for name, details := range controllers {
+		if v, ok := details.Labels[key]; ok && v == value {
+			result[name] = details
+		}
+	}
+	return result
+}
+
+// ControllerNamesByUUID returns the sorted names of the controllers
+// with the specified UUID.
+func ControllerNamesByUUID(controllers map[string]ControllerDetails, controllerUUID string) []string {
+	names := []string{}
+	for name, details := range controllers {
+		if details.ControllerUUID == controllerUUID {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SortControllersByRecency returns the controllers ordered as
+// described by ControllerGetter.AllControllersByRecency.
+func SortControllersByRecency(controllers map[string]ControllerDetails) []NamedControllerDetails {
+	result := make([]NamedControllerDetails, 0, len(controllers))
+	for name, details := range controllers {
+		result = append(result, NamedControllerDetails{Name: name, ControllerDetails: details})
+	}
+	sort.Sort(byRecency(result))
+	return result
+}
+
+type byRecency []NamedControllerDetails
+
+func (r byRecency) Len() int      { return len(r) }
+func (r byRecency) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byRecency) Less(i, j int) bool {
+	ti, tj := r[i].LastConnection, r[j].LastConnection
+	if !ti.Equal(tj) {
+		return ti.After(tj)
+	}
+	return r[i].Name < r[j].Name
+}
+
+// ResolveControllerName returns the name of the controller with the
+// given name or alias. A controller's name takes precedence over an
+// alias of another controller. If name is neither, it is returned
+// unchanged.
+func ResolveControllerName(controllers map[string]ControllerDetails, aliases map[string]string, name string) string {
+	if _, ok := controllers[name]; ok {
+		return name
+	}
+	if controllerName, ok := aliases[name]; ok {
+		return controllerName
+	}
+	return name
+}
+
+// checkControllerAlias returns an error if alias cannot be used as
+// an alias of the named controller, as described by
+// ControllerUpdater.SetControllerAlias.
+func checkControllerAlias(controllers map[string]ControllerDetails, alias, controllerName string) error {
+	if _, ok := controllers[alias]; ok {
+		return errors.AlreadyExistsf("controller %s", alias)
+	}
+	if _, ok := controllers[controllerName]; !ok {
+		return errors.NotFoundf("controller %v", controllerName)
+	}
+	return nil
+}
+
+// removeControllerAliases removes all the aliases of the named
+// controller.
+func removeControllerAliases(aliases map[string]string, controllerName string) {
+	for alias, name := range aliases {
+		if name == controllerName {
+			delete(aliases, alias)
+		}
+	}
+}
+
+// preserveCACerts returns details, with the additional CA
+// certificates of existing if details doesn't specify any, as
+// described by ControllerUpdater.UpdateController.
+func preserveCACerts(details, existing ControllerDetails) ControllerDetails {
+	if details.CACerts == nil {
+		details.CACerts = existing.CACerts
+	}
+	return details
+}
+
+// controllerDetailsFields has the same fields as ControllerDetails,
+// but none of its methods, so that it may be marshalled as a part of
+// ControllerDetails without recursing.
+type controllerDetailsFields ControllerDetails
+
+// controllerDetailsDoc is the serialized form of a ControllerDetails.
+type controllerDetailsDoc struct {
+	Fields         controllerDetailsFields `yaml:",inline"`
+	LastConnection string                  `yaml:"last-connection,omitempty"`
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (d ControllerDetails) MarshalYAML() (interface{}, error) {
+	doc := controllerDetailsDoc{Fields: controllerDetailsFields(d)}
+	if !d.LastConnection.IsZero() {
+		doc.LastConnection = d.LastConnection.UTC().Format(time.RFC3339)
+	}
+	return doc, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (d *ControllerDetails) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var doc controllerDetailsDoc
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	*d = ControllerDetails(doc.Fields)
+	if doc.LastConnection != "" {
+		t, err := time.Parse(time.RFC3339, doc.LastConnection)
+		if err != nil {
+			return errors.Annotate(err, "cannot parse last-connection")
+		}
+		d.LastConnection = t
+	}
+	return nil
 }
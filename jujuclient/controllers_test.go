@@ -5,7 +5,9 @@ package jujuclient_test
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -15,7 +17,7 @@ import (
 
 type ControllersSuite struct {
 	testing.FakeJujuXDGDataHomeSuite
-	store          jujuclient.ControllerStore
+	store          jujuclient.ClientStore
 	controllerName string
 	controller     jujuclient.ControllerDetails
 }
@@ -27,12 +29,16 @@ func (s *ControllersSuite) SetUpTest(c *gc.C) {
 	s.store = jujuclient.NewFileClientStore()
 	s.controllerName = "test.controller"
 	s.controller = jujuclient.ControllerDetails{
-		[]string{"test.server.hostname"},
-		"test.uuid",
-		[]string{"test.api.endpoint"},
+		[]string{"test.server.hostname:17070"},
+		"3a1f0c2e-5b7d-4e9a-8c6b-2d4f6a8b0c1e",
+		[]string{"10.0.0.1:17070"},
 		"test.ca.cert",
+		nil,
 		"aws",
 		"southeastasia",
+		nil,
+		"",
+		time.Time{},
 	}
 }
 
@@ -121,6 +127,252 @@ func (s *ControllersSuite) TestRemoveControllerRemovesIdenticalControllers(c *gc
 	}
 }
 
+func (s *ControllersSuite) TestFindControllersByUUID(c *gc.C) {
+	name := firstTestControllerName(c)
+	details, err := s.store.ControllerByName(name)
+	c.Assert(err, jc.ErrorIsNil)
+
+	names, err := s.store.FindControllersByUUID(details.ControllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{name})
+}
+
+func (s *ControllersSuite) TestFindControllersByUUIDDuplicates(c *gc.C) {
+	name := firstTestControllerName(c)
+	details, err := s.store.ControllerByName(name)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateController(name+"-copy", *details)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, fmt.Sprintf(
+		"controller %s-copy has the same UUID as controller %s; it may be a duplicate", name, name,
+	))
+
+	names, err := s.store.FindControllersByUUID(details.ControllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{name, name + "-copy"})
+}
+
+func (s *ControllersSuite) TestFindControllersByUUIDNotFound(c *gc.C) {
+	writeTestControllersFile(c)
+	names, err := s.store.FindControllersByUUID("no-such-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.NotNil)
+	c.Assert(names, gc.HasLen, 0)
+}
+
+func (s *ControllersSuite) TestUpdateControllerWithLabels(c *gc.C) {
+	s.controller.Labels = map[string]string{"env": "prod", "team": "infra"}
+	err := s.store.UpdateController(s.controllerName, s.controller)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpdateSucceeded(c)
+}
+
+func (s *ControllersSuite) TestSetControllerLabels(c *gc.C) {
+	name := firstTestControllerName(c)
+	labels := map[string]string{"env": "prod"}
+	err := s.store.SetControllerLabels(name, labels)
+	c.Assert(err, jc.ErrorIsNil)
+
+	found, err := s.store.ControllerByName(name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Labels, jc.DeepEquals, labels)
+
+	// Setting labels replaces any existing ones.
+	err = s.store.SetControllerLabels(name, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err = s.store.ControllerByName(name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Labels, gc.HasLen, 0)
+}
+
+func (s *ControllersSuite) TestSetControllerLabelsNotFound(c *gc.C) {
+	writeTestControllersFile(c)
+	err := s.store.SetControllerLabels(s.controllerName, map[string]string{"env": "prod"})
+	c.Assert(err, gc.ErrorMatches, "controller test.controller not found")
+}
+
+func (s *ControllersSuite) TestSetControllerLabelsInvalid(c *gc.C) {
+	name := firstTestControllerName(c)
+	err := s.store.SetControllerLabels(name, map[string]string{"": "prod"})
+	c.Assert(err, gc.ErrorMatches, "empty label key, controller details not valid")
+}
+
+func (s *ControllersSuite) TestControllersByLabel(c *gc.C) {
+	writeTestControllersFile(c)
+	err := s.store.SetControllerLabels("aws-test", map[string]string{"env": "prod"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetControllerLabels("mallards", map[string]string{"env": "staging"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	controllers, err := s.store.ControllersByLabel("env", "prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllers, gc.HasLen, 1)
+	c.Assert(controllers["aws-test"].Labels, jc.DeepEquals, map[string]string{"env": "prod"})
+
+	controllers, err = s.store.ControllersByLabel("team", "prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllers, gc.HasLen, 0)
+}
+
+const recencyControllersYAML = `
+controllers:
+  old:
+    uuid: this-is-the-old-uuid
+    api-endpoints: [this-is-old-api-endpoint]
+    ca-cert: this-is-old-ca-cert
+    last-connection: "2016-08-01T12:00:00Z"
+  never:
+    uuid: this-is-the-never-uuid
+    api-endpoints: [this-is-never-api-endpoint]
+    ca-cert: this-is-never-ca-cert
+  new:
+    uuid: this-is-the-new-uuid
+    api-endpoints: [this-is-new-api-endpoint]
+    ca-cert: this-is-new-ca-cert
+    last-connection: "2016-09-01T12:00:00Z"
+  also-never:
+    uuid: this-is-the-also-never-uuid
+    api-endpoints: [this-is-also-never-api-endpoint]
+    ca-cert: this-is-also-never-ca-cert
+`
+
+func controllerNames(controllers []jujuclient.NamedControllerDetails) []string {
+	names := make([]string, len(controllers))
+	for i, controller := range controllers {
+		names[i] = controller.Name
+	}
+	return names
+}
+
+func (s *ControllersSuite) TestAllControllersByRecency(c *gc.C) {
+	writeStoreFile(c, "controllers.yaml", recencyControllersYAML)
+	controllers, err := s.store.AllControllersByRecency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllerNames(controllers), jc.DeepEquals, []string{
+		"new", "old", "also-never", "never",
+	})
+	c.Assert(controllers[0].LastConnection, gc.Equals, time.Date(2016, 9, 1, 12, 0, 0, 0, time.UTC))
+	c.Assert(controllers[2].LastConnection.IsZero(), jc.IsTrue)
+}
+
+func (s *ControllersSuite) TestAllControllersByRecencyNoFile(c *gc.C) {
+	controllers, err := s.store.AllControllersByRecency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllers, gc.HasLen, 0)
+}
+
+func (s *ControllersSuite) TestRecordControllerConnection(c *gc.C) {
+	writeStoreFile(c, "controllers.yaml", recencyControllersYAML)
+	before := time.Now().UTC().Truncate(time.Second)
+	err := s.store.RecordControllerConnection("never")
+	c.Assert(err, jc.ErrorIsNil)
+
+	controllers, err := s.store.AllControllersByRecency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllerNames(controllers), jc.DeepEquals, []string{
+		"never", "new", "old", "also-never",
+	})
+	c.Assert(controllers[0].LastConnection.Before(before), jc.IsFalse)
+}
+
+func (s *ControllersSuite) TestRecordControllerConnectionNotFound(c *gc.C) {
+	writeStoreFile(c, "controllers.yaml", recencyControllersYAML)
+	err := s.store.RecordControllerConnection("nope")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "controller nope not found")
+}
+
+func (s *ControllersSuite) TestLastConnectionInvalid(c *gc.C) {
+	_, err := jujuclient.ParseControllers([]byte(`
+controllers:
+  ctrl:
+    uuid: this-is-the-ctrl-uuid
+    last-connection: yesterday
+`))
+	c.Assert(err, gc.ErrorMatches, `cannot unmarshal yaml controllers metadata: cannot parse last-connection: .*`)
+}
+
+const switchToControllersYAML = `
+controllers:
+  ctrl:
+    uuid: this-is-the-ctrl-uuid
+    api-endpoints: [this-is-ctrl-api-endpoint]
+    ca-cert: this-is-ctrl-ca-cert
+  kontroll:
+    uuid: this-is-the-kontroll-uuid
+    api-endpoints: [this-is-kontroll-api-endpoint]
+    ca-cert: this-is-kontroll-ca-cert
+current-controller: kontroll
+`
+
+func (s *ControllersSuite) writeSwitchToStore(c *gc.C) {
+	writeStoreFile(c, "controllers.yaml", switchToControllersYAML)
+	writeStoreFile(c, "models.yaml", testModelsYAML)
+	writeStoreFile(c, "accounts.yaml", testAccountsYAML)
+}
+
+func (s *ControllersSuite) assertCurrent(c *gc.C, controllerName, modelName string) {
+	current, err := s.store.CurrentController()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(current, gc.Equals, controllerName)
+	models, err := jujuclient.ReadModelsFile(jujuclient.JujuModelsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(models[controllerName].CurrentModel, gc.Equals, modelName)
+}
+
+func (s *ControllersSuite) TestSwitchTo(c *gc.C) {
+	s.writeSwitchToStore(c)
+	err := s.store.SwitchTo("ctrl", "admin@local", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCurrent(c, "ctrl", "admin")
+
+	// The previous controller's current model is left alone.
+	current, err := s.store.CurrentModel("kontroll")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "my-model")
+}
+
+func (s *ControllersSuite) TestSwitchToCurrentController(c *gc.C) {
+	s.writeSwitchToStore(c)
+	err := s.store.SwitchTo("kontroll", "bob@remote", "my-model")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCurrent(c, "kontroll", "my-model")
+}
+
+func (s *ControllersSuite) TestSwitchToNotFound(c *gc.C) {
+	s.writeSwitchToStore(c)
+	for i, test := range []struct {
+		controller, account, model string
+		expect                     string
+	}{{
+		controller: "nope",
+		account:    "admin@local",
+		model:      "admin",
+		expect:     "controller nope not found",
+	}, {
+		controller: "ctrl",
+		account:    "bob@remote",
+		model:      "admin",
+		expect:     "account bob@remote for controller ctrl not found",
+	}, {
+		controller: "ctrl",
+		account:    "admin@local",
+		model:      "my-model",
+		expect:     "model ctrl:my-model not found",
+	}} {
+		c.Logf("test %d", i)
+		err := s.store.SwitchTo(test.controller, test.account, test.model)
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+		c.Check(err, gc.ErrorMatches, test.expect)
+
+		// Nothing is changed if any part of the switch fails.
+		s.assertCurrent(c, "kontroll", "my-model")
+		models, err := jujuclient.ReadModelsFile(jujuclient.JujuModelsPath())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(models["ctrl"].CurrentModel, gc.Equals, "")
+	}
+}
+
 func (s *ControllersSuite) assertWriteFails(c *gc.C, failureMessage string) {
 	err := s.store.UpdateController(s.controllerName, s.controller)
 	c.Assert(err, gc.ErrorMatches, failureMessage)
@@ -23,22 +23,22 @@ var _ = gc.Suite(&ControllersFileSuite{})
 const testControllersYAML = `
 controllers:
   aws-test:
-    unresolved-api-endpoints: [instance-1-2-4.useast.aws.com]
-    uuid: this-is-the-aws-test-uuid
-    api-endpoints: [this-is-aws-test-of-many-api-endpoints]
+    unresolved-api-endpoints: ['instance-1-2-4.useast.aws.com:17070']
+    uuid: 0b3c1d2e-4f5a-4b6c-8d7e-9f0a1b2c3d4e
+    api-endpoints: ['54.1.2.4:17070']
     ca-cert: this-is-aws-test-ca-cert
     cloud: aws
     region: us-east-1
   mallards:
-    unresolved-api-endpoints: [maas-1-05.cluster.mallards]
-    uuid: this-is-another-uuid
-    api-endpoints: [this-is-another-of-many-api-endpoints, this-is-one-more-of-many-api-endpoints]
+    unresolved-api-endpoints: ['maas-1-05.cluster.mallards:17070']
+    uuid: 5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9
+    api-endpoints: ['10.0.0.5:17070', '[fd00::5]:17070']
     ca-cert: this-is-another-ca-cert
     cloud: mallards
   mark-test-prodstack:
-    unresolved-api-endpoints: [vm-23532.prodstack.canonical.com, great.test.server.hostname.co.nz]
-    uuid: this-is-a-uuid
-    api-endpoints: [this-is-one-of-many-api-endpoints]
+    unresolved-api-endpoints: ['vm-23532.prodstack.canonical.com:17070', 'great.test.server.hostname.co.nz:17070']
+    uuid: 7c8d9e0f-1a2b-4c3d-9e4f-5a6b7c8d9e0f
+    api-endpoints: ['10.1.2.3:17070']
     ca-cert: this-is-a-ca-cert
     cloud: prodstack
 current-controller: mallards
@@ -4,6 +4,10 @@
 package jujuclient_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/jujuclient"
@@ -18,12 +22,16 @@ type ControllerValidationSuite struct {
 func (s *ControllerValidationSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.controller = jujuclient.ControllerDetails{
-		[]string{"test.server.hostname"},
-		"test.uuid",
-		[]string{"test.api.endpoint"},
+		[]string{"test.server.hostname:17070"},
+		"3a1f0c2e-5b7d-4e9a-8c6b-2d4f6a8b0c1e",
+		[]string{"10.0.0.1:17070"},
 		"test.ca.cert",
+		nil,
 		"aws",
 		"southeastasia",
+		nil,
+		"",
+		time.Time{},
 	}
 }
 
@@ -38,11 +46,77 @@ func (s *ControllerValidationSuite) TestValidateControllerDetailsNoControllerUUI
 	s.assertValidateControllerDetailsFails(c, "missing uuid, controller details not valid")
 }
 
+func (s *ControllerValidationSuite) TestValidateControllerDetailsInvalidControllerUUID(c *gc.C) {
+	s.controller.ControllerUUID = "test.uuid"
+	s.assertValidateControllerDetailsFails(c, `uuid "test.uuid", controller details not valid`)
+}
+
+func (s *ControllerValidationSuite) TestValidateControllerDetailsNoEndpoints(c *gc.C) {
+	s.controller.UnresolvedAPIEndpoints = nil
+	s.controller.APIEndpoints = nil
+	c.Assert(jujuclient.ValidateControllerDetails(s.controller), jc.ErrorIsNil)
+}
+
+func (s *ControllerValidationSuite) TestValidateControllerDetailsIPv6Endpoints(c *gc.C) {
+	s.controller.APIEndpoints = []string{"[::1]:17070", "[fd00:1::2]:443"}
+	c.Assert(jujuclient.ValidateControllerDetails(s.controller), jc.ErrorIsNil)
+
+	s.controller.APIEndpoints = []string{"fd00:1::2:443"}
+	err := jujuclient.ValidateControllerDetails(s.controller)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `api-endpoints: endpoint "fd00:1::2:443": .*too many colons.*`)
+}
+
+func (s *ControllerValidationSuite) TestValidateControllerDetailsInvalidEndpoints(c *gc.C) {
+	for i, test := range []struct {
+		endpoint string
+		expect   string
+	}{{
+		endpoint: "10.0.0.1",
+		expect:   `api-endpoints: endpoint "10.0.0.1": .*missing port.*`,
+	}, {
+		endpoint: ":17070",
+		expect:   `api-endpoints: endpoint ":17070" with no host not valid`,
+	}, {
+		endpoint: "10.0.0.1:http",
+		expect:   `api-endpoints: endpoint "10.0.0.1:http" with port "http" not valid`,
+	}, {
+		endpoint: "10.0.0.1:0",
+		expect:   `api-endpoints: endpoint "10.0.0.1:0" with port "0" not valid`,
+	}, {
+		endpoint: "10.0.0.1:65536",
+		expect:   `api-endpoints: endpoint "10.0.0.1:65536" with port "65536" not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.endpoint)
+		s.controller.APIEndpoints = []string{"10.0.0.2:17070", test.endpoint}
+		err := jujuclient.ValidateControllerDetails(s.controller)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *ControllerValidationSuite) TestValidateControllerDetailsInvalidUnresolvedEndpoint(c *gc.C) {
+	s.controller.UnresolvedAPIEndpoints = []string{"test.server.hostname"}
+	err := jujuclient.ValidateControllerDetails(s.controller)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `unresolved-api-endpoints: endpoint "test.server.hostname": .*missing port.*`)
+}
+
 func (s *ControllerValidationSuite) TestValidateControllerDetailsNoCACert(c *gc.C) {
 	s.controller.CACert = ""
 	s.assertValidateControllerDetailsFails(c, "missing ca-cert, controller details not valid")
 }
 
+func (s *ControllerValidationSuite) TestValidateControllerDetailsEmptyCACertsEntry(c *gc.C) {
+	s.controller.CACerts = []string{"rotation.cert", ""}
+	s.assertValidateControllerDetailsFails(c, "empty ca-certs entry, controller details not valid")
+}
+
+func (s *ControllerValidationSuite) TestValidateControllerDetailsEmptyLabelKey(c *gc.C) {
+	s.controller.Labels = map[string]string{"": "prod"}
+	s.assertValidateControllerDetailsFails(c, "empty label key, controller details not valid")
+}
+
 func (s *ControllerValidationSuite) assertValidateControllerDetailsFails(c *gc.C, failureMessage string) {
 	err := jujuclient.ValidateControllerDetails(s.controller)
 	c.Assert(err, gc.ErrorMatches, failureMessage)
@@ -38,19 +38,133 @@ func ReadCredentialsFile(file string) (map[string]cloud.CloudCredential, error)
 	return credentials, nil
 }
 
+// ReadCloudProxiesFile loads the per-cloud proxy settings defined in
+// a given credentials file, keyed on cloud name. If the file is not
+// found, or was written without proxy settings, it is not an error.
+func ReadCloudProxiesFile(file string) (map[string]ProxyConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var proxiesYAML struct {
+		Proxies map[string]ProxyConfig `yaml:"proxies"`
+	}
+	if err := yaml.Unmarshal(data, &proxiesYAML); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal yaml proxies")
+	}
+	return proxiesYAML.Proxies, nil
+}
+
 // WriteCredentialsFile marshals to YAML details of the given credentials
-// and writes it to the credentials file.
+// and writes it to the credentials file. Any proxy settings already in
+// the file are kept.
 func WriteCredentialsFile(credentials map[string]cloud.CloudCredential) error {
-	data, err := yaml.Marshal(credentialsCollection{credentials})
+	proxies, err := ReadCloudProxiesFile(JujuCredentialsPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get proxies")
+	}
+	return writeCredentialsFile(credentialsCollection{credentials, proxies})
+}
+
+// writeCredentialsFile marshals to YAML the given credentials and
+// proxy settings and writes them to the credentials file.
+func writeCredentialsFile(collection credentialsCollection) error {
+	data, err := yaml.Marshal(collection)
 	if err != nil {
 		return errors.Annotate(err, "cannot marshal yaml credentials")
 	}
 	return utils.AtomicWriteFile(JujuCredentialsPath(), data, os.FileMode(0600))
 }
 
+// moveCloudCredentials moves the credentials for fromCloud to toCloud
+// within all, as described by CredentialUpdater.MoveCredentials.
+func moveCloudCredentials(all map[string]cloud.CloudCredential, fromCloud, toCloud string, merge bool) error {
+	from, ok := all[fromCloud]
+	if !ok {
+		return errors.NotFoundf("credentials for cloud %s", fromCloud)
+	}
+	if fromCloud == toCloud {
+		return nil
+	}
+	to, ok := all[toCloud]
+	if !ok {
+		all[toCloud] = from
+		delete(all, fromCloud)
+		return nil
+	}
+	if !merge {
+		return errors.AlreadyExistsf("credentials for cloud %s", toCloud)
+	}
+	for name := range from.AuthCredentials {
+		if _, ok := to.AuthCredentials[name]; ok {
+			return errors.AlreadyExistsf("credential %q for cloud %s", name, toCloud)
+		}
+	}
+	if to.AuthCredentials == nil {
+		to.AuthCredentials = make(map[string]cloud.Credential)
+	}
+	for name, credential := range from.AuthCredentials {
+		to.AuthCredentials[name] = credential
+	}
+	if to.DefaultCredential == "" {
+		to.DefaultCredential = from.DefaultCredential
+	}
+	if to.DefaultRegion == "" {
+		to.DefaultRegion = from.DefaultRegion
+	}
+	all[toCloud] = to
+	delete(all, fromCloud)
+	return nil
+}
+
+// namedCloudCredential returns the credential with the given name
+// for the named cloud from all, as described by
+// CredentialGetter.CredentialForCloudAndName.
+func namedCloudCredential(all map[string]cloud.CloudCredential, cloudName, credentialName string) (*cloud.Credential, error) {
+	credentials, ok := all[cloudName]
+	if !ok {
+		return nil, errors.NotFoundf("credentials for cloud %s", cloudName)
+	}
+	credential, ok := credentials.AuthCredentials[credentialName]
+	if !ok {
+		return nil, errors.NotFoundf("credential %q for cloud %s", credentialName, cloudName)
+	}
+	return &credential, nil
+}
+
+// setDefaultCloudCredential makes the named credential the default
+// for the named cloud within all, as described by
+// CredentialUpdater.SetDefaultCredential.
+func setDefaultCloudCredential(all map[string]cloud.CloudCredential, cloudName, credentialName string) error {
+	if _, err := namedCloudCredential(all, cloudName, credentialName); err != nil {
+		return errors.Trace(err)
+	}
+	credentials := all[cloudName]
+	credentials.DefaultCredential = credentialName
+	all[cloudName] = credentials
+	return nil
+}
+
+// defaultCloudCredential returns the name of the default credential
+// for the named cloud within all, as described by
+// CredentialGetter.DefaultCredential.
+func defaultCloudCredential(all map[string]cloud.CloudCredential, cloudName string) (string, error) {
+	credentials, ok := all[cloudName]
+	if !ok {
+		return "", errors.NotFoundf("credentials for cloud %s", cloudName)
+	}
+	return credentials.DefaultCredential, nil
+}
+
 // credentialsCollection is a struct containing cloud credential information,
 // used marshalling and unmarshalling.
 type credentialsCollection struct {
 	// Credentials is a map of cloud credentials, keyed on cloud name.
 	Credentials map[string]cloud.CloudCredential `yaml:"credentials"`
+
+	// Proxies is a map of proxy settings, keyed on cloud name.
+	Proxies map[string]ProxyConfig `yaml:"proxies,omitempty"`
 }
@@ -4,6 +4,7 @@
 package jujuclient_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -57,6 +58,32 @@ func (s *CredentialsSuite) TestCredentialForCloud(c *gc.C) {
 	c.Assert(found, gc.DeepEquals, &expected)
 }
 
+func (s *CredentialsSuite) TestCredentialForCloudAndName(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.CredentialForCloudAndName(s.cloudName, "paul")
+	c.Assert(err, jc.ErrorIsNil)
+	expected := s.credentials.AuthCredentials["paul"]
+	c.Assert(found, jc.DeepEquals, &expected)
+}
+
+func (s *CredentialsSuite) TestCredentialForCloudAndNameNoCloud(c *gc.C) {
+	writeTestCredentialsFile(c)
+	found, err := s.store.CredentialForCloudAndName(s.cloudName, "paul")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "credentials for cloud testcloud not found")
+	c.Assert(found, gc.IsNil)
+}
+
+func (s *CredentialsSuite) TestCredentialForCloudAndNameNoName(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.store.CredentialForCloudAndName(s.cloudName, "mary")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `credential "mary" for cloud testcloud not found`)
+	c.Assert(found, gc.IsNil)
+}
+
 func (s *CredentialsSuite) TestUpdateCredentialAddFirst(c *gc.C) {
 	err := s.store.UpdateCredential(s.cloudName, s.credentials)
 	c.Assert(err, jc.ErrorIsNil)
@@ -97,6 +124,254 @@ func (s *CredentialsSuite) TestUpdateCredentialRemovesDefaultIfNecessary(c *gc.C
 	c.Assert(creds[s.cloudName].DefaultCredential, gc.Equals, "")
 }
 
+func (s *CredentialsSuite) TestDefaultCredential(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	name, err := s.store.DefaultCredential(s.cloudName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "peter")
+}
+
+func (s *CredentialsSuite) TestDefaultCredentialNoCloud(c *gc.C) {
+	_, err := s.store.DefaultCredential(s.cloudName)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "credentials for cloud testcloud not found")
+}
+
+func (s *CredentialsSuite) TestSetDefaultCredential(c *gc.C) {
+	s.credentials.DefaultCredential = ""
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.SetDefaultCredential(s.cloudName, "paul")
+	c.Assert(err, jc.ErrorIsNil)
+	name, err := s.store.DefaultCredential(s.cloudName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "paul")
+	c.Assert(s.getCredentials(c)[s.cloudName].DefaultCredential, gc.Equals, "paul")
+}
+
+func (s *CredentialsSuite) TestSetDefaultCredentialOverwrites(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.SetDefaultCredential(s.cloudName, "paul")
+	c.Assert(err, jc.ErrorIsNil)
+	name, err := s.store.DefaultCredential(s.cloudName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "paul")
+
+	// The rest of the cloud's credentials are left alone.
+	expected := s.credentials
+	expected.DefaultCredential = "paul"
+	c.Assert(s.getCredentials(c)[s.cloudName], jc.DeepEquals, expected)
+}
+
+func (s *CredentialsSuite) TestSetDefaultCredentialNotFound(c *gc.C) {
+	err := s.store.SetDefaultCredential(s.cloudName, "paul")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "credentials for cloud testcloud not found")
+
+	err = s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetDefaultCredential(s.cloudName, "mary")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `credential "mary" for cloud testcloud not found`)
+
+	name, err := s.store.DefaultCredential(s.cloudName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "peter")
+}
+
+func (s *CredentialsSuite) TestSetDefaultCredentialClearedByRemoval(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetDefaultCredential(s.cloudName, "paul")
+	c.Assert(err, jc.ErrorIsNil)
+
+	newCreds := cloud.CloudCredential{
+		DefaultCredential: "paul",
+		AuthCredentials: map[string]cloud.Credential{
+			"peter": s.credentials.AuthCredentials["peter"],
+		},
+	}
+	err = s.store.UpdateCredential(s.cloudName, newCreds)
+	c.Assert(err, jc.ErrorIsNil)
+	name, err := s.store.DefaultCredential(s.cloudName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "")
+}
+
+func (s *CredentialsSuite) TestMoveCredentials(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.MoveCredentials(s.cloudName, "renamed", false)
+	c.Assert(err, jc.ErrorIsNil)
+	all := s.getCredentials(c)
+	_, ok := all[s.cloudName]
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(all["renamed"], jc.DeepEquals, s.credentials)
+}
+
+func (s *CredentialsSuite) TestMoveCredentialsNotFound(c *gc.C) {
+	err := s.store.MoveCredentials(s.cloudName, "renamed", false)
+	c.Assert(err, gc.ErrorMatches, "credentials for cloud testcloud not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CredentialsSuite) TestMoveCredentialsConflict(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateCredential("renamed", cloud.CloudCredential{
+		AuthCredentials: map[string]cloud.Credential{
+			"mary": cloud.NewCredential(cloud.AccessKeyAuthType, nil),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	before := s.getCredentials(c)
+
+	err = s.store.MoveCredentials(s.cloudName, "renamed", false)
+	c.Assert(err, gc.ErrorMatches, "credentials for cloud renamed already exists")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(s.getCredentials(c), jc.DeepEquals, before)
+}
+
+func (s *CredentialsSuite) TestMoveCredentialsMerge(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateCredential("renamed", cloud.CloudCredential{
+		DefaultCredential: "mary",
+		AuthCredentials: map[string]cloud.Credential{
+			"mary": cloud.NewCredential(cloud.AccessKeyAuthType, nil),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.MoveCredentials(s.cloudName, "renamed", true)
+	c.Assert(err, jc.ErrorIsNil)
+	all := s.getCredentials(c)
+	_, ok := all[s.cloudName]
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(all["renamed"], jc.DeepEquals, cloud.CloudCredential{
+		DefaultCredential: "mary",
+		DefaultRegion:     "east",
+		AuthCredentials: map[string]cloud.Credential{
+			"peter": cloud.NewCredential(cloud.AccessKeyAuthType, nil),
+			"paul":  cloud.NewCredential(cloud.AccessKeyAuthType, nil),
+			"mary":  cloud.NewCredential(cloud.AccessKeyAuthType, nil),
+		},
+	})
+}
+
+func (s *CredentialsSuite) TestMoveCredentialsMergeConflict(c *gc.C) {
+	err := s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateCredential("renamed", cloud.CloudCredential{
+		AuthCredentials: map[string]cloud.Credential{
+			"paul": cloud.NewCredential(cloud.UserPassAuthType, nil),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	before := s.getCredentials(c)
+
+	err = s.store.MoveCredentials(s.cloudName, "renamed", true)
+	c.Assert(err, gc.ErrorMatches, `credential "paul" for cloud renamed already exists`)
+	c.Assert(s.getCredentials(c), jc.DeepEquals, before)
+}
+
+func (s *CredentialsSuite) TestCloudProxyNoFile(c *gc.C) {
+	proxy, err := s.store.CloudProxy("aws")
+	c.Assert(err, gc.ErrorMatches, "proxy for cloud aws not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(proxy, gc.IsNil)
+}
+
+func (s *CredentialsSuite) TestCloudProxyOldFile(c *gc.C) {
+	// Files written before proxies were supported have none.
+	writeTestCredentialsFile(c)
+	_, err := s.store.CloudProxy("aws")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CredentialsSuite) TestSetCloudProxy(c *gc.C) {
+	credentials := writeTestCredentialsFile(c)
+	internal := jujuclient.ProxyConfig{
+		HTTP:    "http://proxy.internal:3128",
+		NoProxy: "localhost,10.0.0.0/8",
+	}
+	external := jujuclient.ProxyConfig{
+		HTTP:  "http://proxy.example.com:8080",
+		HTTPS: "https://proxy.example.com:8443",
+	}
+	err := s.store.SetCloudProxy("aws", internal)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetCloudProxy("aws-gov", external)
+	c.Assert(err, jc.ErrorIsNil)
+
+	proxy, err := s.store.CloudProxy("aws")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*proxy, jc.DeepEquals, internal)
+	proxy, err = s.store.CloudProxy("aws-gov")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*proxy, jc.DeepEquals, external)
+
+	// Setting proxies leaves the credentials alone.
+	c.Assert(s.getCredentials(c), jc.DeepEquals, credentials)
+}
+
+func (s *CredentialsSuite) TestSetCloudProxyKeptByCredentialUpdates(c *gc.C) {
+	proxy := jujuclient.ProxyConfig{HTTP: "http://proxy.internal:3128"}
+	err := s.store.SetCloudProxy(s.cloudName, proxy)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.UpdateCredential(s.cloudName, s.credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.MoveCredentials(s.cloudName, "renamed", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	found, err := s.store.CloudProxy(s.cloudName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, proxy)
+}
+
+func (s *CredentialsSuite) TestSetCloudProxyEmptyRemoves(c *gc.C) {
+	err := s.store.SetCloudProxy("aws", jujuclient.ProxyConfig{HTTP: "http://proxy:3128"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetCloudProxy("aws", jujuclient.ProxyConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.CloudProxy("aws")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CredentialsSuite) TestSetCloudProxyInvalid(c *gc.C) {
+	for i, test := range []struct {
+		proxy  jujuclient.ProxyConfig
+		expect string
+	}{{
+		proxy:  jujuclient.ProxyConfig{HTTP: "proxy:3128"},
+		expect: `http-proxy "proxy:3128", proxy config not valid`,
+	}, {
+		proxy:  jujuclient.ProxyConfig{HTTPS: "ftp://proxy:21"},
+		expect: `https-proxy "ftp://proxy:21", proxy config not valid`,
+	}, {
+		proxy:  jujuclient.ProxyConfig{HTTP: "http://"},
+		expect: `http-proxy "http://", proxy config not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.store.SetCloudProxy("aws", test.proxy)
+		c.Check(err, gc.ErrorMatches, test.expect)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+	_, err := s.store.CloudProxy("aws")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CredentialsSuite) TestSetCloudProxyEmptyCloudName(c *gc.C) {
+	err := s.store.SetCloudProxy("", jujuclient.ProxyConfig{HTTP: "http://proxy:3128"})
+	c.Assert(err, gc.ErrorMatches, "empty cloud name not valid")
+}
+
 func (s *CredentialsSuite) assertCredentialsNotExists(c *gc.C) {
 	all := writeTestCredentialsFile(c)
 	_, exists := all[s.cloudName]
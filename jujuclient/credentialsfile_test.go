@@ -64,6 +64,33 @@ func (s *CredentialsFileSuite) TestReadEmptyFile(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *CredentialsFileSuite) TestReadCloudProxiesFile(c *gc.C) {
+	err := ioutil.WriteFile(osenv.JujuXDGDataHomePath("credentials.yaml"), []byte(testCredentialsYAML+`
+proxies:
+  aws:
+    http-proxy: http://proxy.internal:3128
+    no-proxy: localhost
+`), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	proxies, err := jujuclient.ReadCloudProxiesFile(jujuclient.JujuCredentialsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(proxies, jc.DeepEquals, map[string]jujuclient.ProxyConfig{
+		"aws": {HTTP: "http://proxy.internal:3128", NoProxy: "localhost"},
+	})
+
+	// The credentials in the file are unaffected.
+	credentials, err := jujuclient.ReadCredentialsFile(jujuclient.JujuCredentialsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials, jc.DeepEquals, parseCredentials(c))
+}
+
+func (s *CredentialsFileSuite) TestReadCloudProxiesNoFile(c *gc.C) {
+	proxies, err := jujuclient.ReadCloudProxiesFile("nohere.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(proxies, gc.IsNil)
+}
+
 func parseCredentials(c *gc.C) map[string]cloud.CloudCredential {
 	credentials, err := cloud.ParseCredentials([]byte(testCredentialsYAML))
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,118 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloud"
+)
+
+// CredentialSummary describes a single credential held in a client
+// store, without its secret attributes.
+type CredentialSummary struct {
+	// Cloud is the name of the cloud the credential is for.
+	Cloud string
+
+	// Name is the name of the credential.
+	Name string
+
+	// AuthType is the credential's authentication type.
+	AuthType cloud.AuthType
+
+	// Label is the credential's description, if any.
+	Label string
+
+	// IsDefault is true if the credential is the cloud's default.
+	IsDefault bool
+
+	// Controllers holds the names of the controllers which the store
+	// records as having been bootstrapped with the credential, sorted
+	// by name.
+	Controllers []string
+}
+
+// AllCredentialsDetailed returns a summary of every credential in the
+// store, ordered by cloud, then auth type, then name, so that callers
+// can group credentials without sorting them again.
+func AllCredentialsDetailed(store ClientStore) ([]CredentialSummary, error) {
+	credentials, err := store.AllCredentials()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	usedBy, err := credentialControllers(store)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var summaries []CredentialSummary
+	for cloudName, cloudCredential := range credentials {
+		for name, credential := range cloudCredential.AuthCredentials {
+			summaries = append(summaries, CredentialSummary{
+				Cloud:       cloudName,
+				Name:        name,
+				AuthType:    credential.AuthType(),
+				Label:       credential.Label,
+				IsDefault:   name == cloudCredential.DefaultCredential,
+				Controllers: usedBy[credentialKey{cloudName, name}],
+			})
+		}
+	}
+	sort.Sort(credentialSummaries(summaries))
+	return summaries, nil
+}
+
+type credentialKey struct {
+	cloud string
+	name  string
+}
+
+// credentialControllers returns the names of the controllers
+// bootstrapped with each credential, according to the store's
+// bootstrap config.
+func credentialControllers(store ClientStore) (map[credentialKey][]string, error) {
+	controllers, err := store.AllControllers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var names []string
+	for name := range controllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	usedBy := make(map[credentialKey][]string)
+	for _, name := range names {
+		cfg, err := store.BootstrapConfigForController(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if cfg.Credential == "" {
+			// An auto-detected credential was used, which
+			// isn't in the store.
+			continue
+		}
+		key := credentialKey{cfg.Cloud, cfg.Credential}
+		usedBy[key] = append(usedBy[key], name)
+	}
+	return usedBy, nil
+}
+
+type credentialSummaries []CredentialSummary
+
+func (s credentialSummaries) Len() int      { return len(s) }
+func (s credentialSummaries) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s credentialSummaries) Less(i, j int) bool {
+	if s[i].Cloud != s[j].Cloud {
+		return s[i].Cloud < s[j].Cloud
+	}
+	if s[i].AuthType != s[j].AuthType {
+		return s[i].AuthType < s[j].AuthType
+	}
+	return s[i].Name < s[j].Name
+}
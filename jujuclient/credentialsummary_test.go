@@ -0,0 +1,105 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type CredentialSummarySuite struct {
+	testing.BaseSuite
+	store *jujuclienttesting.MemStore
+}
+
+var _ = gc.Suite(&CredentialSummarySuite{})
+
+func (s *CredentialSummarySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.store = jujuclienttesting.NewMemStore()
+
+	labelled := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": "bob",
+		"password": "hunter2",
+	})
+	labelled.Label = "bob's account"
+	s.store.Credentials["aws"] = cloud.CloudCredential{
+		DefaultCredential: "peter",
+		AuthCredentials: map[string]cloud.Credential{
+			"peter": cloud.NewCredential(cloud.AccessKeyAuthType, nil),
+			"paul":  cloud.NewCredential(cloud.AccessKeyAuthType, nil),
+			"bob":   labelled,
+		},
+	}
+	s.store.Credentials["google"] = cloud.CloudCredential{
+		AuthCredentials: map[string]cloud.Credential{
+			"oauth": cloud.NewCredential(cloud.OAuth2AuthType, nil),
+			"file":  cloud.NewCredential(cloud.JSONFileAuthType, nil),
+		},
+	}
+
+	for _, name := range []string{"ctrl1", "ctrl2", "ctrl3", "ctrl4"} {
+		s.store.Controllers[name] = jujuclient.ControllerDetails{
+			ControllerUUID: name + "-uuid",
+			CACert:         "ca-cert",
+		}
+	}
+	s.store.BootstrapConfig["ctrl1"] = jujuclient.BootstrapConfig{
+		Cloud:      "aws",
+		Credential: "peter",
+	}
+	s.store.BootstrapConfig["ctrl2"] = jujuclient.BootstrapConfig{
+		Cloud:      "aws",
+		Credential: "peter",
+	}
+	s.store.BootstrapConfig["ctrl3"] = jujuclient.BootstrapConfig{
+		Cloud:      "google",
+		Credential: "file",
+	}
+	// ctrl4 was bootstrapped with an auto-detected credential.
+	s.store.BootstrapConfig["ctrl4"] = jujuclient.BootstrapConfig{
+		Cloud: "aws",
+	}
+}
+
+func (s *CredentialSummarySuite) TestAllCredentialsDetailed(c *gc.C) {
+	summaries, err := jujuclient.AllCredentialsDetailed(s.store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summaries, jc.DeepEquals, []jujuclient.CredentialSummary{{
+		Cloud:    "aws",
+		Name:     "paul",
+		AuthType: cloud.AccessKeyAuthType,
+	}, {
+		Cloud:       "aws",
+		Name:        "peter",
+		AuthType:    cloud.AccessKeyAuthType,
+		IsDefault:   true,
+		Controllers: []string{"ctrl1", "ctrl2"},
+	}, {
+		Cloud:    "aws",
+		Name:     "bob",
+		AuthType: cloud.UserPassAuthType,
+		Label:    "bob's account",
+	}, {
+		Cloud:       "google",
+		Name:        "file",
+		AuthType:    cloud.JSONFileAuthType,
+		Controllers: []string{"ctrl3"},
+	}, {
+		Cloud:    "google",
+		Name:     "oauth",
+		AuthType: cloud.OAuth2AuthType,
+	}})
+}
+
+func (s *CredentialSummarySuite) TestAllCredentialsDetailedEmpty(c *gc.C) {
+	summaries, err := jujuclient.AllCredentialsDetailed(jujuclienttesting.NewMemStore())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summaries, gc.HasLen, 0)
+}
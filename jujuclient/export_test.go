@@ -0,0 +1,10 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+var (
+	LockTimeout   = &lockTimeout
+	LockName      = lockName
+	WatchInterval = &watchInterval
+)
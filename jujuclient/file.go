@@ -13,6 +13,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/mutex"
 	"github.com/juju/utils/clock"
+	"github.com/juju/version"
 
 	"github.com/juju/juju/cloud"
 )
@@ -26,22 +27,42 @@ var logger = loggo.GetLogger("juju.jujuclient")
 // reasonable time to get the lock.
 var lockTimeout = 5 * time.Second
 
+// lockName is the name of the lock held while the store's files are
+// read and written.
+const lockName = "store-lock"
+
 // NewFileClientStore returns a new filesystem-based client store
 // that manages files in $XDG_DATA_HOME/juju.
 func NewFileClientStore() ClientStore {
 	return &store{}
 }
 
+// NewEncryptedFileStore returns a new filesystem-based client store
+// like that returned by NewFileClientStore, except that account
+// passwords are encrypted with AES-GCM, using a key derived from the
+// given passphrase. Everything else is stored as plain text. If the
+// passphrase is empty, passwords are not encrypted.
+func NewEncryptedFileStore(passphrase string) ClientStore {
+	return &store{passphrase: passphrase}
+}
+
 // NewFileCredentialStore returns a new filesystem-based credentials store
 // that manages credentials in $XDG_DATA_HOME/juju.
 func NewFileCredentialStore() CredentialStore {
 	return &store{}
 }
 
-type store struct{}
+type store struct {
+	// passphrase, if not empty, is used to encrypt account
+	// passwords.
+	passphrase string
+}
 
+// acquireLock acquires the lock which serialises access to the
+// store's files, both within and between processes. Callers must
+// release the lock, using defer so that it is released even if they
+// panic.
 func (s *store) acquireLock() (mutex.Releaser, error) {
-	const lockName = "store-lock"
 	spec := mutex.Spec{
 		Name:    lockName,
 		Clock:   clock.WallClock,
@@ -49,7 +70,12 @@ func (s *store) acquireLock() (mutex.Releaser, error) {
 		Timeout: lockTimeout,
 	}
 	releaser, err := mutex.Acquire(spec)
-	if err != nil {
+	if errors.Cause(err) == mutex.ErrTimeout {
+		return nil, errors.Errorf(
+			"timed out after %v waiting for the client store lock; another juju command may be using it",
+			lockTimeout,
+		)
+	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return releaser, nil
@@ -102,12 +128,49 @@ func (s *store) ControllerByName(name string) (*ControllerDetails, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	if result, ok := controllers.Controllers[name]; ok {
+	resolved := ResolveControllerName(controllers.Controllers, controllers.Aliases, name)
+	if result, ok := controllers.Controllers[resolved]; ok {
 		return &result, nil
 	}
 	return nil, errors.NotFoundf("controller %s", name)
 }
 
+// ControllersByLabel implements ControllersGetter.
+func (s *store) ControllersByLabel(key, value string) (map[string]ControllerDetails, error) {
+	controllers, err := s.AllControllers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return FilterControllersByLabel(controllers, key, value), nil
+}
+
+// FindControllersByUUID implements ControllersGetter.
+func (s *store) FindControllersByUUID(controllerUUID string) ([]string, error) {
+	controllers, err := s.AllControllers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ControllerNamesByUUID(controllers, controllerUUID), nil
+}
+
+// AllControllersByRecency implements ControllersGetter.
+func (s *store) AllControllersByRecency() ([]NamedControllerDetails, error) {
+	controllers, err := s.AllControllers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return SortControllersByRecency(controllers), nil
+}
+
+// CheckClientCompatibility implements ControllersGetter.
+func (s *store) CheckClientCompatibility(name string, clientVersion version.Number) error {
+	details, err := s.ControllerByName(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(CheckVersionCompatibility(name, *details, clientVersion))
+}
+
 // UpdateController implements ControllersUpdater.
 func (s *store) UpdateController(name string, details ControllerDetails) error {
 	if err := ValidateControllerName(name); err != nil {
@@ -131,8 +194,19 @@ func (s *store) UpdateController(name string, details ControllerDetails) error {
 	if len(all.Controllers) == 0 {
 		all.Controllers = make(map[string]ControllerDetails)
 	}
+	for _, other := range ControllerNamesByUUID(all.Controllers, details.ControllerUUID) {
+		if other != name {
+			logger.Warningf(
+				"controller %v has the same UUID as controller %v; it may be a duplicate",
+				name, other,
+			)
+		}
+	}
 
-	all.Controllers[name] = details
+	all.Controllers[name] = preserveCACerts(details, all.Controllers[name])
+	// A controller's name takes precedence over any alias with the
+	// same name, so the alias is no longer needed.
+	delete(all.Aliases, name)
 	return WriteControllersFile(all)
 }
 
@@ -162,6 +236,185 @@ func (s *store) SetCurrentController(name string) error {
 	return WriteControllersFile(controllers)
 }
 
+// SetControllerLabels implements ControllersUpdater.
+func (s *store) SetControllerLabels(name string, labels map[string]string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerLabels(labels); err != nil {
+		return errors.Trace(err)
+	}
+
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot set labels for controller %v", name)
+	}
+	defer releaser.Release()
+
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	details, ok := controllers.Controllers[name]
+	if !ok {
+		return errors.NotFoundf("controller %v", name)
+	}
+	details.Labels = labels
+	controllers.Controllers[name] = details
+	return WriteControllersFile(controllers)
+}
+
+// UpdateControllerEndpoints implements ControllersUpdater.
+func (s *store) UpdateControllerEndpoints(name string, apiEndpoints []string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerEndpoints(apiEndpoints); err != nil {
+		return errors.Trace(err)
+	}
+
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot update endpoints for controller %v", name)
+	}
+	defer releaser.Release()
+
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	details, ok := controllers.Controllers[name]
+	if !ok {
+		return errors.NotFoundf("controller %v", name)
+	}
+	details.APIEndpoints = apiEndpoints
+	details.UnresolvedAPIEndpoints = apiEndpoints
+	controllers.Controllers[name] = details
+	return WriteControllersFile(controllers)
+}
+
+// RecordControllerConnection implements ControllersUpdater.
+func (s *store) RecordControllerConnection(name string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot record connection to controller %v", name)
+	}
+	defer releaser.Release()
+
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	details, ok := controllers.Controllers[name]
+	if !ok {
+		return errors.NotFoundf("controller %v", name)
+	}
+	details.LastConnection = time.Now().UTC()
+	controllers.Controllers[name] = details
+	return WriteControllersFile(controllers)
+}
+
+// SetControllerAlias implements ControllersUpdater.
+func (s *store) SetControllerAlias(alias, name string) error {
+	if err := ValidateControllerName(alias); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot set alias for controller %v", name)
+	}
+	defer releaser.Release()
+
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name = ResolveControllerName(controllers.Controllers, controllers.Aliases, name)
+	if err := checkControllerAlias(controllers.Controllers, alias, name); err != nil {
+		return errors.Trace(err)
+	}
+	if controllers.Aliases == nil {
+		controllers.Aliases = make(map[string]string)
+	}
+	controllers.Aliases[alias] = name
+	return WriteControllersFile(controllers)
+}
+
+// SwitchTo implements ControllersUpdater.
+func (s *store) SwitchTo(controllerName, accountName, modelName string) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot switch to %s:%s", controllerName, modelName)
+	}
+	defer releaser.Release()
+
+	// Check that everything exists before changing anything.
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := controllers.Controllers[controllerName]; !ok {
+		return errors.NotFoundf("controller %v", controllerName)
+	}
+	accounts, err := ReadAccountsFile(JujuAccountsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if account, ok := accounts[controllerName]; !ok || account.User != accountName {
+		return errors.NotFoundf("account %s for controller %s", accountName, controllerName)
+	}
+	models, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	controllerModels, ok := models[controllerName]
+	if !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	if _, ok := controllerModels.Models[modelName]; !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+
+	// The models file is written first, so that if writing the
+	// controllers file fails the current model can be put back.
+	oldModelName := controllerModels.CurrentModel
+	if oldModelName != modelName {
+		controllerModels.CurrentModel = modelName
+		if err := WriteModelsFile(models); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if controllers.CurrentController == controllerName {
+		return nil
+	}
+	controllers.CurrentController = controllerName
+	if err := WriteControllersFile(controllers); err != nil {
+		if oldModelName != modelName {
+			controllerModels.CurrentModel = oldModelName
+			if rollbackErr := WriteModelsFile(models); rollbackErr != nil {
+				logger.Errorf("cannot restore current model for controller %s: %v", controllerName, rollbackErr)
+			}
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // RemoveController implements ControllersRemover
 func (s *store) RemoveController(name string) error {
 	if err := ValidateControllerName(name); err != nil {
@@ -189,6 +442,7 @@ func (s *store) RemoveController(name string) error {
 		if details.ControllerUUID == namedControllerDetails.ControllerUUID {
 			names = append(names, name)
 			delete(controllers.Controllers, name)
+			removeControllerAliases(controllers.Aliases, name)
 			if controllers.CurrentController == name {
 				controllers.CurrentController = ""
 			}
@@ -264,10 +518,11 @@ func (s *store) UpdateModel(controllerName, modelName string, details ModelDetai
 		controllerName,
 		func(models *ControllerModels) (bool, error) {
 			oldDetails, ok := models.Models[modelName]
-			if ok && details == oldDetails {
+			newDetails := preserveLastUsed(details, oldDetails)
+			if ok && newDetails == oldDetails {
 				return false, nil
 			}
-			models.Models[modelName] = details
+			models.Models[modelName] = newDetails
 			return true, nil
 		},
 	))
@@ -291,16 +546,16 @@ func (s *store) SetCurrentModel(controllerName, modelName string) error {
 	return errors.Trace(updateModels(
 		controllerName,
 		func(models *ControllerModels) (bool, error) {
-			if models.CurrentModel == modelName {
-				return false, nil
-			}
-			if _, ok := models.Models[modelName]; !ok {
+			details, ok := models.Models[modelName]
+			if !ok {
 				return false, errors.NotFoundf(
 					"model %s:%s",
 					controllerName,
 					modelName,
 				)
 			}
+			details.LastUsed = time.Now().UTC()
+			models.Models[modelName] = details
 			models.CurrentModel = modelName
 			return true, nil
 		},
@@ -402,6 +657,40 @@ func (s *store) ModelByName(controllerName, modelName string) (*ModelDetails, er
 	return &details, nil
 }
 
+// ModelByUUID implements ModelGetter.
+func (s *store) ModelByUUID(controllerName, accountName, uuid string) (string, *ModelDetails, error) {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	defer releaser.Release()
+
+	accounts, err := ReadAccountsFile(JujuAccountsPath())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if account, ok := accounts[controllerName]; !ok || account.User != accountName {
+		return "", nil, errors.NotFoundf("account %s for controller %s", accountName, controllerName)
+	}
+	all, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	controllerModels, ok := all[controllerName]
+	if !ok {
+		return "", nil, errors.NotFoundf(
+			"models for controller %s",
+			controllerName,
+		)
+	}
+	name, details, err := FindModelByUUID(controllerName, controllerModels.Models, uuid)
+	return name, details, errors.Trace(err)
+}
+
 // RemoveModel implements ModelRemover.
 func (s *store) RemoveModel(controllerName, modelName string) error {
 	if err := ValidateControllerName(controllerName); err != nil {
@@ -487,10 +776,19 @@ func (s *store) UpdateAccount(controllerName string, details AccountDetails) err
 	if accounts == nil {
 		accounts = make(map[string]AccountDetails)
 	}
-	if oldDetails, ok := accounts[controllerName]; ok && details == oldDetails {
-		return nil
+	if oldDetails, ok := accounts[controllerName]; ok {
+		// The old password can't be compared directly if it is
+		// encrypted, as each encryption is salted differently.
+		oldDetails, err := s.decryptAccount(controllerName, oldDetails)
+		if err == nil && details == oldDetails {
+			return nil
+		}
 	}
 
+	details, err = s.encryptAccount(details)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	accounts[controllerName] = details
 	return errors.Trace(WriteAccountsFile(accounts))
 }
@@ -515,6 +813,10 @@ func (s *store) AccountDetails(controllerName string) (*AccountDetails, error) {
 	if !ok {
 		return nil, errors.NotFoundf("account details for controller %s", controllerName)
 	}
+	details, err = s.decryptAccount(controllerName, details)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &details, nil
 }
 
@@ -577,6 +879,42 @@ func (s *store) UpdateCredential(cloudName string, details cloud.CloudCredential
 	return WriteCredentialsFile(all)
 }
 
+// MoveCredentials implements CredentialUpdater.
+func (s *store) MoveCredentials(fromCloud, toCloud string, merge bool) error {
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot move credentials for %v", fromCloud)
+	}
+	defer releaser.Release()
+
+	all, err := ReadCredentialsFile(JujuCredentialsPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get credentials")
+	}
+	if err := moveCloudCredentials(all, fromCloud, toCloud, merge); err != nil {
+		return errors.Trace(err)
+	}
+	return WriteCredentialsFile(all)
+}
+
+// SetDefaultCredential implements CredentialUpdater.
+func (s *store) SetDefaultCredential(cloudName, credentialName string) error {
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot set default credential for %v", cloudName)
+	}
+	defer releaser.Release()
+
+	all, err := ReadCredentialsFile(JujuCredentialsPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get credentials")
+	}
+	if err := setDefaultCloudCredential(all, cloudName, credentialName); err != nil {
+		return errors.Trace(err)
+	}
+	return WriteCredentialsFile(all)
+}
+
 // CredentialForCloud implements CredentialGetter.
 func (s *store) CredentialForCloud(cloudName string) (*cloud.CloudCredential, error) {
 	cloudCredentials, err := s.AllCredentials()
@@ -590,6 +928,24 @@ func (s *store) CredentialForCloud(cloudName string) (*cloud.CloudCredential, er
 	return &credentials, nil
 }
 
+// CredentialForCloudAndName implements CredentialGetter.
+func (s *store) CredentialForCloudAndName(cloudName, credentialName string) (*cloud.Credential, error) {
+	cloudCredentials, err := s.AllCredentials()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return namedCloudCredential(cloudCredentials, cloudName, credentialName)
+}
+
+// DefaultCredential implements CredentialGetter.
+func (s *store) DefaultCredential(cloudName string) (string, error) {
+	cloudCredentials, err := s.AllCredentials()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return defaultCloudCredential(cloudCredentials, cloudName)
+}
+
 // AllCredentials implements CredentialGetter.
 func (s *store) AllCredentials() (map[string]cloud.CloudCredential, error) {
 	cloudCredentials, err := ReadCredentialsFile(JujuCredentialsPath())
@@ -599,6 +955,53 @@ func (s *store) AllCredentials() (map[string]cloud.CloudCredential, error) {
 	return cloudCredentials, nil
 }
 
+// SetCloudProxy implements CredentialUpdater.
+func (s *store) SetCloudProxy(cloudName string, proxy ProxyConfig) error {
+	if cloudName == "" {
+		return errors.NotValidf("empty cloud name")
+	}
+	if err := ValidateProxyConfig(proxy); err != nil {
+		return errors.Trace(err)
+	}
+
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotatef(err, "cannot set proxy for %v", cloudName)
+	}
+	defer releaser.Release()
+
+	credentials, err := ReadCredentialsFile(JujuCredentialsPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get credentials")
+	}
+	proxies, err := ReadCloudProxiesFile(JujuCredentialsPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get proxies")
+	}
+	if proxy == (ProxyConfig{}) {
+		delete(proxies, cloudName)
+	} else {
+		if proxies == nil {
+			proxies = make(map[string]ProxyConfig)
+		}
+		proxies[cloudName] = proxy
+	}
+	return writeCredentialsFile(credentialsCollection{credentials, proxies})
+}
+
+// CloudProxy implements CredentialGetter.
+func (s *store) CloudProxy(cloudName string) (*ProxyConfig, error) {
+	proxies, err := ReadCloudProxiesFile(JujuCredentialsPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	proxy, ok := proxies[cloudName]
+	if !ok {
+		return nil, errors.NotFoundf("proxy for cloud %s", cloudName)
+	}
+	return &proxy, nil
+}
+
 // UpdateBootstrapConfig implements BootstrapConfigUpdater.
 func (s *store) UpdateBootstrapConfig(controllerName string, cfg BootstrapConfig) error {
 	if err := ValidateControllerName(controllerName); err != nil {
@@ -4,6 +4,10 @@
 package jujuclient
 
 import (
+	"time"
+
+	"github.com/juju/version"
+
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/controller"
 )
@@ -28,18 +32,67 @@ type ControllerDetails struct {
 	// CACert is a security certificate for this controller.
 	CACert string `yaml:"ca-cert"`
 
+	// CACerts holds additional CA certificates which are trusted for
+	// this controller, so that its CA certificate can be rotated
+	// without breaking existing clients. Callers dialing the
+	// controller should try each of the certificates returned by
+	// TrustedCACerts in turn.
+	CACerts []string `yaml:"ca-certs,omitempty"`
+
 	// Cloud is the name of the cloud that this controller runs in.
 	Cloud string `yaml:"cloud"`
 
 	// CloudRegion is the name of the cloud region that this controller
 	// runs in. This will be empty for clouds without regions.
 	CloudRegion string `yaml:"region,omitempty"`
+
+	// Labels holds free-form key/value labels used to group
+	// controllers, for example by team or environment.
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// AgentVersion is the version of the controller's agents when
+	// they were last seen. It will be empty if the version is not
+	// known.
+	AgentVersion string `yaml:"agent-version,omitempty"`
+
+	// LastConnection is the time at which the client last connected
+	// to the controller. It is zero if the client has never connected,
+	// or if the controller was last used by a client which didn't
+	// record connections. It is serialized as RFC3339 by
+	// ControllerDetails' MarshalYAML method.
+	LastConnection time.Time `yaml:"-"`
+}
+
+// TrustedCACerts returns all the CA certificates which are trusted
+// for the controller: CACert, followed by any additional CACerts.
+func (d ControllerDetails) TrustedCACerts() []string {
+	certs := []string{d.CACert}
+	for _, cert := range d.CACerts {
+		if cert != d.CACert {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
+
+// NamedControllerDetails holds the details of a controller along
+// with its name.
+type NamedControllerDetails struct {
+	Name string
+	ControllerDetails
 }
 
 // ModelDetails holds details of a model.
 type ModelDetails struct {
 	// ModelUUID is the unique ID for the model.
 	ModelUUID string `yaml:"uuid"`
+
+	// LastUsed is the time at which the model was last made the
+	// current model. It is zero if the model has never been made
+	// current, or if it was last made current by a client which
+	// didn't record it. It is serialized as RFC3339 by ModelDetails'
+	// MarshalYAML method.
+	LastUsed time.Time `yaml:"-"`
 }
 
 // AccountDetails holds details of an account.
@@ -90,6 +143,26 @@ type BootstrapConfig struct {
 	// when communicating with the cloud's storage service. This will
 	// be empty for clouds that have no cloud-specific API endpoint.
 	CloudStorageEndpoint string `yaml:"storage-endpoint,omitempty"`
+
+	// BootstrapTime is the time at which the controller was
+	// bootstrapped. It is zero for controllers bootstrapped before
+	// the time was recorded. It is serialized as RFC3339 by
+	// BootstrapConfig's MarshalYAML method.
+	BootstrapTime time.Time `yaml:"-"`
+}
+
+// ProxyConfig holds the proxy settings used when connecting to a
+// cloud.
+type ProxyConfig struct {
+	// HTTP is the URL of the proxy used for http connections.
+	HTTP string `yaml:"http-proxy,omitempty"`
+
+	// HTTPS is the URL of the proxy used for https connections.
+	HTTPS string `yaml:"https-proxy,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts which are
+	// connected to directly, bypassing the proxy.
+	NoProxy string `yaml:"no-proxy,omitempty"`
 }
 
 // ControllerUpdater stores controller details.
@@ -98,13 +171,52 @@ type ControllerUpdater interface {
 	// collection.
 	//
 	// If the controller does not already exist, it will be added.
-	// Otherwise, it will be overwritten with the new details.
+	// Otherwise, it will be overwritten with the new details, except
+	// that any additional CA certificates already recorded are kept
+	// if details.CACerts is nil; pass an empty, non-nil slice to
+	// remove them. A warning is logged if the controller's UUID is
+	// already recorded under a different name.
 	UpdateController(controllerName string, details ControllerDetails) error
 
 	// SetCurrentController sets the name of the current controller.
 	// If there exists no controller with the specified name, an error
 	// satisfying errors.IsNotFound will be returned.
 	SetCurrentController(controllerName string) error
+
+	// SetControllerLabels replaces the labels of the controller with
+	// the specified name. If there exists no controller with the
+	// specified name, an error satisfying errors.IsNotFound will be
+	// returned.
+	SetControllerLabels(controllerName string, labels map[string]string) error
+
+	// UpdateControllerEndpoints replaces the API endpoints of the
+	// controller with the specified name, both resolved and
+	// unresolved, leaving the rest of its details alone. If there exists no controller with the
+	// specified name, an error satisfying errors.IsNotFound will be
+	// returned.
+	UpdateControllerEndpoints(controllerName string, apiEndpoints []string) error
+
+	// SwitchTo sets the current controller, and the current model
+	// for that controller, in one step: either both are changed or
+	// neither is. The controller's account must belong to the named
+	// user. If the controller, account or model does not exist, an
+	// error satisfying errors.IsNotFound will be returned.
+	SwitchTo(controllerName, accountName, modelName string) error
+
+	// RecordControllerConnection sets the last connection time of
+	// the controller with the specified name to the current time.
+	// If there exists no controller with the specified name, an
+	// error satisfying errors.IsNotFound will be returned.
+	RecordControllerConnection(controllerName string) error
+
+	// SetControllerAlias records alias as another name for the
+	// controller with the specified name or alias, replacing any
+	// existing alias with the same name. If there exists no
+	// controller with the specified name, an error satisfying
+	// errors.IsNotFound will be returned. If alias is the name of a
+	// controller, an error satisfying errors.IsAlreadyExists will be
+	// returned.
+	SetControllerAlias(alias, controllerName string) error
 }
 
 // ControllerRemover removes controllers.
@@ -114,20 +226,46 @@ type ControllerRemover interface {
 	// will also be removed.
 	//
 	// Removing controllers will remove all information related to those
-	// controllers (models, accounts, bootstrap config.)
+	// controllers (models, accounts, bootstrap config, aliases.)
 	RemoveController(controllerName string) error
 }
 
 // ControllerGetter gets controllers.
 type ControllerGetter interface {
-	// AllControllers gets all controllers.
+	// AllControllers gets all controllers, by name. Controller
+	// aliases are not included.
 	AllControllers() (map[string]ControllerDetails, error)
 
-	// ControllerByName returns the controller with the specified name.
-	// If there exists no controller with the specified name, an error
-	// satisfying errors.IsNotFound will be returned.
+	// ControllerByName returns the controller with the specified name
+	// or alias. If there exists no controller with the specified name
+	// or alias, an error satisfying errors.IsNotFound will be
+	// returned.
 	ControllerByName(controllerName string) (*ControllerDetails, error)
 
+	// ControllersByLabel returns the controllers which have a label
+	// with the specified key and value.
+	ControllersByLabel(key, value string) (map[string]ControllerDetails, error)
+
+	// FindControllersByUUID returns the sorted names of the controllers
+	// with the specified UUID. More than one name is returned if the
+	// same controller has been added under different names. If there
+	// is no controller with the UUID, an empty slice is returned.
+	FindControllersByUUID(controllerUUID string) ([]string, error)
+
+	// AllControllersByRecency returns all controllers, most recently
+	// connected to first. Controllers which have never been connected
+	// to come last. Controllers with the same last connection time
+	// are ordered by name.
+	AllControllersByRecency() ([]NamedControllerDetails, error)
+
+	// CheckClientCompatibility returns an error if a client with the
+	// given version is too old or too new to talk to the controller
+	// with the specified name. If the controller's agent version is
+	// not known, no error is returned. If there exists no controller
+	// with the specified name, an error satisfying errors.IsNotFound
+	// will be returned.
+	CheckClientCompatibility(controllerName string, clientVersion version.Number) error
+
 	// CurrentController returns the name of the current controller.
 	// If there is no current controller, an error satisfying
 	// errors.IsNotFound will be returned.
@@ -179,6 +317,14 @@ type ModelGetter interface {
 	// exist, an error satisfying errors.IsNotFound will be
 	// returned.
 	ModelByName(controllerName, modelName string) (*ModelDetails, error)
+
+	// ModelByUUID returns the name and details of the model with the
+	// specified UUID, among the models cached for the specified
+	// controller. The controller's account must belong to the named
+	// user. If the account or model does not exist, an error
+	// satisfying errors.IsNotFound will be returned. If more than
+	// one cached model has the UUID, an error is returned.
+	ModelByUUID(controllerName, accountName, uuid string) (string, *ModelDetails, error)
 }
 
 // AccountUpdater stores account details.
@@ -210,8 +356,25 @@ type CredentialGetter interface {
 	// CredentialForCloud gets credentials for the named cloud.
 	CredentialForCloud(string) (*cloud.CloudCredential, error)
 
+	// CredentialForCloudAndName gets the named credential for the
+	// named cloud. If either the cloud has no credentials or it has
+	// no credential with the given name, an error satisfying
+	// errors.IsNotFound is returned.
+	CredentialForCloudAndName(cloudName, credentialName string) (*cloud.Credential, error)
+
+	// DefaultCredential returns the name of the default credential
+	// for the named cloud, which is empty if none has been set. If
+	// the cloud has no credentials, an error satisfying
+	// errors.IsNotFound is returned.
+	DefaultCredential(cloudName string) (string, error)
+
 	// AllCredentials gets all credentials.
 	AllCredentials() (map[string]cloud.CloudCredential, error)
+
+	// CloudProxy gets the proxy settings for the named cloud. If
+	// none are set, an error satisfying errors.IsNotFound is
+	// returned.
+	CloudProxy(cloudName string) (*ProxyConfig, error)
 }
 
 // CredentialUpdater stores credentials.
@@ -222,6 +385,31 @@ type CredentialUpdater interface {
 	// If the cloud or credential name does not already exist, it will be added.
 	// Otherwise, it will be overwritten with the new details.
 	UpdateCredential(cloudName string, details cloud.CloudCredential) error
+
+	// MoveCredentials moves the credentials stored for one cloud name
+	// to another, for example when a cloud has been renamed. If there
+	// are no credentials for fromCloud, an error satisfying
+	// errors.IsNotFound is returned.
+	//
+	// If toCloud already has credentials, an error satisfying
+	// errors.IsAlreadyExists is returned unless merge is true, in
+	// which case the credentials are added to those of toCloud. The
+	// default credential and region of toCloud are kept if set.
+	// Credentials with the same name in both clouds are never
+	// overwritten.
+	MoveCredentials(fromCloud, toCloud string, merge bool) error
+
+	// SetDefaultCredential makes the named credential the default
+	// for the named cloud. If the cloud has no credential with that
+	// name, an error satisfying errors.IsNotFound is returned. The
+	// default is cleared when the credential is removed by
+	// UpdateCredential.
+	SetDefaultCredential(cloudName, credentialName string) error
+
+	// SetCloudProxy sets the proxy settings used when connecting to
+	// the named cloud, replacing any already set. Setting an empty
+	// ProxyConfig removes the cloud's proxy settings.
+	SetCloudProxy(cloudName string, proxy ProxyConfig) error
 }
 
 // BootstrapConfigUpdater stores bootstrap config.
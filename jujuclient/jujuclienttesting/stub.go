@@ -5,6 +5,7 @@ package jujuclienttesting
 
 import (
 	"github.com/juju/testing"
+	"github.com/juju/version"
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/jujuclient"
@@ -13,12 +14,22 @@ import (
 type StubStore struct {
 	*testing.Stub
 
-	AllControllersFunc       func() (map[string]jujuclient.ControllerDetails, error)
-	ControllerByNameFunc     func(name string) (*jujuclient.ControllerDetails, error)
-	UpdateControllerFunc     func(name string, one jujuclient.ControllerDetails) error
-	RemoveControllerFunc     func(name string) error
-	SetCurrentControllerFunc func(name string) error
-	CurrentControllerFunc    func() (string, error)
+	AllControllersFunc            func() (map[string]jujuclient.ControllerDetails, error)
+	ControllerByNameFunc          func(name string) (*jujuclient.ControllerDetails, error)
+	UpdateControllerFunc          func(name string, one jujuclient.ControllerDetails) error
+	RemoveControllerFunc          func(name string) error
+	SetCurrentControllerFunc      func(name string) error
+	CurrentControllerFunc         func() (string, error)
+	SetControllerLabelsFunc       func(name string, labels map[string]string) error
+	UpdateControllerEndpointsFunc func(name string, apiEndpoints []string) error
+	ControllersByLabelFunc        func(key, value string) (map[string]jujuclient.ControllerDetails, error)
+	SwitchToFunc                  func(controller, account, model string) error
+
+	CheckClientCompatibilityFunc   func(name string, clientVersion version.Number) error
+	RecordControllerConnectionFunc func(name string) error
+	SetControllerAliasFunc         func(alias, name string) error
+	AllControllersByRecencyFunc    func() ([]jujuclient.NamedControllerDetails, error)
+	FindControllersByUUIDFunc      func(controllerUUID string) ([]string, error)
 
 	UpdateModelFunc     func(controller, model string, details jujuclient.ModelDetails) error
 	SetCurrentModelFunc func(controller, model string) error
@@ -26,14 +37,21 @@ type StubStore struct {
 	AllModelsFunc       func(controller string) (map[string]jujuclient.ModelDetails, error)
 	CurrentModelFunc    func(controller string) (string, error)
 	ModelByNameFunc     func(controller, model string) (*jujuclient.ModelDetails, error)
+	ModelByUUIDFunc     func(controller, account, uuid string) (string, *jujuclient.ModelDetails, error)
 
 	UpdateAccountFunc  func(controllerName string, details jujuclient.AccountDetails) error
 	AccountDetailsFunc func(controllerName string) (*jujuclient.AccountDetails, error)
 	RemoveAccountFunc  func(controllerName string) error
 
-	CredentialForCloudFunc func(string) (*cloud.CloudCredential, error)
-	AllCredentialsFunc     func() (map[string]cloud.CloudCredential, error)
-	UpdateCredentialFunc   func(cloudName string, details cloud.CloudCredential) error
+	CredentialForCloudFunc        func(string) (*cloud.CloudCredential, error)
+	CredentialForCloudAndNameFunc func(cloudName, credentialName string) (*cloud.Credential, error)
+	DefaultCredentialFunc         func(cloudName string) (string, error)
+	AllCredentialsFunc            func() (map[string]cloud.CloudCredential, error)
+	UpdateCredentialFunc          func(cloudName string, details cloud.CloudCredential) error
+	MoveCredentialsFunc           func(fromCloud, toCloud string, merge bool) error
+	SetDefaultCredentialFunc      func(cloudName, credentialName string) error
+	SetCloudProxyFunc             func(cloudName string, proxy jujuclient.ProxyConfig) error
+	CloudProxyFunc                func(cloudName string) (*jujuclient.ProxyConfig, error)
 
 	BootstrapConfigForControllerFunc func(controllerName string) (*jujuclient.BootstrapConfig, error)
 	UpdateBootstrapConfigFunc        func(controllerName string, cfg jujuclient.BootstrapConfig) error
@@ -61,6 +79,33 @@ func NewStubStore() *StubStore {
 	result.CurrentControllerFunc = func() (string, error) {
 		return "", result.Stub.NextErr()
 	}
+	result.SetControllerLabelsFunc = func(name string, labels map[string]string) error {
+		return result.Stub.NextErr()
+	}
+	result.UpdateControllerEndpointsFunc = func(name string, apiEndpoints []string) error {
+		return result.Stub.NextErr()
+	}
+	result.ControllersByLabelFunc = func(key, value string) (map[string]jujuclient.ControllerDetails, error) {
+		return nil, result.Stub.NextErr()
+	}
+	result.SwitchToFunc = func(controller, account, model string) error {
+		return result.Stub.NextErr()
+	}
+	result.CheckClientCompatibilityFunc = func(name string, clientVersion version.Number) error {
+		return result.Stub.NextErr()
+	}
+	result.RecordControllerConnectionFunc = func(name string) error {
+		return result.Stub.NextErr()
+	}
+	result.SetControllerAliasFunc = func(alias, name string) error {
+		return result.Stub.NextErr()
+	}
+	result.AllControllersByRecencyFunc = func() ([]jujuclient.NamedControllerDetails, error) {
+		return nil, result.Stub.NextErr()
+	}
+	result.FindControllersByUUIDFunc = func(controllerUUID string) ([]string, error) {
+		return nil, result.Stub.NextErr()
+	}
 
 	result.UpdateModelFunc = func(controller, model string, details jujuclient.ModelDetails) error {
 		return result.Stub.NextErr()
@@ -80,6 +125,9 @@ func NewStubStore() *StubStore {
 	result.ModelByNameFunc = func(controller, model string) (*jujuclient.ModelDetails, error) {
 		return nil, result.Stub.NextErr()
 	}
+	result.ModelByUUIDFunc = func(controller, account, uuid string) (string, *jujuclient.ModelDetails, error) {
+		return "", nil, result.Stub.NextErr()
+	}
 
 	result.UpdateAccountFunc = func(controllerName string, details jujuclient.AccountDetails) error {
 		return result.Stub.NextErr()
@@ -94,12 +142,30 @@ func NewStubStore() *StubStore {
 	result.CredentialForCloudFunc = func(string) (*cloud.CloudCredential, error) {
 		return nil, result.Stub.NextErr()
 	}
+	result.CredentialForCloudAndNameFunc = func(cloudName, credentialName string) (*cloud.Credential, error) {
+		return nil, result.Stub.NextErr()
+	}
+	result.DefaultCredentialFunc = func(cloudName string) (string, error) {
+		return "", result.Stub.NextErr()
+	}
 	result.AllCredentialsFunc = func() (map[string]cloud.CloudCredential, error) {
 		return nil, result.Stub.NextErr()
 	}
 	result.UpdateCredentialFunc = func(cloudName string, details cloud.CloudCredential) error {
 		return result.Stub.NextErr()
 	}
+	result.MoveCredentialsFunc = func(fromCloud, toCloud string, merge bool) error {
+		return result.Stub.NextErr()
+	}
+	result.SetDefaultCredentialFunc = func(cloudName, credentialName string) error {
+		return result.Stub.NextErr()
+	}
+	result.SetCloudProxyFunc = func(cloudName string, proxy jujuclient.ProxyConfig) error {
+		return result.Stub.NextErr()
+	}
+	result.CloudProxyFunc = func(cloudName string) (*jujuclient.ProxyConfig, error) {
+		return nil, result.Stub.NextErr()
+	}
 
 	result.BootstrapConfigForControllerFunc = func(controllerName string) (*jujuclient.BootstrapConfig, error) {
 		return nil, result.Stub.NextErr()
@@ -121,12 +187,22 @@ func WrapClientStore(underlying jujuclient.ClientStore) *StubStore {
 	stub.RemoveControllerFunc = underlying.RemoveController
 	stub.SetCurrentControllerFunc = underlying.SetCurrentController
 	stub.CurrentControllerFunc = underlying.CurrentController
+	stub.SetControllerLabelsFunc = underlying.SetControllerLabels
+	stub.UpdateControllerEndpointsFunc = underlying.UpdateControllerEndpoints
+	stub.ControllersByLabelFunc = underlying.ControllersByLabel
+	stub.SwitchToFunc = underlying.SwitchTo
+	stub.CheckClientCompatibilityFunc = underlying.CheckClientCompatibility
+	stub.RecordControllerConnectionFunc = underlying.RecordControllerConnection
+	stub.SetControllerAliasFunc = underlying.SetControllerAlias
+	stub.AllControllersByRecencyFunc = underlying.AllControllersByRecency
+	stub.FindControllersByUUIDFunc = underlying.FindControllersByUUID
 	stub.UpdateModelFunc = underlying.UpdateModel
 	stub.SetCurrentModelFunc = underlying.SetCurrentModel
 	stub.RemoveModelFunc = underlying.RemoveModel
 	stub.AllModelsFunc = underlying.AllModels
 	stub.CurrentModelFunc = underlying.CurrentModel
 	stub.ModelByNameFunc = underlying.ModelByName
+	stub.ModelByUUIDFunc = underlying.ModelByUUID
 	stub.UpdateAccountFunc = underlying.UpdateAccount
 	stub.AccountDetailsFunc = underlying.AccountDetails
 	stub.RemoveAccountFunc = underlying.RemoveAccount
@@ -171,6 +247,60 @@ func (c *StubStore) CurrentController() (string, error) {
 	return c.CurrentControllerFunc()
 }
 
+// SetControllerLabels implements ControllersUpdater.SetControllerLabels.
+func (c *StubStore) SetControllerLabels(name string, labels map[string]string) error {
+	c.MethodCall(c, "SetControllerLabels", name, labels)
+	return c.SetControllerLabelsFunc(name, labels)
+}
+
+// UpdateControllerEndpoints implements ControllersUpdater.UpdateControllerEndpoints.
+func (c *StubStore) UpdateControllerEndpoints(name string, apiEndpoints []string) error {
+	c.MethodCall(c, "UpdateControllerEndpoints", name, apiEndpoints)
+	return c.UpdateControllerEndpointsFunc(name, apiEndpoints)
+}
+
+// SwitchTo implements ControllersUpdater.SwitchTo.
+func (c *StubStore) SwitchTo(controller, account, model string) error {
+	c.MethodCall(c, "SwitchTo", controller, account, model)
+	return c.SwitchToFunc(controller, account, model)
+}
+
+// CheckClientCompatibility implements ControllersGetter.CheckClientCompatibility.
+func (c *StubStore) CheckClientCompatibility(name string, clientVersion version.Number) error {
+	c.MethodCall(c, "CheckClientCompatibility", name, clientVersion)
+	return c.CheckClientCompatibilityFunc(name, clientVersion)
+}
+
+// RecordControllerConnection implements ControllersUpdater.RecordControllerConnection.
+func (c *StubStore) RecordControllerConnection(name string) error {
+	c.MethodCall(c, "RecordControllerConnection", name)
+	return c.RecordControllerConnectionFunc(name)
+}
+
+// SetControllerAlias implements ControllersUpdater.SetControllerAlias.
+func (c *StubStore) SetControllerAlias(alias, name string) error {
+	c.MethodCall(c, "SetControllerAlias", alias, name)
+	return c.SetControllerAliasFunc(alias, name)
+}
+
+// AllControllersByRecency implements ControllersGetter.AllControllersByRecency.
+func (c *StubStore) AllControllersByRecency() ([]jujuclient.NamedControllerDetails, error) {
+	c.MethodCall(c, "AllControllersByRecency")
+	return c.AllControllersByRecencyFunc()
+}
+
+// FindControllersByUUID implements ControllersGetter.FindControllersByUUID.
+func (c *StubStore) FindControllersByUUID(controllerUUID string) ([]string, error) {
+	c.MethodCall(c, "FindControllersByUUID", controllerUUID)
+	return c.FindControllersByUUIDFunc(controllerUUID)
+}
+
+// ControllersByLabel implements ControllersGetter.ControllersByLabel.
+func (c *StubStore) ControllersByLabel(key, value string) (map[string]jujuclient.ControllerDetails, error) {
+	c.MethodCall(c, "ControllersByLabel", key, value)
+	return c.ControllersByLabelFunc(key, value)
+}
+
 // UpdateModel implements ModelUpdater.
 func (c *StubStore) UpdateModel(controller, model string, details jujuclient.ModelDetails) error {
 	c.MethodCall(c, "UpdateModel", controller, model, details)
@@ -207,6 +337,12 @@ func (c *StubStore) ModelByName(controller, model string) (*jujuclient.ModelDeta
 	return c.ModelByNameFunc(controller, model)
 }
 
+// ModelByUUID implements ModelGetter.
+func (c *StubStore) ModelByUUID(controller, account, uuid string) (string, *jujuclient.ModelDetails, error) {
+	c.MethodCall(c, "ModelByUUID", controller, account, uuid)
+	return c.ModelByUUIDFunc(controller, account, uuid)
+}
+
 // UpdateAccount implements AccountUpdater.
 func (c *StubStore) UpdateAccount(controllerName string, details jujuclient.AccountDetails) error {
 	c.MethodCall(c, "UpdateAccount", controllerName, details)
@@ -231,6 +367,18 @@ func (c *StubStore) CredentialForCloud(cloudName string) (*cloud.CloudCredential
 	return c.CredentialForCloudFunc(cloudName)
 }
 
+// CredentialForCloudAndName implements CredentialsGetter.
+func (c *StubStore) CredentialForCloudAndName(cloudName, credentialName string) (*cloud.Credential, error) {
+	c.MethodCall(c, "CredentialForCloudAndName", cloudName, credentialName)
+	return c.CredentialForCloudAndNameFunc(cloudName, credentialName)
+}
+
+// DefaultCredential implements CredentialsGetter.
+func (c *StubStore) DefaultCredential(cloudName string) (string, error) {
+	c.MethodCall(c, "DefaultCredential", cloudName)
+	return c.DefaultCredentialFunc(cloudName)
+}
+
 // AllCredentials implements CredentialsGetter.
 func (c *StubStore) AllCredentials() (map[string]cloud.CloudCredential, error) {
 	c.MethodCall(c, "AllCredentials")
@@ -243,6 +391,30 @@ func (c *StubStore) UpdateCredential(cloudName string, details cloud.CloudCreden
 	return c.UpdateCredentialFunc(cloudName, details)
 }
 
+// MoveCredentials implements CredentialsUpdater.
+func (c *StubStore) MoveCredentials(fromCloud, toCloud string, merge bool) error {
+	c.MethodCall(c, "MoveCredentials", fromCloud, toCloud, merge)
+	return c.MoveCredentialsFunc(fromCloud, toCloud, merge)
+}
+
+// SetDefaultCredential implements CredentialsUpdater.
+func (c *StubStore) SetDefaultCredential(cloudName, credentialName string) error {
+	c.MethodCall(c, "SetDefaultCredential", cloudName, credentialName)
+	return c.SetDefaultCredentialFunc(cloudName, credentialName)
+}
+
+// SetCloudProxy implements CredentialsUpdater.
+func (c *StubStore) SetCloudProxy(cloudName string, proxy jujuclient.ProxyConfig) error {
+	c.MethodCall(c, "SetCloudProxy", cloudName, proxy)
+	return c.SetCloudProxyFunc(cloudName, proxy)
+}
+
+// CloudProxy implements CredentialsGetter.
+func (c *StubStore) CloudProxy(cloudName string) (*jujuclient.ProxyConfig, error) {
+	c.MethodCall(c, "CloudProxy", cloudName)
+	return c.CloudProxyFunc(cloudName)
+}
+
 // BootstrapConfigForController implements BootstrapConfigGetter.
 func (c *StubStore) BootstrapConfigForController(controllerName string) (*jujuclient.BootstrapConfig, error) {
 	c.MethodCall(c, "BootstrapConfigForController", controllerName)
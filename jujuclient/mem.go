@@ -0,0 +1,582 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/cloud"
+)
+
+var _ ClientStore = (*MemStore)(nil)
+var _ Transactor = (*MemStore)(nil)
+
+// NewMemStore returns a new in-memory client store.
+func NewMemStore() *MemStore {
+	return &MemStore{storeContents: storeContents{}.copy()}
+}
+
+// MemStore is an in-memory client store, which behaves as the
+// file-based store returned by NewFileClientStore does but never
+// touches the disk. Its methods are safe for concurrent use; its
+// collections may be accessed directly only while no method is
+// running.
+type MemStore struct {
+	mu sync.Mutex
+	storeContents
+}
+
+// AllControllers implements ControllersGetter.
+func (s *MemStore) AllControllers() (map[string]ControllerDetails, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.Controllers) == 0 {
+		return nil, nil
+	}
+	controllers := make(map[string]ControllerDetails)
+	for name, details := range s.Controllers {
+		controllers[name] = details
+	}
+	return controllers, nil
+}
+
+// CurrentController implements ControllersGetter.
+func (s *MemStore) CurrentController() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.CurrentControllerName == "" {
+		return "", errors.NotFoundf("current controller")
+	}
+	return s.CurrentControllerName, nil
+}
+
+// ControllerByName implements ControllersGetter.
+func (s *MemStore) ControllerByName(name string) (*ControllerDetails, error) {
+	if err := ValidateControllerName(name); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if result, ok := s.Controllers[ResolveControllerName(s.Controllers, s.ControllerAliases, name)]; ok {
+		return &result, nil
+	}
+	return nil, errors.NotFoundf("controller %s", name)
+}
+
+// ControllersByLabel implements ControllersGetter.
+func (s *MemStore) ControllersByLabel(key, value string) (map[string]ControllerDetails, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return FilterControllersByLabel(s.Controllers, key, value), nil
+}
+
+// FindControllersByUUID implements ControllersGetter.
+func (s *MemStore) FindControllersByUUID(controllerUUID string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ControllerNamesByUUID(s.Controllers, controllerUUID), nil
+}
+
+// AllControllersByRecency implements ControllersGetter.
+func (s *MemStore) AllControllersByRecency() ([]NamedControllerDetails, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SortControllersByRecency(s.Controllers), nil
+}
+
+// CheckClientCompatibility implements ControllersGetter.
+func (s *MemStore) CheckClientCompatibility(name string, clientVersion version.Number) error {
+	details, err := s.ControllerByName(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(CheckVersionCompatibility(name, *details, clientVersion))
+}
+
+// UpdateController implements ControllersUpdater.
+func (s *MemStore) UpdateController(name string, details ControllerDetails) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerDetails(details); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, other := range ControllerNamesByUUID(s.Controllers, details.ControllerUUID) {
+		if other != name {
+			logger.Warningf(
+				"controller %v has the same UUID as controller %v; it may be a duplicate",
+				name, other,
+			)
+		}
+	}
+	s.Controllers[name] = preserveCACerts(details, s.Controllers[name])
+	delete(s.ControllerAliases, name)
+	return nil
+}
+
+// SetCurrentController implements ControllersUpdater.
+func (s *MemStore) SetCurrentController(name string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.Controllers[name]; !ok {
+		return errors.NotFoundf("controller %v", name)
+	}
+	s.CurrentControllerName = name
+	return nil
+}
+
+// SetControllerLabels implements ControllersUpdater.
+func (s *MemStore) SetControllerLabels(name string, labels map[string]string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerLabels(labels); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	details, ok := s.Controllers[name]
+	if !ok {
+		return errors.NotFoundf("controller %v", name)
+	}
+	details.Labels = labels
+	s.Controllers[name] = details
+	return nil
+}
+
+// UpdateControllerEndpoints implements ControllersUpdater.
+func (s *MemStore) UpdateControllerEndpoints(name string, apiEndpoints []string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerEndpoints(apiEndpoints); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	details, ok := s.Controllers[name]
+	if !ok {
+		return errors.NotFoundf("controller %v", name)
+	}
+	details.APIEndpoints = apiEndpoints
+	details.UnresolvedAPIEndpoints = apiEndpoints
+	s.Controllers[name] = details
+	return nil
+}
+
+// RecordControllerConnection implements ControllersUpdater.
+func (s *MemStore) RecordControllerConnection(name string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	details, ok := s.Controllers[name]
+	if !ok {
+		return errors.NotFoundf("controller %v", name)
+	}
+	details.LastConnection = time.Now().UTC()
+	s.Controllers[name] = details
+	return nil
+}
+
+// SetControllerAlias implements ControllersUpdater.
+func (s *MemStore) SetControllerAlias(alias, name string) error {
+	if err := ValidateControllerName(alias); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name = ResolveControllerName(s.Controllers, s.ControllerAliases, name)
+	if err := checkControllerAlias(s.Controllers, alias, name); err != nil {
+		return errors.Trace(err)
+	}
+	s.ControllerAliases[alias] = name
+	return nil
+}
+
+// SwitchTo implements ControllersUpdater.
+func (s *MemStore) SwitchTo(controllerName, accountName, modelName string) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.Controllers[controllerName]; !ok {
+		return errors.NotFoundf("controller %v", controllerName)
+	}
+	if account, ok := s.Accounts[controllerName]; !ok || account.User != accountName {
+		return errors.NotFoundf("account %s for controller %s", accountName, controllerName)
+	}
+	controllerModels, ok := s.Models[controllerName]
+	if !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	if _, ok := controllerModels.Models[modelName]; !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	controllerModels.CurrentModel = modelName
+	s.CurrentControllerName = controllerName
+	return nil
+}
+
+// RemoveController implements ControllersRemover
+func (s *MemStore) RemoveController(name string) error {
+	if err := ValidateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// We remove all controllers with the same UUID as the named one.
+	namedControllerDetails, ok := s.Controllers[name]
+	if !ok {
+		return nil
+	}
+	for _, name := range ControllerNamesByUUID(s.Controllers, namedControllerDetails.ControllerUUID) {
+		delete(s.Models, name)
+		delete(s.Accounts, name)
+		delete(s.BootstrapConfig, name)
+		delete(s.Controllers, name)
+		removeControllerAliases(s.ControllerAliases, name)
+		if s.CurrentControllerName == name {
+			s.CurrentControllerName = ""
+		}
+	}
+	return nil
+}
+
+// UpdateModel implements ModelUpdater.
+func (s *MemStore) UpdateModel(controllerName, modelName string, details ModelDetails) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateModelDetails(details); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	controllerModels, ok := s.Models[controllerName]
+	if !ok {
+		controllerModels = &ControllerModels{
+			Models: make(map[string]ModelDetails),
+		}
+		s.Models[controllerName] = controllerModels
+	}
+	controllerModels.Models[modelName] = preserveLastUsed(details, controllerModels.Models[modelName])
+	return nil
+}
+
+// SetCurrentModel implements ModelUpdater.
+func (s *MemStore) SetCurrentModel(controllerName, modelName string) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	controllerModels, ok := s.Models[controllerName]
+	if !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	details, ok := controllerModels.Models[modelName]
+	if !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	details.LastUsed = time.Now().UTC()
+	controllerModels.Models[modelName] = details
+	controllerModels.CurrentModel = modelName
+	return nil
+}
+
+// AllModels implements ModelGetter.
+func (s *MemStore) AllModels(controllerName string) (map[string]ModelDetails, error) {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	controllerModels, ok := s.Models[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("models for controller %s", controllerName)
+	}
+	models := make(map[string]ModelDetails)
+	for name, details := range controllerModels.Models {
+		models[name] = details
+	}
+	return models, nil
+}
+
+// CurrentModel implements ModelGetter.
+func (s *MemStore) CurrentModel(controllerName string) (string, error) {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return "", errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	controllerModels, ok := s.Models[controllerName]
+	if !ok || controllerModels.CurrentModel == "" {
+		return "", errors.NotFoundf("current model for controller %s", controllerName)
+	}
+	return controllerModels.CurrentModel, nil
+}
+
+// ModelByName implements ModelGetter.
+func (s *MemStore) ModelByName(controllerName, modelName string) (*ModelDetails, error) {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := ValidateModelName(modelName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	controllerModels, ok := s.Models[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("models for controller %s", controllerName)
+	}
+	details, ok := controllerModels.Models[modelName]
+	if !ok {
+		return nil, errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	return &details, nil
+}
+
+// ModelByUUID implements ModelGetter.
+func (s *MemStore) ModelByUUID(controllerName, accountName, uuid string) (string, *ModelDetails, error) {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if account, ok := s.Accounts[controllerName]; !ok || account.User != accountName {
+		return "", nil, errors.NotFoundf("account %s for controller %s", accountName, controllerName)
+	}
+	controllerModels, ok := s.Models[controllerName]
+	if !ok {
+		return "", nil, errors.NotFoundf("models for controller %s", controllerName)
+	}
+	name, details, err := FindModelByUUID(controllerName, controllerModels.Models, uuid)
+	return name, details, errors.Trace(err)
+}
+
+// RemoveModel implements ModelRemover.
+func (s *MemStore) RemoveModel(controllerName, modelName string) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	controllerModels, ok := s.Models[controllerName]
+	if !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	if _, ok := controllerModels.Models[modelName]; !ok {
+		return errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	delete(controllerModels.Models, modelName)
+	if controllerModels.CurrentModel == modelName {
+		controllerModels.CurrentModel = ""
+	}
+	return nil
+}
+
+// UpdateAccount implements AccountUpdater.
+func (s *MemStore) UpdateAccount(controllerName string, details AccountDetails) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateAccountDetails(details); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Accounts[controllerName] = details
+	return nil
+}
+
+// AccountDetails implements AccountGetter.
+func (s *MemStore) AccountDetails(controllerName string) (*AccountDetails, error) {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	details, ok := s.Accounts[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("account details for controller %s", controllerName)
+	}
+	return &details, nil
+}
+
+// RemoveAccount implements AccountRemover.
+func (s *MemStore) RemoveAccount(controllerName string) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.Accounts[controllerName]; !ok {
+		return errors.NotFoundf("account details for controller %s", controllerName)
+	}
+	delete(s.Accounts, controllerName)
+	return nil
+}
+
+// UpdateCredential implements CredentialUpdater.
+func (s *MemStore) UpdateCredential(cloudName string, details cloud.CloudCredential) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Clear the default credential if we are removing that one.
+	if existing, ok := s.Credentials[cloudName]; ok && existing.DefaultCredential != "" {
+		if _, ok := details.AuthCredentials[existing.DefaultCredential]; !ok {
+			details.DefaultCredential = ""
+		}
+	}
+	s.Credentials[cloudName] = details
+	return nil
+}
+
+// MoveCredentials implements CredentialUpdater.
+func (s *MemStore) MoveCredentials(fromCloud, toCloud string, merge bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Trace(moveCloudCredentials(s.Credentials, fromCloud, toCloud, merge))
+}
+
+// SetDefaultCredential implements CredentialUpdater.
+func (s *MemStore) SetDefaultCredential(cloudName, credentialName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Trace(setDefaultCloudCredential(s.Credentials, cloudName, credentialName))
+}
+
+// CredentialForCloud implements CredentialGetter.
+func (s *MemStore) CredentialForCloud(cloudName string) (*cloud.CloudCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	credentials, ok := s.Credentials[cloudName]
+	if !ok {
+		return nil, errors.NotFoundf("credentials for cloud %s", cloudName)
+	}
+	return &credentials, nil
+}
+
+// CredentialForCloudAndName implements CredentialGetter.
+func (s *MemStore) CredentialForCloudAndName(cloudName, credentialName string) (*cloud.Credential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return namedCloudCredential(s.Credentials, cloudName, credentialName)
+}
+
+// DefaultCredential implements CredentialGetter.
+func (s *MemStore) DefaultCredential(cloudName string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return defaultCloudCredential(s.Credentials, cloudName)
+}
+
+// AllCredentials implements CredentialGetter.
+func (s *MemStore) AllCredentials() (map[string]cloud.CloudCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.Credentials) == 0 {
+		return nil, nil
+	}
+	all := make(map[string]cloud.CloudCredential)
+	for name, credentials := range s.Credentials {
+		all[name] = credentials
+	}
+	return all, nil
+}
+
+// SetCloudProxy implements CredentialUpdater.
+func (s *MemStore) SetCloudProxy(cloudName string, proxy ProxyConfig) error {
+	if cloudName == "" {
+		return errors.NotValidf("empty cloud name")
+	}
+	if err := ValidateProxyConfig(proxy); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if proxy == (ProxyConfig{}) {
+		delete(s.CloudProxies, cloudName)
+	} else {
+		s.CloudProxies[cloudName] = proxy
+	}
+	return nil
+}
+
+// CloudProxy implements CredentialGetter.
+func (s *MemStore) CloudProxy(cloudName string) (*ProxyConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	proxy, ok := s.CloudProxies[cloudName]
+	if !ok {
+		return nil, errors.NotFoundf("proxy for cloud %s", cloudName)
+	}
+	return &proxy, nil
+}
+
+// UpdateBootstrapConfig implements BootstrapConfigUpdater.
+func (s *MemStore) UpdateBootstrapConfig(controllerName string, cfg BootstrapConfig) error {
+	if err := ValidateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateBootstrapConfig(cfg); err != nil {
+		return errors.Trace(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.BootstrapConfig[controllerName] = cfg
+	return nil
+}
+
+// BootstrapConfigForController implements BootstrapConfigGetter.
+func (s *MemStore) BootstrapConfigForController(controllerName string) (*BootstrapConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.BootstrapConfig[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("bootstrap config for controller %s", controllerName)
+	}
+	return &cfg, nil
+}
+
+// Do implements Transactor.
+func (s *MemStore) Do(f func(tx ClientStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := &MemStore{storeContents: s.storeContents.copy()}
+	if err := f(tx); err != nil {
+		return errors.Trace(err)
+	}
+	s.storeContents = tx.storeContents
+	return nil
+}
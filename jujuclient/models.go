@@ -6,6 +6,9 @@ package jujuclient
 import (
 	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -76,6 +79,78 @@ type ControllerModels struct {
 	CurrentModel string `yaml:"current-model,omitempty"`
 }
 
+// FindModelByUUID returns the name and details of the model with
+// the given UUID among the given models of the named controller, as
+// described by ModelGetter.ModelByUUID.
+func FindModelByUUID(controllerName string, models map[string]ModelDetails, uuid string) (string, *ModelDetails, error) {
+	var names []string
+	for name, details := range models {
+		if details.ModelUUID == uuid {
+			names = append(names, name)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return "", nil, errors.NotFoundf("model with UUID %s on controller %s", uuid, controllerName)
+	case 1:
+		details := models[names[0]]
+		return names[0], &details, nil
+	}
+	sort.Strings(names)
+	return "", nil, errors.Errorf(
+		"model UUID %s is ambiguous on controller %s: cached as %s",
+		uuid, controllerName, strings.Join(names, ", "),
+	)
+}
+
+// preserveLastUsed returns details, with its LastUsed time taken
+// from existing if it has none of its own. This allows callers to
+// update the details of a model without knowing when it was last
+// used.
+func preserveLastUsed(details, existing ModelDetails) ModelDetails {
+	if details.LastUsed.IsZero() {
+		details.LastUsed = existing.LastUsed
+	}
+	return details
+}
+
+// modelDetailsFields has the same fields as ModelDetails, but none
+// of its methods, so that it may be marshalled as a part of
+// ModelDetails without recursing.
+type modelDetailsFields ModelDetails
+
+// modelDetailsDoc is the serialized form of a ModelDetails.
+type modelDetailsDoc struct {
+	Fields   modelDetailsFields `yaml:",inline"`
+	LastUsed string             `yaml:"last-used,omitempty"`
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (d ModelDetails) MarshalYAML() (interface{}, error) {
+	doc := modelDetailsDoc{Fields: modelDetailsFields(d)}
+	if !d.LastUsed.IsZero() {
+		doc.LastUsed = d.LastUsed.UTC().Format(time.RFC3339)
+	}
+	return doc, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (d *ModelDetails) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var doc modelDetailsDoc
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	*d = ModelDetails(doc.Fields)
+	if doc.LastUsed != "" {
+		t, err := time.Parse(time.RFC3339, doc.LastUsed)
+		if err != nil {
+			return errors.Annotate(err, "cannot parse last-used")
+		}
+		d.LastUsed = t
+	}
+	return nil
+}
+
 // TODO(axw) 2016-07-14 #NNN
 // Drop this code once we get to 2.0-beta13.
 func migrateLegacyModels(data []byte) error {
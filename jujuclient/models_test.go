@@ -6,6 +6,7 @@ package jujuclient_test
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -103,8 +104,78 @@ func (s *ModelsSuite) TestSetCurrentModel(c *gc.C) {
 	c.Assert(all["kontroll"].CurrentModel, gc.Equals, "admin")
 }
 
+func (s *ModelsSuite) TestSetCurrentModelRecordsLastUsed(c *gc.C) {
+	details, err := s.store.ModelByName("kontroll", "my-model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.LastUsed.IsZero(), jc.IsTrue)
+
+	before := time.Now().UTC().Truncate(time.Second)
+	err = s.store.SetCurrentModel("kontroll", "my-model")
+	c.Assert(err, jc.ErrorIsNil)
+	models, err := s.store.AllModels("kontroll")
+	c.Assert(err, jc.ErrorIsNil)
+	first := models["my-model"].LastUsed
+	c.Assert(first.Before(before), jc.IsFalse)
+	c.Assert(models["admin"].LastUsed.IsZero(), jc.IsTrue)
+
+	// Making the model current again bumps the time.
+	time.Sleep(time.Second)
+	err = s.store.SetCurrentModel("kontroll", "my-model")
+	c.Assert(err, jc.ErrorIsNil)
+	details, err = s.store.ModelByName("kontroll", "my-model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.LastUsed.After(first), jc.IsTrue)
+}
+
+func (s *ModelsSuite) TestUpdateModelPreservesLastUsed(c *gc.C) {
+	err := s.store.SetCurrentModel("kontroll", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	before, err := s.store.ModelByName("kontroll", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.UpdateModel("kontroll", "admin", jujuclient.ModelDetails{ModelUUID: "new.uuid"})
+	c.Assert(err, jc.ErrorIsNil)
+	after, err := s.store.ModelByName("kontroll", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(after.ModelUUID, gc.Equals, "new.uuid")
+	c.Assert(after.LastUsed, gc.Equals, before.LastUsed)
+}
+
+func (s *ModelsSuite) TestLastUsedRoundTrip(c *gc.C) {
+	lastUsed := time.Date(2016, 9, 1, 12, 0, 0, 0, time.UTC)
+	models := map[string]*jujuclient.ControllerModels{
+		"ctrl": {
+			Models: map[string]jujuclient.ModelDetails{
+				"used":   {ModelUUID: "used.uuid", LastUsed: lastUsed},
+				"unused": {ModelUUID: "unused.uuid"},
+			},
+		},
+	}
+	err := jujuclient.WriteModelsFile(models)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(jujuclient.JujuModelsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "last-used: 2016-09-01T12:00:00Z")
+
+	read, err := jujuclient.ReadModelsFile(jujuclient.JujuModelsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, models)
+}
+
+func (s *ModelsSuite) TestLastUsedInvalid(c *gc.C) {
+	_, err := jujuclient.ParseModels([]byte(`
+controllers:
+  ctrl:
+    models:
+      admin:
+        uuid: test.uuid
+        last-used: yesterday
+`))
+	c.Assert(err, gc.ErrorMatches, `cannot unmarshal models: cannot parse last-used: .*`)
+}
+
 func (s *ModelsSuite) TestUpdateModelNewController(c *gc.C) {
-	testModelDetails := jujuclient.ModelDetails{"test.uuid"}
+	testModelDetails := jujuclient.ModelDetails{ModelUUID: "test.uuid"}
 	err := s.store.UpdateModel("new-controller", "new-model", testModelDetails)
 	c.Assert(err, jc.ErrorIsNil)
 	models, err := s.store.AllModels("new-controller")
@@ -115,7 +186,7 @@ func (s *ModelsSuite) TestUpdateModelNewController(c *gc.C) {
 }
 
 func (s *ModelsSuite) TestUpdateModelExistingControllerAndModelNewModel(c *gc.C) {
-	testModelDetails := jujuclient.ModelDetails{"test.uuid"}
+	testModelDetails := jujuclient.ModelDetails{ModelUUID: "test.uuid"}
 	err := s.store.UpdateModel("kontroll", "new-model", testModelDetails)
 	c.Assert(err, jc.ErrorIsNil)
 	models, err := s.store.AllModels("kontroll")
@@ -128,7 +199,7 @@ func (s *ModelsSuite) TestUpdateModelExistingControllerAndModelNewModel(c *gc.C)
 }
 
 func (s *ModelsSuite) TestUpdateModelOverwrites(c *gc.C) {
-	testModelDetails := jujuclient.ModelDetails{"test.uuid"}
+	testModelDetails := jujuclient.ModelDetails{ModelUUID: "test.uuid"}
 	for i := 0; i < 2; i++ {
 		// Twice so we exercise the code path of updating with
 		// identical details.
@@ -151,7 +222,7 @@ controllers:
 `[1:]), 0644)
 	c.Assert(err, jc.ErrorIsNil)
 
-	testModelDetails := jujuclient.ModelDetails{"test.uuid"}
+	testModelDetails := jujuclient.ModelDetails{ModelUUID: "test.uuid"}
 	err = s.store.UpdateModel("ctrl", "admin", testModelDetails)
 	c.Assert(err, jc.ErrorIsNil)
 	models, err := s.store.AllModels("ctrl")
@@ -188,7 +259,7 @@ func (s *ModelsSuite) TestRemoveModel(c *gc.C) {
 func (s *ModelsSuite) TestRemoveControllerRemovesModels(c *gc.C) {
 	store := jujuclient.NewFileClientStore()
 	err := store.UpdateController("kontroll", jujuclient.ControllerDetails{
-		ControllerUUID: "abc",
+		ControllerUUID: "6a7b8c9d-0e1f-4a2b-8c3d-4e5f6a7b8c9d",
 		CACert:         "woop",
 	})
 	c.Assert(err, jc.ErrorIsNil)
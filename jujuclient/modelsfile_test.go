@@ -50,9 +50,9 @@ var testControllerModels = map[string]*jujuclient.ControllerModels{
 	},
 }
 
-var kontrollAdminModelDetails = jujuclient.ModelDetails{"abc"}
-var kontrollMyModelModelDetails = jujuclient.ModelDetails{"def"}
-var ctrlAdminModelDetails = jujuclient.ModelDetails{"ghi"}
+var kontrollAdminModelDetails = jujuclient.ModelDetails{ModelUUID: "abc"}
+var kontrollMyModelModelDetails = jujuclient.ModelDetails{ModelUUID: "def"}
+var ctrlAdminModelDetails = jujuclient.ModelDetails{ModelUUID: "ghi"}
 
 func (s *ModelsFileSuite) TestWriteFile(c *gc.C) {
 	writeTestModelsFile(c)
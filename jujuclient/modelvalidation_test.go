@@ -18,7 +18,7 @@ type ModelValidationSuite struct {
 func (s *ModelValidationSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.model = jujuclient.ModelDetails{
-		"test.uuid",
+		ModelUUID: "test.uuid",
 	}
 }
 
@@ -0,0 +1,135 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedPasswordPrefix marks the account passwords which have
+// been encrypted by an encrypted file store. The rest of the value
+// is the base64 encoding of the salt, the nonce and the sealed
+// password.
+const encryptedPasswordPrefix = "aes-gcm:"
+
+const (
+	passwordSaltSize = 16
+	passwordKeySize  = 32
+)
+
+// errIncorrectPassphrase is returned when an encrypted password
+// cannot be opened, which almost always means that it was encrypted
+// with a different passphrase.
+var errIncorrectPassphrase = errors.New("incorrect passphrase")
+
+// isEncryptedPassword reports whether the given password was
+// encrypted by encryptPassword.
+func isEncryptedPassword(password string) bool {
+	return strings.HasPrefix(password, encryptedPasswordPrefix)
+}
+
+// passwordCipher returns the AES-GCM cipher for the key derived from
+// the given passphrase and salt.
+func passwordCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<14, 8, 1, passwordKeySize)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptPassword encrypts password with a key derived from
+// passphrase and a random salt.
+func encryptPassword(passphrase, password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Annotate(err, "cannot generate salt")
+	}
+	aead, err := passwordCipher(passphrase, salt)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Annotate(err, "cannot generate nonce")
+	}
+	data := append(salt, nonce...)
+	data = aead.Seal(data, nonce, []byte(password), nil)
+	return encryptedPasswordPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decryptPassword decrypts a password encrypted by encryptPassword.
+// If the passphrase is not the one the password was encrypted with,
+// errIncorrectPassphrase is returned.
+func decryptPassword(passphrase, encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(
+		strings.TrimPrefix(encrypted, encryptedPasswordPrefix),
+	)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot decode encrypted password")
+	}
+	if len(data) < passwordSaltSize {
+		return "", errors.New("encrypted password too short")
+	}
+	salt, data := data[:passwordSaltSize], data[passwordSaltSize:]
+	aead, err := passwordCipher(passphrase, salt)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(data) < aead.NonceSize() {
+		return "", errors.New("encrypted password too short")
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	password, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errIncorrectPassphrase
+	}
+	return string(password), nil
+}
+
+// encryptAccount returns details with its password encrypted, if the
+// store has a passphrase.
+func (s *store) encryptAccount(details AccountDetails) (AccountDetails, error) {
+	if s.passphrase == "" || details.Password == "" {
+		return details, nil
+	}
+	password, err := encryptPassword(s.passphrase, details.Password)
+	if err != nil {
+		return details, errors.Annotate(err, "cannot encrypt password")
+	}
+	details.Password = password
+	return details, nil
+}
+
+// decryptAccount returns the details of the named controller's
+// account with its password decrypted. Passwords recorded before
+// the store was encrypted are returned unchanged.
+func (s *store) decryptAccount(controllerName string, details AccountDetails) (AccountDetails, error) {
+	if !isEncryptedPassword(details.Password) {
+		return details, nil
+	}
+	if s.passphrase == "" {
+		return details, errors.Errorf(
+			"password for controller %s is encrypted, and no passphrase was supplied",
+			controllerName,
+		)
+	}
+	password, err := decryptPassword(s.passphrase, details.Password)
+	if err != nil {
+		return details, errors.Annotatef(err, "cannot decrypt password for controller %s", controllerName)
+	}
+	details.Password = password
+	return details, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloud"
+)
+
+// ErrReadOnly is returned by the mutating methods of a store
+// returned by ReadOnly.
+var ErrReadOnly = errors.New("store is read-only")
+
+// ReadOnly returns a ClientStore backed by the given store, which
+// may be used to inspect the store but not modify it. All getters are
+// passed through to the underlying store, so they are subject to the
+// same locking behaviour. All mutating methods return ErrReadOnly
+// without touching the underlying store.
+func ReadOnly(store ClientStore) ClientStore {
+	return &readOnlyStore{
+		ControllerGetter:      store,
+		ModelGetter:           store,
+		AccountGetter:         store,
+		CredentialGetter:      store,
+		BootstrapConfigGetter: store,
+	}
+}
+
+// readOnlyStore only embeds the getter interfaces, so that any
+// mutator added to ClientStore must be explicitly handled here.
+type readOnlyStore struct {
+	ControllerGetter
+	ModelGetter
+	AccountGetter
+	CredentialGetter
+	BootstrapConfigGetter
+}
+
+var _ ClientStore = (*readOnlyStore)(nil)
+
+// UpdateController implements ControllerUpdater.
+func (*readOnlyStore) UpdateController(string, ControllerDetails) error {
+	return ErrReadOnly
+}
+
+// SetCurrentController implements ControllerUpdater.
+func (*readOnlyStore) SetCurrentController(string) error {
+	return ErrReadOnly
+}
+
+// SetControllerLabels implements ControllerUpdater.
+func (*readOnlyStore) SetControllerLabels(string, map[string]string) error {
+	return ErrReadOnly
+}
+
+// UpdateControllerEndpoints implements ControllerUpdater.
+func (*readOnlyStore) UpdateControllerEndpoints(string, []string) error {
+	return ErrReadOnly
+}
+
+// SetControllerAlias implements ControllerUpdater.
+func (*readOnlyStore) SetControllerAlias(string, string) error {
+	return ErrReadOnly
+}
+
+// SwitchTo implements ControllerUpdater.
+func (*readOnlyStore) SwitchTo(string, string, string) error {
+	return ErrReadOnly
+}
+
+// RecordControllerConnection implements ControllerUpdater.
+func (*readOnlyStore) RecordControllerConnection(string) error {
+	return ErrReadOnly
+}
+
+// RemoveController implements ControllerRemover.
+func (*readOnlyStore) RemoveController(string) error {
+	return ErrReadOnly
+}
+
+// UpdateModel implements ModelUpdater.
+func (*readOnlyStore) UpdateModel(string, string, ModelDetails) error {
+	return ErrReadOnly
+}
+
+// SetCurrentModel implements ModelUpdater.
+func (*readOnlyStore) SetCurrentModel(string, string) error {
+	return ErrReadOnly
+}
+
+// RemoveModel implements ModelRemover.
+func (*readOnlyStore) RemoveModel(string, string) error {
+	return ErrReadOnly
+}
+
+// UpdateAccount implements AccountUpdater.
+func (*readOnlyStore) UpdateAccount(string, AccountDetails) error {
+	return ErrReadOnly
+}
+
+// RemoveAccount implements AccountRemover.
+func (*readOnlyStore) RemoveAccount(string) error {
+	return ErrReadOnly
+}
+
+// UpdateCredential implements CredentialUpdater.
+func (*readOnlyStore) UpdateCredential(string, cloud.CloudCredential) error {
+	return ErrReadOnly
+}
+
+// MoveCredentials implements CredentialUpdater.
+func (*readOnlyStore) MoveCredentials(string, string, bool) error {
+	return ErrReadOnly
+}
+
+// SetDefaultCredential implements CredentialUpdater.
+func (*readOnlyStore) SetDefaultCredential(string, string) error {
+	return ErrReadOnly
+}
+
+// SetCloudProxy implements CredentialUpdater.
+func (*readOnlyStore) SetCloudProxy(string, ProxyConfig) error {
+	return ErrReadOnly
+}
+
+// UpdateBootstrapConfig implements BootstrapConfigUpdater.
+func (*readOnlyStore) UpdateBootstrapConfig(string, BootstrapConfig) error {
+	return ErrReadOnly
+}
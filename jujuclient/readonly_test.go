@@ -0,0 +1,142 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type ReadOnlySuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	store jujuclient.ClientStore
+}
+
+var _ = gc.Suite(&ReadOnlySuite{})
+
+func (s *ReadOnlySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	writeTestControllersFile(c)
+	writeTestModelsFile(c)
+	writeTestAccountsFile(c)
+	writeTestCredentialsFile(c)
+	writeTestBootstrapConfigFile(c)
+	s.store = jujuclient.ReadOnly(jujuclient.NewFileClientStore())
+}
+
+func (s *ReadOnlySuite) TestGetters(c *gc.C) {
+	controllers, err := s.store.AllControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(controllers, gc.HasLen, 3)
+
+	_, err = s.store.ControllerByName("mallards")
+	c.Check(err, jc.ErrorIsNil)
+
+	current, err := s.store.CurrentController()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(current, gc.Equals, "mallards")
+
+	models, err := s.store.AllModels("kontroll")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(models, gc.HasLen, 2)
+
+	currentModel, err := s.store.CurrentModel("kontroll")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(currentModel, gc.Equals, "my-model")
+
+	model, err := s.store.ModelByName("kontroll", "admin")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(*model, jc.DeepEquals, kontrollAdminModelDetails)
+
+	account, err := s.store.AccountDetails("ctrl")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(*account, jc.DeepEquals, ctrlAdminAccountDetails)
+
+	_, err = s.store.CredentialForCloud("aws")
+	c.Check(err, jc.ErrorIsNil)
+
+	credentials, err := s.store.AllCredentials()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(credentials, gc.HasLen, 2)
+
+	_, err = s.store.BootstrapConfigForController("aws-test")
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ReadOnlySuite) TestMutators(c *gc.C) {
+	before := s.snapshot(c)
+
+	mutators := []func() error{
+		func() error {
+			return s.store.UpdateController("new", jujuclient.ControllerDetails{
+				ControllerUUID: "1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e",
+				CACert:         "cert",
+			})
+		},
+		func() error { return s.store.SetCurrentController("aws-test") },
+		func() error {
+			return s.store.SetControllerLabels("mallards", map[string]string{"env": "prod"})
+		},
+		func() error { return s.store.UpdateControllerEndpoints("mallards", []string{"10.0.0.9:17070"}) },
+		func() error { return s.store.SetControllerAlias("mal", "mallards") },
+		func() error { return s.store.SwitchTo("kontroll", "bob@remote", "admin") },
+		func() error { return s.store.RecordControllerConnection("kontroll") },
+		func() error { return s.store.RemoveController("mallards") },
+		func() error { return s.store.UpdateModel("ctrl", "new", jujuclient.ModelDetails{ModelUUID: "xyz"}) },
+		func() error { return s.store.SetCurrentModel("kontroll", "admin") },
+		func() error { return s.store.RemoveModel("kontroll", "admin") },
+		func() error {
+			return s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "bob@local"})
+		},
+		func() error { return s.store.RemoveAccount("ctrl") },
+		func() error { return s.store.UpdateCredential("aws", cloud.CloudCredential{}) },
+		func() error { return s.store.MoveCredentials("aws", "aws-new", true) },
+		func() error { return s.store.SetDefaultCredential("aws", "peter") },
+		func() error {
+			return s.store.SetCloudProxy("aws", jujuclient.ProxyConfig{HTTP: "http://proxy:3128"})
+		},
+		func() error {
+			return s.store.UpdateBootstrapConfig("ctrl", jujuclient.BootstrapConfig{
+				Cloud:  "aws",
+				Config: map[string]interface{}{"name": "admin"},
+			})
+		},
+	}
+	for i, mutate := range mutators {
+		c.Logf("mutator %d", i)
+		err := mutate()
+		c.Check(err, gc.Equals, jujuclient.ErrReadOnly)
+		c.Check(err, gc.ErrorMatches, "store is read-only")
+	}
+
+	c.Assert(s.snapshot(c), jc.DeepEquals, before)
+}
+
+type storeSnapshot struct {
+	controllers     *jujuclient.Controllers
+	models          map[string]*jujuclient.ControllerModels
+	accounts        map[string]jujuclient.AccountDetails
+	credentials     map[string]cloud.CloudCredential
+	bootstrapConfig map[string]jujuclient.BootstrapConfig
+}
+
+func (s *ReadOnlySuite) snapshot(c *gc.C) storeSnapshot {
+	var snap storeSnapshot
+	var err error
+	snap.controllers, err = jujuclient.ReadControllersFile(jujuclient.JujuControllersPath())
+	c.Assert(err, jc.ErrorIsNil)
+	snap.models, err = jujuclient.ReadModelsFile(jujuclient.JujuModelsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	snap.accounts, err = jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	snap.credentials, err = jujuclient.ReadCredentialsFile(jujuclient.JujuCredentialsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	snap.bootstrapConfig, err = jujuclient.ReadBootstrapConfigFile(jujuclient.JujuBootstrapConfigPath())
+	c.Assert(err, jc.ErrorIsNil)
+	return snap
+}
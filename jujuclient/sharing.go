@@ -0,0 +1,180 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// sharedController is the portable document written by
+// ExportController and read by ImportController.
+type sharedController struct {
+	// Name is the name of the controller.
+	Name string `yaml:"name"`
+
+	// Controller holds the controller's connection details.
+	Controller ControllerDetails `yaml:"controller"`
+
+	// Account holds the details of the controller's account, if any.
+	Account *AccountDetails `yaml:"account,omitempty"`
+
+	// Models holds the controller's cached models, indexed by name.
+	Models map[string]ModelDetails `yaml:"models,omitempty"`
+
+	// CurrentModel is the name of the controller's current model.
+	CurrentModel string `yaml:"current-model,omitempty"`
+
+	// BootstrapConfig holds the configuration used to bootstrap the
+	// controller, if any.
+	BootstrapConfig *BootstrapConfig `yaml:"bootstrap-config,omitempty"`
+}
+
+// ExportController writes the details of the named controller,
+// along with its account, models and bootstrap config, to w as a
+// document which may be read by ImportController. The account's
+// password and macaroon are only written if includeSecrets is true.
+func ExportController(store ClientStore, controllerName string, w io.Writer, includeSecrets bool) error {
+	details, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	shared := sharedController{
+		Name:       controllerName,
+		Controller: *details,
+	}
+
+	account, err := store.AccountDetails(controllerName)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	} else if err == nil {
+		sharedAccount := *account
+		if !includeSecrets {
+			sharedAccount.Password = ""
+			sharedAccount.Macaroon = ""
+		}
+		shared.Account = &sharedAccount
+	}
+
+	models, err := store.AllModels(controllerName)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	shared.Models = models
+	currentModel, err := store.CurrentModel(controllerName)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	shared.CurrentModel = currentModel
+
+	bootstrapConfig, err := store.BootstrapConfigForController(controllerName)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	shared.BootstrapConfig = bootstrapConfig
+
+	data, err := yaml.Marshal(shared)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal controller details")
+	}
+	_, err = w.Write(data)
+	return errors.Trace(err)
+}
+
+// ImportController reads a document written by ExportController
+// from r and adds its contents to the store, returning the name of
+// the imported controller. If a controller with the same name
+// already exists, an error satisfying errors.IsAlreadyExists is
+// returned, unless force is true, in which case the existing
+// controller and all information related to it are removed first.
+func ImportController(store ClientStore, r io.Reader, force bool) (string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var shared sharedController
+	if err := yaml.Unmarshal(data, &shared); err != nil {
+		return "", errors.Annotate(err, "cannot unmarshal controller details")
+	}
+	if err := validateSharedController(shared); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	_, err = store.ControllerByName(shared.Name)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return "", errors.Trace(err)
+	case !force:
+		return "", errors.AlreadyExistsf("controller %q", shared.Name)
+	default:
+		if err := store.RemoveController(shared.Name); err != nil {
+			return "", errors.Annotatef(err, "removing existing controller %q", shared.Name)
+		}
+	}
+
+	if err := store.UpdateController(shared.Name, shared.Controller); err != nil {
+		return "", errors.Trace(err)
+	}
+	if shared.Account != nil {
+		if err := store.UpdateAccount(shared.Name, *shared.Account); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	for modelName, details := range shared.Models {
+		if err := store.UpdateModel(shared.Name, modelName, details); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	if shared.CurrentModel != "" {
+		if err := store.SetCurrentModel(shared.Name, shared.CurrentModel); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	if shared.BootstrapConfig != nil {
+		if err := store.UpdateBootstrapConfig(shared.Name, *shared.BootstrapConfig); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	return shared.Name, nil
+}
+
+// validateSharedController checks everything in a shared controller
+// document before any of it is written to the store, so that a bad
+// document doesn't leave a partially imported controller behind.
+func validateSharedController(shared sharedController) error {
+	if err := ValidateControllerName(shared.Name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateControllerDetails(shared.Controller); err != nil {
+		return errors.Trace(err)
+	}
+	if shared.Account != nil {
+		if err := ValidateAccountDetails(*shared.Account); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for modelName, details := range shared.Models {
+		if err := ValidateModelName(modelName); err != nil {
+			return errors.Trace(err)
+		}
+		if err := ValidateModelDetails(details); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if shared.CurrentModel != "" {
+		if _, ok := shared.Models[shared.CurrentModel]; !ok {
+			return errors.NotValidf("current model %q", shared.CurrentModel)
+		}
+	}
+	if shared.BootstrapConfig != nil {
+		if err := ValidateBootstrapConfig(*shared.BootstrapConfig); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type SharingSuite struct {
+	testing.BaseSuite
+	store *jujuclienttesting.MemStore
+}
+
+var _ = gc.Suite(&SharingSuite{})
+
+const sharedControllerUUID = "5c3f2b1a-9e8d-4c7b-a6f5-e4d3c2b1a090"
+
+func (s *SharingSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.Controllers["ctrl"] = jujuclient.ControllerDetails{
+		ControllerUUID: sharedControllerUUID,
+		APIEndpoints:   []string{"10.0.0.1:17070"},
+		CACert:         "ca-cert",
+		Cloud:          "aws",
+		CloudRegion:    "us-east-1",
+	}
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{
+		User:     "bob@local",
+		Password: "hunter2",
+		Macaroon: "macaroon",
+	}
+	s.store.Models["ctrl"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin":   {ModelUUID: "admin-uuid"},
+			"default": {ModelUUID: "default-uuid"},
+		},
+		CurrentModel: "default",
+	}
+	s.store.BootstrapConfig["ctrl"] = jujuclient.BootstrapConfig{
+		Config:     map[string]interface{}{"name": "admin", "type": "ec2"},
+		Credential: "default",
+		Cloud:      "aws",
+	}
+}
+
+func (s *SharingSuite) export(c *gc.C, includeSecrets bool) *bytes.Buffer {
+	var buf bytes.Buffer
+	err := jujuclient.ExportController(s.store, "ctrl", &buf, includeSecrets)
+	c.Assert(err, jc.ErrorIsNil)
+	return &buf
+}
+
+func (s *SharingSuite) TestRoundTrip(c *gc.C) {
+	buf := s.export(c, true)
+
+	target := jujuclienttesting.NewMemStore()
+	name, err := jujuclient.ImportController(target, buf, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "ctrl")
+
+	c.Check(target.Controllers, jc.DeepEquals, s.store.Controllers)
+	c.Check(target.Accounts, jc.DeepEquals, s.store.Accounts)
+	c.Check(target.Models, jc.DeepEquals, s.store.Models)
+	c.Check(target.BootstrapConfig, jc.DeepEquals, s.store.BootstrapConfig)
+}
+
+func (s *SharingSuite) TestExportExcludesSecrets(c *gc.C) {
+	buf := s.export(c, false)
+	c.Check(buf.String(), gc.Not(jc.Contains), "hunter2")
+	c.Check(buf.String(), gc.Not(jc.Contains), "macaroon")
+
+	target := jujuclienttesting.NewMemStore()
+	_, err := jujuclient.ImportController(target, buf, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(target.Accounts["ctrl"], jc.DeepEquals, jujuclient.AccountDetails{User: "bob@local"})
+
+	// The source store must not have been modified.
+	c.Check(s.store.Accounts["ctrl"].Password, gc.Equals, "hunter2")
+}
+
+func (s *SharingSuite) TestExportControllerOnly(c *gc.C) {
+	s.store.Accounts = map[string]jujuclient.AccountDetails{}
+	s.store.Models = map[string]*jujuclient.ControllerModels{}
+	s.store.BootstrapConfig = map[string]jujuclient.BootstrapConfig{}
+	buf := s.export(c, true)
+
+	target := jujuclienttesting.NewMemStore()
+	_, err := jujuclient.ImportController(target, buf, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(target.Controllers, jc.DeepEquals, s.store.Controllers)
+	c.Check(target.Accounts, gc.HasLen, 0)
+	c.Check(target.Models, gc.HasLen, 0)
+	c.Check(target.BootstrapConfig, gc.HasLen, 0)
+}
+
+func (s *SharingSuite) TestExportControllerNotFound(c *gc.C) {
+	var buf bytes.Buffer
+	err := jujuclient.ExportController(s.store, "nope", &buf, true)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(buf.Len(), gc.Equals, 0)
+}
+
+func (s *SharingSuite) TestImportExisting(c *gc.C) {
+	buf := s.export(c, true)
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{User: "mary@local"}
+
+	_, err := jujuclient.ImportController(s.store, buf, false)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `controller "ctrl" already exists`)
+	c.Check(s.store.Accounts["ctrl"].User, gc.Equals, "mary@local")
+}
+
+func (s *SharingSuite) TestImportExistingForce(c *gc.C) {
+	buf := s.export(c, true)
+	s.store.Models["ctrl"].Models["stale"] = jujuclient.ModelDetails{ModelUUID: "stale-uuid"}
+
+	_, err := jujuclient.ImportController(s.store, buf, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.store.Models["ctrl"].Models, jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"admin":   {ModelUUID: "admin-uuid"},
+		"default": {ModelUUID: "default-uuid"},
+	})
+}
+
+func (s *SharingSuite) TestImportInvalid(c *gc.C) {
+	doc := `
+name: ctrl
+controller:
+  uuid: 5c3f2b1a-9e8d-4c7b-a6f5-e4d3c2b1a090
+  ca-cert: ca-cert
+models:
+  admin:
+    uuid: ""
+`[1:]
+	target := jujuclienttesting.NewMemStore()
+	_, err := jujuclient.ImportController(target, strings.NewReader(doc), false)
+	c.Assert(err, gc.ErrorMatches, "missing uuid, model details not valid")
+	c.Check(target.Controllers, gc.HasLen, 0)
+}
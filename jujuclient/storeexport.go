@@ -0,0 +1,266 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/cloud"
+)
+
+// exportVersion is the version of the document written by
+// ExportStore.
+const exportVersion = 1
+
+// exportedStore is the document written by ExportStore.
+type exportedStore struct {
+	Version           int                              `yaml:"version"`
+	Controllers       map[string]ControllerDetails     `yaml:"controllers,omitempty"`
+	CurrentController string                           `yaml:"current-controller,omitempty"`
+	Accounts          map[string]AccountDetails        `yaml:"accounts,omitempty"`
+	Models            map[string]*ControllerModels     `yaml:"models,omitempty"`
+	Credentials       map[string]cloud.CloudCredential `yaml:"credentials,omitempty"`
+	Proxies           map[string]ProxyConfig           `yaml:"proxies,omitempty"`
+	BootstrapConfig   map[string]BootstrapConfig       `yaml:"bootstrap-config,omitempty"`
+}
+
+// ExportStore returns a YAML document holding everything recorded by
+// the given store: controllers, accounts, models, credentials, proxy
+// settings and bootstrap config. Sensitive details such as passwords
+// and credential attributes are included, so the document should be
+// kept as safe as the store itself. Controller aliases are not
+// included.
+func ExportStore(store ClientStore) ([]byte, error) {
+	doc := exportedStore{
+		Version:         exportVersion,
+		Accounts:        make(map[string]AccountDetails),
+		Models:          make(map[string]*ControllerModels),
+		Proxies:         make(map[string]ProxyConfig),
+		BootstrapConfig: make(map[string]BootstrapConfig),
+	}
+	var err error
+	if doc.Controllers, err = store.AllControllers(); err != nil {
+		return nil, errors.Annotate(err, "cannot get controllers")
+	}
+	if doc.CurrentController, err = store.CurrentController(); err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Annotate(err, "cannot get current controller")
+	}
+	if doc.Credentials, err = store.AllCredentials(); err != nil {
+		return nil, errors.Annotate(err, "cannot get credentials")
+	}
+
+	clouds := set.NewStrings()
+	for cloudName := range doc.Credentials {
+		clouds.Add(cloudName)
+	}
+	for name, details := range doc.Controllers {
+		clouds.Add(details.Cloud)
+		if err := exportController(store, name, &doc); err != nil {
+			return nil, errors.Annotatef(err, "cannot export controller %s", name)
+		}
+	}
+	for _, cloudName := range clouds.SortedValues() {
+		proxy, err := store.CloudProxy(cloudName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "cannot get proxy settings for cloud %s", cloudName)
+		}
+		doc.Proxies[cloudName] = *proxy
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot marshal client store")
+	}
+	return data, nil
+}
+
+// exportController adds the account, models and bootstrap config of
+// the named controller to doc.
+func exportController(store ClientStore, name string, doc *exportedStore) error {
+	account, err := store.AccountDetails(name)
+	if err == nil {
+		doc.Accounts[name] = *account
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+
+	models, err := store.AllModels(name)
+	if err == nil {
+		controllerModels := &ControllerModels{Models: models}
+		controllerModels.CurrentModel, err = store.CurrentModel(name)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		doc.Models[name] = controllerModels
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+
+	cfg, err := store.BootstrapConfigForController(name)
+	if err == nil {
+		doc.BootstrapConfig[name] = *cfg
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// ImportStore adds everything recorded in data, a document written
+// by ExportStore, to the given store. Anything already recorded by
+// the store is kept: a controller, account, model, cloud's
+// credentials, proxy settings or bootstrap config in data is only
+// added if the store has nothing with the same name, and the current
+// controller and models are only set if the store has none. If the
+// store is a Transactor, the import is done in a single transaction.
+func ImportStore(store ClientStore, data []byte) error {
+	return errors.Trace(importStore(store, data, false))
+}
+
+// ImportStoreOverwrite is like ImportStore, except that everything
+// recorded in data replaces whatever the store has recorded with the
+// same name, and the current controller and models are always set.
+// Anything which the store has recorded and data doesn't mention is
+// kept.
+func ImportStoreOverwrite(store ClientStore, data []byte) error {
+	return errors.Trace(importStore(store, data, true))
+}
+
+func importStore(store ClientStore, data []byte, overwrite bool) error {
+	var doc exportedStore
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return errors.Annotate(err, "cannot unmarshal client store")
+	}
+	if doc.Version != exportVersion {
+		return errors.Errorf("unsupported client store version %d", doc.Version)
+	}
+	if transactor, ok := store.(Transactor); ok {
+		return transactor.Do(func(tx ClientStore) error {
+			return importDoc(tx, doc, overwrite)
+		})
+	}
+	return importDoc(store, doc, overwrite)
+}
+
+func importDoc(store ClientStore, doc exportedStore, overwrite bool) error {
+	// exists reports whether an entry should be left alone because
+	// the store already has one with the same name.
+	exists := func(err error) (bool, error) {
+		if errors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		return !overwrite, nil
+	}
+
+	for name, details := range doc.Controllers {
+		_, err := store.ControllerByName(name)
+		if skip, err := exists(err); err != nil {
+			return errors.Trace(err)
+		} else if skip {
+			continue
+		}
+		if err := store.UpdateController(name, details); err != nil {
+			return errors.Annotatef(err, "cannot import controller %s", name)
+		}
+	}
+	for name, details := range doc.Accounts {
+		_, err := store.AccountDetails(name)
+		if skip, err := exists(err); err != nil {
+			return errors.Trace(err)
+		} else if skip {
+			continue
+		}
+		if err := store.UpdateAccount(name, details); err != nil {
+			return errors.Annotatef(err, "cannot import account for controller %s", name)
+		}
+	}
+	for controllerName, controllerModels := range doc.Models {
+		imported := set.NewStrings()
+		for name, details := range controllerModels.Models {
+			_, err := store.ModelByName(controllerName, name)
+			if skip, err := exists(err); err != nil {
+				return errors.Trace(err)
+			} else if skip {
+				continue
+			}
+			if err := store.UpdateModel(controllerName, name, details); err != nil {
+				return errors.Annotatef(err, "cannot import model %s:%s", controllerName, name)
+			}
+			imported.Add(name)
+		}
+		if controllerModels.CurrentModel == "" {
+			continue
+		}
+		_, err := store.CurrentModel(controllerName)
+		if skip, err := exists(err); err != nil {
+			return errors.Trace(err)
+		} else if skip {
+			continue
+		}
+		if err := store.SetCurrentModel(controllerName, controllerModels.CurrentModel); err != nil {
+			return errors.Annotatef(err, "cannot import current model for controller %s", controllerName)
+		}
+		// Setting the current model records it as used now, so
+		// restore the time at which it was really last used.
+		details := controllerModels.Models[controllerModels.CurrentModel]
+		if imported.Contains(controllerModels.CurrentModel) && !details.LastUsed.IsZero() {
+			err := store.UpdateModel(controllerName, controllerModels.CurrentModel, details)
+			if err != nil {
+				return errors.Annotatef(err, "cannot import model %s:%s", controllerName, controllerModels.CurrentModel)
+			}
+		}
+	}
+	for cloudName, credentials := range doc.Credentials {
+		_, err := store.CredentialForCloud(cloudName)
+		if skip, err := exists(err); err != nil {
+			return errors.Trace(err)
+		} else if skip {
+			continue
+		}
+		if err := store.UpdateCredential(cloudName, credentials); err != nil {
+			return errors.Annotatef(err, "cannot import credentials for cloud %s", cloudName)
+		}
+	}
+	for cloudName, proxy := range doc.Proxies {
+		_, err := store.CloudProxy(cloudName)
+		if skip, err := exists(err); err != nil {
+			return errors.Trace(err)
+		} else if skip {
+			continue
+		}
+		if err := store.SetCloudProxy(cloudName, proxy); err != nil {
+			return errors.Annotatef(err, "cannot import proxy settings for cloud %s", cloudName)
+		}
+	}
+	for name, cfg := range doc.BootstrapConfig {
+		_, err := store.BootstrapConfigForController(name)
+		if skip, err := exists(err); err != nil {
+			return errors.Trace(err)
+		} else if skip {
+			continue
+		}
+		if err := store.UpdateBootstrapConfig(name, cfg); err != nil {
+			return errors.Annotatef(err, "cannot import bootstrap config for controller %s", name)
+		}
+	}
+
+	if doc.CurrentController == "" {
+		return nil
+	}
+	_, err := store.CurrentController()
+	if skip, err := exists(err); err != nil {
+		return errors.Trace(err)
+	} else if skip {
+		return nil
+	}
+	return errors.Annotate(
+		store.SetCurrentController(doc.CurrentController),
+		"cannot import current controller",
+	)
+}
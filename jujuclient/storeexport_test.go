@@ -0,0 +1,159 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"os"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type StoreExportSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+}
+
+var _ = gc.Suite(&StoreExportSuite{})
+
+// populateStore records something in every collection of the given
+// store.
+func populateStore(c *gc.C, store jujuclient.ClientStore) {
+	err := store.UpdateController("ctrl", storeController)
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.SetCurrentController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateAccount("ctrl", jujuclient.AccountDetails{
+		User:     "bob@local",
+		Password: "hunter2",
+		Macaroon: "macaroon",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateModel("ctrl", "admin", storeModel)
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateModel("ctrl", "default", jujuclient.ModelDetails{ModelUUID: "default.uuid"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.SetCurrentModel("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateCredential("aws", cloud.CloudCredential{
+		DefaultCredential: "one",
+		AuthCredentials: map[string]cloud.Credential{
+			"one": cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+				"access-key": "key",
+				"secret-key": "secret",
+			}),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.SetCloudProxy("aws", jujuclient.ProxyConfig{HTTPS: "https://proxy.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateBootstrapConfig("ctrl", jujuclient.BootstrapConfig{
+		Cloud:  "aws",
+		Config: map[string]interface{}{"name": "admin"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StoreExportSuite) TestRoundTrip(c *gc.C) {
+	store := jujuclient.NewFileClientStore()
+	populateStore(c, store)
+	data, err := jujuclient.ExportStore(store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "version: 1\n")
+	c.Assert(string(data), jc.Contains, "hunter2")
+	c.Assert(string(data), jc.Contains, "secret")
+
+	for _, path := range []string{
+		jujuclient.JujuControllersPath(),
+		jujuclient.JujuModelsPath(),
+		jujuclient.JujuAccountsPath(),
+		jujuclient.JujuCredentialsPath(),
+		jujuclient.JujuBootstrapConfigPath(),
+	} {
+		err := os.Remove(path)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = jujuclient.ImportStore(store, data)
+	c.Assert(err, jc.ErrorIsNil)
+
+	reexported, err := jujuclient.ExportStore(store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(reexported), gc.Equals, string(data))
+	account, err := store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(account.Password, gc.Equals, "hunter2")
+}
+
+func (s *StoreExportSuite) TestImportMerges(c *gc.C) {
+	from := jujuclient.NewMemStore()
+	populateStore(c, from)
+	data, err := jujuclient.ExportStore(from)
+	c.Assert(err, jc.ErrorIsNil)
+
+	store := jujuclient.NewMemStore()
+	other := storeController
+	other.ControllerUUID = otherControllerUUID
+	err = store.UpdateController("ctrl", other)
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateController("mine", storeController)
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.SetCurrentController("mine")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuclient.ImportStore(store, data)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.ControllerUUID, gc.Equals, otherControllerUUID)
+	current, err := store.CurrentController()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "mine")
+	// Everything the store didn't have is added.
+	model, err := store.CurrentModel("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model, gc.Equals, "admin")
+	_, err = store.CredentialForCloud("aws")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StoreExportSuite) TestImportOverwrite(c *gc.C) {
+	from := jujuclient.NewMemStore()
+	populateStore(c, from)
+	data, err := jujuclient.ExportStore(from)
+	c.Assert(err, jc.ErrorIsNil)
+
+	store := jujuclient.NewMemStore()
+	other := storeController
+	other.ControllerUUID = otherControllerUUID
+	err = store.UpdateController("ctrl", other)
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateController("mine", storeController)
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.SetCurrentController("mine")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuclient.ImportStoreOverwrite(store, data)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*found, jc.DeepEquals, storeController)
+	current, err := store.CurrentController()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "ctrl")
+	// Anything not in the document is kept.
+	_, err = store.ControllerByName("mine")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StoreExportSuite) TestImportUnsupportedVersion(c *gc.C) {
+	store := jujuclient.NewMemStore()
+	err := jujuclient.ImportStore(store, []byte("version: 2\n"))
+	c.Assert(err, gc.ErrorMatches, "unsupported client store version 2")
+	_, err = store.CurrentController()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,243 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloud"
+)
+
+// Transactor is implemented by client stores which can apply a
+// number of changes together, such as adding a controller along with
+// its account and models.
+type Transactor interface {
+	// Do calls f with a ClientStore which buffers all the changes
+	// made through it. If f returns nil, the changes are applied to
+	// the store together; otherwise they are discarded, and f's
+	// error is returned. The store must only be used through tx
+	// while f is running.
+	Do(f func(tx ClientStore) error) error
+}
+
+var _ Transactor = (*store)(nil)
+
+// Do implements Transactor. The store's files are locked while f
+// runs, and only the files holding collections which f changed are
+// written. Each file is replaced atomically, and the controllers
+// file is written last so that controllers never refer to details
+// which haven't been written. If a file can't be written, the files
+// already written are restored to their previous contents.
+func (s *store) Do(f func(tx ClientStore) error) error {
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotate(err, "cannot start transaction")
+	}
+	defer releaser.Release()
+
+	// The original contents are kept as they are on disk, with any
+	// encrypted accounts, so that they can be restored.
+	original, err := readStoreContents()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	before := original.copy()
+	if s.passphrase != "" {
+		for name, details := range before.Accounts {
+			if before.Accounts[name], err = s.decryptAccount(name, details); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	tx := &MemStore{storeContents: before.copy()}
+	if err := f(tx); err != nil {
+		return errors.Trace(err)
+	}
+	after := tx.storeContents
+	if reflect.DeepEqual(before.Accounts, after.Accounts) {
+		after.Accounts = original.Accounts
+	} else {
+		for name, details := range after.Accounts {
+			if after.Accounts[name], err = s.encryptAccount(details); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return errors.Trace(writeStoreContents(original, after))
+}
+
+// storeContents holds everything recorded by a client store. Its
+// fields are exported so that the collections of a MemStore may be
+// inspected and modified directly by tests.
+type storeContents struct {
+	Controllers           map[string]ControllerDetails
+	CurrentControllerName string
+	ControllerAliases     map[string]string
+	Models                map[string]*ControllerModels
+	Accounts              map[string]AccountDetails
+	Credentials           map[string]cloud.CloudCredential
+	CloudProxies          map[string]ProxyConfig
+	BootstrapConfig       map[string]BootstrapConfig
+}
+
+// copy returns a copy of c which shares nothing that the store may
+// change in place with c. The maps of the copy are never nil.
+func (c storeContents) copy() storeContents {
+	result := storeContents{
+		Controllers:           make(map[string]ControllerDetails),
+		CurrentControllerName: c.CurrentControllerName,
+		ControllerAliases:     make(map[string]string),
+		Models:                make(map[string]*ControllerModels),
+		Accounts:              make(map[string]AccountDetails),
+		Credentials:           make(map[string]cloud.CloudCredential),
+		CloudProxies:          make(map[string]ProxyConfig),
+		BootstrapConfig:       make(map[string]BootstrapConfig),
+	}
+	for name, details := range c.Controllers {
+		result.Controllers[name] = details
+	}
+	for alias, name := range c.ControllerAliases {
+		result.ControllerAliases[alias] = name
+	}
+	for name, controllerModels := range c.Models {
+		modelsCopy := *controllerModels
+		if controllerModels.Models != nil {
+			modelsCopy.Models = make(map[string]ModelDetails)
+			for modelName, details := range controllerModels.Models {
+				modelsCopy.Models[modelName] = details
+			}
+		}
+		result.Models[name] = &modelsCopy
+	}
+	for name, details := range c.Accounts {
+		result.Accounts[name] = details
+	}
+	for name, credentials := range c.Credentials {
+		if credentials.AuthCredentials != nil {
+			authCredentials := make(map[string]cloud.Credential)
+			for credName, credential := range credentials.AuthCredentials {
+				authCredentials[credName] = credential
+			}
+			credentials.AuthCredentials = authCredentials
+		}
+		result.Credentials[name] = credentials
+	}
+	for name, proxy := range c.CloudProxies {
+		result.CloudProxies[name] = proxy
+	}
+	for name, cfg := range c.BootstrapConfig {
+		result.BootstrapConfig[name] = cfg
+	}
+	return result
+}
+
+// readStoreContents reads the contents of the file-based client
+// store.
+func readStoreContents() (storeContents, error) {
+	var contents storeContents
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return contents, errors.Trace(err)
+	}
+	contents.Controllers = controllers.Controllers
+	contents.CurrentControllerName = controllers.CurrentController
+	contents.ControllerAliases = controllers.Aliases
+	if contents.Models, err = ReadModelsFile(JujuModelsPath()); err != nil {
+		return contents, errors.Trace(err)
+	}
+	if contents.Accounts, err = ReadAccountsFile(JujuAccountsPath()); err != nil {
+		return contents, errors.Trace(err)
+	}
+	if contents.Credentials, err = ReadCredentialsFile(JujuCredentialsPath()); err != nil {
+		return contents, errors.Trace(err)
+	}
+	if contents.CloudProxies, err = ReadCloudProxiesFile(JujuCredentialsPath()); err != nil {
+		return contents, errors.Trace(err)
+	}
+	if contents.BootstrapConfig, err = ReadBootstrapConfigFile(JujuBootstrapConfigPath()); err != nil {
+		return contents, errors.Trace(err)
+	}
+	return contents, nil
+}
+
+// storeFileWriter writes one of the files of the file-based client
+// store.
+type storeFileWriter struct {
+	// changed reports whether the file's contents differ between
+	// the two store contents.
+	changed func(before, after storeContents) bool
+
+	// write writes the file with the given store contents.
+	write func(contents storeContents) error
+}
+
+// storeFileWriters holds the writers of the file-based client store's
+// files, in the order in which they are written.
+var storeFileWriters = []storeFileWriter{{
+	changed: func(before, after storeContents) bool {
+		return !reflect.DeepEqual(before.Credentials, after.Credentials) ||
+			!reflect.DeepEqual(before.CloudProxies, after.CloudProxies)
+	},
+	write: func(contents storeContents) error {
+		return writeCredentialsFile(credentialsCollection{contents.Credentials, contents.CloudProxies})
+	},
+}, {
+	changed: func(before, after storeContents) bool {
+		return !reflect.DeepEqual(before.BootstrapConfig, after.BootstrapConfig)
+	},
+	write: func(contents storeContents) error {
+		return WriteBootstrapConfigFile(contents.BootstrapConfig)
+	},
+}, {
+	changed: func(before, after storeContents) bool {
+		return !reflect.DeepEqual(before.Accounts, after.Accounts)
+	},
+	write: func(contents storeContents) error {
+		return WriteAccountsFile(contents.Accounts)
+	},
+}, {
+	changed: func(before, after storeContents) bool {
+		return !reflect.DeepEqual(before.Models, after.Models)
+	},
+	write: func(contents storeContents) error {
+		return WriteModelsFile(contents.Models)
+	},
+}, {
+	changed: func(before, after storeContents) bool {
+		return !reflect.DeepEqual(before.Controllers, after.Controllers) ||
+			before.CurrentControllerName != after.CurrentControllerName ||
+			!reflect.DeepEqual(before.ControllerAliases, after.ControllerAliases)
+	},
+	write: func(contents storeContents) error {
+		return WriteControllersFile(&Controllers{
+			Controllers:       contents.Controllers,
+			CurrentController: contents.CurrentControllerName,
+			Aliases:           contents.ControllerAliases,
+		})
+	},
+}}
+
+// writeStoreContents writes the collections which differ between
+// before and after to the file-based client store. If a file can't
+// be written, the files already written are rewritten with before.
+func writeStoreContents(before, after storeContents) error {
+	var written []storeFileWriter
+	for _, writer := range storeFileWriters {
+		if !writer.changed(before, after) {
+			continue
+		}
+		if err := writer.write(after); err != nil {
+			for i := len(written) - 1; i >= 0; i-- {
+				if rollbackErr := written[i].write(before); rollbackErr != nil {
+					logger.Errorf("cannot restore client store file: %v", rollbackErr)
+				}
+			}
+			return errors.Trace(err)
+		}
+		written = append(written, writer)
+	}
+	return nil
+}
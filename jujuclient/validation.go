@@ -4,7 +4,13 @@
 package jujuclient
 
 import (
+	"net"
+	"net/url"
+	"strconv"
+
 	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 )
 
@@ -13,9 +19,73 @@ func ValidateControllerDetails(details ControllerDetails) error {
 	if details.ControllerUUID == "" {
 		return errors.NotValidf("missing uuid, controller details")
 	}
+	if !utils.IsValidUUIDString(details.ControllerUUID) {
+		return errors.NotValidf("uuid %q, controller details", details.ControllerUUID)
+	}
+	// Controllers which haven't been reached yet have no endpoints.
+	for _, endpoint := range details.UnresolvedAPIEndpoints {
+		if err := validateAPIEndpoint(endpoint); err != nil {
+			return errors.Annotate(err, "unresolved-api-endpoints")
+		}
+	}
+	if err := ValidateControllerEndpoints(details.APIEndpoints); err != nil {
+		return errors.Trace(err)
+	}
 	if details.CACert == "" {
 		return errors.NotValidf("missing ca-cert, controller details")
 	}
+	for _, cert := range details.CACerts {
+		if cert == "" {
+			return errors.NotValidf("empty ca-certs entry, controller details")
+		}
+	}
+	if err := ValidateControllerLabels(details.Labels); err != nil {
+		return errors.Trace(err)
+	}
+	if details.AgentVersion != "" {
+		if _, err := version.Parse(details.AgentVersion); err != nil {
+			return errors.NotValidf("agent-version %q, controller details", details.AgentVersion)
+		}
+	}
+	return nil
+}
+
+// ValidateControllerEndpoints ensures that the given controller API
+// endpoints are valid.
+func ValidateControllerEndpoints(endpoints []string) error {
+	for _, endpoint := range endpoints {
+		if err := validateAPIEndpoint(endpoint); err != nil {
+			return errors.Annotate(err, "api-endpoints")
+		}
+	}
+	return nil
+}
+
+// validateAPIEndpoint ensures that the given API endpoint is a
+// host and port, as accepted by net.SplitHostPort. IPv6 addresses
+// must be enclosed in square brackets.
+func validateAPIEndpoint(endpoint string) error {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return errors.Annotatef(errors.NewNotValid(err, ""), "endpoint %q", endpoint)
+	}
+	if host == "" {
+		return errors.NotValidf("endpoint %q with no host", endpoint)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return errors.NotValidf("endpoint %q with port %q", endpoint, port)
+	}
+	return nil
+}
+
+// ValidateControllerLabels ensures that the given controller labels
+// are valid.
+func ValidateControllerLabels(labels map[string]string) error {
+	for key := range labels {
+		if key == "" {
+			return errors.NotValidf("empty label key, controller details")
+		}
+	}
 	return nil
 }
 
@@ -85,3 +155,30 @@ func validateUser(name string) error {
 	}
 	return nil
 }
+
+// ValidateProxyConfig ensures that the given proxy settings are valid.
+func ValidateProxyConfig(proxy ProxyConfig) error {
+	if err := validateProxyURL("http-proxy", proxy.HTTP); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateProxyURL("https-proxy", proxy.HTTPS); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func validateProxyURL(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return errors.NotValidf("%s %q, proxy config", field, value)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return errors.NotValidf("%s %q, proxy config", field, value)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// ProblemKind identifies a kind of inconsistency in a client store.
+type ProblemKind string
+
+const (
+	// DanglingCurrentController indicates that the current
+	// controller refers to a controller which doesn't exist.
+	DanglingCurrentController ProblemKind = "dangling-current-controller"
+
+	// DanglingCurrentModel indicates that a controller's current
+	// model refers to a model which doesn't exist.
+	DanglingCurrentModel ProblemKind = "dangling-current-model"
+
+	// OrphanedModels indicates that models are recorded for a
+	// controller which doesn't exist.
+	OrphanedModels ProblemKind = "orphaned-models"
+
+	// OrphanedAccount indicates that an account is recorded for a
+	// controller which doesn't exist.
+	OrphanedAccount ProblemKind = "orphaned-account"
+
+	// OrphanedBootstrapConfig indicates that bootstrap config is
+	// recorded for a controller which doesn't exist.
+	OrphanedBootstrapConfig ProblemKind = "orphaned-bootstrap-config"
+)
+
+// Problem describes an inconsistency found in a client store.
+type Problem struct {
+	// Kind identifies the kind of problem.
+	Kind ProblemKind
+
+	// Controller is the name of the controller the problem relates to.
+	Controller string
+
+	// Model is the name of the model the problem relates to, if any.
+	Model string
+}
+
+// String implements fmt.Stringer.
+func (p Problem) String() string {
+	switch p.Kind {
+	case DanglingCurrentController:
+		return fmt.Sprintf("current controller %q does not exist", p.Controller)
+	case DanglingCurrentModel:
+		return fmt.Sprintf("current model %q for controller %q does not exist", p.Model, p.Controller)
+	case OrphanedModels:
+		return fmt.Sprintf("models recorded for unknown controller %q", p.Controller)
+	case OrphanedAccount:
+		return fmt.Sprintf("account recorded for unknown controller %q", p.Controller)
+	case OrphanedBootstrapConfig:
+		return fmt.Sprintf("bootstrap config recorded for unknown controller %q", p.Controller)
+	}
+	return fmt.Sprintf("%s (controller %q)", p.Kind, p.Controller)
+}
+
+// StoreVerifier checks a client store for inconsistencies, such as
+// those left behind by editing the store's files by hand.
+type StoreVerifier interface {
+	// Verify returns the problems found in the store, without
+	// modifying it.
+	Verify() ([]Problem, error)
+
+	// Repair fixes the problems found in the store, and returns the
+	// problems that were fixed. Dangling current controller and
+	// model pointers are cleared, and orphaned records are removed.
+	Repair() ([]Problem, error)
+}
+
+var _ StoreVerifier = (*store)(nil)
+
+// Verify implements StoreVerifier.
+func (s *store) Verify() ([]Problem, error) {
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot verify store")
+	}
+	defer releaser.Release()
+
+	contents, err := readVerifyContents()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return contents.check(false), nil
+}
+
+// Repair implements StoreVerifier.
+func (s *store) Repair() ([]Problem, error) {
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot repair store")
+	}
+	defer releaser.Release()
+
+	contents, err := readVerifyContents()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	problems := contents.check(true)
+	if len(problems) == 0 {
+		return nil, nil
+	}
+	if err := contents.write(problems); err != nil {
+		return nil, errors.Annotate(err, "cannot repair store")
+	}
+	return problems, nil
+}
+
+// verifyContents holds the contents of the store's files which
+// refer to controllers.
+type verifyContents struct {
+	controllers     *Controllers
+	models          map[string]*ControllerModels
+	accounts        map[string]AccountDetails
+	bootstrapConfig map[string]BootstrapConfig
+}
+
+func readVerifyContents() (*verifyContents, error) {
+	var contents verifyContents
+	var err error
+	if contents.controllers, err = ReadControllersFile(JujuControllersPath()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if contents.models, err = ReadModelsFile(JujuModelsPath()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if contents.accounts, err = ReadAccountsFile(JujuAccountsPath()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if contents.bootstrapConfig, err = ReadBootstrapConfigFile(JujuBootstrapConfigPath()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &contents, nil
+}
+
+// check returns the problems found in the store contents, ordered
+// by controller name. If repair is true, the contents are modified
+// to fix the problems.
+func (c *verifyContents) check(repair bool) []Problem {
+	var problems []Problem
+	knownController := func(name string) bool {
+		_, ok := c.controllers.Controllers[name]
+		return ok
+	}
+
+	if current := c.controllers.CurrentController; current != "" && !knownController(current) {
+		problems = append(problems, Problem{Kind: DanglingCurrentController, Controller: current})
+		if repair {
+			c.controllers.CurrentController = ""
+		}
+	}
+
+	var names []string
+	for name := range c.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		controllerModels := c.models[name]
+		if !knownController(name) {
+			problems = append(problems, Problem{Kind: OrphanedModels, Controller: name})
+			if repair {
+				delete(c.models, name)
+			}
+			continue
+		}
+		current := controllerModels.CurrentModel
+		if _, ok := controllerModels.Models[current]; current != "" && !ok {
+			problems = append(problems, Problem{
+				Kind:       DanglingCurrentModel,
+				Controller: name,
+				Model:      current,
+			})
+			if repair {
+				controllerModels.CurrentModel = ""
+			}
+		}
+	}
+
+	names = names[:0]
+	for name := range c.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !knownController(name) {
+			problems = append(problems, Problem{Kind: OrphanedAccount, Controller: name})
+			if repair {
+				delete(c.accounts, name)
+			}
+		}
+	}
+
+	names = names[:0]
+	for name := range c.bootstrapConfig {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !knownController(name) {
+			problems = append(problems, Problem{Kind: OrphanedBootstrapConfig, Controller: name})
+			if repair {
+				delete(c.bootstrapConfig, name)
+			}
+		}
+	}
+	return problems
+}
+
+// write writes out the files affected by the given problems.
+func (c *verifyContents) write(problems []Problem) error {
+	affected := make(map[ProblemKind]bool)
+	for _, p := range problems {
+		affected[p.Kind] = true
+	}
+	if affected[DanglingCurrentController] {
+		if err := WriteControllersFile(c.controllers); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if affected[DanglingCurrentModel] || affected[OrphanedModels] {
+		if err := WriteModelsFile(c.models); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if affected[OrphanedAccount] {
+		if err := WriteAccountsFile(c.accounts); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if affected[OrphanedBootstrapConfig] {
+		if err := WriteBootstrapConfigFile(c.bootstrapConfig); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type VerifySuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	store jujuclient.StoreVerifier
+}
+
+var _ = gc.Suite(&VerifySuite{})
+
+func (s *VerifySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewFileClientStore().(jujuclient.StoreVerifier)
+}
+
+const corruptControllersYAML = `
+controllers:
+  kontroll:
+    uuid: this-is-a-uuid
+    api-endpoints: [this-is-one-of-many-api-endpoints]
+    ca-cert: this-is-a-ca-cert
+current-controller: gone
+`
+
+const corruptModelsYAML = `
+controllers:
+  kontroll:
+    models:
+      admin:
+        uuid: abc
+    current-model: my-model
+  orphan:
+    models:
+      admin:
+        uuid: ghi
+`
+
+const corruptAccountsYAML = `
+controllers:
+  kontroll:
+    user: bob@remote
+  orphan:
+    user: admin@local
+    password: hunter2
+`
+
+const corruptBootstrapConfigYAML = `
+controllers:
+  orphan:
+    cloud: aws
+    region: us-east-1
+`
+
+func writeStoreFile(c *gc.C, name, content string) {
+	err := ioutil.WriteFile(osenv.JujuXDGDataHomePath(name), []byte(content[1:]), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *VerifySuite) writeCorruptStore(c *gc.C) {
+	writeStoreFile(c, "controllers.yaml", corruptControllersYAML)
+	writeStoreFile(c, "models.yaml", corruptModelsYAML)
+	writeStoreFile(c, "accounts.yaml", corruptAccountsYAML)
+	writeStoreFile(c, "bootstrap-config.yaml", corruptBootstrapConfigYAML)
+}
+
+var corruptStoreProblems = []jujuclient.Problem{
+	{Kind: jujuclient.DanglingCurrentController, Controller: "gone"},
+	{Kind: jujuclient.DanglingCurrentModel, Controller: "kontroll", Model: "my-model"},
+	{Kind: jujuclient.OrphanedModels, Controller: "orphan"},
+	{Kind: jujuclient.OrphanedAccount, Controller: "orphan"},
+	{Kind: jujuclient.OrphanedBootstrapConfig, Controller: "orphan"},
+}
+
+func (s *VerifySuite) TestVerifyEmptyStore(c *gc.C) {
+	problems, err := s.store.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(problems, gc.HasLen, 0)
+}
+
+func (s *VerifySuite) TestVerifyCurrentControllerOnly(c *gc.C) {
+	writeStoreFile(c, "controllers.yaml", corruptControllersYAML)
+	writeStoreFile(c, "accounts.yaml", `
+controllers:
+  kontroll:
+    user: bob@remote
+`)
+	problems, err := s.store.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(problems, jc.DeepEquals, []jujuclient.Problem{
+		{Kind: jujuclient.DanglingCurrentController, Controller: "gone"},
+	})
+}
+
+func (s *VerifySuite) TestVerify(c *gc.C) {
+	s.writeCorruptStore(c)
+	before := s.readFiles(c)
+
+	problems, err := s.store.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(problems, jc.DeepEquals, corruptStoreProblems)
+
+	// Verify must not modify the store.
+	c.Assert(s.readFiles(c), jc.DeepEquals, before)
+}
+
+func (s *VerifySuite) TestVerifyUnreadableFile(c *gc.C) {
+	writeStoreFile(c, "models.yaml", "\nfail me now")
+	_, err := s.store.Verify()
+	c.Assert(err, gc.ErrorMatches, "cannot unmarshal models: .*")
+}
+
+func (s *VerifySuite) TestRepair(c *gc.C) {
+	s.writeCorruptStore(c)
+
+	problems, err := s.store.Repair()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(problems, jc.DeepEquals, corruptStoreProblems)
+
+	controllers, err := jujuclient.ReadControllersFile(jujuclient.JujuControllersPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(controllers.CurrentController, gc.Equals, "")
+	c.Check(controllers.Controllers, gc.HasLen, 1)
+
+	models, err := jujuclient.ReadModelsFile(jujuclient.JujuModelsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(models, jc.DeepEquals, map[string]*jujuclient.ControllerModels{
+		"kontroll": {
+			Models: map[string]jujuclient.ModelDetails{
+				"admin": {ModelUUID: "abc"},
+			},
+		},
+	})
+
+	accounts, err := jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(accounts, jc.DeepEquals, map[string]jujuclient.AccountDetails{
+		"kontroll": {User: "bob@remote"},
+	})
+
+	bootstrapConfig, err := jujuclient.ReadBootstrapConfigFile(jujuclient.JujuBootstrapConfigPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bootstrapConfig, gc.HasLen, 0)
+
+	// Once repaired, there is nothing left to find.
+	problems, err = s.store.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(problems, gc.HasLen, 0)
+}
+
+func (s *VerifySuite) TestRepairNoProblems(c *gc.C) {
+	writeStoreFile(c, "controllers.yaml", `
+controllers:
+  kontroll:
+    uuid: this-is-a-uuid
+    api-endpoints: [this-is-one-of-many-api-endpoints]
+    ca-cert: this-is-a-ca-cert
+current-controller: kontroll
+`)
+	before := s.readFiles(c)
+
+	problems, err := s.store.Repair()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(problems, gc.HasLen, 0)
+	c.Assert(s.readFiles(c), jc.DeepEquals, before)
+}
+
+func (s *VerifySuite) TestProblemString(c *gc.C) {
+	expect := []string{
+		`current controller "gone" does not exist`,
+		`current model "my-model" for controller "kontroll" does not exist`,
+		`models recorded for unknown controller "orphan"`,
+		`account recorded for unknown controller "orphan"`,
+		`bootstrap config recorded for unknown controller "orphan"`,
+	}
+	for i, problem := range corruptStoreProblems {
+		c.Check(problem.String(), gc.Equals, expect[i])
+	}
+}
+
+// readFiles returns the raw contents of the store's files, keyed
+// by file name. Missing files are omitted.
+func (s *VerifySuite) readFiles(c *gc.C) map[string]string {
+	files := make(map[string]string)
+	for _, name := range []string{
+		"controllers.yaml",
+		"models.yaml",
+		"accounts.yaml",
+		"bootstrap-config.yaml",
+	} {
+		data, err := ioutil.ReadFile(osenv.JujuXDGDataHomePath(name))
+		if err == nil {
+			files[name] = string(data)
+		}
+	}
+	return files
+}
@@ -0,0 +1,200 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+)
+
+// StoreCollection identifies one of the collections held by a client
+// store.
+type StoreCollection string
+
+const (
+	ControllersCollection     StoreCollection = "controllers"
+	ModelsCollection          StoreCollection = "models"
+	AccountsCollection        StoreCollection = "accounts"
+	CredentialsCollection     StoreCollection = "credentials"
+	BootstrapConfigCollection StoreCollection = "bootstrap-config"
+)
+
+// StoreChange describes a change made to a client store.
+type StoreChange struct {
+	// Collections holds the collections which changed, in sorted
+	// order.
+	Collections []StoreCollection
+}
+
+// StoreWatcher is implemented by client stores which can report
+// changes made to them by other processes.
+type StoreWatcher interface {
+	// WatchStore returns a channel on which changes to the store are
+	// reported, and a function which stops watching and closes the
+	// channel. Changes made in quick succession are reported
+	// together in a single StoreChange.
+	WatchStore() (<-chan StoreChange, func(), error)
+}
+
+var _ StoreWatcher = (*store)(nil)
+
+// watchInterval is how often the store's files are checked for
+// changes.
+var watchInterval = time.Second
+
+// storeCollectionPaths returns the paths of the files which hold each
+// of the store's collections.
+func storeCollectionPaths() map[StoreCollection]string {
+	return map[StoreCollection]string{
+		ControllersCollection:     JujuControllersPath(),
+		ModelsCollection:          JujuModelsPath(),
+		AccountsCollection:        JujuAccountsPath(),
+		CredentialsCollection:     JujuCredentialsPath(),
+		BootstrapConfigCollection: JujuBootstrapConfigPath(),
+	}
+}
+
+// WatchStore implements StoreWatcher. The store's files are polled
+// every watchInterval; as the files are always replaced atomically, a
+// change is reported whenever a file is created, replaced, modified
+// or removed. A change is reported once the files have been left
+// alone for a whole interval.
+func (s *store) WatchStore() (<-chan StoreChange, func(), error) {
+	paths := storeCollectionPaths()
+	files, err := statStoreFiles(paths)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	w := &storeWatcher{
+		clock: clock.WallClock,
+		paths: paths,
+		files: files,
+		out:   make(chan StoreChange),
+		stop:  make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer close(w.out)
+		w.loop()
+	}()
+	return w.out, w.kill, nil
+}
+
+// storeWatcher polls the files of a file-based client store for
+// changes.
+type storeWatcher struct {
+	clock clock.Clock
+	paths map[StoreCollection]string
+	files map[StoreCollection]os.FileInfo
+	out   chan StoreChange
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// kill stops the watcher and waits for its channel to be closed.
+func (w *storeWatcher) kill() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	w.wg.Wait()
+}
+
+func (w *storeWatcher) loop() {
+	// pending records the collections which have changed since the
+	// last change was sent. They are sent only once the files have
+	// settled, and are kept until they have been sent.
+	pending := make(map[StoreCollection]bool)
+	var out chan<- StoreChange
+	var change StoreChange
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.clock.After(watchInterval):
+			changed, err := w.poll()
+			if err != nil {
+				logger.Warningf("cannot check client store for changes: %v", err)
+				continue
+			}
+			for _, collection := range changed {
+				pending[collection] = true
+			}
+			if len(changed) > 0 || len(pending) == 0 {
+				out = nil
+				continue
+			}
+			change = StoreChange{Collections: sortedCollections(pending)}
+			out = w.out
+		case out <- change:
+			pending = make(map[StoreCollection]bool)
+			out = nil
+		}
+	}
+}
+
+// poll returns the collections whose files have changed since it was
+// last called.
+func (w *storeWatcher) poll() ([]StoreCollection, error) {
+	files, err := statStoreFiles(w.paths)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var changed []StoreCollection
+	for collection, info := range files {
+		if fileChanged(w.files[collection], info) {
+			changed = append(changed, collection)
+		}
+	}
+	w.files = files
+	return changed, nil
+}
+
+// statStoreFiles returns the file info of each of the given paths,
+// which is nil for any path which doesn't exist.
+func statStoreFiles(paths map[StoreCollection]string) (map[StoreCollection]os.FileInfo, error) {
+	files := make(map[StoreCollection]os.FileInfo)
+	for collection, path := range paths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			files[collection] = nil
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		files[collection] = info
+	}
+	return files, nil
+}
+
+// fileChanged reports whether a store file has been created,
+// replaced, modified or removed.
+func fileChanged(old, current os.FileInfo) bool {
+	if old == nil || current == nil {
+		return old != current
+	}
+	return !os.SameFile(old, current) ||
+		!old.ModTime().Equal(current.ModTime()) ||
+		old.Size() != current.Size()
+}
+
+func sortedCollections(collections map[StoreCollection]bool) []StoreCollection {
+	result := make([]StoreCollection, 0, len(collections))
+	for collection := range collections {
+		result = append(result, collection)
+	}
+	sort.Sort(byCollection(result))
+	return result
+}
+
+type byCollection []StoreCollection
+
+func (c byCollection) Len() int           { return len(c) }
+func (c byCollection) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c byCollection) Less(i, j int) bool { return c[i] < c[j] }
@@ -0,0 +1,83 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"io/ioutil"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type WatchSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	changes <-chan jujuclient.StoreChange
+	stop    func()
+}
+
+var _ = gc.Suite(&WatchSuite{})
+
+func (s *WatchSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.PatchValue(jujuclient.WatchInterval, 10*time.Millisecond)
+	store := jujuclient.NewFileClientStore()
+	changes, stop, err := store.(jujuclient.StoreWatcher).WatchStore()
+	c.Assert(err, jc.ErrorIsNil)
+	s.changes = changes
+	s.stop = stop
+	s.AddCleanup(func(*gc.C) { stop() })
+}
+
+func (s *WatchSuite) assertChange(c *gc.C, expect ...jujuclient.StoreCollection) {
+	select {
+	case change, ok := <-s.changes:
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(change.Collections, jc.DeepEquals, expect)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for store change")
+	}
+}
+
+func (s *WatchSuite) assertNoChange(c *gc.C) {
+	select {
+	case change := <-s.changes:
+		c.Fatalf("unexpected store change %v", change)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *WatchSuite) TestExternalChange(c *gc.C) {
+	s.assertNoChange(c)
+	err := ioutil.WriteFile(jujuclient.JujuControllersPath(), []byte("controllers: {}\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertChange(c, jujuclient.ControllersCollection)
+	s.assertNoChange(c)
+}
+
+func (s *WatchSuite) TestStoreChanges(c *gc.C) {
+	store := jujuclient.NewFileClientStore()
+	err := store.UpdateController("ctrl", storeController)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertChange(c, jujuclient.ControllersCollection)
+
+	err = store.RemoveController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertChange(c, jujuclient.ControllersCollection)
+}
+
+func (s *WatchSuite) TestStop(c *gc.C) {
+	s.stop()
+	select {
+	case _, ok := <-s.changes:
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for changes channel to be closed")
+	}
+	// Stopping again is harmless.
+	s.stop()
+}
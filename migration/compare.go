@@ -0,0 +1,263 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/description"
+)
+
+// Difference describes a discrepancy found between two serialized
+// models by CompareModels.
+type Difference struct {
+	// Entity identifies the part of the model which differs, for
+	// example `application "mysql"` or `unit "mysql/0"`.
+	Entity string
+
+	// Field names the attribute of the entity which differs. It is
+	// empty if the entity is only present in one of the models.
+	Field string
+
+	// Source and Target hold the differing values from the source
+	// and target models. If the entity is missing from one of the
+	// models, the corresponding value is nil.
+	Source interface{}
+	Target interface{}
+
+	// Cosmetic is true if the difference doesn't affect how the model
+	// behaves, such as a status having been updated at a different
+	// time. Cosmetic differences may be safely ignored.
+	Cosmetic bool
+}
+
+// String implements fmt.Stringer.
+func (d Difference) String() string {
+	switch {
+	case d.Field != "":
+		return fmt.Sprintf("%s %s: %v != %v", d.Entity, d.Field, d.Source, d.Target)
+	case d.Target == nil:
+		return fmt.Sprintf("%s missing from target", d.Entity)
+	default:
+		return fmt.Sprintf("%s missing from source", d.Entity)
+	}
+}
+
+// CompareModels deserializes the source and target models, and
+// returns the differences between their config, applications, units
+// and relations. It is used to check that a model has been imported
+// faithfully into the target controller of a migration.
+func CompareModels(source, target []byte) ([]Difference, error) {
+	sourceModel, err := description.Deserialize(source)
+	if err != nil {
+		return nil, errors.Annotate(err, "source model")
+	}
+	targetModel, err := description.Deserialize(target)
+	if err != nil {
+		return nil, errors.Annotate(err, "target model")
+	}
+	var c comparer
+	c.compareModels(sourceModel, targetModel)
+	return c.differences, nil
+}
+
+// CriticalDifferences returns the differences which aren't cosmetic.
+func CriticalDifferences(differences []Difference) []Difference {
+	var critical []Difference
+	for _, d := range differences {
+		if !d.Cosmetic {
+			critical = append(critical, d)
+		}
+	}
+	return critical
+}
+
+// comparer accumulates the differences between two models.
+type comparer struct {
+	differences []Difference
+}
+
+func (c *comparer) field(entity, field string, source, target interface{}) {
+	if !reflect.DeepEqual(source, target) {
+		c.differences = append(c.differences, Difference{
+			Entity: entity,
+			Field:  field,
+			Source: source,
+			Target: target,
+		})
+	}
+}
+
+func (c *comparer) cosmetic(entity, field string, source, target interface{}) {
+	if !reflect.DeepEqual(source, target) {
+		c.differences = append(c.differences, Difference{
+			Entity:   entity,
+			Field:    field,
+			Source:   source,
+			Target:   target,
+			Cosmetic: true,
+		})
+	}
+}
+
+// presence records a difference if an entity is only present in one
+// of the models, and returns whether it is present in both.
+func (c *comparer) presence(entity string, inSource, inTarget bool) bool {
+	if inSource == inTarget {
+		return inSource
+	}
+	d := Difference{Entity: entity}
+	if inSource {
+		d.Source = entity
+	} else {
+		d.Target = entity
+	}
+	c.differences = append(c.differences, d)
+	return false
+}
+
+func (c *comparer) status(entity, field string, source, target description.Status) {
+	if source == nil || target == nil {
+		c.field(entity, field, statusValue(source), statusValue(target))
+		return
+	}
+	c.field(entity, field, source.Value(), target.Value())
+	c.field(entity, field+" message", source.Message(), target.Message())
+	c.cosmetic(entity, field+" updated", source.Updated(), target.Updated())
+}
+
+// statusValue returns the value of a status which may be nil.
+func statusValue(status description.Status) interface{} {
+	if status == nil {
+		return nil
+	}
+	return status.Value()
+}
+
+func (c *comparer) compareModels(source, target description.Model) {
+	c.field("model", "owner", source.Owner(), target.Owner())
+	compareConfig(c, "model", "config", source.Config(), target.Config())
+
+	sourceApps := make(map[string]description.Application)
+	for _, app := range source.Applications() {
+		sourceApps[app.Name()] = app
+	}
+	targetApps := make(map[string]description.Application)
+	for _, app := range target.Applications() {
+		targetApps[app.Name()] = app
+	}
+	for _, name := range unionKeys(sourceApps, targetApps) {
+		entity := fmt.Sprintf("application %q", name)
+		sourceApp, inSource := sourceApps[name]
+		targetApp, inTarget := targetApps[name]
+		if c.presence(entity, inSource, inTarget) {
+			c.compareApplications(entity, sourceApp, targetApp)
+		}
+	}
+
+	sourceRelations := make(map[string]description.Relation)
+	for _, rel := range source.Relations() {
+		sourceRelations[rel.Key()] = rel
+	}
+	targetRelations := make(map[string]description.Relation)
+	for _, rel := range target.Relations() {
+		targetRelations[rel.Key()] = rel
+	}
+	for _, key := range unionKeys(sourceRelations, targetRelations) {
+		entity := fmt.Sprintf("relation %q", key)
+		sourceRel, inSource := sourceRelations[key]
+		targetRel, inTarget := targetRelations[key]
+		if c.presence(entity, inSource, inTarget) {
+			c.compareRelations(entity, sourceRel, targetRel)
+		}
+	}
+}
+
+func (c *comparer) compareApplications(entity string, source, target description.Application) {
+	c.field(entity, "series", source.Series(), target.Series())
+	c.field(entity, "charm-url", source.CharmURL(), target.CharmURL())
+	c.field(entity, "channel", source.Channel(), target.Channel())
+	c.field(entity, "exposed", source.Exposed(), target.Exposed())
+	c.field(entity, "min-units", source.MinUnits(), target.MinUnits())
+	c.field(entity, "leader", source.Leader(), target.Leader())
+	compareConfig(c, entity, "settings", source.Settings(), target.Settings())
+	c.status(entity, "status", source.Status(), target.Status())
+
+	sourceUnits := make(map[string]description.Unit)
+	for _, unit := range source.Units() {
+		sourceUnits[unit.Name()] = unit
+	}
+	targetUnits := make(map[string]description.Unit)
+	for _, unit := range target.Units() {
+		targetUnits[unit.Name()] = unit
+	}
+	for _, name := range unionKeys(sourceUnits, targetUnits) {
+		unitEntity := fmt.Sprintf("unit %q", name)
+		sourceUnit, inSource := sourceUnits[name]
+		targetUnit, inTarget := targetUnits[name]
+		if c.presence(unitEntity, inSource, inTarget) {
+			c.compareUnits(unitEntity, sourceUnit, targetUnit)
+		}
+	}
+}
+
+func (c *comparer) compareUnits(entity string, source, target description.Unit) {
+	c.field(entity, "machine", source.Machine(), target.Machine())
+	c.field(entity, "principal", source.Principal(), target.Principal())
+	c.field(entity, "workload-version", source.WorkloadVersion(), target.WorkloadVersion())
+	c.status(entity, "workload-status", source.WorkloadStatus(), target.WorkloadStatus())
+	c.status(entity, "agent-status", source.AgentStatus(), target.AgentStatus())
+}
+
+func (c *comparer) compareRelations(entity string, source, target description.Relation) {
+	c.field(entity, "id", source.Id(), target.Id())
+	sourceEndpoints := make(map[string]description.Endpoint)
+	for _, ep := range source.Endpoints() {
+		sourceEndpoints[ep.ApplicationName()+":"+ep.Name()] = ep
+	}
+	targetEndpoints := make(map[string]description.Endpoint)
+	for _, ep := range target.Endpoints() {
+		targetEndpoints[ep.ApplicationName()+":"+ep.Name()] = ep
+	}
+	for _, name := range unionKeys(sourceEndpoints, targetEndpoints) {
+		epEntity := fmt.Sprintf("%s endpoint %q", entity, name)
+		sourceEp, inSource := sourceEndpoints[name]
+		targetEp, inTarget := targetEndpoints[name]
+		if c.presence(epEntity, inSource, inTarget) {
+			c.field(epEntity, "role", sourceEp.Role(), targetEp.Role())
+			c.field(epEntity, "interface", sourceEp.Interface(), targetEp.Interface())
+			c.field(epEntity, "scope", sourceEp.Scope(), targetEp.Scope())
+			c.field(epEntity, "unit-count", sourceEp.UnitCount(), targetEp.UnitCount())
+		}
+	}
+}
+
+// compareConfig compares config maps key by key, so that each
+// differing key is reported separately.
+func compareConfig(c *comparer, entity, field string, source, target map[string]interface{}) {
+	for _, key := range unionKeys(source, target) {
+		c.field(entity, fmt.Sprintf("%s %q", field, key), source[key], target[key])
+	}
+}
+
+// unionKeys returns the sorted union of the keys of two maps with
+// string keys.
+func unionKeys(a, b interface{}) []string {
+	seen := make(map[string]bool)
+	for _, m := range []reflect.Value{reflect.ValueOf(a), reflect.ValueOf(b)} {
+		for _, key := range m.MapKeys() {
+			seen[key.String()] = true
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
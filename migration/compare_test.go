@@ -0,0 +1,136 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	"strconv"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/core/description"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/testing"
+)
+
+type CompareSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&CompareSuite{})
+
+var compareTime = time.Date(2016, 8, 1, 12, 0, 0, 0, time.UTC)
+
+// modelTweaks describes changes made to the model built by
+// makeCompareModel.
+type modelTweaks struct {
+	mysqlSeries   string
+	statusUpdated time.Time
+	extraUnit     bool
+	noRelation    bool
+}
+
+func makeCompareModel(c *gc.C, tweaks modelTweaks) []byte {
+	model := description.NewModel(description.ModelArgs{
+		Owner:  names.NewUserTag("admin"),
+		Config: map[string]interface{}{"name": "default", "uuid": "some-uuid"},
+	})
+	series := "xenial"
+	if tweaks.mysqlSeries != "" {
+		series = tweaks.mysqlSeries
+	}
+	updated := compareTime
+	if !tweaks.statusUpdated.IsZero() {
+		updated = tweaks.statusUpdated
+	}
+	status := description.StatusArgs{Value: "active", Updated: updated}
+
+	app := model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("mysql"),
+		Series:   series,
+		CharmURL: "cs:xenial/mysql-1",
+		Settings: map[string]interface{}{"dataset-size": "80%"},
+	})
+	app.SetStatus(status)
+	unitNames := []string{"mysql/0"}
+	if tweaks.extraUnit {
+		unitNames = append(unitNames, "mysql/1")
+	}
+	for i, name := range unitNames {
+		unit := app.AddUnit(description.UnitArgs{
+			Tag:     names.NewUnitTag(name),
+			Machine: names.NewMachineTag(strconv.Itoa(i)),
+		})
+		unit.SetWorkloadStatus(status)
+		unit.SetAgentStatus(description.StatusArgs{Value: "idle", Updated: updated})
+	}
+
+	if !tweaks.noRelation {
+		rel := model.AddRelation(description.RelationArgs{Id: 1, Key: "mysql:cluster"})
+		rel.AddEndpoint(description.EndpointArgs{
+			ApplicationName: "mysql",
+			Name:            "cluster",
+			Role:            "peer",
+			Interface:       "mysql-ha",
+			Scope:           "global",
+		})
+	}
+
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+	return bytes
+}
+
+func (s *CompareSuite) TestIdenticalModels(c *gc.C) {
+	model := makeCompareModel(c, modelTweaks{})
+	differences, err := migration.CompareModels(model, model)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(differences, gc.HasLen, 0)
+}
+
+func (s *CompareSuite) TestFieldDifference(c *gc.C) {
+	source := makeCompareModel(c, modelTweaks{})
+	target := makeCompareModel(c, modelTweaks{mysqlSeries: "trusty"})
+	differences, err := migration.CompareModels(source, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(differences, jc.DeepEquals, []migration.Difference{{
+		Entity: `application "mysql"`,
+		Field:  "series",
+		Source: "xenial",
+		Target: "trusty",
+	}})
+	c.Check(differences[0].String(), gc.Equals, `application "mysql" series: xenial != trusty`)
+}
+
+func (s *CompareSuite) TestMissingEntities(c *gc.C) {
+	source := makeCompareModel(c, modelTweaks{})
+	target := makeCompareModel(c, modelTweaks{extraUnit: true, noRelation: true})
+	differences, err := migration.CompareModels(source, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(differences, gc.HasLen, 2)
+	c.Check(differences[0].String(), gc.Equals, `unit "mysql/1" missing from source`)
+	c.Check(differences[1].String(), gc.Equals, `relation "mysql:cluster" missing from target`)
+	c.Check(migration.CriticalDifferences(differences), jc.DeepEquals, differences)
+}
+
+func (s *CompareSuite) TestCosmeticDifferences(c *gc.C) {
+	source := makeCompareModel(c, modelTweaks{})
+	target := makeCompareModel(c, modelTweaks{statusUpdated: compareTime.Add(time.Minute)})
+	differences, err := migration.CompareModels(source, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(differences, gc.HasLen, 3)
+	for _, d := range differences {
+		c.Check(d.Cosmetic, jc.IsTrue)
+	}
+	c.Check(differences[0].Field, gc.Equals, "status updated")
+	c.Check(migration.CriticalDifferences(differences), gc.HasLen, 0)
+}
+
+func (s *CompareSuite) TestBadModel(c *gc.C) {
+	model := makeCompareModel(c, modelTweaks{})
+	_, err := migration.CompareModels(model, []byte("not a model"))
+	c.Assert(err, gc.ErrorMatches, "target model: yaml: unmarshal errors:\n.*")
+}
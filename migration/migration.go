@@ -4,18 +4,22 @@
 package migration
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
+	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/core/description"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/binarystorage"
 	"github.com/juju/juju/tools"
@@ -96,6 +100,18 @@ type ToolsUploader interface {
 	UploadTools(io.ReadSeeker, version.Binary, ...string) (tools.List, error)
 }
 
+// BinaryVerifier defines a single method that is used to confirm that
+// the target controller stored the charms and tools uploaded to it in
+// a migration intact.
+type BinaryVerifier interface {
+	VerifyBinaries(charms map[string]string, tools map[version.Binary]string) error
+}
+
+// DefaultDownloadConcurrency is the number of binaries downloaded from
+// the source controller at once when UploadBinariesConfig doesn't
+// specify a DownloadConcurrency.
+const DefaultDownloadConcurrency = 1
+
 // UploadBinariesConfig provides all the configuration that the
 // UploadBinaries function needs to operate. To construct the config
 // with the default helper functions, use `NewUploadBinariesConfig`.
@@ -104,9 +120,48 @@ type UploadBinariesConfig struct {
 	CharmDownloader CharmDownloader
 	CharmUploader   CharmUploader
 
+	// CharmOrigins records where each of the charms came from, keyed
+	// by charm URL. UploadBinaries fails if any charm has no known
+	// origin, rather than risk the target resolving it wrongly.
+	CharmOrigins map[string]coremigration.CharmOrigin
+
 	Tools           map[version.Binary]string
 	ToolsDownloader ToolsDownloader
 	ToolsUploader   ToolsUploader
+
+	// CharmSHA256s and ToolsSHA256s hold the SHA256 sums the uploaded
+	// charms and tools are expected to have, keyed by charm URL and
+	// tools version. If any are given, BinaryVerifier is used to
+	// check them once all the binaries have been uploaded.
+	CharmSHA256s   map[string]string
+	ToolsSHA256s   map[version.Binary]string
+	BinaryVerifier BinaryVerifier
+
+	// DownloadConcurrency limits the number of binaries being
+	// downloaded from the source at once, to avoid overwhelming the
+	// source controller or charm store. It is independent of the
+	// number of binaries being uploaded to the target.
+	// DefaultDownloadConcurrency is used if it is zero.
+	DownloadConcurrency int
+
+	// Concurrency limits the number of binaries being uploaded to
+	// the target at once. Binaries may be uploaded in any order. If
+	// an upload fails, those not yet started are abandoned. One
+	// binary is uploaded at a time if it is zero.
+	Concurrency int
+
+	// UploadRateLimit limits the rate, in bytes per second, at which
+	// each binary is uploaded to the target, to avoid saturating a
+	// constrained link. Zero means unlimited.
+	UploadRateLimit int64
+
+	// Clock is used to pace rate limited uploads. The wall clock is
+	// used if it is nil.
+	Clock clock.Clock
+
+	// Abort, if not nil, may be closed to abandon the transfer. Rate
+	// limited uploads then stop waiting and fail.
+	Abort <-chan struct{}
 }
 
 // Validate makes sure that all the config values are non-nil.
@@ -123,6 +178,18 @@ func (c *UploadBinariesConfig) Validate() error {
 	if c.ToolsUploader == nil {
 		return errors.NotValidf("missing ToolsUploader")
 	}
+	if c.BinaryVerifier == nil && (len(c.CharmSHA256s) > 0 || len(c.ToolsSHA256s) > 0) {
+		return errors.NotValidf("missing BinaryVerifier")
+	}
+	if c.DownloadConcurrency < 0 {
+		return errors.NotValidf("negative DownloadConcurrency")
+	}
+	if c.Concurrency < 0 {
+		return errors.NotValidf("negative Concurrency")
+	}
+	if c.UploadRateLimit < 0 {
+		return errors.NotValidf("negative UploadRateLimit")
+	}
 	return nil
 }
 
@@ -132,88 +199,231 @@ func UploadBinaries(config UploadBinariesConfig) error {
 	if err := config.Validate(); err != nil {
 		return errors.Trace(err)
 	}
-	if err := uploadCharms(config); err != nil {
+	transfers, err := charmTransfers(config)
+	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := uploadTools(config); err != nil {
+	transfers = append(transfers, toolsTransfers(config)...)
+	if config.UploadRateLimit > 0 {
+		clk := config.Clock
+		if clk == nil {
+			clk = clock.WallClock
+		}
+		transfers = throttleUploads(transfers, config.UploadRateLimit, clk, config.Abort)
+	}
+
+	downloadConcurrency := config.DownloadConcurrency
+	if downloadConcurrency == 0 {
+		downloadConcurrency = DefaultDownloadConcurrency
+	}
+	uploadConcurrency := config.Concurrency
+	if uploadConcurrency == 0 {
+		uploadConcurrency = 1
+	}
+	if err := transferBinaries(transfers, downloadConcurrency, uploadConcurrency); err != nil {
 		return errors.Trace(err)
 	}
-	return nil
+	if len(config.CharmSHA256s) == 0 && len(config.ToolsSHA256s) == 0 {
+		return nil
+	}
+	logger.Debugf("verifying binaries uploaded to target")
+	err = config.BinaryVerifier.VerifyBinaries(config.CharmSHA256s, config.ToolsSHA256s)
+	return errors.Annotate(err, "uploaded binaries failed verification")
 }
 
-func streamThroughTempFile(r io.Reader) (_ io.ReadSeeker, cleanup func(), err error) {
-	tempFile, err := ioutil.TempFile("", "juju-migrate-binary")
-	if err != nil {
-		return nil, nil, errors.Trace(err)
+// binaryTransfer describes how to download a single binary from the
+// source and upload it to the target.
+type binaryTransfer struct {
+	describe string
+	download func() (io.ReadCloser, error)
+	upload   func(io.ReadSeeker) error
+}
+
+func charmTransfers(config UploadBinariesConfig) ([]binaryTransfer, error) {
+	if err := coremigration.CheckCharmOrigins(config.Charms, config.CharmOrigins); err != nil {
+		return nil, errors.Annotate(err, "cannot resolve charm")
 	}
-	defer func() {
+	var transfers []binaryTransfer
+	for _, charmUrl := range config.Charms {
+		origin := config.CharmOrigins[charmUrl]
+		if origin == coremigration.CharmHubOrigin {
+			// The target can't resolve charmhub charms from an
+			// uploaded archive, so don't pretend otherwise.
+			return nil, errors.NotSupportedf("migrating charmhub charm %q", charmUrl)
+		}
+		curl, err := charm.ParseURL(charmUrl)
 		if err != nil {
-			os.Remove(tempFile.Name())
+			return nil, errors.Annotate(err, "bad charm URL")
 		}
-	}()
-	_, err = io.Copy(tempFile, r)
-	if err != nil {
-		return nil, nil, errors.Trace(err)
+		transfers = append(transfers, binaryTransfer{
+			describe: fmt.Sprintf("%s charm %s", origin, charmUrl),
+			download: func() (io.ReadCloser, error) {
+				reader, err := config.CharmDownloader.OpenCharm(curl)
+				return reader, errors.Annotate(err, "cannot open charm")
+			},
+			upload: func(content io.ReadSeeker) error {
+				_, err := config.CharmUploader.UploadCharm(curl, content)
+				return errors.Annotate(err, "cannot upload charm")
+			},
+		})
 	}
-	tempFile.Seek(0, 0)
-	rmTempFile := func() {
-		filename := tempFile.Name()
-		tempFile.Close()
-		os.Remove(filename)
+	return transfers, nil
+}
+
+func toolsTransfers(config UploadBinariesConfig) []binaryTransfer {
+	var transfers []binaryTransfer
+	for v, uri := range config.Tools {
+		v, uri := v, uri
+		transfers = append(transfers, binaryTransfer{
+			describe: "tools " + v.String(),
+			download: func() (io.ReadCloser, error) {
+				reader, err := config.ToolsDownloader.OpenURI(uri, nil)
+				return reader, errors.Annotate(err, "cannot open charm")
+			},
+			upload: func(content io.ReadSeeker) error {
+				_, err := config.ToolsUploader.UploadTools(content, v)
+				return errors.Annotate(err, "cannot upload tools")
+			},
+		})
 	}
+	return transfers
+}
 
-	return tempFile, rmTempFile, nil
+// downloadResult holds a binary downloaded into a temporary file.
+type downloadResult struct {
+	content io.ReadSeeker
+	cleanup func()
+	err     error
 }
 
-func uploadCharms(config UploadBinariesConfig) error {
-	for _, charmUrl := range config.Charms {
-		logger.Debugf("sending charm %s to target", charmUrl)
+var errTransferAborted = errors.New("binary transfer aborted")
 
-		curl, err := charm.ParseURL(charmUrl)
-		if err != nil {
-			return errors.Annotate(err, "bad charm URL")
+// transferBinaries downloads binaries in order with at most
+// downloadConcurrency downloads in progress at once, and uploads them
+// as their downloads complete with at most uploadConcurrency uploads
+// in progress at once. On error, the uploads not yet started and the
+// remaining downloads are abandoned and their temporary files
+// removed; uploads already in progress are waited for.
+func transferBinaries(transfers []binaryTransfer, downloadConcurrency, uploadConcurrency int) error {
+	results := make([]chan downloadResult, len(transfers))
+	for i := range results {
+		results[i] = make(chan downloadResult, 1)
+	}
+	abort := make(chan struct{})
+	slots := make(chan struct{}, downloadConcurrency)
+	go func() {
+		for i, transfer := range transfers {
+			select {
+			case slots <- struct{}{}:
+			case <-abort:
+				for _, result := range results[i:] {
+					result <- downloadResult{err: errTransferAborted}
+				}
+				return
+			}
+			go func(transfer binaryTransfer, result chan<- downloadResult) {
+				defer func() { <-slots }()
+				result <- download(transfer)
+			}(transfer, results[i])
 		}
+	}()
 
-		reader, err := config.CharmDownloader.OpenCharm(curl)
-		if err != nil {
-			return errors.Annotate(err, "cannot open charm")
+	next := 0
+	defer func() {
+		// Wait for any downloads still outstanding, and throw
+		// their content away.
+		close(abort)
+		for _, result := range results[next:] {
+			if r := <-result; r.cleanup != nil {
+				r.cleanup()
+			}
 		}
-		defer reader.Close()
+	}()
 
-		content, cleanup, err := streamThroughTempFile(reader)
-		if err != nil {
+	// Each upload reports any failure before freeing its slot, so
+	// that a failure is always seen before the next upload can
+	// start.
+	uploadSlots := make(chan struct{}, uploadConcurrency)
+	uploadErrs := make(chan error, len(transfers))
+	var uploads sync.WaitGroup
+	defer uploads.Wait()
+	for next < len(transfers) {
+		transfer := transfers[next]
+		r := <-results[next]
+		next++
+		if r.err != nil {
+			return errors.Trace(r.err)
+		}
+		select {
+		case uploadSlots <- struct{}{}:
+		case err := <-uploadErrs:
+			r.cleanup()
 			return errors.Trace(err)
 		}
-		defer cleanup()
-
-		if _, err := config.CharmUploader.UploadCharm(curl, content); err != nil {
-			return errors.Annotate(err, "cannot upload charm")
+		// The slot may have been freed by a failed upload.
+		select {
+		case err := <-uploadErrs:
+			<-uploadSlots
+			r.cleanup()
+			return errors.Trace(err)
+		default:
 		}
+		uploads.Add(1)
+		go func(transfer binaryTransfer, r downloadResult) {
+			defer uploads.Done()
+			defer func() { <-uploadSlots }()
+			logger.Debugf("sending %s to target", transfer.describe)
+			err := transfer.upload(r.content)
+			r.cleanup()
+			if err != nil {
+				uploadErrs <- errors.Trace(err)
+			}
+		}(transfer, r)
+	}
+	uploads.Wait()
+	select {
+	case err := <-uploadErrs:
+		return errors.Trace(err)
+	default:
+		return nil
 	}
-	return nil
 }
 
-func uploadTools(config UploadBinariesConfig) error {
-	for v, uri := range config.Tools {
-		logger.Debugf("sending tools to target: %s", v)
-
-		reader, err := config.ToolsDownloader.OpenURI(uri, nil)
-		if err != nil {
-			return errors.Annotate(err, "cannot open charm")
-		}
-		defer reader.Close()
+func download(transfer binaryTransfer) downloadResult {
+	reader, err := transfer.download()
+	if err != nil {
+		return downloadResult{err: err}
+	}
+	defer reader.Close()
+	content, cleanup, err := streamThroughTempFile(reader)
+	if err != nil {
+		return downloadResult{err: errors.Trace(err)}
+	}
+	return downloadResult{content: content, cleanup: cleanup}
+}
 
-		content, cleanup, err := streamThroughTempFile(reader)
+func streamThroughTempFile(r io.Reader) (_ io.ReadSeeker, cleanup func(), err error) {
+	tempFile, err := ioutil.TempFile("", "juju-migrate-binary")
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer func() {
 		if err != nil {
-			return errors.Trace(err)
-		}
-		defer cleanup()
-
-		if _, err := config.ToolsUploader.UploadTools(content, v); err != nil {
-			return errors.Annotate(err, "cannot upload tools")
+			os.Remove(tempFile.Name())
 		}
+	}()
+	_, err = io.Copy(tempFile, r)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
 	}
-	return nil
+	tempFile.Seek(0, 0)
+	rmTempFile := func() {
+		filename := tempFile.Name()
+		tempFile.Close()
+		os.Remove(filename)
+	}
+
+	return tempFile, rmTempFile, nil
 }
 
 // PrecheckBackend is implemented by *state.State but defined as an interface
@@ -5,10 +5,14 @@ package migration_test
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -20,6 +24,7 @@ import (
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/description"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/environs/bootstrap"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/jujuclient/jujuclienttesting"
@@ -115,6 +120,11 @@ func (s *ImportSuite) TestUploadBinariesConfigValidate(c *gc.C) {
 	check(func(c *T) { c.CharmUploader = nil }, "CharmUploader")
 	check(func(c *T) { c.ToolsDownloader = nil }, "ToolsDownloader")
 	check(func(c *T) { c.ToolsUploader = nil }, "ToolsUploader")
+	check(func(c *T) {
+		c.ToolsSHA256s = map[version.Binary]string{
+			version.MustParseBinary("2.1.0-trusty-amd64"): "sha256",
+		}
+	}, "BinaryVerifier")
 }
 
 func (s *ImportSuite) TestBinariesMigration(c *gc.C) {
@@ -132,6 +142,10 @@ func (s *ImportSuite) TestBinariesMigration(c *gc.C) {
 		Charms:          []string{"local:trusty/magic", "cs:trusty/postgresql-42"},
 		CharmDownloader: downloader,
 		CharmUploader:   uploader,
+		CharmOrigins: map[string]coremigration.CharmOrigin{
+			"local:trusty/magic":      coremigration.LocalOrigin,
+			"cs:trusty/postgresql-42": coremigration.CharmStoreOrigin,
+		},
 		Tools:           toolsMap,
 		ToolsDownloader: downloader,
 		ToolsUploader:   uploader,
@@ -154,14 +168,443 @@ func (s *ImportSuite) TestBinariesMigration(c *gc.C) {
 	c.Assert(uploader.tools, jc.DeepEquals, toolsMap)
 }
 
+func (s *ImportSuite) TestUploadBinariesConfigNegativeDownloadConcurrency(c *gc.C) {
+	config := migration.UploadBinariesConfig{
+		CharmDownloader:     struct{ migration.CharmDownloader }{},
+		CharmUploader:       struct{ migration.CharmUploader }{},
+		ToolsDownloader:     struct{ migration.ToolsDownloader }{},
+		ToolsUploader:       struct{ migration.ToolsUploader }{},
+		DownloadConcurrency: -1,
+	}
+	c.Check(config.Validate(), gc.ErrorMatches, "negative DownloadConcurrency not valid")
+}
+
+func (s *ImportSuite) TestUploadBinariesConfigNegativeUploadRateLimit(c *gc.C) {
+	config := migration.UploadBinariesConfig{
+		CharmDownloader: struct{ migration.CharmDownloader }{},
+		CharmUploader:   struct{ migration.CharmUploader }{},
+		ToolsDownloader: struct{ migration.ToolsDownloader }{},
+		ToolsUploader:   struct{ migration.ToolsUploader }{},
+		UploadRateLimit: -1,
+	}
+	c.Check(config.Validate(), gc.ErrorMatches, "negative UploadRateLimit not valid")
+}
+
+func (s *ImportSuite) TestBinariesMigrationUploadRateLimit(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	clock := testing.NewClock(time.Now())
+	config := migration.UploadBinariesConfig{
+		Charms:          []string{"cs:trusty/a-1"},
+		CharmDownloader: downloader,
+		CharmUploader:   uploader,
+		CharmOrigins:    charmStoreOrigins("cs:trusty/a-1"),
+		ToolsDownloader: downloader,
+		ToolsUploader:   uploader,
+		UploadRateLimit: 2,
+		Clock:           clock,
+	}
+	done := make(chan error)
+	go func() {
+		done <- migration.UploadBinaries(config)
+	}()
+
+	// Keep the clock moving until the upload completes, measuring
+	// how much time it took.
+	var elapsed time.Duration
+	for {
+		select {
+		case err := <-done:
+			c.Assert(err, jc.ErrorIsNil)
+			content := "cs:trusty/a-1 content"
+			c.Assert(uploader.charms, jc.DeepEquals, map[string]string{
+				"cs:trusty/a-1": content,
+			})
+			minimum := time.Duration(len(content)) * time.Second / 2
+			c.Assert(elapsed >= minimum, jc.IsTrue, gc.Commentf("upload took %s", elapsed))
+			return
+		case <-clock.Alarms():
+			clock.Advance(time.Second)
+			elapsed += time.Second
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for upload")
+		}
+	}
+}
+
+func (s *ImportSuite) TestBinariesMigrationUploadRateLimitAbort(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	clock := testing.NewClock(time.Now())
+	abort := make(chan struct{})
+	config := migration.UploadBinariesConfig{
+		Charms:          []string{"cs:trusty/a-1"},
+		CharmDownloader: downloader,
+		CharmUploader:   uploader,
+		CharmOrigins:    charmStoreOrigins("cs:trusty/a-1"),
+		ToolsDownloader: downloader,
+		ToolsUploader:   uploader,
+		UploadRateLimit: 2,
+		Clock:           clock,
+		Abort:           abort,
+	}
+	done := make(chan error)
+	go func() {
+		done <- migration.UploadBinaries(config)
+	}()
+
+	// Abort the upload while it waits, without advancing the clock.
+	select {
+	case <-clock.Alarms():
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for upload to wait")
+	}
+	close(abort)
+	select {
+	case err := <-done:
+		c.Assert(err, gc.ErrorMatches, ".*binary transfer aborted")
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for upload to abort")
+	}
+	c.Assert(uploader.charms, gc.HasLen, 0)
+}
+
+func (s *ImportSuite) TestBinariesMigrationDownloadConcurrency(c *gc.C) {
+	downloader := &blockingDownloader{
+		fakeDownloader: &fakeDownloader{},
+		started:        make(chan string, 10),
+		release:        make(chan struct{}),
+	}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	charms := []string{"cs:trusty/a-1", "cs:trusty/b-1", "cs:trusty/c-1", "cs:trusty/d-1"}
+	config := migration.UploadBinariesConfig{
+		Charms:              charms,
+		CharmDownloader:     downloader,
+		CharmUploader:       uploader,
+		CharmOrigins:        charmStoreOrigins(charms...),
+		ToolsDownloader:     downloader,
+		ToolsUploader:       uploader,
+		DownloadConcurrency: 2,
+	}
+	done := make(chan error)
+	go func() {
+		done <- migration.UploadBinaries(config)
+	}()
+
+	// Two downloads start, but no more until one of them finishes.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-downloader.started:
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for download %d", i)
+		}
+	}
+	select {
+	case curl := <-downloader.started:
+		c.Fatalf("download of %s exceeded concurrency limit", curl)
+	case <-time.After(testing.ShortWait):
+	}
+	c.Assert(atomic.LoadInt32(&downloader.maxActive), gc.Equals, int32(2))
+
+	close(downloader.release)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for upload")
+	}
+	c.Assert(atomic.LoadInt32(&downloader.maxActive), gc.Equals, int32(2))
+	c.Assert(downloader.charms, jc.SameContents, charms)
+	c.Assert(uploader.charms, gc.HasLen, 4)
+}
+
+func (s *ImportSuite) TestUploadBinariesConfigNegativeConcurrency(c *gc.C) {
+	config := migration.UploadBinariesConfig{
+		CharmDownloader: struct{ migration.CharmDownloader }{},
+		CharmUploader:   struct{ migration.CharmUploader }{},
+		ToolsDownloader: struct{ migration.ToolsDownloader }{},
+		ToolsUploader:   struct{ migration.ToolsUploader }{},
+		Concurrency:     -1,
+	}
+	c.Check(config.Validate(), gc.ErrorMatches, "negative Concurrency not valid")
+}
+
+func (s *ImportSuite) TestBinariesMigrationUploadConcurrency(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &blockingUploader{
+		fakeUploader: &fakeUploader{
+			charms: make(map[string]string),
+			tools:  make(map[version.Binary]string),
+		},
+		started: make(chan string, 10),
+		release: make(chan struct{}),
+	}
+	charms := []string{"cs:trusty/a-1", "cs:trusty/b-1", "cs:trusty/c-1", "cs:trusty/d-1"}
+	config := migration.UploadBinariesConfig{
+		Charms:              charms,
+		CharmDownloader:     downloader,
+		CharmUploader:       uploader,
+		CharmOrigins:        charmStoreOrigins(charms...),
+		ToolsDownloader:     downloader,
+		ToolsUploader:       uploader,
+		DownloadConcurrency: 4,
+		Concurrency:         2,
+	}
+	done := make(chan error)
+	go func() {
+		done <- migration.UploadBinaries(config)
+	}()
+
+	// Two uploads start, but no more until one of them finishes.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-uploader.started:
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for upload %d", i)
+		}
+	}
+	select {
+	case curl := <-uploader.started:
+		c.Fatalf("unexpected upload of %s", curl)
+	case <-time.After(testing.ShortWait):
+	}
+
+	close(uploader.release)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for uploads")
+	}
+	c.Assert(atomic.LoadInt32(&uploader.maxActive), gc.Equals, int32(2))
+	c.Assert(uploader.charms, gc.HasLen, 4)
+}
+
+func (s *ImportSuite) TestBinariesMigrationUploadFailureCancelsPending(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &blockingUploader{
+		fakeUploader: &fakeUploader{
+			charms: make(map[string]string),
+			tools:  make(map[version.Binary]string),
+		},
+		started:   make(chan string, 10),
+		release:   make(chan struct{}),
+		failCharm: "cs:trusty/b-1",
+	}
+	charms := []string{"cs:trusty/a-1", "cs:trusty/b-1", "cs:trusty/c-1", "cs:trusty/d-1"}
+	config := migration.UploadBinariesConfig{
+		Charms:              charms,
+		CharmDownloader:     downloader,
+		CharmUploader:       uploader,
+		CharmOrigins:        charmStoreOrigins(charms...),
+		ToolsDownloader:     downloader,
+		ToolsUploader:       uploader,
+		DownloadConcurrency: 4,
+		Concurrency:         2,
+	}
+	done := make(chan error)
+	go func() {
+		done <- migration.UploadBinaries(config)
+	}()
+
+	var started []string
+	for i := 0; i < 2; i++ {
+		select {
+		case curl := <-uploader.started:
+			started = append(started, curl)
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for upload %d", i)
+		}
+	}
+	c.Assert(started, jc.SameContents, []string{"cs:trusty/a-1", "cs:trusty/b-1"})
+
+	// The upload in progress is allowed to finish, but no more are
+	// started once one has failed.
+	close(uploader.release)
+	select {
+	case err := <-done:
+		c.Assert(err, gc.ErrorMatches, "cannot upload charm: boom")
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for uploads")
+	}
+	c.Assert(uploader.started, gc.HasLen, 0)
+	c.Assert(uploader.charms, jc.DeepEquals, map[string]string{
+		"cs:trusty/a-1": "cs:trusty/a-1 content",
+	})
+}
+
+func (s *ImportSuite) TestBinariesMigrationDownloadFailure(c *gc.C) {
+	downloader := &fakeDownloader{failCharm: "cs:trusty/b-1"}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	charms := []string{"cs:trusty/a-1", "cs:trusty/b-1", "cs:trusty/c-1"}
+	config := migration.UploadBinariesConfig{
+		Charms:              charms,
+		CharmDownloader:     downloader,
+		CharmUploader:       uploader,
+		CharmOrigins:        charmStoreOrigins(charms...),
+		ToolsDownloader:     downloader,
+		ToolsUploader:       uploader,
+		DownloadConcurrency: 3,
+	}
+	err := migration.UploadBinaries(config)
+	c.Assert(err, gc.ErrorMatches, "cannot open charm: boom")
+	c.Assert(uploader.charms, jc.DeepEquals, map[string]string{
+		"cs:trusty/a-1": "cs:trusty/a-1 content",
+	})
+}
+
+func (s *ImportSuite) TestBinariesMigrationCharmOrigins(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	config := migration.UploadBinariesConfig{
+		Charms:          []string{"cs:trusty/a-1", "local:trusty/b-2"},
+		CharmDownloader: downloader,
+		CharmUploader:   uploader,
+		CharmOrigins: map[string]coremigration.CharmOrigin{
+			"cs:trusty/a-1":    coremigration.CharmStoreOrigin,
+			"local:trusty/b-2": coremigration.LocalOrigin,
+		},
+		ToolsDownloader: downloader,
+		ToolsUploader:   uploader,
+	}
+	err := migration.UploadBinaries(config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uploader.charms, jc.DeepEquals, map[string]string{
+		"cs:trusty/a-1":    "cs:trusty/a-1 content",
+		"local:trusty/b-2": "local:trusty/b-2 content",
+	})
+}
+
+func (s *ImportSuite) TestBinariesMigrationCharmHubOrigin(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	config := migration.UploadBinariesConfig{
+		Charms:          []string{"cs:trusty/a-1", "ch:amd64/xenial/c-3"},
+		CharmDownloader: downloader,
+		CharmUploader:   uploader,
+		CharmOrigins: map[string]coremigration.CharmOrigin{
+			"cs:trusty/a-1":       coremigration.CharmStoreOrigin,
+			"ch:amd64/xenial/c-3": coremigration.CharmHubOrigin,
+		},
+		ToolsDownloader: downloader,
+		ToolsUploader:   uploader,
+	}
+	err := migration.UploadBinaries(config)
+	c.Assert(err, gc.ErrorMatches, `migrating charmhub charm "ch:amd64/xenial/c-3" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(uploader.charms, gc.HasLen, 0)
+}
+
+func (s *ImportSuite) TestBinariesMigrationUnknownCharmOrigin(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	config := migration.UploadBinariesConfig{
+		Charms:          []string{"cs:trusty/a-1", "local:trusty/b-2"},
+		CharmDownloader: downloader,
+		CharmUploader:   uploader,
+		CharmOrigins:    charmStoreOrigins("cs:trusty/a-1"),
+		ToolsDownloader: downloader,
+		ToolsUploader:   uploader,
+	}
+	err := migration.UploadBinaries(config)
+	c.Assert(err, gc.ErrorMatches, `cannot resolve charm: charm "local:trusty/b-2" with unknown origin not valid`)
+	c.Assert(downloader.charms, gc.HasLen, 0)
+	c.Assert(uploader.charms, gc.HasLen, 0)
+}
+
+func (s *ImportSuite) TestBinariesMigrationVerified(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &fakeUploader{
+		charms: make(map[string]string),
+		tools:  make(map[version.Binary]string),
+	}
+	v := version.MustParseBinary("2.1.0-trusty-amd64")
+	config := migration.UploadBinariesConfig{
+		Charms:          []string{"cs:trusty/a-1"},
+		CharmDownloader: downloader,
+		CharmUploader:   uploader,
+		CharmOrigins:    charmStoreOrigins("cs:trusty/a-1"),
+		Tools:           map[version.Binary]string{v: "/tools/0"},
+		ToolsDownloader: downloader,
+		ToolsUploader:   uploader,
+		CharmSHA256s: map[string]string{
+			"cs:trusty/a-1": sha256Of("cs:trusty/a-1 content"),
+		},
+		ToolsSHA256s:   map[version.Binary]string{v: sha256Of("/tools/0")},
+		BinaryVerifier: uploader,
+	}
+	err := migration.UploadBinaries(config)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ImportSuite) TestBinariesMigrationCorruptTools(c *gc.C) {
+	downloader := &fakeDownloader{}
+	uploader := &fakeUploader{
+		charms:       make(map[string]string),
+		tools:        make(map[version.Binary]string),
+		corruptTools: true,
+	}
+	v := version.MustParseBinary("2.1.0-trusty-amd64")
+	config := migration.UploadBinariesConfig{
+		Tools:           map[version.Binary]string{v: "/tools/0"},
+		CharmDownloader: downloader,
+		CharmUploader:   uploader,
+		ToolsDownloader: downloader,
+		ToolsUploader:   uploader,
+		ToolsSHA256s:    map[version.Binary]string{v: sha256Of("/tools/0")},
+		BinaryVerifier:  uploader,
+	}
+	err := migration.UploadBinaries(config)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(
+		"uploaded binaries failed verification: tools 2.1.0-trusty-amd64 have SHA256 %s, expected %s",
+		sha256Of("/too"), sha256Of("/tools/0"),
+	))
+}
+
+func charmStoreOrigins(charms ...string) map[string]coremigration.CharmOrigin {
+	origins := make(map[string]coremigration.CharmOrigin)
+	for _, curl := range charms {
+		origins[curl] = coremigration.CharmStoreOrigin
+	}
+	return origins
+}
+
 type fakeDownloader struct {
-	charms []string
-	uris   []string
+	mu        sync.Mutex
+	charms    []string
+	uris      []string
+	failCharm string
 }
 
 func (d *fakeDownloader) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
 	urlStr := curl.String()
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	d.charms = append(d.charms, urlStr)
+	if urlStr == d.failCharm {
+		return nil, errors.New("boom")
+	}
 	// Return the charm URL string as the fake charm content
 	return ioutil.NopCloser(bytes.NewReader([]byte(urlStr + " content"))), nil
 }
@@ -170,14 +613,44 @@ func (d *fakeDownloader) OpenURI(uri string, query url.Values) (io.ReadCloser, e
 	if query != nil {
 		panic("query should be empty")
 	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	d.uris = append(d.uris, uri)
 	// Return the URI string as fake content
 	return ioutil.NopCloser(bytes.NewReader([]byte(uri))), nil
 }
 
+// blockingDownloader records the number of charm downloads in
+// progress, which block until release is closed.
+type blockingDownloader struct {
+	*fakeDownloader
+	started   chan string
+	release   chan struct{}
+	active    int32
+	maxActive int32
+}
+
+func (d *blockingDownloader) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	active := atomic.AddInt32(&d.active, 1)
+	defer atomic.AddInt32(&d.active, -1)
+	for {
+		max := atomic.LoadInt32(&d.maxActive)
+		if active <= max || atomic.CompareAndSwapInt32(&d.maxActive, max, active) {
+			break
+		}
+	}
+	d.started <- curl.String()
+	<-d.release
+	return d.fakeDownloader.OpenCharm(curl)
+}
+
 type fakeUploader struct {
+	mu     sync.Mutex
 	tools  map[version.Binary]string
 	charms map[string]string
+
+	// corruptTools causes uploaded tools to be stored truncated.
+	corruptTools bool
 }
 
 func (f *fakeUploader) UploadTools(r io.ReadSeeker, v version.Binary, _ ...string) (tools.List, error) {
@@ -185,20 +658,73 @@ func (f *fakeUploader) UploadTools(r io.ReadSeeker, v version.Binary, _ ...strin
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if f.corruptTools {
+		data = data[:len(data)/2]
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.tools[v] = string(data)
 	return tools.List{&tools.Tools{Version: v}}, nil
 }
 
+func (f *fakeUploader) VerifyBinaries(charms map[string]string, tools map[version.Binary]string) error {
+	for curl, expect := range charms {
+		if sum := sha256Of(f.charms[curl]); sum != expect {
+			return errors.Errorf("charm %s has SHA256 %s, expected %s", curl, sum, expect)
+		}
+	}
+	for v, expect := range tools {
+		if sum := sha256Of(f.tools[v]); sum != expect {
+			return errors.Errorf("tools %s have SHA256 %s, expected %s", v, sum, expect)
+		}
+	}
+	return nil
+}
+
+func sha256Of(content string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+}
+
 func (f *fakeUploader) UploadCharm(u *charm.URL, r io.ReadSeeker) (*charm.URL, error) {
 	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.charms[u.String()] = string(data)
 	return u, nil
 }
 
+// blockingUploader records the number of charm uploads in progress,
+// which block until release is closed. Uploading failCharm fails
+// without blocking.
+type blockingUploader struct {
+	*fakeUploader
+	started   chan string
+	release   chan struct{}
+	failCharm string
+	active    int32
+	maxActive int32
+}
+
+func (u *blockingUploader) UploadCharm(curl *charm.URL, r io.ReadSeeker) (*charm.URL, error) {
+	active := atomic.AddInt32(&u.active, 1)
+	defer atomic.AddInt32(&u.active, -1)
+	for {
+		max := atomic.LoadInt32(&u.maxActive)
+		if active <= max || atomic.CompareAndSwapInt32(&u.maxActive, max, active) {
+			break
+		}
+	}
+	u.started <- curl.String()
+	if curl.String() == u.failCharm {
+		return nil, errors.New("boom")
+	}
+	<-u.release
+	return u.fakeUploader.UploadCharm(curl, r)
+}
+
 type ExportSuite struct {
 	statetesting.StateSuite
 }
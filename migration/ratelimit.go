@@ -0,0 +1,59 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"io"
+	"time"
+
+	"github.com/juju/utils/clock"
+)
+
+// throttleUploads returns copies of the given transfers whose uploads
+// read their content no faster than limit bytes per second. Closing
+// abort causes the uploads' reads to fail rather than wait.
+func throttleUploads(transfers []binaryTransfer, limit int64, clk clock.Clock, abort <-chan struct{}) []binaryTransfer {
+	throttled := make([]binaryTransfer, len(transfers))
+	for i, transfer := range transfers {
+		upload := transfer.upload
+		transfer.upload = func(content io.ReadSeeker) error {
+			return upload(&rateLimitedReader{
+				ReadSeeker: content,
+				limit:      limit,
+				clock:      clk,
+				abort:      abort,
+			})
+		}
+		throttled[i] = transfer
+	}
+	return throttled
+}
+
+// rateLimitedReader wraps an io.ReadSeeker so that reads from it
+// proceed no faster than limit bytes per second, as measured by
+// clock. Each read returns at most limit bytes, and is followed by a
+// wait long enough for those bytes to be within the limit. If abort
+// is closed during the wait, the read fails with errTransferAborted.
+type rateLimitedReader struct {
+	io.ReadSeeker
+	limit int64
+	clock clock.Clock
+	abort <-chan struct{}
+}
+
+// Read implements io.Reader.
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.limit {
+		p = p[:r.limit]
+	}
+	n, err := r.ReadSeeker.Read(p)
+	if n > 0 {
+		select {
+		case <-r.clock.After(time.Duration(n) * time.Second / time.Duration(r.limit)):
+		case <-r.abort:
+			return n, errTransferAborted
+		}
+	}
+	return n, err
+}
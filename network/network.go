@@ -443,3 +443,17 @@ func FilterBridgeAddresses(addresses []Address) []Address {
 	addresses = filterLXDAddresses(addresses)
 	return addresses
 }
+
+// CIDRsOverlap reports whether the two given CIDRs have any addresses
+// in common.
+func CIDRsOverlap(cidrA, cidrB string) (bool, error) {
+	_, netA, err := net.ParseCIDR(cidrA)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	_, netB, err := net.ParseCIDR(cidrB)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return netA.Contains(netB.IP) || netB.Contains(netA.IP), nil
+}
@@ -176,3 +176,28 @@ func (s *NetworkSuite) TestNoAddressError(c *gc.C) {
 	c.Assert(network.IsNoAddressError(err), jc.IsTrue)
 	c.Assert(network.IsNoAddressError(errors.New("address found")), jc.IsFalse)
 }
+
+func (s *NetworkSuite) TestCIDRsOverlap(c *gc.C) {
+	for i, test := range []struct {
+		a, b    string
+		overlap bool
+	}{
+		{"10.0.0.0/16", "10.0.1.0/24", true},
+		{"10.0.1.0/24", "10.0.0.0/16", true},
+		{"10.0.0.0/24", "10.0.0.0/24", true},
+		{"10.0.0.0/24", "10.0.1.0/24", false},
+		{"192.168.0.0/16", "10.0.0.0/8", false},
+		{"2001:db8::/32", "2001:db8:1::/48", true},
+		{"2001:db8::/32", "10.0.0.0/8", false},
+	} {
+		c.Logf("test %d: %s, %s", i, test.a, test.b)
+		overlap, err := network.CIDRsOverlap(test.a, test.b)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(overlap, gc.Equals, test.overlap)
+	}
+}
+
+func (s *NetworkSuite) TestCIDRsOverlapInvalid(c *gc.C) {
+	_, err := network.CIDRsOverlap("10.0.0.0/24", "bad")
+	c.Assert(err, gc.ErrorMatches, "invalid CIDR address: bad")
+}
@@ -0,0 +1,107 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/azure"
+	"github.com/juju/errors"
+)
+
+// azureCloud describes the endpoints of one of the Azure clouds:
+// public Azure, or one of the sovereign clouds.
+type azureCloud struct {
+	// name is the name of the cloud, for error messages.
+	name string
+
+	// resourceManagerHost is the host of the cloud's Resource
+	// Manager endpoint, i.e. of the "endpoint" config.
+	resourceManagerHost string
+
+	// resourceManagerScope is the resource for which tokens are
+	// requested to authorize Resource Manager API requests.
+	resourceManagerScope string
+
+	// activeDirectoryEndpoint is the URL of the cloud's Active
+	// Directory login endpoint, from which tokens are acquired.
+	activeDirectoryEndpoint string
+
+	// storageSuffix is the domain of the cloud's storage endpoints,
+	// i.e. of the "storage-endpoint" config.
+	storageSuffix string
+}
+
+// publicActiveDirectoryEndpoint is the login endpoint of public Azure.
+const publicActiveDirectoryEndpoint = "https://login.microsoftonline.com/"
+
+// knownClouds holds the Azure clouds whose endpoints are known.
+var knownClouds = []azureCloud{{
+	name:                    "Azure",
+	resourceManagerHost:     "management.azure.com",
+	resourceManagerScope:    azure.AzureResourceManagerScope,
+	activeDirectoryEndpoint: publicActiveDirectoryEndpoint,
+	storageSuffix:           "core.windows.net",
+}, {
+	name:                    "Azure China",
+	resourceManagerHost:     "management.chinacloudapi.cn",
+	resourceManagerScope:    "https://management.chinacloudapi.cn/",
+	activeDirectoryEndpoint: "https://login.chinacloudapi.cn/",
+	storageSuffix:           "core.chinacloudapi.cn",
+}, {
+	name:                    "Azure US Government",
+	resourceManagerHost:     "management.usgovcloudapi.net",
+	resourceManagerScope:    "https://management.usgovcloudapi.net/",
+	activeDirectoryEndpoint: "https://login.microsoftonline.us/",
+	storageSuffix:           "core.usgovcloudapi.net",
+}, {
+	name:                    "Azure Germany",
+	resourceManagerHost:     "management.microsoftazure.de",
+	resourceManagerScope:    "https://management.microsoftazure.de/",
+	activeDirectoryEndpoint: "https://login.microsoftonline.de/",
+	storageSuffix:           "core.cloudapi.de",
+}}
+
+// cloudForEndpoints returns the Azure cloud with the given Resource
+// Manager and storage endpoints, which must belong to the same cloud.
+//
+// If the endpoints don't belong to a known cloud, e.g. for Azure
+// Stack, the token scope is taken from the Resource Manager endpoint,
+// and tokens are acquired from the public Azure login endpoint.
+func cloudForEndpoints(endpoint, storageEndpoint *url.URL) (azureCloud, error) {
+	endpointCloud, endpointKnown := knownCloud(func(cloud azureCloud) bool {
+		return strings.EqualFold(endpoint.Host, cloud.resourceManagerHost)
+	})
+	storageCloud, storageKnown := knownCloud(func(cloud azureCloud) bool {
+		host := strings.ToLower(storageEndpoint.Host)
+		return host == cloud.storageSuffix || strings.HasSuffix(host, "."+cloud.storageSuffix)
+	})
+	if endpointKnown || storageKnown {
+		if endpointCloud.name != storageCloud.name {
+			return azureCloud{}, errors.Errorf(
+				"%q config %q and %q config %q are not in the same Azure cloud",
+				configAttrEndpoint, endpoint,
+				configAttrStorageEndpoint, storageEndpoint,
+			)
+		}
+		return endpointCloud, nil
+	}
+	return azureCloud{
+		name:                    endpoint.Host,
+		resourceManagerHost:     endpoint.Host,
+		resourceManagerScope:    strings.TrimSuffix(endpoint.String(), "/") + "/",
+		activeDirectoryEndpoint: publicActiveDirectoryEndpoint,
+		storageSuffix:           storageEndpoint.Host,
+	}, nil
+}
+
+func knownCloud(match func(azureCloud) bool) (azureCloud, bool) {
+	for _, cloud := range knownClouds {
+		if match(cloud) {
+			return cloud, true
+		}
+	}
+	return azureCloud{}, false
+}
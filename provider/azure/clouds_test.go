@@ -0,0 +1,69 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure_test
+
+import (
+	autorestazure "github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/azure"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/azure"
+	"github.com/juju/juju/testing"
+)
+
+type cloudsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&cloudsSuite{})
+
+func (s *cloudsSuite) TestCloudEndpoints(c *gc.C) {
+	for i, test := range []struct {
+		endpoint        string
+		storageEndpoint string
+		scope           string
+		login           string
+		err             string
+	}{{
+		endpoint:        "https://management.azure.com",
+		storageEndpoint: "https://core.windows.net",
+		scope:           autorestazure.AzureResourceManagerScope,
+		login:           "https://login.microsoftonline.com/",
+	}, {
+		endpoint:        "https://management.chinacloudapi.cn",
+		storageEndpoint: "https://core.chinacloudapi.cn",
+		scope:           "https://management.chinacloudapi.cn/",
+		login:           "https://login.chinacloudapi.cn/",
+	}, {
+		endpoint:        "https://management.usgovcloudapi.net",
+		storageEndpoint: "https://blob.core.usgovcloudapi.net",
+		scope:           "https://management.usgovcloudapi.net/",
+		login:           "https://login.microsoftonline.us/",
+	}, {
+		// Endpoints of unknown clouds, such as Azure Stack, are
+		// used as they are.
+		endpoint:        "https://api.azurestack.local",
+		storageEndpoint: "https://storage.azurestack.local",
+		scope:           "https://api.azurestack.local/",
+		login:           "https://login.microsoftonline.com/",
+	}, {
+		endpoint:        "https://management.azure.com",
+		storageEndpoint: "https://core.chinacloudapi.cn",
+		err:             `"endpoint" config "https://management.azure.com" and "storage-endpoint" config "https://core.chinacloudapi.cn" are not in the same Azure cloud`,
+	}, {
+		endpoint:        "https://api.azurestack.local",
+		storageEndpoint: "https://core.windows.net",
+		err:             `"endpoint" config "https://api.azurestack.local" and "storage-endpoint" config "https://core.windows.net" are not in the same Azure cloud`,
+	}} {
+		c.Logf("test %d: %s, %s", i, test.endpoint, test.storageEndpoint)
+		scope, login, err := azure.CloudEndpoints(test.endpoint, test.storageEndpoint)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(scope, gc.Equals, test.scope)
+		c.Check(login, gc.Equals, test.login)
+	}
+}
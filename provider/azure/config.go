@@ -4,10 +4,14 @@
 package azure
 
 import (
+	"fmt"
 	"net/url"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/azure-sdk-for-go/arm/storage"
 	"github.com/juju/errors"
 	"github.com/juju/schema"
@@ -21,10 +25,23 @@ const (
 	configAttrSubscriptionId     = "subscription-id"
 	configAttrTenantId           = "tenant-id"
 	configAttrAppPassword        = "application-password"
+	configAttrAuthType           = "auth-type"
 	configAttrLocation           = "location"
 	configAttrEndpoint           = "endpoint"
 	configAttrStorageEndpoint    = "storage-endpoint"
 	configAttrStorageAccountType = "storage-account-type"
+	configAttrUseManagedDisks    = "use-managed-disks"
+	configAttrResourceGroupName  = "resource-group-name"
+	configAttrAPIMaxRetries      = "api-max-retries"
+	configAttrAPIRetryDelay      = "api-retry-delay"
+
+	// authTypeServicePrincipal and authTypeManagedIdentity are the
+	// values of auth-type. With a service principal, Juju
+	// authenticates with the application ID and password; with a
+	// managed identity, it authenticates as the Azure virtual
+	// machine it is running on.
+	authTypeServicePrincipal = "service-principal"
+	authTypeManagedIdentity  = "managed-identity"
 
 	// The below bits are internal book-keeping things, rather than
 	// configuration. Config is just what we have to work with.
@@ -42,23 +59,38 @@ var configFields = schema.Fields{
 	configAttrSubscriptionId:     schema.String(),
 	configAttrTenantId:           schema.String(),
 	configAttrAppPassword:        schema.String(),
+	configAttrAuthType:           schema.String(),
 	configAttrStorageAccountType: schema.String(),
+	configAttrUseManagedDisks:    schema.Bool(),
+	configAttrResourceGroupName:  schema.String(),
+	configAttrAPIMaxRetries:      schema.ForceInt(),
+	configAttrAPIRetryDelay:      schema.String(),
 }
 
 var configDefaults = schema.Defaults{
+	configAttrAuthType:           authTypeServicePrincipal,
 	configAttrStorageAccountType: string(storage.StandardLRS),
+	configAttrUseManagedDisks:    false,
+	configAttrResourceGroupName:  "",
+	configAttrAPIMaxRetries:      defaultAPIMaxRetries,
+	configAttrAPIRetryDelay:      defaultAPIRetryDelay.String(),
 }
 
 var requiredConfigAttributes = []string{
-	configAttrAppId,
-	configAttrAppPassword,
 	configAttrSubscriptionId,
-	configAttrTenantId,
 	configAttrLocation,
 	configAttrEndpoint,
 	configAttrStorageEndpoint,
 }
 
+// servicePrincipalConfigAttributes are additionally required when
+// authenticating with a service principal.
+var servicePrincipalConfigAttributes = []string{
+	configAttrAppId,
+	configAttrAppPassword,
+	configAttrTenantId,
+}
+
 var immutableConfigAttributes = []string{
 	configAttrSubscriptionId,
 	configAttrTenantId,
@@ -67,26 +99,88 @@ var immutableConfigAttributes = []string{
 
 type azureModelConfig struct {
 	*config.Config
-	token              *azure.ServicePrincipalToken
+	token              azureToken
 	subscriptionId     string
 	location           string // canonicalized
 	endpoint           string
 	storageEndpoint    string
 	storageAccountType storage.AccountType
+	useManagedDisks    bool
+	resourceGroup      string
+	apiMaxRetries      int
+	apiRetryDelay      time.Duration
+}
+
+// credentialConfigAttributes are the attributes from which the token
+// used to authorize API requests is constructed.
+var credentialConfigAttributes = []string{
+	configAttrAuthType,
+	configAttrAppId,
+	configAttrAppPassword,
+	configAttrTenantId,
 }
 
-var knownStorageAccountTypes = []string{
-	"Standard_LRS", "Standard_GRS", "Standard_RAGRS", "Standard_ZRS", "Premium_LRS",
+// validResourceGroupName matches the names Azure allows for resource
+// groups: letters, digits, underscores, hyphens, parentheses and
+// periods, but not ending with a period.
+var validResourceGroupName = regexp.MustCompile(`^[-\pL\pN_.()]*[-\pL\pN_()]$`)
+
+// knownStorageAccountTypes holds the storage account types that may
+// be used for storage-account-type.
+var knownStorageAccountTypes = storageAccountTypes(sdkStorageAccountTypes)
+
+// fallbackStorageAccountTypes are the storage account types that are
+// known if the storage SDK doesn't enumerate them.
+var fallbackStorageAccountTypes = []storage.AccountType{
+	storage.StandardLRS,
+	storage.StandardGRS,
+	storage.StandardRAGRS,
+	storage.StandardZRS,
+	storage.PremiumLRS,
+}
+
+// sdkStorageAccountTypes returns the storage account types enumerated
+// by the storage SDK. The SDK we use predates its enumeration of
+// account types, so there are none; when it is updated, this should
+// return storage.PossibleAccountTypeValues().
+func sdkStorageAccountTypes() []storage.AccountType {
+	return nil
+}
+
+// storageAccountTypes returns the names of the storage account types
+// returned by sdkTypes, or of the fallback types if there are none.
+func storageAccountTypes(sdkTypes func() []storage.AccountType) []string {
+	types := sdkTypes()
+	if len(types) == 0 {
+		types = fallbackStorageAccountTypes
+	}
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return names
 }
 
 // Validate ensures that the provided configuration is valid for this
 // provider, and that changes between the old (if provided) and new
 // configurations are valid.
 func (*azureEnvironProvider) Validate(newCfg, oldCfg *config.Config) (*config.Config, error) {
-	_, err := validateConfig(newCfg, oldCfg)
+	ecfg, err := validateConfig(newCfg, oldCfg)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The location is only checked when it is first set or changed,
+	// so that models in locations that Juju doesn't know about keep
+	// working on controllers without the escape hatch set.
+	var oldLocation string
+	if oldCfg != nil {
+		oldLocation, _ = oldCfg.UnknownAttrs()[configAttrLocation].(string)
+	}
+	if canonicalLocation(oldLocation) != ecfg.location {
+		if err := validateLocation(ecfg.location); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return newCfg, nil
 }
 
@@ -95,6 +189,15 @@ func validateConfig(newCfg, oldCfg *config.Config) (*azureModelConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	if newCfg.FirewallMode() == config.FwGlobal {
+		// We do not currently support the "global" firewall mode.
+		return nil, errors.Errorf(`%v
+
+Azure only supports firewalling each instance individually. Please set
+"firewall-mode" to %q (the default), or to %q to disable firewalling.`,
+			errNoFwGlobal, config.FwInstance, config.FwNone,
+		)
+	}
 
 	validated, err := newCfg.ValidateUnknownAttrs(configFields, configDefaults)
 	if err != nil {
@@ -102,7 +205,22 @@ func validateConfig(newCfg, oldCfg *config.Config) (*azureModelConfig, error) {
 	}
 
 	// Ensure required configuration is provided.
-	for _, key := range requiredConfigAttributes {
+	authType := validated[configAttrAuthType].(string)
+	required := requiredConfigAttributes
+	switch authType {
+	case authTypeServicePrincipal:
+		required = append(required, servicePrincipalConfigAttributes...)
+	case authTypeManagedIdentity:
+		// The managed identity of the virtual machine is used,
+		// so no application password is needed.
+	default:
+		return nil, errors.Errorf(
+			"invalid %q config %q, expected one of: %q",
+			configAttrAuthType, authType,
+			[]string{authTypeServicePrincipal, authTypeManagedIdentity},
+		)
+	}
+	for _, key := range required {
 		if value, ok := validated[key].(string); !ok || value == "" {
 			return nil, errors.Errorf("%q config not specified", key)
 		}
@@ -128,44 +246,101 @@ func validateConfig(newCfg, oldCfg *config.Config) (*azureModelConfig, error) {
 			}
 			// It's valid to go from not having to having.
 		}
-		// TODO(axw) figure out how we intend to handle changing
-		// secrets, such as application key
-	}
-
-	// Resource group names must not exceed 80 characters. Resource group
-	// names are based on the model UUID and model name, the latter of
-	// which the model creator controls.
-	modelTag := names.NewModelTag(newCfg.UUID())
-	resourceGroup := resourceGroupName(modelTag, newCfg.Name())
-	if n := len(resourceGroup); n > resourceNameLengthMax {
-		smallestResourceGroup := resourceGroupName(modelTag, "")
-		return nil, errors.Errorf(`resource group name %q is too long
-
-Please choose a model name of no more than %d characters.`,
-			resourceGroup,
-			resourceNameLengthMax-len(smallestResourceGroup),
-		)
+		// Models created before use-managed-disks was introduced
+		// use unmanaged disks, so not having it is the same as
+		// having it false.
+		oldUseManagedDisks := false
+		if value, ok := oldUnknownAttrs[configAttrUseManagedDisks]; ok {
+			coerced, err := configFields[configAttrUseManagedDisks].Coerce(value, nil)
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid old %q config", configAttrUseManagedDisks)
+			}
+			oldUseManagedDisks = coerced.(bool)
+		}
+		if newUseManagedDisks := validated[configAttrUseManagedDisks].(bool); newUseManagedDisks != oldUseManagedDisks {
+			return nil, errors.Errorf(
+				"cannot change immutable %q config (%v -> %v)",
+				configAttrUseManagedDisks, oldUseManagedDisks, newUseManagedDisks,
+			)
+		}
+		// The model's resource group can't be moved, and models
+		// created before resource-group-name was introduced use
+		// the derived name, so not having it is the same as
+		// having it empty.
+		oldResourceGroupName, _ := oldUnknownAttrs[configAttrResourceGroupName].(string)
+		if newResourceGroupName := validated[configAttrResourceGroupName].(string); newResourceGroupName != oldResourceGroupName {
+			return nil, errors.Errorf(
+				"cannot change immutable %q config (%q -> %q)",
+				configAttrResourceGroupName, oldResourceGroupName, newResourceGroupName,
+			)
+		}
+		// The application password may be changed, so that it can
+		// be rotated; a new token is constructed below, and used
+		// by the environ once the config is set.
+	}
+
+	// Resource group names must not exceed 80 characters. Unless
+	// overridden, resource group names are based on the model UUID
+	// and model name, the latter of which the model creator controls.
+	resourceGroup := validated[configAttrResourceGroupName].(string)
+	if resourceGroup != "" {
+		if n := len(resourceGroup); n > resourceNameLengthMax {
+			return nil, errors.Errorf(
+				"invalid %q config %q, expected no more than %d characters",
+				configAttrResourceGroupName, resourceGroup, resourceNameLengthMax,
+			)
+		}
+		if !validResourceGroupName.MatchString(resourceGroup) {
+			return nil, errors.Errorf(
+				"invalid %q config %q, expected letters, digits, underscores, hyphens, parentheses and periods, not ending with a period",
+				configAttrResourceGroupName, resourceGroup,
+			)
+		}
+	} else {
+		modelTag := names.NewModelTag(newCfg.UUID())
+		resourceGroup = resourceGroupName(modelTag, newCfg.Name())
+		if n := len(resourceGroup); n > resourceNameLengthMax {
+			smallestResourceGroup := resourceGroupName(modelTag, "")
+			return nil, errors.Errorf(`resource group name %q is too long
+
+Please choose a model name of no more than %d characters,
+or set %q.`,
+				resourceGroup,
+				resourceNameLengthMax-len(smallestResourceGroup),
+				configAttrResourceGroupName,
+			)
+		}
 	}
 
 	location := canonicalLocation(validated[configAttrLocation].(string))
 	endpoint := validated[configAttrEndpoint].(string)
 	storageEndpoint := validated[configAttrStorageEndpoint].(string)
-	appId := validated[configAttrAppId].(string)
+	appId, _ := validated[configAttrAppId].(string)
 	subscriptionId := validated[configAttrSubscriptionId].(string)
-	tenantId := validated[configAttrTenantId].(string)
-	appPassword := validated[configAttrAppPassword].(string)
+	tenantId, _ := validated[configAttrTenantId].(string)
+	appPassword, _ := validated[configAttrAppPassword].(string)
 	storageAccountType := validated[configAttrStorageAccountType].(string)
+	useManagedDisks := validated[configAttrUseManagedDisks].(bool)
+	apiMaxRetries := validated[configAttrAPIMaxRetries].(int)
+	apiRetryDelayString := validated[configAttrAPIRetryDelay].(string)
 
-	if newCfg.FirewallMode() == config.FwGlobal {
-		// We do not currently support the "global" firewall mode.
-		return nil, errNoFwGlobal
+	// Managed disks don't need a storage account, so the storage
+	// account type may be left empty when they are used.
+	if storageAccountType != "" || !useManagedDisks {
+		if !isKnownStorageAccountType(storageAccountType) {
+			return nil, errors.Errorf(
+				"invalid storage account type %q, expected one of: %q",
+				storageAccountType, knownStorageAccountTypes,
+			)
+		}
 	}
 
-	if !isKnownStorageAccountType(storageAccountType) {
-		return nil, errors.Errorf(
-			"invalid storage account type %q, expected one of: %q",
-			storageAccountType, knownStorageAccountTypes,
-		)
+	if apiMaxRetries < 0 {
+		return nil, errors.Errorf("invalid %q config %d, expected a non-negative number", configAttrAPIMaxRetries, apiMaxRetries)
+	}
+	apiRetryDelay, err := time.ParseDuration(apiRetryDelayString)
+	if err != nil || apiRetryDelay <= 0 {
+		return nil, errors.Errorf("invalid %q config %q, expected a positive duration", configAttrAPIRetryDelay, apiRetryDelayString)
 	}
 
 	// The Azure storage code wants the endpoint host only, not the URL.
@@ -173,13 +348,29 @@ Please choose a model name of no more than %d characters.`,
 	if err != nil {
 		return nil, errors.Annotate(err, "parsing storage endpoint URL")
 	}
-
-	token, err := azure.NewServicePrincipalToken(
-		appId, appPassword, tenantId,
-		azure.AzureResourceManagerScope,
-	)
+	endpointURL, err := url.Parse(endpoint)
 	if err != nil {
-		return nil, errors.Annotate(err, "constructing service principal token")
+		return nil, errors.Annotate(err, "parsing endpoint URL")
+	}
+	// Tokens must be requested for the cloud that the endpoints
+	// belong to, e.g. one of the sovereign clouds.
+	cloud, err := cloudForEndpoints(endpointURL, storageEndpointURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var token azureToken
+	switch authType {
+	case authTypeServicePrincipal:
+		token = newServicePrincipalToken(
+			cloud.activeDirectoryEndpoint, tenantId,
+			appId, appPassword,
+			cloud.resourceManagerScope,
+		)
+	case authTypeManagedIdentity:
+		// If an application ID is specified, it identifies a
+		// user-assigned managed identity.
+		token = newManagedIdentityToken(appId, cloud.resourceManagerScope)
 	}
 
 	azureConfig := &azureModelConfig{
@@ -190,11 +381,27 @@ Please choose a model name of no more than %d characters.`,
 		endpoint,
 		storageEndpointURL.Host,
 		storage.AccountType(storageAccountType),
+		useManagedDisks,
+		resourceGroup,
+		apiMaxRetries,
+		apiRetryDelay,
 	}
 
 	return azureConfig, nil
 }
 
+// sameCredentials reports whether or not the two configurations
+// have the same credentials, and so can share a token.
+func sameCredentials(a, b *config.Config) bool {
+	aAttrs, bAttrs := a.UnknownAttrs(), b.UnknownAttrs()
+	for _, key := range credentialConfigAttributes {
+		if aAttrs[key] != bAttrs[key] {
+			return false
+		}
+	}
+	return true
+}
+
 // isKnownStorageAccountType reports whether or not the given string identifies
 // a known storage account type.
 func isKnownStorageAccountType(t string) bool {
@@ -206,6 +413,110 @@ func isKnownStorageAccountType(t string) bool {
 	return false
 }
 
+// knownLocations holds the Azure Resource Manager regions known to
+// Juju, in canonical form.
+var knownLocations = []string{
+	"australiaeast",
+	"australiasoutheast",
+	"brazilsouth",
+	"canadacentral",
+	"canadaeast",
+	"centralindia",
+	"centralus",
+	"chinaeast",
+	"chinanorth",
+	"eastasia",
+	"eastus",
+	"eastus2",
+	"germanycentral",
+	"germanynortheast",
+	"japaneast",
+	"japanwest",
+	"koreacentral",
+	"koreasouth",
+	"northcentralus",
+	"northeurope",
+	"southcentralus",
+	"southeastasia",
+	"southindia",
+	"uksouth",
+	"ukwest",
+	"usgoviowa",
+	"usgovvirginia",
+	"westcentralus",
+	"westeurope",
+	"westindia",
+	"westus",
+	"westus2",
+}
+
+// allowUnknownLocationEnvKey is the environment variable which, when
+// set to true, allows locations that aren't in knownLocations to be
+// used, e.g. for regions added after this version of Juju, or for
+// Azure Stack.
+const allowUnknownLocationEnvKey = "JUJU_AZURE_ALLOW_UNKNOWN_LOCATION"
+
+// validateLocation returns an error if the given canonicalized
+// location isn't a known Azure region, suggesting known regions with
+// similar names.
+func validateLocation(location string) error {
+	for _, known := range knownLocations {
+		if location == known {
+			return nil
+		}
+	}
+	if allow, _ := strconv.ParseBool(os.Getenv(allowUnknownLocationEnvKey)); allow {
+		logger.Debugf("using unknown location %q", location)
+		return nil
+	}
+	var similar []string
+	for _, known := range knownLocations {
+		if editDistance(location, known) <= maxLocationEditDistance {
+			similar = append(similar, known)
+		}
+	}
+	var suggestion string
+	if len(similar) > 0 {
+		suggestion = fmt.Sprintf(", did you mean one of: %q?", similar)
+	}
+	return errors.Errorf(`unknown location %q%s
+
+To use a location that Juju doesn't know about, set %s=true.`,
+		location, suggestion, allowUnknownLocationEnvKey,
+	)
+}
+
+// maxLocationEditDistance is the greatest edit distance between an
+// unknown location and a known one for the latter to be suggested.
+const maxLocationEditDistance = 2
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if n := prev[j] + 1; n < curr[j] {
+				curr[j] = n
+			}
+			if n := curr[j-1] + 1; n < curr[j] {
+				curr[j] = n
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
 // canonicalLocation returns the canonicalized location string. This involves
 // stripping whitespace, and lowercasing. The ARM APIs do not support embedded
 // whitespace, whereas the old Service Management APIs used to; we allow the
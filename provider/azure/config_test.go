@@ -4,7 +4,10 @@
 package azure_test
 
 import (
+	"strings"
+
 	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/mocks"
+	"github.com/Azure/azure-sdk-for-go/arm/storage"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -48,26 +51,156 @@ func (s *configSuite) TestValidateInvalidStorageAccountType(c *gc.C) {
 	)
 }
 
+func (s *configSuite) TestValidateStorageAccountTypeFromSDK(c *gc.C) {
+	// Storage account types enumerated by the SDK are known
+	// without being listed in the provider.
+	s.PatchValue(azure.KnownStorageAccountTypes, azure.StorageAccountTypes(func() []storage.AccountType {
+		return []storage.AccountType{storage.StandardLRS, "Premium_ZRS"}
+	}))
+	s.assertConfigValid(c, testing.Attrs{"storage-account-type": "Premium_ZRS"})
+	s.assertConfigInvalid(
+		c, testing.Attrs{"storage-account-type": "Premium_LRS"},
+		`invalid storage account type "Premium_LRS", expected one of: \["Standard_LRS" "Premium_ZRS"\]`,
+	)
+}
+
+func (s *configSuite) TestStorageAccountTypesFallback(c *gc.C) {
+	types := azure.StorageAccountTypes(func() []storage.AccountType { return nil })
+	c.Assert(types, jc.DeepEquals, []string{
+		"Standard_LRS", "Standard_GRS", "Standard_RAGRS", "Standard_ZRS", "Premium_LRS",
+	})
+}
+
+func (s *configSuite) TestValidateEndpoints(c *gc.C) {
+	s.assertConfigValid(c, testing.Attrs{
+		"endpoint":         "https://management.azure.com",
+		"storage-endpoint": "https://core.windows.net",
+	})
+	s.assertConfigValid(c, testing.Attrs{
+		"location":         "chinaeast",
+		"endpoint":         "https://management.chinacloudapi.cn",
+		"storage-endpoint": "https://core.chinacloudapi.cn",
+	})
+	s.assertConfigInvalid(c, testing.Attrs{
+		"endpoint":         "https://management.chinacloudapi.cn",
+		"storage-endpoint": "https://core.windows.net",
+	}, `"endpoint" config "https://management.chinacloudapi.cn" and "storage-endpoint" config "https://core.windows.net" are not in the same Azure cloud`)
+}
+
+func (s *configSuite) TestValidateAPIRetryConfig(c *gc.C) {
+	s.assertConfigValid(c, testing.Attrs{"api-max-retries": 0, "api-retry-delay": "1m"})
+}
+
+func (s *configSuite) TestValidateInvalidAPIMaxRetries(c *gc.C) {
+	s.assertConfigInvalid(
+		c, testing.Attrs{"api-max-retries": -1},
+		`invalid "api-max-retries" config -1, expected a non-negative number`,
+	)
+}
+
+func (s *configSuite) TestValidateInvalidAPIRetryDelay(c *gc.C) {
+	s.assertConfigInvalid(
+		c, testing.Attrs{"api-retry-delay": "soon"},
+		`invalid "api-retry-delay" config "soon", expected a positive duration`,
+	)
+	s.assertConfigInvalid(
+		c, testing.Attrs{"api-retry-delay": "0s"},
+		`invalid "api-retry-delay" config "0s", expected a positive duration`,
+	)
+}
+
 func (s *configSuite) TestValidateInvalidFirewallMode(c *gc.C) {
 	s.assertConfigInvalid(
 		c, testing.Attrs{"firewall-mode": "global"},
-		"global firewall mode is not supported",
+		`global firewall mode is not supported
+
+Azure only supports firewalling each instance individually. Please set
+"firewall-mode" to "instance" \(the default\), or to "none" to disable firewalling.`,
+	)
+}
+
+func (s *configSuite) TestValidateInvalidFirewallModeFirst(c *gc.C) {
+	// The firewall mode is checked before anything provider-specific.
+	s.assertConfigInvalid(
+		c, testing.Attrs{
+			"firewall-mode":        "global",
+			"application-id":       "",
+			"storage-account-type": "savings",
+		},
+		`global firewall mode is not supported(.|\n)*`,
 	)
 }
 
 func (s *configSuite) TestValidateLocation(c *gc.C) {
 	s.assertConfigInvalid(c, testing.Attrs{"location": ""}, `"location" config not specified`)
-	// We don't validate locations, because new locations may be added.
-	// Azure will complain if the location is invalid anyway.
+	s.assertConfigValid(c, testing.Attrs{"location": "westeurope"})
+	s.assertConfigValid(c, testing.Attrs{"location": "West Europe"})
+	s.assertConfigInvalid(c, testing.Attrs{"location": "eurasia"}, `unknown location "eurasia"
+
+To use a location that Juju doesn't know about, set JUJU_AZURE_ALLOW_UNKNOWN_LOCATION=true.`)
+}
+
+func (s *configSuite) TestValidateLocationTypo(c *gc.C) {
+	s.assertConfigInvalid(c, testing.Attrs{"location": "westeuropee"}, `unknown location "westeuropee", did you mean one of: \["westeurope"\]\?
+
+To use a location that Juju doesn't know about, set JUJU_AZURE_ALLOW_UNKNOWN_LOCATION=true.`)
+	s.assertConfigInvalid(c, testing.Attrs{"location": "westus3"}, `unknown location "westus3", did you mean one of: \["westus" "westus2"\]\?(.|\n)*`)
+}
+
+func (s *configSuite) TestValidateUnknownLocationAllowed(c *gc.C) {
+	// New regions, and Azure Stack regions, can be used with the
+	// escape hatch.
+	s.PatchEnvironment("JUJU_AZURE_ALLOW_UNKNOWN_LOCATION", "true")
 	s.assertConfigValid(c, testing.Attrs{"location": "eurasia"})
 }
 
+func (s *configSuite) TestValidateUnchangedUnknownLocation(c *gc.C) {
+	// Models in unknown locations keep working without the escape
+	// hatch, as the location is only checked when it is set.
+	cfg := makeTestModelConfig(c, testing.Attrs{"location": "eurasia"})
+	_, err := s.provider.Validate(cfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *configSuite) TestValidateModelNameLength(c *gc.C) {
 	s.assertConfigInvalid(
 		c, testing.Attrs{"name": "someextremelyoverlylongishmodelname"},
 		`resource group name "juju-someextremelyoverlylongishmodelname-model-deadbeef-0bad-400d-8000-4b1d0d06f00d" is too long
 
-Please choose a model name of no more than 32 characters.`)
+Please choose a model name of no more than 32 characters,
+or set "resource-group-name".`)
+}
+
+func (s *configSuite) TestValidateResourceGroupName(c *gc.C) {
+	// A long model name is fine if the resource group is named.
+	s.assertConfigValid(c, testing.Attrs{
+		"name":                "someextremelyoverlylongishmodelname",
+		"resource-group-name": "my-(resource)_group.1",
+	})
+	s.assertConfigInvalid(
+		c, testing.Attrs{"resource-group-name": strings.Repeat("a", 81)},
+		`invalid "resource-group-name" config "a+", expected no more than 80 characters`,
+	)
+	s.assertConfigInvalid(
+		c, testing.Attrs{"resource-group-name": "my/group"},
+		`invalid "resource-group-name" config "my/group", expected letters, digits, underscores, hyphens, parentheses and periods, not ending with a period`,
+	)
+	s.assertConfigInvalid(
+		c, testing.Attrs{"resource-group-name": "my-group."},
+		`invalid "resource-group-name" config "my-group.", expected .*`,
+	)
+}
+
+func (s *configSuite) TestValidateResourceGroupNameCantChange(c *gc.C) {
+	cfgOld := makeTestModelConfig(c)
+	cfgNew := makeTestModelConfig(c, testing.Attrs{"resource-group-name": "my-group"})
+	_, err := s.provider.Validate(cfgNew, cfgNew)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.provider.Validate(cfgNew, cfgOld)
+	c.Assert(err, gc.ErrorMatches, `cannot change immutable "resource-group-name" config \("" -> "my-group"\)`)
+	_, err = s.provider.Validate(cfgOld, cfgNew)
+	c.Assert(err, gc.ErrorMatches, `cannot change immutable "resource-group-name" config \("my-group" -> ""\)`)
 }
 
 func (s *configSuite) TestValidateInvalidCredentials(c *gc.C) {
@@ -77,6 +210,28 @@ func (s *configSuite) TestValidateInvalidCredentials(c *gc.C) {
 	s.assertConfigInvalid(c, testing.Attrs{"subscription-id": ""}, `"subscription-id" config not specified`)
 }
 
+func (s *configSuite) TestValidateAuthType(c *gc.C) {
+	s.assertConfigValid(c, testing.Attrs{"auth-type": "service-principal"})
+	s.assertConfigInvalid(
+		c, testing.Attrs{"auth-type": "certificate"},
+		`invalid "auth-type" config "certificate", expected one of: \["service-principal" "managed-identity"\]`,
+	)
+}
+
+func (s *configSuite) TestValidateManagedIdentityCredentials(c *gc.C) {
+	// Only the subscription is needed to use a managed identity.
+	s.assertConfigValid(c, testing.Attrs{
+		"auth-type":            "managed-identity",
+		"application-id":       "",
+		"application-password": "",
+		"tenant-id":            "",
+	})
+	s.assertConfigInvalid(c, testing.Attrs{
+		"auth-type":       "managed-identity",
+		"subscription-id": "",
+	}, `"subscription-id" config not specified`)
+}
+
 func (s *configSuite) TestValidateStorageAccountTypeCantChange(c *gc.C) {
 	cfgOld := makeTestModelConfig(c, testing.Attrs{"storage-account-type": "Standard_LRS"})
 	_, err := s.provider.Validate(cfgOld, cfgOld)
@@ -87,6 +242,54 @@ func (s *configSuite) TestValidateStorageAccountTypeCantChange(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot change immutable "storage-account-type" config \(Standard_LRS -> Premium_LRS\)`)
 }
 
+func (s *configSuite) TestValidateApplicationPasswordCanChange(c *gc.C) {
+	cfgOld := makeTestModelConfig(c)
+	cfgNew := makeTestModelConfig(c, testing.Attrs{"application-password": "rotated"})
+	_, err := s.provider.Validate(cfgNew, cfgOld)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfgNew = makeTestModelConfig(c, testing.Attrs{
+		"application-password": "rotated",
+		"tenant-id":            "33333333-3333-3333-3333-333333333333",
+	})
+	_, err = s.provider.Validate(cfgNew, cfgOld)
+	c.Assert(err, gc.ErrorMatches, `cannot change immutable "tenant-id" config .*`)
+}
+
+func (s *configSuite) TestValidateUseManagedDisks(c *gc.C) {
+	s.assertConfigValid(c, testing.Attrs{"use-managed-disks": true})
+	s.assertConfigValid(c, testing.Attrs{"use-managed-disks": true, "storage-account-type": "Premium_LRS"})
+	// Managed disks don't need a storage account type.
+	s.assertConfigValid(c, testing.Attrs{"use-managed-disks": true, "storage-account-type": ""})
+	s.assertConfigInvalid(
+		c, testing.Attrs{"storage-account-type": ""},
+		`invalid storage account type "", expected one of: \["Standard_LRS" "Standard_GRS" "Standard_RAGRS" "Standard_ZRS" "Premium_LRS"\]`,
+	)
+}
+
+func (s *configSuite) TestValidateUseManagedDisksCantChange(c *gc.C) {
+	cfgOld := makeTestModelConfig(c, testing.Attrs{"use-managed-disks": true})
+	_, err := s.provider.Validate(cfgOld, cfgOld)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfgNew := makeTestModelConfig(c, testing.Attrs{"use-managed-disks": false})
+	_, err = s.provider.Validate(cfgNew, cfgOld)
+	c.Assert(err, gc.ErrorMatches, `cannot change immutable "use-managed-disks" config \(true -> false\)`)
+	_, err = s.provider.Validate(cfgOld, cfgNew)
+	c.Assert(err, gc.ErrorMatches, `cannot change immutable "use-managed-disks" config \(false -> true\)`)
+}
+
+func (s *configSuite) TestValidateUseManagedDisksMissingFromOldConfig(c *gc.C) {
+	// Models which predate use-managed-disks use unmanaged disks.
+	cfgOld := makeTestModelConfig(c)
+	_, err := s.provider.Validate(makeTestModelConfig(c, testing.Attrs{"use-managed-disks": false}), cfgOld)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfgNew := makeTestModelConfig(c, testing.Attrs{"use-managed-disks": true})
+	_, err = s.provider.Validate(cfgNew, cfgOld)
+	c.Assert(err, gc.ErrorMatches, `cannot change immutable "use-managed-disks" config \(false -> true\)`)
+}
+
 func (s *configSuite) assertConfigValid(c *gc.C, attrs testing.Attrs) {
 	cfg := makeTestModelConfig(c, attrs)
 	_, err := s.provider.Validate(cfg, nil)
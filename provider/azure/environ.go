@@ -5,7 +5,9 @@ package azure
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"sync"
@@ -36,6 +38,7 @@ import (
 	"github.com/juju/juju/instance"
 	jujunetwork "github.com/juju/juju/network"
 	internalazurestorage "github.com/juju/juju/provider/azure/internal/azurestorage"
+	"github.com/juju/juju/provider/azure/internal/imageutils"
 	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/tools"
@@ -65,10 +68,22 @@ type azureEnviron struct {
 	storage       storage.ManagementClient
 	network       network.ManagementClient
 	storageClient azurestorage.Client
+
+	// apiCallerMu guards apiCaller. It is separate from mu because
+	// API calls are made while mu is held.
+	apiCallerMu sync.Mutex
+	apiCaller   backoffAPIRequestCaller
 }
 
 var _ environs.Environ = (*azureEnviron)(nil)
 var _ state.Prechecker = (*azureEnviron)(nil)
+var _ environs.InstanceTagger = (*azureEnviron)(nil)
+var _ environs.InstanceTagReader = (*azureEnviron)(nil)
+var _ environs.NetworkAvailabilityChecker = (*azureEnviron)(nil)
+var _ environs.InstanceSuspender = (*azureEnviron)(nil)
+var _ environs.ProviderStatusReporter = (*azureEnviron)(nil)
+var _ environs.ImageCacher = (*azureEnviron)(nil)
+var _ environs.ConsoleLogger = (*azureEnviron)(nil)
 
 // newEnviron creates a new azureEnviron.
 func newEnviron(provider *azureEnvironProvider, cfg *config.Config) (*azureEnviron, error) {
@@ -77,8 +92,7 @@ func newEnviron(provider *azureEnvironProvider, cfg *config.Config) (*azureEnvir
 	if err != nil {
 		return nil, err
 	}
-	modelTag := names.NewModelTag(cfg.UUID())
-	env.resourceGroup = resourceGroupName(modelTag, cfg.Name())
+	env.resourceGroup = env.config.resourceGroup
 	env.envName = cfg.Name()
 	return &env, nil
 }
@@ -219,9 +233,9 @@ func createStorageAccount(
 
 // ControllerInstances is specified in the Environ interface.
 func (env *azureEnviron) ControllerInstances(controllerUUID string) ([]instance.Id, error) {
-	// controllers are tagged with tags.JujuIsController, so just
-	// list the instances in the controller resource group and pick
-	// those ones out.
+	// controllers are tagged with tags.JujuIsController and
+	// tags.JujuController, so just list the instances in the
+	// controller resource group and pick those ones out.
 	instances, err := env.allInstances(env.resourceGroup, true)
 	if err != nil {
 		return nil, err
@@ -229,9 +243,14 @@ func (env *azureEnviron) ControllerInstances(controllerUUID string) ([]instance.
 	var ids []instance.Id
 	for _, inst := range instances {
 		azureInstance := inst.(*azureInstance)
-		if toTags(azureInstance.Tags)[tags.JujuIsController] == "true" {
-			ids = append(ids, inst.Id())
+		instanceTags := toTags(azureInstance.Tags)
+		if instanceTags[tags.JujuIsController] != "true" {
+			continue
+		}
+		if instanceTags[tags.JujuController] != controllerUUID {
+			continue
 		}
+		ids = append(ids, inst.Id())
 	}
 	if len(ids) == 0 {
 		return nil, environs.ErrNoInstances
@@ -259,8 +278,23 @@ func (env *azureEnviron) SetConfig(cfg *config.Config) error {
 	if err != nil {
 		return err
 	}
+	if old != nil && sameCredentials(old, cfg) {
+		// Keep the existing token, along with any access token
+		// it has already acquired. If the credentials have
+		// changed, e.g. because the application password was
+		// rotated, the new token is used instead.
+		ecfg.token = env.config.token
+	}
 	env.config = ecfg
 
+	env.apiCallerMu.Lock()
+	env.apiCaller = backoffAPIRequestCaller{
+		clock:      env.provider.config.RetryClock,
+		maxRetries: ecfg.apiMaxRetries,
+		retryDelay: ecfg.apiRetryDelay,
+	}
+	env.apiCallerMu.Unlock()
+
 	// Initialise clients.
 	env.compute = compute.NewWithBaseURI(ecfg.endpoint, env.config.subscriptionId)
 	env.resources = resources.NewWithBaseURI(ecfg.endpoint, env.config.subscriptionId)
@@ -343,11 +377,27 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes is defined on the Environs interface.
+func (env *azureEnviron) InstanceTypes(cons constraints.Value) ([]instances.InstanceType, error) {
+	instanceTypes, err := env.getInstanceTypes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	allInstanceTypes := make([]instances.InstanceType, 0, len(instanceTypes))
+	for _, instanceType := range instanceTypes {
+		allInstanceTypes = append(allInstanceTypes, instanceType)
+	}
+	return instances.FilterInstanceTypes(allInstanceTypes, cons), nil
+}
+
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (env *azureEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
 	if placement != "" {
 		return fmt.Errorf("unknown placement directive: %s", placement)
 	}
+	if err := validateRootDisk(cons); err != nil {
+		return errors.Trace(err)
+	}
 	if !cons.HasInstanceType() {
 		return nil
 	}
@@ -374,6 +424,9 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	if args.ControllerUUID == "" {
 		return nil, errors.New("missing controller UUID")
 	}
+	if err := validateRootDisk(args.Constraints); err != nil {
+		return nil, errors.Trace(err)
+	}
 	// Get the required configuration and config-dependent information
 	// required to create the instance. We take the lock just once, to
 	// ensure we obtain all information based on the same configuration.
@@ -407,7 +460,11 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	}
 	env.mu.Unlock()
 
-	// Identify the instance type and image to provision.
+	// Identify the instance type and image to provision. The root
+	// disk is sized separately from the instance type, so the
+	// root-disk constraint plays no part in choosing the type.
+	instanceCons := args.Constraints
+	instanceCons.RootDisk = nil
 	instanceSpec, err := findInstanceSpec(
 		vmImagesClient,
 		instanceTypes,
@@ -415,13 +472,16 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 			Region:      location,
 			Series:      args.Tools.OneSeries(),
 			Arches:      args.Tools.Arches(),
-			Constraints: args.Constraints,
+			Constraints: instanceCons,
 		},
 		imageStream,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if args.Constraints.RootDisk != nil {
+		instanceSpec.InstanceType.RootDisk = *args.Constraints.RootDisk
+	}
 
 	// Pick tools by filtering the available tools down to the architecture of
 	// the image that will be provisioned.
@@ -718,6 +778,10 @@ func newStorageProfile(
 			),
 		},
 	}
+	if rootDisk := instanceSpec.InstanceType.RootDisk; rootDisk > defaultRootDiskSize {
+		// The OS disk is grown from the size of the image.
+		osDisk.DiskSizeGB = to.IntPtr(int(mibToGib(rootDisk)))
+	}
 	return &compute.StorageProfile{
 		ImageReference: &compute.ImageReference{
 			Publisher: to.StringPtr(publisher),
@@ -816,6 +880,60 @@ func (env *azureEnviron) StopInstances(ids ...instance.Id) error {
 	return nil
 }
 
+// SuspendInstances is specified in the environs.InstanceSuspender
+// interface. The virtual machines are deallocated, so that they no
+// longer incur compute charges, but their disks and network
+// interfaces are kept.
+func (env *azureEnviron) SuspendInstances(ids ...instance.Id) error {
+	env.mu.Lock()
+	vmClient := compute.VirtualMachinesClient{env.compute}
+	env.mu.Unlock()
+	for _, id := range ids {
+		logger.Debugf("deallocating virtual machine %q", id)
+		var result autorest.Response
+		if err := env.callAPI(func() (autorest.Response, error) {
+			var err error
+			result, err = vmClient.Deallocate(env.resourceGroup, string(id))
+			return result, err
+		}); err != nil {
+			if result.Response != nil && result.StatusCode == http.StatusNotFound {
+				return errors.NotFoundf("instance %q", id)
+			}
+			return errors.Annotatef(err, "deallocating virtual machine %q", id)
+		}
+	}
+	return nil
+}
+
+// ResumeInstances is specified in the environs.InstanceSuspender
+// interface.
+func (env *azureEnviron) ResumeInstances(ids ...instance.Id) ([]instance.Instance, error) {
+	env.mu.Lock()
+	vmClient := compute.VirtualMachinesClient{env.compute}
+	env.mu.Unlock()
+	for _, id := range ids {
+		logger.Debugf("starting virtual machine %q", id)
+		var result autorest.Response
+		if err := env.callAPI(func() (autorest.Response, error) {
+			var err error
+			result, err = vmClient.Start(env.resourceGroup, string(id))
+			return result, err
+		}); err != nil {
+			if result.Response != nil && result.StatusCode == http.StatusNotFound {
+				return nil, errors.NotFoundf("instance %q", id)
+			}
+			return nil, errors.Annotatef(err, "starting virtual machine %q", id)
+		}
+	}
+	// Query the instances again, as a deallocated virtual
+	// machine's dynamic public IP address may have changed.
+	instances, err := env.Instances(ids)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return instances, nil
+}
+
 // deleteInstances deletes a virtual machine and all of the resources that
 // it owns, and any corresponding network security rules.
 func deleteInstance(
@@ -1163,6 +1281,131 @@ func (env *azureEnviron) deleteResourceGroup(resourceGroup string) error {
 	return nil
 }
 
+// ProviderStatus is specified in the environs.ProviderStatusReporter
+// interface. The health is derived from the state of the model's
+// resource group: if Azure can't be queried the cloud is unavailable,
+// and if the group isn't in the "Succeeded" state it is degraded.
+func (env *azureEnviron) ProviderStatus() (environs.ProviderHealth, error) {
+	env.mu.Lock()
+	client := resources.GroupsClient{env.resources}
+	env.mu.Unlock()
+	var group resources.ResourceGroup
+	if err := env.callAPI(func() (autorest.Response, error) {
+		var err error
+		group, err = client.Get(env.resourceGroup)
+		return group.Response, err
+	}); err != nil {
+		return environs.ProviderHealth{
+			Status:   environs.HealthUnavailable,
+			Messages: []string{fmt.Sprintf("querying resource group: %v", err)},
+		}, nil
+	}
+	var state string
+	if group.Properties != nil {
+		state = to.String(group.Properties.ProvisioningState)
+	}
+	if state != "" && state != "Succeeded" {
+		return environs.ProviderHealth{
+			Status: environs.HealthDegraded,
+			Messages: []string{fmt.Sprintf(
+				"resource group %q is in state %q", env.resourceGroup, state,
+			)},
+		}, nil
+	}
+	return environs.ProviderHealth{Status: environs.HealthHealthy}, nil
+}
+
+// CacheImages is specified in the environs.ImageCacher interface.
+// Azure fetches marketplace images itself, so there is nothing to
+// cache; instead, the images for each series are checked to be
+// available in the model's location, so that missing images are
+// found before any instances are started.
+func (env *azureEnviron) CacheImages(series []string, imageArch string) error {
+	if imageArch != arch.AMD64 {
+		// Azure only supports AMD64.
+		return errors.NotSupportedf("%s images", imageArch)
+	}
+	env.mu.Lock()
+	location := env.config.location
+	imageStream := env.config.ImageStream()
+	client := compute.VirtualMachineImagesClient{env.compute}
+	env.mu.Unlock()
+	for _, seriesName := range series {
+		if _, err := imageutils.CheckSeriesImage(seriesName, imageStream, location, client); err != nil {
+			return errors.Annotatef(err, "checking %s image", seriesName)
+		}
+	}
+	return nil
+}
+
+// InstanceConsoleLog is specified in the environs.ConsoleLogger
+// interface. The serial console log is recorded by the virtual
+// machine's boot diagnostics, which are only available if they were
+// enabled with a storage URI in the model's storage account.
+func (env *azureEnviron) InstanceConsoleLog(id instance.Id) ([]byte, error) {
+	vm, err := env.getVirtualMachine(id, "instanceView")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var logURI string
+	if vm.Properties != nil && vm.Properties.InstanceView != nil {
+		if diagnostics := vm.Properties.InstanceView.BootDiagnostics; diagnostics != nil {
+			logURI = to.String(diagnostics.SerialConsoleLogBlobURI)
+		}
+	}
+	if logURI == "" {
+		return nil, errors.NotFoundf("console log for instance %q", id)
+	}
+
+	storageAccount, err := env.getStorageAccount(false)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting storage account")
+	}
+	container, blob, err := parseBlobURI(logURI, storageAccount)
+	if err != nil {
+		return nil, errors.Annotatef(err, "locating console log for instance %q", id)
+	}
+	storageClient, err := env.getStorageClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reader, err := storageClient.GetBlobService().GetBlob(container, blob)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading console log for instance %q", id)
+	}
+	defer reader.Close()
+	output, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading console log for instance %q", id)
+	}
+	return output, nil
+}
+
+// parseBlobURI returns the container and blob names from the URI of
+// a blob, which must be in the specified storage account.
+func parseBlobURI(blobURI string, storageAccount *storage.Account) (container, blob string, _ error) {
+	u, err := url.Parse(blobURI)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	var blobEndpoint string
+	if storageAccount.Properties != nil && storageAccount.Properties.PrimaryEndpoints != nil {
+		blobEndpoint = to.String(storageAccount.Properties.PrimaryEndpoints.Blob)
+	}
+	endpoint, err := url.Parse(blobEndpoint)
+	if err != nil || endpoint.Host == "" || endpoint.Host != u.Host {
+		return "", "", errors.NotSupportedf(
+			"blob %q outside storage account %q",
+			blobURI, to.String(storageAccount.Name),
+		)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("blob URI %q", blobURI)
+	}
+	return parts[0], parts[1], nil
+}
+
 var errNoFwGlobal = errors.New("global firewall mode is not supported")
 
 // OpenPorts is specified in the Environ interface. However, Azure does not
@@ -1182,6 +1425,85 @@ func (env *azureEnviron) Ports() ([]jujunetwork.PortRange, error) {
 	return nil, errNoFwGlobal
 }
 
+// reservedTags holds the names of the tags which Juju relies on to
+// identify its instances. These may not be changed by TagInstance.
+var reservedTags = set.NewStrings(
+	tags.JujuModel,
+	tags.JujuController,
+	tags.JujuIsController,
+	jujuMachineNameTag,
+)
+
+// InstanceTags is specified in the InstanceTagReader interface.
+func (env *azureEnviron) InstanceTags(id instance.Id) (map[string]string, error) {
+	vm, err := env.getVirtualMachine(id, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	vmTags := toTags(vm.Tags)
+	if vmTags == nil {
+		vmTags = make(map[string]string)
+	}
+	return vmTags, nil
+}
+
+// TagInstance is specified in the InstanceTagger interface. Existing
+// tags with the same names are replaced, and other tags are left
+// alone. An error satisfying errors.IsNotValid is returned if any of
+// the tags are reserved for use by Juju.
+func (env *azureEnviron) TagInstance(id instance.Id, newTags map[string]string) error {
+	for name := range newTags {
+		if reservedTags.Contains(name) {
+			return errors.NotValidf("changing reserved tag %q", name)
+		}
+	}
+	vm, err := env.getVirtualMachine(id, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	vmTags := toTags(vm.Tags)
+	if vmTags == nil {
+		vmTags = make(map[string]string)
+	}
+	for name, value := range newTags {
+		vmTags[name] = value
+	}
+	vm.Tags = toTagsPtr(vmTags)
+
+	env.mu.Lock()
+	vmClient := compute.VirtualMachinesClient{env.compute}
+	env.mu.Unlock()
+	if err := env.callAPI(func() (autorest.Response, error) {
+		result, err := vmClient.CreateOrUpdate(env.resourceGroup, string(id), vm)
+		return result.Response, err
+	}); err != nil {
+		return errors.Annotatef(err, "updating tags of virtual machine %q", id)
+	}
+	return nil
+}
+
+// getVirtualMachine returns the virtual machine with the given
+// instance ID, or an error satisfying errors.IsNotFound if there is
+// no such virtual machine. If expand is "instanceView", the virtual
+// machine's instance view is included.
+func (env *azureEnviron) getVirtualMachine(id instance.Id, expand string) (compute.VirtualMachine, error) {
+	env.mu.Lock()
+	vmClient := compute.VirtualMachinesClient{env.compute}
+	env.mu.Unlock()
+	var vm compute.VirtualMachine
+	if err := env.callAPI(func() (autorest.Response, error) {
+		var err error
+		vm, err = vmClient.Get(env.resourceGroup, string(id), expand)
+		return vm.Response, err
+	}); err != nil {
+		if vm.Response.Response != nil && vm.StatusCode == http.StatusNotFound {
+			return compute.VirtualMachine{}, errors.NotFoundf("instance %q", id)
+		}
+		return compute.VirtualMachine{}, errors.Annotatef(err, "getting virtual machine %q", id)
+	}
+	return vm, nil
+}
+
 // Provider is specified in the Environ interface.
 func (env *azureEnviron) Provider() environs.EnvironProvider {
 	return env.provider
@@ -1246,6 +1568,53 @@ func (env *azureEnviron) getInstanceTypesLocked() (map[string]instances.Instance
 	return instanceTypes, nil
 }
 
+// CheckNetworkAvailability is specified in the
+// environs.NetworkAvailabilityChecker interface. The CIDR is checked
+// against the address space and subnets of the model's virtual
+// network; if the virtual network hasn't been created yet, the CIDR
+// is free.
+func (env *azureEnviron) CheckNetworkAvailability(cidr string) error {
+	env.mu.Lock()
+	vnetClient := network.VirtualNetworksClient{env.network}
+	env.mu.Unlock()
+	var vnet network.VirtualNetwork
+	if err := env.callAPI(func() (autorest.Response, error) {
+		var err error
+		vnet, err = vnetClient.Get(env.resourceGroup, internalNetworkName)
+		return vnet.Response, err
+	}); err != nil {
+		if vnet.Response.Response != nil && vnet.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return errors.Annotatef(err, "getting virtual network %q", internalNetworkName)
+	}
+	if vnet.Properties == nil {
+		return nil
+	}
+
+	var inUse []string
+	if vnet.Properties.AddressSpace != nil && vnet.Properties.AddressSpace.AddressPrefixes != nil {
+		inUse = append(inUse, *vnet.Properties.AddressSpace.AddressPrefixes...)
+	}
+	if vnet.Properties.Subnets != nil {
+		for _, subnet := range *vnet.Properties.Subnets {
+			if subnet.Properties != nil && subnet.Properties.AddressPrefix != nil {
+				inUse = append(inUse, *subnet.Properties.AddressPrefix)
+			}
+		}
+	}
+	for _, prefix := range inUse {
+		overlap, err := jujunetwork.CIDRsOverlap(cidr, prefix)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if overlap {
+			return environs.NewNetworkConflictError(cidr, prefix)
+		}
+	}
+	return nil
+}
+
 // getInternalSubnetLocked queries the internal subnet for the environment.
 func (env *azureEnviron) getInternalSubnetLocked() (*network.Subnet, error) {
 	client := network.SubnetsClient{env.network}
@@ -1366,5 +1735,8 @@ func (env *azureEnviron) AgentMirror() (simplestreams.CloudSpec, error) {
 }
 
 func (env *azureEnviron) callAPI(f func() (autorest.Response, error)) error {
-	return backoffAPIRequestCaller{env.provider.config.RetryClock}.call(f)
+	env.apiCallerMu.Lock()
+	caller := env.apiCaller
+	env.apiCallerMu.Unlock()
+	return caller.call(f)
 }
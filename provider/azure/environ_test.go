@@ -6,10 +6,12 @@ package azure_test
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"path"
 	"reflect"
+	"strings"
 	"time"
 
 	autorestazure "github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/azure"
@@ -19,6 +21,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/arm/network"
 	"github.com/Azure/azure-sdk-for-go/arm/resources"
 	"github.com/Azure/azure-sdk-for-go/arm/storage"
+	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -39,6 +42,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/azure"
 	"github.com/juju/juju/provider/azure/internal/azuretesting"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/tools"
 	"github.com/juju/version"
@@ -499,6 +503,35 @@ func (s *environSuite) TestCloudEndpointManagementURI(c *gc.C) {
 	c.Assert(s.requests[0].URL.Host, gc.Equals, "api.azurestack.local")
 }
 
+func (s *environSuite) TestSetConfigRotateApplicationPassword(c *gc.C) {
+	env := s.openEnviron(c)
+
+	// With unchanged credentials, the token acquired when the environ
+	// was opened is kept, so listing instances makes one request.
+	err := env.SetConfig(makeTestModelConfig(c))
+	c.Assert(err, jc.ErrorIsNil)
+	listSender := mocks.NewSender()
+	listSender.EmitContent("{}")
+	s.sender = azuretesting.Senders{listSender}
+	_, err = env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// With a new application password, a new token is acquired
+	// with it.
+	err = env.SetConfig(makeTestModelConfig(c, testing.Attrs{
+		"application-password": "rotated",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	tokenSender := &recordingSender{Sender: tokenRefreshSender()}
+	s.sender = azuretesting.Senders{tokenSender, listSender}
+	_, err = env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokenSender.requests, gc.HasLen, 1)
+	err = tokenSender.requests[0].ParseForm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokenSender.requests[0].PostForm.Get("client_secret"), gc.Equals, "rotated")
+}
+
 func (s *environSuite) TestStartInstance(c *gc.C) {
 	env := s.openEnviron(c)
 	s.sender = s.startInstanceSenders(false)
@@ -526,6 +559,43 @@ func (s *environSuite) TestStartInstance(c *gc.C) {
 	c.Assert(availabilitySetName, gc.Equals, "juju")
 }
 
+func (s *environSuite) TestStartInstanceRootDisk(c *gc.C) {
+	env := s.openEnviron(c)
+	s.virtualMachine.Properties.StorageProfile.OsDisk.DiskSizeGB = to.IntPtr(50)
+	s.sender = s.startInstanceSenders(false)
+	s.requests = nil
+	params := makeStartInstanceParams(c, s.controllerUUID, "quantal")
+	params.Constraints = constraints.MustParse("root-disk=50G")
+	result, err := env.StartInstance(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result.Hardware.RootDisk, gc.Equals, uint64(50*1024))
+	s.assertStartInstanceRequests(c, s.requests)
+}
+
+func (s *environSuite) TestStartInstanceRootDiskInvalid(c *gc.C) {
+	env := s.openEnviron(c)
+	s.requests = nil
+	params := makeStartInstanceParams(c, s.controllerUUID, "quantal")
+	params.Constraints = constraints.MustParse("root-disk=10G")
+	_, err := env.StartInstance(params)
+	c.Assert(err, gc.ErrorMatches, "root-disk 10240M is smaller than the minimum of 29495M")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	params.Constraints = constraints.MustParse("root-disk=2T")
+	_, err = env.StartInstance(params)
+	c.Assert(err, gc.ErrorMatches, "root-disk 2097152M is larger than the maximum of 1047552M")
+	c.Assert(s.requests, gc.HasLen, 0)
+}
+
+func (s *environSuite) TestPrecheckInstanceRootDisk(c *gc.C) {
+	env := s.openEnviron(c)
+	prechecker := env.(state.Prechecker)
+	err := prechecker.PrecheckInstance("quantal", constraints.MustParse("root-disk=100G"), "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = prechecker.PrecheckInstance("quantal", constraints.MustParse("root-disk=1G"), "")
+	c.Assert(err, gc.ErrorMatches, "root-disk 1024M is smaller than the minimum of 29495M")
+}
+
 func (s *environSuite) TestStartInstanceTooManyRequests(c *gc.C) {
 	env := s.openEnviron(c)
 	senders := s.startInstanceSenders(false)
@@ -605,6 +675,59 @@ func (s *environSuite) TestStartInstanceTooManyRequestsTimeout(c *gc.C) {
 	})
 }
 
+func (s *environSuite) TestServiceUnavailableRetryAfter(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		retryAfterSender{"30"},
+		s.networkInterfacesSender(),
+	}
+
+	instances, err := env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 0)
+	c.Assert(s.requests, gc.HasLen, 2)
+
+	// The delay requested by the server is used in place
+	// of the configured delay.
+	s.retryClock.CheckCalls(c, []gitjujutesting.StubCall{
+		{"After", []interface{}{30 * time.Second}},
+	})
+}
+
+// retryAfterSender responds to all requests with a 503
+// (StatusServiceUnavailable) status and the given Retry-After header.
+type retryAfterSender struct {
+	retryAfter string
+}
+
+func (s retryAfterSender) Do(req *http.Request) (*http.Response, error) {
+	resp := mocks.NewResponseWithStatus("(」゜ロ゜)」", http.StatusServiceUnavailable)
+	resp.Header = http.Header{"Retry-After": []string{s.retryAfter}}
+	return resp, nil
+}
+
+func (s *environSuite) TestTooManyRequestsMaxRetries(c *gc.C) {
+	env := s.openEnviron(c, testing.Attrs{
+		"api-max-retries": 2,
+		"api-retry-delay": "1s",
+	})
+	rateLimitedSender := mocks.NewSender()
+	rateLimitedSender.EmitStatus("(」゜ロ゜)」", http.StatusTooManyRequests)
+	s.sender = azuretesting.Senders{
+		rateLimitedSender,
+		rateLimitedSender,
+		rateLimitedSender,
+	}
+
+	_, err := env.AllInstances()
+	c.Assert(err, gc.ErrorMatches, "attempt count exceeded: .*failed with.*")
+	c.Assert(s.requests, gc.HasLen, 3)
+	s.retryClock.CheckCalls(c, []gitjujutesting.StubCall{
+		{"After", []interface{}{1 * time.Second}},
+		{"After", []interface{}{2 * time.Second}},
+	})
+}
+
 func (s *environSuite) TestStartInstanceDistributionGroup(c *gc.C) {
 	c.Skip("TODO: test StartInstance's DistributionGroup behaviour")
 }
@@ -832,6 +955,43 @@ func (s *environSuite) TestStopInstances(c *gc.C) {
 	s.storageClient.CheckCall(c, 2, "DeleteBlobIfExists", "osvhds", "machine-1")
 }
 
+func (s *environSuite) TestSuspendResumeInstances(c *gc.C) {
+	env := s.openEnviron(c)
+	suspender := env.(environs.InstanceSuspender)
+
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/virtualMachines/machine-0/deallocate", nil),
+	}
+	err := suspender.SuspendInstances("machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Assert(s.requests[0].Method, gc.Equals, "POST")
+
+	s.requests = nil
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/virtualMachines/machine-0/start", nil),
+		s.networkInterfacesSender(makeNetworkInterface("nic-0", "machine-0")),
+		s.virtualMachinesSender(makeVirtualMachine("machine-0")),
+		s.publicIPAddressesSender(),
+	}
+	instances, err := suspender.ResumeInstances("machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+	c.Assert(instances[0].Id(), gc.Equals, instance.Id("machine-0"))
+	c.Assert(s.requests, gc.HasLen, 4)
+	c.Assert(s.requests[0].Method, gc.Equals, "POST")
+}
+
+func (s *environSuite) TestSuspendInstancesNotFound(c *gc.C) {
+	env := s.openEnviron(c)
+	sender := mocks.NewSender()
+	sender.EmitStatus("vm not found", http.StatusNotFound)
+	s.sender = azuretesting.Senders{sender}
+	err := env.(environs.InstanceSuspender).SuspendInstances("machine-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `instance "machine-0" not found`)
+}
+
 func (s *environSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	validator := s.constraintsValidator(c)
 	unsupported, err := validator.Validate(constraints.MustParse(
@@ -882,6 +1042,50 @@ func (s *environSuite) TestAgentMirror(c *gc.C) {
 	})
 }
 
+func (s *environSuite) TestControllerInstances(c *gc.C) {
+	makeController := func(name, controllerUUID string) compute.VirtualMachine {
+		vm := makeVirtualMachine(name)
+		vmTags := map[string]*string{
+			"juju-machine-name":    to.StringPtr(name),
+			"juju-is-controller":   to.StringPtr("true"),
+			"juju-controller-uuid": to.StringPtr(controllerUUID),
+		}
+		vm.Tags = &vmTags
+		return vm
+	}
+	vms := []compute.VirtualMachine{
+		makeController("machine-0", s.controllerUUID),
+		makeController("machine-1", utils.MustNewUUID().String()),
+		makeVirtualMachine("machine-2"),
+	}
+	nics := []network.Interface{
+		makeNetworkInterface("nic-0", "machine-0"),
+		makeNetworkInterface("nic-1", "machine-1"),
+		makeNetworkInterface("nic-2", "machine-2"),
+	}
+
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.networkInterfacesSender(nics...),
+		s.virtualMachinesSender(vms...),
+		s.publicIPAddressesSender(),
+	}
+	ids, err := env.ControllerInstances(s.controllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []instance.Id{"machine-0"})
+}
+
+func (s *environSuite) TestControllerInstancesNoneMatching(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.networkInterfacesSender(makeNetworkInterface("nic-0", "machine-0")),
+		s.virtualMachinesSender(makeVirtualMachine("machine-0")),
+		s.publicIPAddressesSender(),
+	}
+	_, err := env.ControllerInstances(s.controllerUUID)
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
 func (s *environSuite) TestDestroyHostedModel(c *gc.C) {
 	env := s.openEnviron(c, testing.Attrs{"controller-uuid": utils.MustNewUUID().String()})
 	s.sender = azuretesting.Senders{
@@ -967,3 +1171,262 @@ func (s *environSuite) TestDestroyControllerErrors(c *gc.C) {
 	c.Check(destroyErr, gc.ErrorMatches, ".*failed with foo.*")
 	c.Check(destroyErr, gc.ErrorMatches, ".*failed with bar.*")
 }
+
+func (s *environSuite) TestInstanceTags(c *gc.C) {
+	env := s.openEnviron(c)
+	vm := makeVirtualMachine("machine-0")
+	vmTags := map[string]*string{
+		"juju-model-uuid": to.StringPtr(testing.ModelTag.Id()),
+		"cost-center":     to.StringPtr("cc-1"),
+	}
+	vm.Tags = &vmTags
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/virtualMachines/machine-0", vm), // GET
+	}
+	s.requests = nil
+
+	instanceTags, err := env.(environs.InstanceTagReader).InstanceTags("machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceTags, jc.DeepEquals, map[string]string{
+		"juju-model-uuid": testing.ModelTag.Id(),
+		"cost-center":     "cc-1",
+	})
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Assert(s.requests[0].Method, gc.Equals, "GET")
+}
+
+func (s *environSuite) TestInstanceTagsNotFound(c *gc.C) {
+	env := s.openEnviron(c)
+	sender := mocks.NewSender()
+	sender.EmitStatus("vm not found", http.StatusNotFound)
+	s.sender = azuretesting.Senders{sender}
+
+	_, err := env.(environs.InstanceTagReader).InstanceTags("machine-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `instance "machine-0" not found`)
+}
+
+func (s *environSuite) TestInstanceConsoleLog(c *gc.C) {
+	env := s.openEnviron(c)
+	vm := makeVirtualMachine("machine-0")
+	vm.Properties.InstanceView = &compute.VirtualMachineInstanceView{
+		BootDiagnostics: &compute.BootDiagnosticsInstanceView{
+			SerialConsoleLogBlobURI: to.StringPtr(fmt.Sprintf(
+				"https://%s.blob.storage.azurestack.local/bootdiagnostics-machine0/machine-0.serialconsole.log",
+				fakeStorageAccount,
+			)),
+		},
+	}
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/virtualMachines/machine-0", vm), // GET
+		s.storageAccountsSender(),
+		s.storageAccountKeysSender(),
+	}
+	s.requests = nil
+	const consoleLog = "Cloud-init v. 0.7.8 running 'init'\nFailed running runcmd\n"
+	s.storageClient.GetBlobFunc = func(container, name string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(consoleLog)), nil
+	}
+
+	output, err := environs.InstanceConsoleLog(env, "machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(output), gc.Equals, consoleLog)
+	c.Assert(s.requests, gc.HasLen, 3)
+	c.Assert(s.requests[0].Method, gc.Equals, "GET")
+	c.Assert(s.requests[0].URL.Query().Get("$expand"), gc.Equals, "instanceView")
+	s.storageClient.CheckCallNames(c, "NewClient", "GetBlob")
+	s.storageClient.CheckCall(c, 1, "GetBlob", "bootdiagnostics-machine0", "machine-0.serialconsole.log")
+}
+
+func (s *environSuite) TestInstanceConsoleLogNoBootDiagnostics(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/virtualMachines/machine-0", makeVirtualMachine("machine-0")),
+	}
+	_, err := environs.InstanceConsoleLog(env, "machine-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `console log for instance "machine-0" not found`)
+}
+
+func (s *environSuite) TestInstanceConsoleLogOtherStorageAccount(c *gc.C) {
+	env := s.openEnviron(c)
+	vm := makeVirtualMachine("machine-0")
+	vm.Properties.InstanceView = &compute.VirtualMachineInstanceView{
+		BootDiagnostics: &compute.BootDiagnosticsInstanceView{
+			SerialConsoleLogBlobURI: to.StringPtr(
+				"https://elsewhere.blob.core.windows.net/bootdiagnostics/machine-0.serialconsole.log",
+			),
+		},
+	}
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/virtualMachines/machine-0", vm), // GET
+		s.storageAccountsSender(),
+	}
+	_, err := environs.InstanceConsoleLog(env, "machine-0")
+	c.Assert(err, gc.ErrorMatches, `locating console log for instance "machine-0": blob ".*" outside storage account "my-storage-account" not supported`)
+}
+
+func (s *environSuite) TestTagInstance(c *gc.C) {
+	env := s.openEnviron(c)
+	vm := makeVirtualMachine("machine-0")
+	vmTags := map[string]*string{
+		"juju-model-uuid": to.StringPtr(testing.ModelTag.Id()),
+		"cost-center":     to.StringPtr("cc-1"),
+		"owner":           to.StringPtr("bob"),
+	}
+	vm.Tags = &vmTags
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/virtualMachines/machine-0", vm), // GET
+		s.makeSender(".*/virtualMachines/machine-0", vm), // PUT
+	}
+	s.requests = nil
+
+	err := env.(environs.InstanceTagger).TagInstance("machine-0", map[string]string{
+		"cost-center": "cc-2",
+		"team":        "ops",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.requests, gc.HasLen, 2)
+	c.Assert(s.requests[0].Method, gc.Equals, "GET")
+	c.Assert(s.requests[1].Method, gc.Equals, "PUT")
+	var updated compute.VirtualMachine
+	unmarshalRequestBody(c, s.requests[1], &updated)
+	c.Assert(to.StringMap(*updated.Tags), jc.DeepEquals, map[string]string{
+		"juju-model-uuid": testing.ModelTag.Id(),
+		"cost-center":     "cc-2",
+		"owner":           "bob",
+		"team":            "ops",
+	})
+}
+
+func (s *environSuite) TestTagInstanceReservedTag(c *gc.C) {
+	env := s.openEnviron(c)
+	s.requests = nil
+	for _, name := range []string{
+		"juju-model-uuid",
+		"juju-controller-uuid",
+		"juju-is-controller",
+		"juju-machine-name",
+	} {
+		err := env.(environs.InstanceTagger).TagInstance("machine-0", map[string]string{
+			name:          "foo",
+			"cost-center": "cc-2",
+		})
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, `changing reserved tag "`+name+`" not valid`)
+	}
+	c.Assert(s.requests, gc.HasLen, 0)
+}
+
+func (s *environSuite) TestCheckNetworkAvailability(c *gc.C) {
+	env := s.openEnviron(c)
+	vnet := *s.vnet
+	vnetProperties := *vnet.Properties
+	vnetProperties.Subnets = &[]network.Subnet{{
+		Properties: &network.SubnetPropertiesFormat{
+			AddressPrefix: to.StringPtr("10.0.0.0/24"),
+		},
+	}}
+	vnet.Properties = &vnetProperties
+	checker := env.(environs.NetworkAvailabilityChecker)
+
+	s.sender = azuretesting.Senders{s.makeSender(".*/virtualNetworks/juju-internal-network", vnet)}
+	err := checker.CheckNetworkAvailability("10.1.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.sender = azuretesting.Senders{s.makeSender(".*/virtualNetworks/juju-internal-network", vnet)}
+	err = checker.CheckNetworkAvailability("10.0.0.128/25")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `CIDR "10.0.0.128/25" conflicts with existing network "10.0.0.0/16"`)
+}
+
+func (s *environSuite) TestCacheImages(c *gc.C) {
+	env := s.openEnviron(c)
+	s.requests = nil
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/Canonical/.*/UbuntuServer/skus", s.ubuntuServerSKUs),
+		s.makeSender(".*/MicrosoftWindowsServer/.*/WindowsServer/skus", []compute.VirtualMachineImageResource{
+			{Name: to.StringPtr("2012-Datacenter")},
+			{Name: to.StringPtr("2012-R2-Datacenter")},
+		}),
+	}
+	err := environs.CacheImages(env, []string{"xenial", "win2012r2"}, "amd64")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 2)
+}
+
+func (s *environSuite) TestCacheImagesNotPublished(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/OpenLogic/.*/CentOS/skus", []compute.VirtualMachineImageResource{
+			{Name: to.StringPtr("6.5")},
+		}),
+	}
+	err := environs.CacheImages(env, []string{"centos7"}, "amd64")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "checking centos7 image: image OpenLogic:CentOS:7.1:latest in westus not found")
+}
+
+func (s *environSuite) TestCacheImagesArchNotSupported(c *gc.C) {
+	env := s.openEnviron(c)
+	s.requests = nil
+	err := environs.CacheImages(env, []string{"xenial"}, "arm64")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(s.requests, gc.HasLen, 0)
+}
+
+func (s *environSuite) TestProviderStatusHealthy(c *gc.C) {
+	env := s.openEnviron(c)
+	group := *s.group
+	group.Properties = &resources.ResourceGroupProperties{
+		ProvisioningState: to.StringPtr("Succeeded"),
+	}
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/resourcegroups/juju-testenv-model-"+testing.ModelTag.Id(), group),
+	}
+	health, err := env.(environs.ProviderStatusReporter).ProviderStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, environs.ProviderHealth{Status: environs.HealthHealthy})
+}
+
+func (s *environSuite) TestProviderStatusDegraded(c *gc.C) {
+	env := s.openEnviron(c)
+	group := *s.group
+	group.Properties = &resources.ResourceGroupProperties{
+		ProvisioningState: to.StringPtr("Deleting"),
+	}
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/resourcegroups/juju-testenv-model-"+testing.ModelTag.Id(), group),
+	}
+	health, err := env.(environs.ProviderStatusReporter).ProviderStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, environs.ProviderHealth{
+		Status: environs.HealthDegraded,
+		Messages: []string{
+			`resource group "juju-testenv-model-` + testing.ModelTag.Id() + `" is in state "Deleting"`,
+		},
+	})
+}
+
+func (s *environSuite) TestProviderStatusUnavailable(c *gc.C) {
+	env := s.openEnviron(c)
+	sender := mocks.NewSender()
+	sender.EmitStatus("internal error", http.StatusInternalServerError)
+	s.sender = azuretesting.Senders{sender}
+	health, err := env.(environs.ProviderStatusReporter).ProviderStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Status, gc.Equals, environs.HealthUnavailable)
+	c.Assert(health.Messages, gc.HasLen, 1)
+	c.Assert(health.Messages[0], gc.Matches, "querying resource group: .*")
+}
+
+func (s *environSuite) TestCheckNetworkAvailabilityNoVirtualNetwork(c *gc.C) {
+	env := s.openEnviron(c)
+	sender := mocks.NewSender()
+	sender.EmitStatus("vnet not found", http.StatusNotFound)
+	s.sender = azuretesting.Senders{sender}
+
+	err := env.(environs.NetworkAvailabilityChecker).CheckNetworkAvailability("10.0.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -132,6 +132,9 @@ func (prov *azureEnvironProvider) PrepareForBootstrap(ctx environs.BootstrapCont
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := validateLocation(env.(*azureEnviron).config.location); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if ctx.ShouldVerifyCredentials() {
 		if err := verifyCredentials(env.(*azureEnviron)); err != nil {
 			return nil, errors.Trace(err)
@@ -149,6 +152,18 @@ func (prov *azureEnvironProvider) SecretAttrs(cfg *config.Config) (map[string]st
 	return secretAttrs, nil
 }
 
+// Capabilities is specified in the environs.CapabilitiesProvider interface.
+//
+// Azure Resource Manager instances are placed in availability sets
+// rather than availability zones. Managed disks are used if the model
+// is configured with use-managed-disks.
+func (prov *azureEnvironProvider) Capabilities() environs.ProviderCapabilities {
+	return environs.ProviderCapabilities{
+		AvailabilitySets: true,
+		ManagedDisks:     true,
+	}
+}
+
 // verifyCredentials issues a cheap, non-modifying request to Azure to
 // verify the configured credentials. If verification fails, a user-friendly
 // error will be returned, and the original error will be logged at debug
@@ -52,6 +52,14 @@ func fakeUserPassCredential() cloud.Credential {
 	)
 }
 
+func (s *environProviderSuite) TestCapabilities(c *gc.C) {
+	capabilities := environs.Capabilities(s.provider)
+	c.Assert(capabilities, gc.Equals, environs.ProviderCapabilities{
+		AvailabilitySets: true,
+		ManagedDisks:     true,
+	})
+}
+
 func (s *environProviderSuite) TestBootstrapConfig(c *gc.C) {
 	cfg := makeTestModelConfig(c)
 	s.sender = azuretesting.Senders{tokenRefreshSender()}
@@ -4,6 +4,11 @@
 package azure
 
 import (
+	"net/url"
+
+	autorestazure "github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/azure"
+	armstorage "github.com/Azure/azure-sdk-for-go/arm/storage"
+
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/storage"
 )
@@ -15,3 +20,39 @@ func ForceVolumeSourceTokenRefresh(vs storage.VolumeSource) error {
 func ForceTokenRefresh(env environs.Environ) error {
 	return env.(*azureEnviron).config.token.Refresh()
 }
+
+// Token is the interface of the tokens used to authorize Azure API
+// requests.
+type Token azureToken
+
+func NewManagedIdentityToken(clientId string) Token {
+	return newManagedIdentityToken(clientId, autorestazure.AzureResourceManagerScope)
+}
+
+func NewServicePrincipalToken(loginEndpoint, tenantId string) Token {
+	return newServicePrincipalToken(
+		loginEndpoint, tenantId, "app-id", "app-password",
+		autorestazure.AzureResourceManagerScope,
+	)
+}
+
+var KnownStorageAccountTypes = &knownStorageAccountTypes
+
+func StorageAccountTypes(sdkTypes func() []armstorage.AccountType) []string {
+	return storageAccountTypes(sdkTypes)
+}
+
+// CloudEndpoints returns the token scope and Active Directory login
+// endpoint of the Azure cloud with the given endpoints.
+func CloudEndpoints(endpoint, storageEndpoint string) (scope, login string, err error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+	storageEndpointURL, err := url.Parse(storageEndpoint)
+	if err != nil {
+		return "", "", err
+	}
+	cloud, err := cloudForEndpoints(endpointURL, storageEndpointURL)
+	return cloud.resourceManagerScope, cloud.activeDirectoryEndpoint, err
+}
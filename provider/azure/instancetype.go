@@ -4,6 +4,8 @@
 package azure
 
 import (
+	"fmt"
+
 	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/to"
 	"github.com/Azure/azure-sdk-for-go/arm/compute"
 	"github.com/juju/errors"
@@ -16,6 +18,17 @@ import (
 
 const defaultMem = 1024 // 1GiB
 
+const (
+	// defaultRootDiskSize is the size, in MiB, of the root disk of
+	// machines started from the published images, which are all
+	// ~30GiB. The root disk can't be made any smaller than the image.
+	defaultRootDiskSize uint64 = 29495
+
+	// maxRootDiskSize is the largest root disk, in MiB, that Azure
+	// supports.
+	maxRootDiskSize uint64 = 1023 * 1024
+)
+
 // newInstanceType creates an InstanceType based on a VirtualMachineSize.
 func newInstanceType(size compute.VirtualMachineSize) instances.InstanceType {
 	// We're not doing real costs for now; just made-up, relative
@@ -114,7 +127,9 @@ func newInstanceType(size compute.VirtualMachineSize) instances.InstanceType {
 		// determining the image size.
 		//
 		// All of the published images that we use are ~30GiB.
-		RootDisk: uint64(29495),
+		// A larger root disk may be requested with the root-disk
+		// constraint; see validateRootDisk.
+		RootDisk: defaultRootDiskSize,
 		Cost:     uint64(cost),
 		VirtType: &vtype,
 		// tags are not currently supported by azure
@@ -152,6 +167,26 @@ func findInstanceSpec(
 	return instances.FindInstanceSpec(images, constraint, instanceTypes)
 }
 
+// validateRootDisk returns an error if the root-disk constraint, if
+// any, can't be satisfied by Azure.
+func validateRootDisk(cons constraints.Value) error {
+	if cons.RootDisk == nil {
+		return nil
+	}
+	size := *cons.RootDisk
+	if size < defaultRootDiskSize {
+		return errors.NewNotValid(nil, fmt.Sprintf(
+			"root-disk %dM is smaller than the minimum of %dM", size, defaultRootDiskSize,
+		))
+	}
+	if size > maxRootDiskSize {
+		return errors.NewNotValid(nil, fmt.Sprintf(
+			"root-disk %dM is larger than the maximum of %dM", size, maxRootDiskSize,
+		))
+	}
+	return nil
+}
+
 func constraintHasArch(constraint *instances.InstanceConstraint, arch string) bool {
 	for _, constraintArch := range constraint.Arches {
 		if constraintArch == arch {
@@ -4,6 +4,8 @@
 package azurestorage
 
 import (
+	"io"
+
 	"github.com/Azure/azure-sdk-for-go/storage"
 	"github.com/juju/errors"
 )
@@ -34,6 +36,12 @@ type BlobStorageClient interface {
 	//
 	// See https://godoc.org/github.com/Azure/azure-sdk-for-go/storage#BlobStorageClient.DeleteBlobIfExists
 	DeleteBlobIfExists(container, name string) (bool, error)
+
+	// GetBlob returns a stream to read the blob. Caller must call
+	// Close() on the reader to close on the underlying connection.
+	//
+	// See https://godoc.org/github.com/Azure/azure-sdk-for-go/storage#BlobStorageClient.GetBlob
+	GetBlob(container, name string) (io.ReadCloser, error)
 }
 
 // NewClientFunc is the type of the NewClient function.
@@ -4,6 +4,8 @@
 package azuretesting
 
 import (
+	"io"
+
 	"github.com/Azure/azure-sdk-for-go/storage"
 	"github.com/juju/testing"
 
@@ -15,6 +17,7 @@ type MockStorageClient struct {
 
 	ListBlobsFunc          func(container string, _ storage.ListBlobsParameters) (storage.BlobListResponse, error)
 	DeleteBlobIfExistsFunc func(container, name string) (bool, error)
+	GetBlobFunc            func(container, name string) (io.ReadCloser, error)
 }
 
 // NewClient exists to satisfy users who want a NewClientFunc.
@@ -48,3 +51,11 @@ func (c *MockStorageClient) DeleteBlobIfExists(container, name string) (bool, er
 	}
 	return false, c.NextErr()
 }
+
+func (c *MockStorageClient) GetBlob(container, name string) (io.ReadCloser, error) {
+	c.MethodCall(c, "GetBlob", container, name)
+	if c.GetBlobFunc != nil {
+		return c.GetBlobFunc(container, name)
+	}
+	return nil, c.NextErr()
+}
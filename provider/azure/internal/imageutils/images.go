@@ -108,6 +108,39 @@ func SeriesImage(
 	}, nil
 }
 
+// CheckSeriesImage returns the image for the specified series, image
+// stream and location, like SeriesImage, after checking that the
+// image's SKU is published in the location. Ubuntu SKUs are already
+// checked by SeriesImage; the others are fixed, so may not have been
+// published everywhere.
+func CheckSeriesImage(
+	series, stream, location string,
+	client compute.VirtualMachineImagesClient,
+) (*instances.Image, error) {
+	image, err := SeriesImage(series, stream, location, client)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	parts := strings.Split(image.Id, ":")
+	publisher, offering, sku := parts[0], parts[1], parts[2]
+	if publisher == ubuntuPublisher {
+		return image, nil
+	}
+	logger.Debugf("listing SKUs: Location=%s, Publisher=%s, Offer=%s", location, publisher, offering)
+	result, err := client.ListSkus(location, publisher, offering)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing %s SKUs", offering)
+	}
+	if result.Value != nil {
+		for _, result := range *result.Value {
+			if to.String(result.Name) == sku {
+				return image, nil
+			}
+		}
+	}
+	return nil, errors.NotFoundf("image %s in %s", image.Id, location)
+}
+
 // ubuntuSKU returns the best SKU for the Canonical:UbuntuServer offering,
 // matching the given series.
 func ubuntuSKU(series, stream, location string, client compute.VirtualMachineImagesClient) (string, error) {
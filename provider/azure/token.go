@@ -0,0 +1,199 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/azure"
+	"github.com/juju/errors"
+)
+
+// azureToken is the interface of the tokens used to authorize
+// Azure Resource Manager API requests.
+type azureToken interface {
+	autorest.Authorizer
+
+	// SetSender sets the sender used to acquire tokens.
+	SetSender(autorest.Sender)
+
+	// EnsureFresh acquires a new token if the current one has
+	// expired, or is about to.
+	EnsureFresh() error
+
+	// Refresh acquires a new token.
+	Refresh() error
+}
+
+var _ azureToken = (*oauthToken)(nil)
+
+const (
+	// managedIdentityAPIVersion is the version of the instance
+	// metadata identity API that is used to acquire tokens.
+	managedIdentityAPIVersion = "2018-02-01"
+
+	// tokenRefreshWithin is how long before a token expires that a
+	// new one is acquired.
+	tokenRefreshWithin = 5 * time.Minute
+)
+
+// managedIdentityEndpoint is the instance metadata endpoint from which
+// managed identity tokens are acquired. It is only reachable from
+// Azure virtual machines.
+var managedIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// oauthToken is an azureToken which is acquired with the acquire
+// function when first needed, and acquired again when it is about to
+// expire.
+type oauthToken struct {
+	acquire func(autorest.Sender) (azure.Token, error)
+
+	mu     sync.Mutex
+	sender autorest.Sender
+	token  azure.Token
+}
+
+// newManagedIdentityToken returns a new token for the given resource,
+// acquired from the instance metadata service for the managed
+// identity of the Azure virtual machine that the controller is
+// running on. If clientId is non-empty, it identifies the
+// user-assigned managed identity to use; otherwise the
+// system-assigned identity is used.
+func newManagedIdentityToken(clientId, resource string) *oauthToken {
+	return &oauthToken{
+		acquire: func(sender autorest.Sender) (azure.Token, error) {
+			return acquireManagedIdentityToken(sender, clientId, resource)
+		},
+		sender: &http.Client{},
+	}
+}
+
+// newServicePrincipalToken returns a new token for the given
+// resource, acquired for a service principal from the tenant's OAuth
+// token endpoint at the given Active Directory login endpoint.
+func newServicePrincipalToken(loginEndpoint, tenantId, appId, appPassword, resource string) *oauthToken {
+	tokenURL := strings.TrimSuffix(loginEndpoint, "/") + "/" + url.QueryEscape(tenantId) + "/oauth2/token"
+	return &oauthToken{
+		acquire: func(sender autorest.Sender) (azure.Token, error) {
+			return acquireServicePrincipalToken(sender, tokenURL, appId, appPassword, resource)
+		},
+		sender: &http.Client{},
+	}
+}
+
+// SetSender is part of the azureToken interface.
+func (t *oauthToken) SetSender(sender autorest.Sender) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sender = sender
+}
+
+// EnsureFresh is part of the azureToken interface.
+func (t *oauthToken) EnsureFresh() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token.AccessToken != "" && !t.token.WillExpireIn(tokenRefreshWithin) {
+		return nil
+	}
+	return t.refresh()
+}
+
+// Refresh is part of the azureToken interface.
+func (t *oauthToken) Refresh() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.refresh()
+}
+
+func (t *oauthToken) refresh() error {
+	token, err := t.acquire(t.sender)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	t.token = token
+	return nil
+}
+
+// WithAuthorization is part of the autorest.Authorizer interface. A
+// new token is acquired before the request is sent, if necessary.
+func (t *oauthToken) WithAuthorization() autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil {
+				return r, err
+			}
+			if err := t.EnsureFresh(); err != nil {
+				return r, errors.Trace(err)
+			}
+			t.mu.Lock()
+			accessToken := t.token.AccessToken
+			t.mu.Unlock()
+			if r.Header == nil {
+				r.Header = make(http.Header)
+			}
+			r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+			return r, nil
+		})
+	}
+}
+
+func acquireManagedIdentityToken(sender autorest.Sender, clientId, resource string) (azure.Token, error) {
+	query := url.Values{
+		"api-version": {managedIdentityAPIVersion},
+		"resource":    {resource},
+	}
+	if clientId != "" {
+		query.Set("client_id", clientId)
+	}
+	req, err := http.NewRequest("GET", managedIdentityEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return azure.Token{}, errors.Trace(err)
+	}
+	req.Header.Set("Metadata", "true")
+	return sendTokenRequest(sender, req, "managed identity")
+}
+
+func acquireServicePrincipalToken(sender autorest.Sender, tokenURL, appId, appPassword, resource string) (azure.Token, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {appId},
+		"client_secret": {appPassword},
+		"resource":      {resource},
+	}
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return azure.Token{}, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return sendTokenRequest(sender, req, "service principal")
+}
+
+// sendTokenRequest sends a request for an OAuth token and decodes the
+// token from the response. The kind of token is used in errors.
+func sendTokenRequest(sender autorest.Sender, req *http.Request, kind string) (azure.Token, error) {
+	resp, err := sender.Do(req)
+	if err != nil {
+		return azure.Token{}, errors.Annotatef(err, "acquiring %s token", kind)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return azure.Token{}, errors.Errorf("acquiring %s token: %s", kind, resp.Status)
+	}
+	var token azure.Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return azure.Token{}, errors.Annotatef(err, "decoding %s token", kind)
+	}
+	if token.AccessToken == "" {
+		return azure.Token{}, errors.Errorf("acquiring %s token: no access token returned", kind)
+	}
+	return token, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/mocks"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/azure"
+	"github.com/juju/juju/provider/azure/internal/azuretesting"
+	"github.com/juju/juju/testing"
+)
+
+type tokenSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&tokenSuite{})
+
+// recordingSender records the requests sent through it.
+type recordingSender struct {
+	autorest.Sender
+	requests []*http.Request
+}
+
+func (s *recordingSender) Do(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+	return s.Sender.Do(req)
+}
+
+// statusSender responds to every request with an empty response with
+// its status code.
+type statusSender int
+
+func (s statusSender) Do(req *http.Request) (*http.Response, error) {
+	code := int(s)
+	return mocks.NewResponseWithStatus(fmt.Sprintf("%d %s", code, http.StatusText(code)), code), nil
+}
+
+// oauthTokenSender responds to requests for the given path with a
+// token which expires after the given duration.
+func oauthTokenSender(pathPattern string, expiresIn time.Duration) *azuretesting.MockSender {
+	sender := azuretesting.NewSenderWithValue(&autorestazure.Token{
+		AccessToken: "access-token",
+		ExpiresOn:   fmt.Sprint(time.Now().Add(expiresIn).Unix()),
+		Type:        "Bearer",
+	})
+	sender.PathPattern = pathPattern
+	return sender
+}
+
+func managedIdentityTokenSender(expiresIn time.Duration) *azuretesting.MockSender {
+	return oauthTokenSender("/metadata/identity/oauth2/token", expiresIn)
+}
+
+func (s *tokenSuite) TestManagedIdentityTokenAuthorizes(c *gc.C) {
+	sender := &recordingSender{Sender: managedIdentityTokenSender(time.Hour)}
+	token := azure.NewManagedIdentityToken("")
+	token.SetSender(sender)
+
+	for i := 0; i < 2; i++ {
+		req, err := autorest.Prepare(&http.Request{}, token.WithAuthorization())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(req.Header.Get("Authorization"), gc.Equals, "Bearer access-token")
+	}
+	// The token is acquired once, and reused while it is fresh.
+	c.Assert(sender.requests, gc.HasLen, 1)
+	req := sender.requests[0]
+	c.Assert(req.Header.Get("Metadata"), gc.Equals, "true")
+	c.Assert(req.URL.Query().Get("resource"), gc.Equals, autorestazure.AzureResourceManagerScope)
+	c.Assert(req.URL.Query().Get("api-version"), gc.Not(gc.Equals), "")
+	c.Assert(req.URL.Query()["client_id"], gc.HasLen, 0)
+}
+
+func (s *tokenSuite) TestManagedIdentityTokenRefreshesExpiring(c *gc.C) {
+	sender := &recordingSender{Sender: managedIdentityTokenSender(time.Minute)}
+	token := azure.NewManagedIdentityToken("")
+	token.SetSender(sender)
+
+	c.Assert(token.EnsureFresh(), jc.ErrorIsNil)
+	c.Assert(token.EnsureFresh(), jc.ErrorIsNil)
+	c.Assert(sender.requests, gc.HasLen, 2)
+}
+
+func (s *tokenSuite) TestManagedIdentityTokenClientId(c *gc.C) {
+	sender := &recordingSender{Sender: managedIdentityTokenSender(time.Hour)}
+	token := azure.NewManagedIdentityToken("client-id")
+	token.SetSender(sender)
+
+	c.Assert(token.Refresh(), jc.ErrorIsNil)
+	c.Assert(sender.requests, gc.HasLen, 1)
+	c.Assert(sender.requests[0].URL.Query().Get("client_id"), gc.Equals, "client-id")
+}
+
+func (s *tokenSuite) TestManagedIdentityTokenError(c *gc.C) {
+	token := azure.NewManagedIdentityToken("")
+	token.SetSender(statusSender(http.StatusForbidden))
+
+	err := token.Refresh()
+	c.Assert(err, gc.ErrorMatches, "acquiring managed identity token: 403 Forbidden")
+}
+
+func (s *tokenSuite) TestServicePrincipalToken(c *gc.C) {
+	sender := &recordingSender{Sender: oauthTokenSender("/tenant-id/oauth2/token", time.Hour)}
+	token := azure.NewServicePrincipalToken("https://login.chinacloudapi.cn/", "tenant-id")
+	token.SetSender(sender)
+
+	req, err := autorest.Prepare(&http.Request{}, token.WithAuthorization())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(req.Header.Get("Authorization"), gc.Equals, "Bearer access-token")
+
+	// The token is acquired from the cloud's login endpoint.
+	c.Assert(sender.requests, gc.HasLen, 1)
+	tokenReq := sender.requests[0]
+	c.Assert(tokenReq.URL.String(), gc.Equals, "https://login.chinacloudapi.cn/tenant-id/oauth2/token")
+	err = tokenReq.ParseForm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokenReq.PostForm.Get("grant_type"), gc.Equals, "client_credentials")
+	c.Assert(tokenReq.PostForm.Get("client_id"), gc.Equals, "app-id")
+	c.Assert(tokenReq.PostForm.Get("client_secret"), gc.Equals, "app-password")
+	c.Assert(tokenReq.PostForm.Get("resource"), gc.Equals, autorestazure.AzureResourceManagerScope)
+}
+
+func (s *tokenSuite) TestServicePrincipalTokenError(c *gc.C) {
+	token := azure.NewServicePrincipalToken("https://login.microsoftonline.com/", "tenant-id")
+	token.SetSender(statusSender(http.StatusUnauthorized))
+
+	err := token.Refresh()
+	c.Assert(err, gc.ErrorMatches, "acquiring service principal token: 401 Unauthorized")
+}
@@ -6,19 +6,21 @@ package azure
 import (
 	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/azure-sdk-for-go/Godeps/_workspace/src/github.com/Azure/go-autorest/autorest/to"
-	"github.com/juju/retry"
+	"github.com/juju/errors"
 	"github.com/juju/utils"
 	"github.com/juju/utils/clock"
 )
 
 const (
-	retryDelay       = 5 * time.Second
-	maxRetryDelay    = 1 * time.Minute
-	maxRetryDuration = 5 * time.Minute
+	defaultAPIMaxRetries = 10
+	defaultAPIRetryDelay = 5 * time.Second
+	maxRetryDelay        = 1 * time.Minute
+	maxRetryDuration     = 5 * time.Minute
 )
 
 func toTagsPtr(tags map[string]string) *map[string]*string {
@@ -62,30 +64,73 @@ type callAPIFunc func(func() (autorest.Response, error)) error
 // be used as a callAPIFunc.
 type backoffAPIRequestCaller struct {
 	clock clock.Clock
+
+	// maxRetries is the maximum number of times that a rate-limited
+	// request will be retried.
+	maxRetries int
+
+	// retryDelay is the delay before the first retry. The delay is
+	// doubled after each retry, up to maxRetryDelay.
+	retryDelay time.Duration
 }
 
 // call will call the supplied function, with exponential backoff
-// as long as the request returns an http.StatusTooManyRequests
-// status.
+// as long as the request returns an http.StatusTooManyRequests or
+// http.StatusServiceUnavailable status. If the response carries a
+// Retry-After header, the delay it specifies is used instead of the
+// backoff delay.
 func (c backoffAPIRequestCaller) call(f func() (autorest.Response, error)) error {
-	var resp *http.Response
-	return retry.Call(retry.CallArgs{
-		Func: func() error {
-			autorestResp, err := f()
-			resp = autorestResp.Response
+	start := c.clock.Now()
+	delay := c.retryDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := f()
+		if err == nil {
+			return nil
+		}
+		if !isRetryableResponse(resp.Response) {
 			return err
-		},
-		IsFatalError: func(err error) bool {
-			return resp == nil || !autorest.ResponseHasStatusCode(resp, http.StatusTooManyRequests)
-		},
-		NotifyFunc: func(err error, attempt int) {
-			logger.Debugf("attempt %d: %v", attempt, err)
-		},
-		Attempts:    -1,
-		Delay:       retryDelay,
-		MaxDelay:    maxRetryDelay,
-		MaxDuration: maxRetryDuration,
-		BackoffFunc: retry.DoubleDelay,
-		Clock:       c.clock,
-	})
+		}
+		logger.Debugf("attempt %d: %v", attempt, err)
+		if attempt > c.maxRetries {
+			return errors.Annotate(err, "attempt count exceeded")
+		}
+		wait := retryAfter(resp.Response, c.clock.Now(), delay)
+		if c.clock.Now().Sub(start)+wait > maxRetryDuration {
+			return errors.Annotate(err, "max duration exceeded")
+		}
+		<-c.clock.After(wait)
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// isRetryableResponse reports whether or not the given response
+// indicates that the request was rejected due to rate-limiting or
+// a temporary service outage, and so may be retried.
+func isRetryableResponse(resp *http.Response) bool {
+	return resp != nil && autorest.ResponseHasStatusCode(
+		resp, http.StatusTooManyRequests, http.StatusServiceUnavailable,
+	)
+}
+
+// retryAfter returns the delay requested by the response's
+// Retry-After header, which may hold either a number of seconds or
+// an HTTP date. If the header is missing or invalid, defaultDelay is
+// returned.
+func retryAfter(resp *http.Response, now time.Time, defaultDelay time.Duration) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return defaultDelay
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultDelay
 }
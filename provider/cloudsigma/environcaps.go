@@ -4,8 +4,11 @@
 package cloudsigma
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/provider/common"
 )
@@ -50,6 +53,12 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes returns an error satisfying errors.IsNotSupported, as
+// CloudSigma servers are sized by constraints, not by instance types.
+func (env *environ) InstanceTypes(constraints.Value) ([]instances.InstanceType, error) {
+	return nil, errors.NotSupportedf("instance types")
+}
+
 // SupportNetworks returns whether the environment has support to
 // specify networks for applications and machines.
 func (env *environ) SupportNetworks() bool {
@@ -6,6 +6,8 @@ package common
 import (
 	"sort"
 
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 )
@@ -131,6 +133,31 @@ func AvailabilityZoneAllocations(env ZonedEnviron, group []instance.Id) ([]Avail
 	return zoneInstances, nil
 }
 
+// InstanceAvailabilityZone returns the name of the availability zone
+// the specified instance is running in, so that callers can verify the
+// actual distribution of instances. An empty name is returned if the
+// provider doesn't know the instance's zone. If the environment
+// doesn't support availability zones, an error satisfying
+// errors.IsNotSupported is returned.
+func InstanceAvailabilityZone(env environs.Environ, id instance.Id) (string, error) {
+	zonedEnv, ok := env.(ZonedEnviron)
+	if !ok {
+		return "", errors.NotSupportedf("availability zones")
+	}
+	zones, err := zonedEnv.InstanceAvailabilityZoneNames([]instance.Id{id})
+	switch err {
+	case nil:
+	case environs.ErrNoInstances:
+		return "", errors.NotFoundf("instance %q", id)
+	default:
+		return "", errors.Trace(err)
+	}
+	if len(zones) != 1 {
+		return "", errors.Errorf("expected 1 zone, got %d", len(zones))
+	}
+	return zones[0], nil
+}
+
 var internalAvailabilityZoneAllocations = AvailabilityZoneAllocations
 
 // DistributeInstances is a common function for implement the
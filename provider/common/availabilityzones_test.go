@@ -6,6 +6,7 @@ package common_test
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -254,3 +255,44 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		c.Assert(eligible, jc.SameContents, test.eligible)
 	}
 }
+
+func (s *AvailabilityZoneSuite) TestInstanceAvailabilityZone(c *gc.C) {
+	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
+		c.Assert(ids, gc.DeepEquals, []instance.Id{"inst1"})
+		return []string{"az1"}, nil
+	})
+	zone, err := common.InstanceAvailabilityZone(&s.env, "inst1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, gc.Equals, "az1")
+}
+
+func (s *AvailabilityZoneSuite) TestInstanceAvailabilityZoneUnknown(c *gc.C) {
+	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
+		return []string{""}, nil
+	})
+	zone, err := common.InstanceAvailabilityZone(&s.env, "inst1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zone, gc.Equals, "")
+}
+
+func (s *AvailabilityZoneSuite) TestInstanceAvailabilityZoneNotFound(c *gc.C) {
+	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
+		return nil, environs.ErrNoInstances
+	})
+	_, err := common.InstanceAvailabilityZone(&s.env, "inst9")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `instance "inst9" not found`)
+}
+
+func (s *AvailabilityZoneSuite) TestInstanceAvailabilityZoneError(c *gc.C) {
+	s.PatchValue(&s.env.instanceAvailabilityZoneNames, func(ids []instance.Id) ([]string, error) {
+		return nil, fmt.Errorf("oh noes")
+	})
+	_, err := common.InstanceAvailabilityZone(&s.env, "inst1")
+	c.Assert(err, gc.ErrorMatches, "oh noes")
+}
+
+func (s *AvailabilityZoneSuite) TestInstanceAvailabilityZoneNotSupported(c *gc.C) {
+	_, err := common.InstanceAvailabilityZone(&mockEnviron{}, "inst1")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -137,7 +137,7 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 		fmt.Fprintf(ctx.GetStderr(), "%s      \r", info)
 		return nil
 	}
-	result, err := env.StartInstance(environs.StartInstanceParams{
+	result, err := startInstance(ctx, env, environs.StartInstanceParams{
 		ControllerUUID: args.ControllerConfig.ControllerUUID(),
 		Constraints:    args.BootstrapConstraints,
 		Tools:          availableTools,
@@ -171,6 +171,50 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	return result, selectedSeries, finalize, nil
 }
 
+// startInstance starts the bootstrap instance, aborting if the
+// bootstrap context is cancelled. StartInstance can't be interrupted,
+// so if the context is cancelled while the instance is being started,
+// startInstance waits for it to start and then stops it.
+func startInstance(
+	ctx environs.BootstrapContext,
+	env environs.Environ,
+	args environs.StartInstanceParams,
+) (*environs.StartInstanceResult, error) {
+	cancelled := ctx.StdContext().Done()
+	select {
+	case <-cancelled:
+		return nil, errors.Trace(ctx.StdContext().Err())
+	default:
+	}
+
+	type startResult struct {
+		result *environs.StartInstanceResult
+		err    error
+	}
+	started := make(chan startResult, 1)
+	go func() {
+		result, err := env.StartInstance(args)
+		started <- startResult{result, err}
+	}()
+	var r startResult
+	select {
+	case r = <-started:
+	case <-cancelled:
+		fmt.Fprintln(ctx.GetStderr(), "Bootstrap cancelled: waiting to clean up instance")
+		r = <-started
+	}
+	if err := ctx.StdContext().Err(); err != nil {
+		if r.err == nil {
+			id := r.result.Instance.Id()
+			if err := env.StopInstances(id); err != nil {
+				logger.Errorf("cannot stop cancelled bootstrap instance %q: %v", id, err)
+			}
+		}
+		return nil, errors.Trace(err)
+	}
+	return r.result, r.err
+}
+
 // FinishBootstrap completes the bootstrap process by connecting
 // to the instance via SSH and carrying out the cloud-config.
 //
@@ -186,6 +230,21 @@ var FinishBootstrap = func(
 	interrupted := make(chan os.Signal, 1)
 	ctx.InterruptNotify(interrupted)
 	defer ctx.StopInterruptNotify(interrupted)
+
+	// Cancelling the bootstrap context interrupts the wait too.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.StdContext().Done():
+			select {
+			case interrupted <- os.Interrupt:
+			default:
+			}
+		case <-done:
+		}
+	}()
+
 	addr, err := WaitSSH(
 		ctx.GetStderr(),
 		interrupted,
@@ -13,9 +13,11 @@ import (
 	"github.com/juju/utils/series"
 	"github.com/juju/utils/ssh"
 	"github.com/juju/version"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -136,6 +138,87 @@ func (s *BootstrapSuite) TestCannotStartInstance(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot start bootstrap instance: meh, not started")
 }
 
+func (s *BootstrapSuite) TestCancelledWhileStartingInstance(c *gc.C) {
+	s.PatchValue(&jujuversion.Current, coretesting.FakeVersionNumber)
+	bootstrapCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	starting := make(chan struct{})
+	go func() {
+		select {
+		case <-starting:
+			cancel()
+		case <-time.After(coretesting.LongWait):
+			c.Errorf("timed out waiting for instance to start")
+		}
+	}()
+	startInstance := func(
+		string, constraints.Value, []string, tools.List, *instancecfg.InstanceConfig,
+	) (instance.Instance, *instance.HardwareCharacteristics, []network.InterfaceInfo, error) {
+		// Starting the instance takes until the bootstrap is cancelled.
+		close(starting)
+		<-bootstrapCtx.Done()
+		return &mockInstance{id: "i-bootstrap"}, &instance.HardwareCharacteristics{}, nil, nil
+	}
+	var stopped []instance.Id
+	stopInstances := func(ids []instance.Id) error {
+		stopped = append(stopped, ids...)
+		return nil
+	}
+	env := &mockEnviron{
+		storage:       newStorage(s, c),
+		startInstance: startInstance,
+		stopInstances: stopInstances,
+		config:        configGetter(c),
+	}
+
+	ctx := modelcmd.CancellableBootstrapContext(bootstrapCtx, coretesting.Context(c))
+	_, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AvailableTools: tools.List{
+			&tools.Tools{
+				Version: version.Binary{
+					Number: jujuversion.Current,
+					Arch:   arch.HostArch(),
+					Series: series.HostSeries(),
+				},
+			},
+		}})
+	c.Assert(err, gc.ErrorMatches, "cannot start bootstrap instance: context canceled")
+	c.Assert(stopped, jc.DeepEquals, []instance.Id{"i-bootstrap"})
+}
+
+func (s *BootstrapSuite) TestCancelledBeforeStartingInstance(c *gc.C) {
+	s.PatchValue(&jujuversion.Current, coretesting.FakeVersionNumber)
+	bootstrapCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+	}
+	env.startInstance = func(
+		string, constraints.Value, []string, tools.List, *instancecfg.InstanceConfig,
+	) (instance.Instance, *instance.HardwareCharacteristics, []network.InterfaceInfo, error) {
+		c.Fatalf("unexpected call to StartInstance")
+		return nil, nil, nil, nil
+	}
+
+	ctx := modelcmd.CancellableBootstrapContext(bootstrapCtx, coretesting.Context(c))
+	_, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AvailableTools: tools.List{
+			&tools.Tools{
+				Version: version.Binary{
+					Number: jujuversion.Current,
+					Arch:   arch.HostArch(),
+					Series: series.HostSeries(),
+				},
+			},
+		}})
+	c.Assert(err, gc.ErrorMatches, "cannot start bootstrap instance: context canceled")
+}
+
 func (s *BootstrapSuite) TestBootstrapSeries(c *gc.C) {
 	s.PatchValue(&jujuversion.Current, coretesting.FakeVersionNumber)
 	s.PatchValue(&series.HostSeries, func() string { return "precise" })
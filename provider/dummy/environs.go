@@ -52,6 +52,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/mongo/mongotest"
@@ -857,6 +858,35 @@ func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// instanceTypes holds the instance types offered by the dummy provider.
+var instanceTypes = []instances.InstanceType{{
+	Name:     "dummy.small",
+	Arches:   []string{arch.AMD64, arch.I386, arch.PPC64EL, arch.ARM64},
+	CpuCores: 1,
+	Mem:      1024,
+	Cost:     10,
+}, {
+	Name:     "dummy.medium",
+	Arches:   []string{arch.AMD64, arch.I386, arch.PPC64EL, arch.ARM64},
+	CpuCores: 2,
+	Mem:      4096,
+	Cost:     40,
+}, {
+	Name:     "dummy.large",
+	Arches:   []string{arch.AMD64, arch.ARM64},
+	CpuCores: 4,
+	Mem:      16384,
+	Cost:     160,
+}}
+
+// InstanceTypes is defined on the Environs interface.
+func (e *environ) InstanceTypes(cons constraints.Value) ([]instances.InstanceType, error) {
+	if err := e.checkBroken("InstanceTypes"); err != nil {
+		return nil, err
+	}
+	return instances.FilterInstanceTypes(instanceTypes, cons), nil
+}
+
 // MaintainInstance is specified in the InstanceBroker interface.
 func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
 	return nil
@@ -910,6 +940,10 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		series:       series,
 		firewallMode: e.Config().FirewallMode(),
 		state:        estate,
+		tags:         make(map[string]string),
+	}
+	for k, v := range args.InstanceConfig.Tags {
+		i.tags[k] = v
 	}
 
 	var hc *instance.HardwareCharacteristics
@@ -1018,6 +1052,56 @@ func (e *environ) StopInstances(ids ...instance.Id) error {
 	return nil
 }
 
+// TagInstance implements environs.InstanceTagger.
+func (e *environ) TagInstance(id instance.Id, tags map[string]string) error {
+	if err := e.checkBroken("TagInstance"); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	inst := estate.insts[id]
+	estate.mu.Unlock()
+	if inst == nil {
+		return errors.NotFoundf("instance %q", id)
+	}
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.tags == nil {
+		inst.tags = make(map[string]string)
+	}
+	for k, v := range tags {
+		inst.tags[k] = v
+	}
+	return nil
+}
+
+// InstanceTags implements environs.InstanceTagReader.
+func (e *environ) InstanceTags(id instance.Id) (map[string]string, error) {
+	if err := e.checkBroken("InstanceTags"); err != nil {
+		return nil, err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	inst := estate.insts[id]
+	estate.mu.Unlock()
+	if inst == nil {
+		return nil, errors.NotFoundf("instance %q", id)
+	}
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	tags := make(map[string]string)
+	for k, v := range inst.tags {
+		tags[k] = v
+	}
+	return tags, nil
+}
+
 func (e *environ) Instances(ids []instance.Id) (insts []instance.Instance, err error) {
 	defer delay()
 	if err := e.checkBroken("Instances"); err != nil {
@@ -1362,6 +1446,7 @@ type dummyInstance struct {
 	mu        sync.Mutex
 	addresses []network.Address
 	broken    []string
+	tags      map[string]string
 }
 
 func (inst *dummyInstance) Id() instance.Id {
@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
 	"github.com/juju/juju/environs/jujutest"
@@ -156,6 +157,65 @@ func (s *suite) TestAvailabilityZone(c *gc.C) {
 	c.Check(hwc.AvailabilityZone, gc.IsNil)
 }
 
+func (s *suite) TestInstanceTypes(c *gc.C) {
+	e := s.bootstrapTestEnviron(c)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	itypes, err := e.InstanceTypes(constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(itypes, gc.HasLen, 3)
+
+	itypes, err = e.InstanceTypes(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(itypes, gc.HasLen, 2)
+	c.Check(itypes[0].Name, gc.Equals, "dummy.medium")
+	c.Check(itypes[0].Mem, gc.Equals, uint64(4096))
+	c.Check(itypes[0].CpuCores, gc.Equals, uint64(2))
+	c.Check(itypes[0].Cost, gc.Equals, uint64(40))
+	c.Check(itypes[1].Name, gc.Equals, "dummy.large")
+
+	itypes, err = e.InstanceTypes(constraints.MustParse("mem=32G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(itypes, gc.HasLen, 0)
+}
+
+func (s *suite) TestTagInstances(c *gc.C) {
+	e := s.bootstrapTestEnviron(c)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	inst0, _ := jujutesting.AssertStartInstance(c, e, s.ControllerUUID, "0")
+	inst1, _ := jujutesting.AssertStartInstance(c, e, s.ControllerUUID, "1")
+	ids := []instance.Id{inst0.Id(), inst1.Id()}
+
+	err := environs.TagInstances(e, ids, map[string]string{"owner": "finance", "cost-center": "42"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = environs.TagInstances(e, ids[1:], map[string]string{"owner": "marketing"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The tags applied to each instance are recorded alongside the
+	// tags Juju gave it when it was started.
+	tagReader := e.(environs.InstanceTagReader)
+	tags, err := tagReader.InstanceTags(inst0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tags["owner"], gc.Equals, "finance")
+	c.Check(tags["cost-center"], gc.Equals, "42")
+	c.Check(tags["juju-model-uuid"], gc.Not(gc.Equals), "")
+	tags, err = tagReader.InstanceTags(inst1.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tags["owner"], gc.Equals, "marketing")
+	c.Check(tags["cost-center"], gc.Equals, "42")
+
+	err = environs.TagInstances(e, []instance.Id{"unknown"}, map[string]string{"owner": "finance"})
+	c.Assert(err, gc.ErrorMatches, `tagging instance "unknown": instance "unknown" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *suite) TestSupportsSpaces(c *gc.C) {
 	e := s.bootstrapTestEnviron(c)
 	defer func() {
@@ -187,6 +187,15 @@ func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes is defined on the Environs interface.
+func (e *environ) InstanceTypes(cons constraints.Value) ([]instances.InstanceType, error) {
+	itypes, err := regionInstanceTypes(e.ecfg().region())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return instances.FilterInstanceTypes(itypes, cons), nil
+}
+
 func archMatches(arches []string, arch *string) bool {
 	if arch == nil {
 		return true
@@ -606,6 +615,14 @@ func tagResources(e *ec2.EC2, tags map[string]string, resourceIds ...string) err
 	return err
 }
 
+// TagInstance implements environs.InstanceTagger.
+func (e *environ) TagInstance(id instance.Id, tags map[string]string) error {
+	if err := tagResources(e.ec2(), tags, string(id)); err != nil {
+		return errors.Annotate(err, "tagging instance")
+	}
+	return nil
+}
+
 func tagRootDisk(e *ec2.EC2, tags map[string]string, inst *ec2.Instance) error {
 	if len(tags) == 0 {
 		return nil
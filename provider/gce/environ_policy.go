@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/provider/common"
 )
@@ -112,6 +113,12 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes returns the GCE machine types which match the given
+// constraints.
+func (env *environ) InstanceTypes(cons constraints.Value) ([]instances.InstanceType, error) {
+	return instances.FilterInstanceTypes(allInstanceTypes, cons), nil
+}
+
 // SupportNetworks returns whether the environment has support to
 // specify networks for applications and machines.
 func (env *environ) SupportNetworks() bool {
@@ -82,6 +82,15 @@ func (env *joyentEnviron) ConstraintsValidator() (constraints.Validator, error)
 	return validator, nil
 }
 
+// InstanceTypes is defined on the Environs interface.
+func (env *joyentEnviron) InstanceTypes(cons constraints.Value) ([]instances.InstanceType, error) {
+	allInstanceTypes, err := env.listInstanceTypes()
+	if err != nil {
+		return nil, err
+	}
+	return instances.FilterInstanceTypes(allInstanceTypes, cons), nil
+}
+
 // MaintainInstance is specified in the InstanceBroker interface.
 func (*joyentEnviron) MaintainInstance(args environs.StartInstanceParams) error {
 	return nil
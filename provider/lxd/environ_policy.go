@@ -10,6 +10,7 @@ import (
 	"github.com/juju/utils/arch"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/instances"
 )
 
 // PrecheckInstance verifies that the provided series and constraints
@@ -61,6 +62,12 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes is specified in the environs.Environ interface. LXD
+// does not have instance types.
+func (env *environ) InstanceTypes(constraints.Value) ([]instances.InstanceType, error) {
+	return nil, errors.NotSupportedf("instance types")
+}
+
 // SupportNetworks returns whether the environment has support to
 // specify networks for applications and machines.
 func (env *environ) SupportNetworks() bool {
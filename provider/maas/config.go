@@ -25,6 +25,11 @@ var configSchema = environschema.Fields{
 		Description: "maas-oauth holds the OAuth credentials from MAAS.",
 		Type:        environschema.Tstring,
 	},
+	"maas-http-proxy": {
+		Description: "maas-http-proxy is an optional URL of the HTTP proxy through which the MAAS server is reached. If not set, the proxy is taken from the environment.",
+		Type:        environschema.Tstring,
+		Example:     "http://squid.internal:3128/",
+	},
 	"maas-agent-name": {
 		Description: "maas-agent-name is an optional UUID to group the instances acquired from MAAS, to support multiple models per MAAS user.",
 		Type:        environschema.Tstring,
@@ -43,6 +48,7 @@ var configDefaults = schema.Defaults{
 	// For backward-compatibility, maas-agent-name is the empty string
 	// by default. However, new environments should all use a UUID.
 	"maas-agent-name": "",
+	"maas-http-proxy": "",
 }
 
 type maasModelConfig struct {
@@ -58,6 +64,13 @@ func (cfg *maasModelConfig) maasOAuth() string {
 	return cfg.attrs["maas-oauth"].(string)
 }
 
+func (cfg *maasModelConfig) maasHTTPProxy() string {
+	if proxy, ok := cfg.attrs["maas-http-proxy"].(string); ok {
+		return proxy
+	}
+	return ""
+}
+
 func (cfg *maasModelConfig) maasAgentName() string {
 	if uuid, ok := cfg.attrs["maas-agent-name"].(string); ok {
 		return uuid
@@ -140,6 +153,11 @@ func (prov maasEnvironProvider) Validate(cfg, oldCfg *config.Config) (*config.Co
 	if strings.Count(oauth, ":") != 2 {
 		return nil, errMalformedMaasOAuth
 	}
+	if proxy := envCfg.maasHTTPProxy(); proxy != "" {
+		if _, err := parseHTTPProxy(proxy); err != nil {
+			return nil, err
+		}
+	}
 
 	return cfg.Apply(envCfg.attrs)
 }
@@ -4,6 +4,8 @@
 package maas
 
 import (
+	"net/http"
+
 	"github.com/juju/gomaasapi"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -53,7 +55,7 @@ func (s *configSuite) SetUpTest(c *gc.C) {
 		return set.NewStrings("network-deployment-ubuntu"), nil
 	}
 	s.PatchValue(&GetCapabilities, mockCapabilities)
-	mockGetController := func(maasServer, apiKey string) (gomaasapi.Controller, error) {
+	mockGetController := func(maasServer, apiKey string, httpClient *http.Client) (gomaasapi.Controller, error) {
 		return nil, gomaasapi.NewUnsupportedVersionError("oops")
 	}
 	s.PatchValue(&GetMAAS2Controller, mockGetController)
@@ -106,6 +108,34 @@ func (*configSuite) TestChecksWellFormedMaasOAuth(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, ".*malformed maas-oauth.*")
 }
 
+func (*configSuite) TestParsesMaasHTTPProxy(c *gc.C) {
+	ecfg, err := newConfig(map[string]interface{}{
+		"maas-server":     "http://maas.testing.invalid/maas/",
+		"maas-oauth":      "consumer-key:resource-token:resource-secret",
+		"maas-http-proxy": "http://squid.testing.invalid:3128/",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ecfg.maasHTTPProxy(), gc.Equals, "http://squid.testing.invalid:3128/")
+}
+
+func (*configSuite) TestMaasHTTPProxyDefault(c *gc.C) {
+	ecfg, err := newConfig(map[string]interface{}{
+		"maas-server": "http://maas.testing.invalid/maas/",
+		"maas-oauth":  "consumer-key:resource-token:resource-secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ecfg.maasHTTPProxy(), gc.Equals, "")
+}
+
+func (*configSuite) TestChecksWellFormedMaasHTTPProxy(c *gc.C) {
+	_, err := newConfig(map[string]interface{}{
+		"maas-server":     "http://maas.testing.invalid/maas/",
+		"maas-oauth":      "consumer-key:resource-token:resource-secret",
+		"maas-http-proxy": "ftp://squid.testing.invalid/",
+	})
+	c.Check(err, gc.ErrorMatches, `.*maas-http-proxy URL "ftp://squid.testing.invalid/" with scheme "ftp" not valid`)
+}
+
 func (*configSuite) TestBlockStorageProviderDefault(c *gc.C) {
 	ecfg, err := newConfig(map[string]interface{}{
 		"maas-server": "http://maas.testing.invalid/maas/",
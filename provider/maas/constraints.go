@@ -13,6 +13,7 @@ import (
 	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/network"
 )
 
@@ -34,6 +35,12 @@ func (environ *maasEnviron) ConstraintsValidator() (constraints.Validator, error
 	return validator, nil
 }
 
+// InstanceTypes is defined on the Environs interface. MAAS nodes are
+// acquired by constraints, so there are no instance types to list.
+func (environ *maasEnviron) InstanceTypes(constraints.Value) ([]instances.InstanceType, error) {
+	return nil, errors.NotSupportedf("instance types")
+}
+
 // convertConstraints converts the given constraints into an url.Values object
 // suitable to pass to MAAS when acquiring a node. CpuPower is ignored because
 // it cannot be translated into something meaningful for MAAS right now.
@@ -225,7 +225,19 @@ func (suite *environSuite) TestSelectNodeInvalidZone(c *gc.C) {
 
 	_, err := env.selectNode(snArgs)
 	c.Assert(err, gc.NotNil)
-	c.Assert(err, gc.ErrorMatches, `cannot run instances: ServerError: 409 Conflict \(\)`)
+	c.Assert(err, gc.ErrorMatches, `cannot run instances: no available node: ServerError: 409 Conflict \(\)`)
+}
+
+func (suite *environSuite) TestSelectNodeNoMatchingTags(c *gc.C) {
+	env := suite.makeEnviron()
+
+	snArgs := selectNodeArgs{
+		AvailabilityZones: []string{"foo"},
+		Constraints:       constraints.Value{Tags: stringslicep("gpu", "^virtual")},
+	}
+
+	_, err := env.selectNode(snArgs)
+	c.Assert(err, gc.ErrorMatches, `cannot run instances: no available node matches constraints "tags=gpu,\^virtual": ServerError: 409 Conflict \(\)`)
 }
 
 func (suite *environSuite) TestAcquireNode(c *gc.C) {
@@ -60,10 +60,11 @@ var (
 	GetMAAS2Controller   = getMAAS2Controller
 )
 
-func getMAAS2Controller(maasServer, apiKey string) (gomaasapi.Controller, error) {
+func getMAAS2Controller(maasServer, apiKey string, httpClient *http.Client) (gomaasapi.Controller, error) {
 	return gomaasapi.NewController(gomaasapi.ControllerArgs{
-		BaseURL: maasServer,
-		APIKey:  apiKey,
+		BaseURL:    maasServer,
+		APIKey:     apiKey,
+		HTTPClient: httpClient,
 	})
 }
 
@@ -102,6 +103,10 @@ type maasEnviron struct {
 }
 
 var _ environs.Environ = (*maasEnviron)(nil)
+var _ environs.NetworkAvailabilityChecker = (*maasEnviron)(nil)
+var _ environs.ProviderStatusReporter = (*maasEnviron)(nil)
+var _ environs.ImageCacher = (*maasEnviron)(nil)
+var _ environs.ConsoleLogger = (*maasEnviron)(nil)
 
 func NewEnviron(cfg *config.Config) (*maasEnviron, error) {
 	env := new(maasEnviron)
@@ -202,11 +207,16 @@ func (env *maasEnviron) SetConfig(cfg *config.Config) error {
 
 	env.ecfgUnlocked = ecfg
 
+	httpClient, err := newMAASHTTPClient(ecfg.maasHTTPProxy())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	// We need to know the version of the server we're on. We support 1.9
 	// and 2.0. MAAS 1.9 uses the 1.0 api version and 2.0 uses the 2.0 api
 	// version.
 	apiVersion := apiVersion2
-	controller, err := GetMAAS2Controller(ecfg.maasServer(), ecfg.maasOAuth())
+	controller, err := GetMAAS2Controller(ecfg.maasServer(), ecfg.maasOAuth(), httpClient)
 	switch {
 	case gomaasapi.IsUnsupportedVersionError(err):
 		apiVersion = apiVersion1
@@ -214,6 +224,7 @@ func (env *maasEnviron) SetConfig(cfg *config.Config) error {
 		if err != nil {
 			return errors.Trace(err)
 		}
+		authClient.HTTPClient = httpClient
 		env.maasClientUnlocked = gomaasapi.NewMAAS(*authClient)
 		caps, err := GetCapabilities(env.maasClientUnlocked)
 		if err != nil {
@@ -1150,11 +1161,12 @@ func (environ *maasEnviron) selectNode(args selectNodeArgs) (*gomaasapi.MAASObje
 			args.Volumes,
 		)
 
-		if err, ok := errors.Cause(err).(gomaasapi.ServerError); ok && err.StatusCode == http.StatusConflict {
+		if serverErr, ok := errors.Cause(err).(gomaasapi.ServerError); ok && serverErr.StatusCode == http.StatusConflict {
 			if i+1 < len(args.AvailabilityZones) {
 				logger.Infof("could not acquire a node in zone %q, trying another zone", zoneName)
 				continue
 			}
+			err = noMatchingNodeError(err, args.Constraints)
 		}
 		if err != nil {
 			return nil, errors.Errorf("cannot run instances: %v", err)
@@ -1184,6 +1196,7 @@ func (environ *maasEnviron) selectNode2(args selectNodeArgs) (maasInstance, erro
 				logger.Infof("could not acquire a node in zone %q, trying another zone", zoneName)
 				continue
 			}
+			err = noMatchingNodeError(err, args.Constraints)
 		}
 		if err != nil {
 			return nil, errors.Annotatef(err, "cannot run instance")
@@ -1195,6 +1208,17 @@ func (environ *maasEnviron) selectNode2(args selectNodeArgs) (maasInstance, erro
 	return inst, nil
 }
 
+// noMatchingNodeError annotates err, returned by MAAS when none of
+// its available nodes matches a request to acquire one, with the
+// constraints which were requested. Constraints such as tags are
+// easily mistyped, so they are the first thing to check.
+func noMatchingNodeError(err error, cons constraints.Value) error {
+	if consString := cons.String(); consString != "" {
+		return errors.Annotatef(err, "no available node matches constraints %q", consString)
+	}
+	return errors.Annotate(err, "no available node")
+}
+
 // setupJujuNetworking returns a string representing the script to run
 // in order to prepare the Juju-specific networking config on a node.
 func setupJujuNetworking() string {
@@ -1719,9 +1743,15 @@ func (environ *maasEnviron) Spaces() ([]network.SpaceInfo, error) {
 	return environ.spaces2()
 }
 
+// spaces1 returns the spaces known to a MAAS 1.x server. Servers
+// older than 1.9 have no spaces API, and so have no spaces.
 func (environ *maasEnviron) spaces1() ([]network.SpaceInfo, error) {
 	spacesClient := environ.getMAASClient().GetSubObject("spaces")
 	spacesJson, err := spacesClient.CallGet("", nil)
+	if maasErr, ok := errors.Cause(err).(gomaasapi.ServerError); ok && maasErr.StatusCode == http.StatusNotFound {
+		logger.Debugf("MAAS server does not support spaces: %v", err)
+		return []network.SpaceInfo{}, nil
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1794,6 +1824,183 @@ func (environ *maasEnviron) spaces2() ([]network.SpaceInfo, error) {
 	return result, nil
 }
 
+// CheckNetworkAvailability is specified in the
+// environs.NetworkAvailabilityChecker interface. The CIDR is checked
+// against every subnet in MAAS's inventory.
+func (environ *maasEnviron) CheckNetworkAvailability(cidr string) error {
+	spaces, err := environ.Spaces()
+	if err != nil {
+		return errors.Annotate(err, "getting subnets")
+	}
+	for _, space := range spaces {
+		for _, subnet := range space.Subnets {
+			overlap, err := network.CIDRsOverlap(cidr, subnet.CIDR)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if overlap {
+				return environs.NewNetworkConflictError(cidr, subnet.CIDR)
+			}
+		}
+	}
+	return nil
+}
+
+// ProviderStatus is specified in the environs.ProviderStatusReporter
+// interface. MAAS is unavailable if its API can't be queried. With
+// MAAS 1.x, it is degraded if it has no cluster controllers to manage
+// its nodes.
+func (environ *maasEnviron) ProviderStatus() (environs.ProviderHealth, error) {
+	if environ.usingMAAS2() {
+		if _, err := environ.maasController.Zones(); err != nil {
+			return environs.ProviderHealth{
+				Status:   environs.HealthUnavailable,
+				Messages: []string{fmt.Sprintf("querying MAAS: %v", err)},
+			}, nil
+		}
+		return environs.ProviderHealth{Status: environs.HealthHealthy}, nil
+	}
+
+	nodegroups, err := environ.getNodegroups()
+	if err != nil {
+		return environs.ProviderHealth{
+			Status:   environs.HealthUnavailable,
+			Messages: []string{fmt.Sprintf("querying MAAS cluster controllers: %v", err)},
+		}, nil
+	}
+	if len(nodegroups) == 0 {
+		return environs.ProviderHealth{
+			Status:   environs.HealthDegraded,
+			Messages: []string{"MAAS has no cluster controllers"},
+		}, nil
+	}
+	return environs.ProviderHealth{Status: environs.HealthHealthy}, nil
+}
+
+// CacheImages is specified in the environs.ImageCacher interface.
+// MAAS deploys nodes from the boot images it has imported, so any
+// missing images for the series and architecture are imported. MAAS
+// 1.x imports the images selected by its boot sources; with MAAS 2.0,
+// imports can't be started through the API that Juju uses, so missing
+// images are reported as an error satisfying errors.IsNotFound.
+func (environ *maasEnviron) CacheImages(seriesNames []string, arch string) error {
+	missing, err := environ.missingBootImages(seriesNames, arch)
+	if err != nil {
+		return errors.Annotate(err, "querying boot images")
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if environ.usingMAAS2() {
+		return errors.NotFoundf("MAAS boot images for %s", strings.Join(missing, ", "))
+	}
+	logger.Infof("importing MAAS boot images for %s", strings.Join(missing, ", "))
+	nodegroups := environ.getMAASClient().GetSubObject("nodegroups")
+	if _, err := nodegroups.CallPost("import_boot_images", nil); err != nil {
+		return errors.Annotate(err, "importing boot images")
+	}
+	return nil
+}
+
+// missingBootImages returns the "series/arch" names of the boot images
+// for the specified series and architecture which MAAS hasn't imported.
+func (environ *maasEnviron) missingBootImages(seriesNames []string, arch string) ([]string, error) {
+	imported := set.NewStrings()
+	if environ.usingMAAS2() {
+		resources, err := environ.maasController.BootResources()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, resource := range resources {
+			// Boot resource names are "os/series", and
+			// architectures are "arch/subarch".
+			nameParts := strings.Split(resource.Name(), "/")
+			resourceArch := strings.Split(resource.Architecture(), "/")[0]
+			imported.Add(nameParts[len(nameParts)-1] + "/" + resourceArch)
+		}
+	} else {
+		nodegroups, err := environ.getNodegroups()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, nodegroup := range nodegroups {
+			bootImages, err := environ.nodegroupBootImages(nodegroup)
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot get boot images for nodegroup %v", nodegroup)
+			}
+			for _, image := range bootImages {
+				imported.Add(image.release + "/" + image.architecture)
+			}
+		}
+	}
+	var missing []string
+	for _, seriesName := range seriesNames {
+		if name := seriesName + "/" + arch; !imported.Contains(name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+// InstanceConsoleLog is specified in the environs.ConsoleLogger
+// interface. MAAS doesn't keep the console output of nodes, so the
+// node's event log, which records its progress through power on,
+// PXE boot and deployment, is returned instead. Only the most recent
+// page of events is included.
+func (environ *maasEnviron) InstanceConsoleLog(id instance.Id) ([]byte, error) {
+	if environ.usingMAAS2() {
+		return nil, errors.NotSupportedf("node event logs with MAAS 2")
+	}
+	params := url.Values{
+		"id":    {extractSystemId(id)},
+		"level": {"DEBUG"},
+	}
+	result, err := environ.getMAASClient().GetSubObject("events").CallGet("query", params)
+	if err != nil {
+		return nil, errors.Annotatef(err, "querying events for instance %q", id)
+	}
+	output, err := formatNodeEvents(result)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading events for instance %q", id)
+	}
+	if len(output) == 0 {
+		return nil, errors.NotFoundf("events for instance %q", id)
+	}
+	return output, nil
+}
+
+// formatNodeEvents formats the result of a MAAS events query as a
+// log with a line per event, oldest first. MAAS returns the most
+// recent events first.
+func formatNodeEvents(result gomaasapi.JSONObject) ([]byte, error) {
+	resultMap, err := result.GetMap()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	events, err := resultMap["events"].GetArray()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var lines []string
+	for i := len(events) - 1; i >= 0; i-- {
+		event, err := events[i].GetMap()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// Missing fields are left empty rather than failing the
+		// whole log.
+		created, _ := event["created"].GetString()
+		eventType, _ := event["type"].GetString()
+		description, _ := event["description"].GetString()
+		line := created + " " + eventType
+		if description != "" {
+			line += ": " + description
+		}
+		lines = append(lines, line+"\n")
+	}
+	return []byte(strings.Join(lines, "")), nil
+}
+
 // Subnets returns basic information about the specified subnets known
 // by the provider for the specified instance. subnetIds must not be
 // empty. Implements NetworkingEnviron.Subnets.
@@ -4,6 +4,7 @@
 package maas_test
 
 import (
+	"net/http"
 	stdtesting "testing"
 
 	"github.com/juju/gomaasapi"
@@ -47,7 +48,7 @@ func (s *environSuite) SetUpTest(c *gc.C) {
 	mockCapabilities := func(client *gomaasapi.MAASObject) (set.Strings, error) {
 		return set.NewStrings("network-deployment-ubuntu"), nil
 	}
-	mockGetController := func(maasServer, apiKey string) (gomaasapi.Controller, error) {
+	mockGetController := func(maasServer, apiKey string, httpClient *http.Client) (gomaasapi.Controller, error) {
 		return nil, gomaasapi.NewUnsupportedVersionError("oops")
 	}
 	s.PatchValue(&maas.GetCapabilities, mockCapabilities)
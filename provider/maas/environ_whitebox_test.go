@@ -8,6 +8,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"regexp"
 
@@ -505,6 +507,43 @@ func (suite *environSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are: \\[amd64 armhf\\]")
 }
 
+func (suite *environSuite) TestCacheImagesAllImported(c *gc.C) {
+	suite.testMAASObject.TestServer.AddBootImage("uuid-0", `{"architecture": "amd64", "release": "trusty"}`)
+	suite.testMAASObject.TestServer.AddBootImage("uuid-1", `{"architecture": "amd64", "release": "xenial"}`)
+	env := suite.makeEnviron()
+	err := env.CacheImages([]string{"trusty", "xenial"}, "amd64")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+const nodeEventsJSON = `{
+    "count": 3,
+    "events": [
+        {"node": "node0", "level": "INFO", "created": "Mon, 14 Nov. 2016 10:03:12", "type": "Node changed status", "description": "From 'Deploying' to 'Failed deployment'"},
+        {"node": "node0", "level": "DEBUG", "created": "Mon, 14 Nov. 2016 10:02:40", "type": "PXE Request - installation"},
+        {"node": "node0", "level": "INFO", "created": "Mon, 14 Nov. 2016 10:01:02", "type": "Powering node on", "description": ""}
+    ]
+}`
+
+func (suite *environSuite) TestFormatNodeEvents(c *gc.C) {
+	result, err := gomaasapi.Parse(gomaasapi.Client{}, []byte(nodeEventsJSON))
+	c.Assert(err, jc.ErrorIsNil)
+	output, err := formatNodeEvents(result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(output), gc.Equals, `
+Mon, 14 Nov. 2016 10:01:02 Powering node on
+Mon, 14 Nov. 2016 10:02:40 PXE Request - installation
+Mon, 14 Nov. 2016 10:03:12 Node changed status: From 'Deploying' to 'Failed deployment'
+`[1:])
+}
+
+func (suite *environSuite) TestFormatNodeEventsNoEvents(c *gc.C) {
+	result, err := gomaasapi.Parse(gomaasapi.Client{}, []byte(`{"count": 0, "events": []}`))
+	c.Assert(err, jc.ErrorIsNil)
+	output, err := formatNodeEvents(result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.HasLen, 0)
+}
+
 func (suite *environSuite) TestSupportsNetworking(c *gc.C) {
 	env := suite.makeEnviron()
 	_, supported := environs.SupportsNetworking(env)
@@ -664,6 +703,23 @@ func (suite *environSuite) TestSpaces(c *gc.C) {
 	c.Assert(spaces, jc.DeepEquals, expectedSpaces)
 }
 
+func (suite *environSuite) TestSpacesNotSupported(c *gc.C) {
+	// MAAS servers older than 1.9 have no spaces API.
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	client, err := gomaasapi.NewAnonymousClient(server.URL, "1.0")
+	c.Assert(err, jc.ErrorIsNil)
+	env := suite.makeEnviron()
+	env.maasClientUnlocked = gomaasapi.NewMAAS(*client)
+
+	spaces, err := env.Spaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, gc.HasLen, 0)
+
+	err = env.CheckNetworkAvailability("192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (suite *environSuite) assertSpaces(c *gc.C, numberOfSubnets int, filters []network.Id) {
 	server := suite.testMAASObject.TestServer
 	testInstance := suite.getInstance("node1")
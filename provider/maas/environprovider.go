@@ -4,9 +4,9 @@
 package maas
 
 import (
-	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/gomaasapi"
@@ -59,13 +59,14 @@ func (p maasEnvironProvider) PrepareForCreateEnvironment(controllerUUID string,
 // BootstrapConfig is specified in the EnvironProvider interface.
 func (p maasEnvironProvider) BootstrapConfig(args environs.BootstrapConfigParams) (*config.Config, error) {
 	// For MAAS, the cloud endpoint may be either a full URL
-	// for the MAAS server, or just the IP/host.
+	// for the MAAS server, or just the IP/host, optionally with
+	// a port and path.
 	if args.CloudEndpoint == "" {
 		return nil, errors.New("MAAS server not specified")
 	}
-	server := args.CloudEndpoint
-	if url, err := url.Parse(server); err != nil || url.Scheme == "" {
-		server = fmt.Sprintf("http://%s/MAAS", args.CloudEndpoint)
+	server, err := maasServerURL(args.CloudEndpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
 
 	attrs := map[string]interface{}{
@@ -88,6 +89,32 @@ func (p maasEnvironProvider) BootstrapConfig(args environs.BootstrapConfigParams
 	return p.PrepareForCreateEnvironment(args.ControllerUUID, cfg)
 }
 
+// maasServerURL returns the URL of the MAAS server for the given
+// cloud endpoint. An endpoint with a scheme is used as it is, so that
+// servers proxied under another path may be specified; otherwise
+// http is assumed, along with the default /MAAS path if the endpoint
+// is just a host or host:port.
+func maasServerURL(endpoint string) (string, error) {
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return "", errors.Annotatef(err, "invalid MAAS server %q", endpoint)
+		}
+		if u.Host == "" {
+			return "", errors.NotValidf("MAAS server %q with no host", endpoint)
+		}
+		return endpoint, nil
+	}
+	u, err := url.Parse("http://" + endpoint)
+	if err != nil {
+		return "", errors.Annotatef(err, "invalid MAAS server %q", endpoint)
+	}
+	if u.Path == "" {
+		u.Path = "/MAAS"
+	}
+	return u.String(), nil
+}
+
 // PrepareForBootstrap is specified in the EnvironProvider interface.
 func (p maasEnvironProvider) PrepareForBootstrap(ctx environs.BootstrapContext, cfg *config.Config) (environs.Environ, error) {
 	env, err := p.Open(cfg)
@@ -104,12 +131,16 @@ func (p maasEnvironProvider) PrepareForBootstrap(ctx environs.BootstrapContext,
 
 func verifyCredentials(env *maasEnviron) error {
 	// Verify we can connect to the server and authenticate.
+	var err error
 	if env.usingMAAS2() {
-		// The maas2 controller verifies credentials at creation time.
-		return nil
+		// Creating the maas2 controller only reads the API version,
+		// which doesn't need authentication, so list the zones as
+		// a cheap authenticated call.
+		_, err = env.maasController.Zones()
+	} else {
+		_, err = env.getMAASClient().GetSubObject("maas").CallGet("get_config", nil)
 	}
-	_, err := env.getMAASClient().GetSubObject("maas").CallGet("get_config", nil)
-	if err, ok := errors.Cause(err).(gomaasapi.ServerError); ok && err.StatusCode == http.StatusUnauthorized {
+	if isUnauthorizedError(err) {
 		logger.Debugf("authentication failed: %v", err)
 		return errors.New(`authentication failed.
 
@@ -118,7 +149,30 @@ Please ensure the credentials are correct.`)
 	return nil
 }
 
-// SecretAttrs is specified in the EnvironProvider interface.
+// isUnauthorizedError reports whether err was caused by MAAS
+// rejecting a request with 401 Unauthorized. The maas2 controller
+// hides the ServerError behind its own errors, so the whole chain of
+// underlying errors is searched.
+func isUnauthorizedError(err error) bool {
+	for err != nil {
+		if serverErr, ok := errors.Cause(err).(gomaasapi.ServerError); ok {
+			return serverErr.StatusCode == http.StatusUnauthorized
+		}
+		wrapper, ok := err.(interface {
+			Underlying() error
+		})
+		if !ok {
+			return false
+		}
+		err = wrapper.Underlying()
+	}
+	return false
+}
+
+// SecretAttrs is specified in the EnvironProvider interface. The
+// maas-oauth attribute holds the whole API key, that is the consumer
+// key, the token key and the token secret separated by colons, which
+// is everything needed to reconstruct a client for MAAS 1.x or 2.0.
 func (prov maasEnvironProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	secretAttrs := make(map[string]string)
 	maasCfg, err := prov.newConfig(cfg)
@@ -133,3 +187,12 @@ func (prov maasEnvironProvider) SecretAttrs(cfg *config.Config) (map[string]stri
 func (p maasEnvironProvider) DetectRegions() ([]cloud.Region, error) {
 	return nil, errors.NotFoundf("regions")
 }
+
+// Capabilities is specified in the environs.CapabilitiesProvider interface.
+func (p maasEnvironProvider) Capabilities() environs.ProviderCapabilities {
+	return environs.ProviderCapabilities{
+		Spaces:            true,
+		SpaceDiscovery:    true,
+		AvailabilityZones: true,
+	}
+}
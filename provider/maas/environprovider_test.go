@@ -5,6 +5,7 @@ package maas
 
 import (
 	"io/ioutil"
+	"strings"
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -39,6 +40,33 @@ func (suite *EnvironProviderSuite) TestSecretAttrsReturnsSensitiveMAASAttributes
 	c.Check(secretAttrs, gc.DeepEquals, expectedAttrs)
 }
 
+func (suite *EnvironProviderSuite) TestSecretAttrsRoundTripsAPIKey(c *gc.C) {
+	const oauth = "consumer-key:token-key:token-secret"
+	attrs := testing.FakeConfig().Merge(testing.Attrs{
+		"type":        "maas",
+		"maas-oauth":  oauth,
+		"maas-server": "http://maas.testing.invalid/maas/",
+	})
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	secretAttrs, err := providerInstance.SecretAttrs(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Restore the secrets into config which doesn't have them, as
+	// when credentials are migrated between controllers.
+	restoredAttrs := attrs.Merge(testing.Attrs{"maas-oauth": "x:y:z"})
+	for k, v := range secretAttrs {
+		restoredAttrs[k] = v
+	}
+	restored, err := config.New(config.NoDefaults, restoredAttrs)
+	c.Assert(err, jc.ErrorIsNil)
+	ecfg, err := providerInstance.newConfig(restored)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Split(ecfg.maasOAuth(), ":"), jc.DeepEquals, []string{
+		"consumer-key", "token-key", "token-secret",
+	})
+}
+
 func (suite *EnvironProviderSuite) TestCredentialsSetup(c *gc.C) {
 	attrs := testing.FakeConfig().Merge(testing.Attrs{
 		"type": "maas",
@@ -96,15 +124,50 @@ func (suite *EnvironProviderSuite) TestUnknownAttrsContainAgentName(c *gc.C) {
 }
 
 func (suite *EnvironProviderSuite) TestMAASServerFromEndpoint(c *gc.C) {
+	for i, test := range []struct {
+		endpoint string
+		server   string
+	}{{
+		endpoint: "maas.testing",
+		server:   "http://maas.testing/MAAS",
+	}, {
+		endpoint: "maas.testing:5240",
+		server:   "http://maas.testing:5240/MAAS",
+	}, {
+		endpoint: "10.0.0.1:5240",
+		server:   "http://10.0.0.1:5240/MAAS",
+	}, {
+		endpoint: "maas.testing/infra/MAAS/",
+		server:   "http://maas.testing/infra/MAAS/",
+	}, {
+		endpoint: "http://maas.testing/MAAS/",
+		server:   "http://maas.testing/MAAS/",
+	}, {
+		endpoint: "https://maas.example/infra/MAAS/",
+		server:   "https://maas.example/infra/MAAS/",
+	}} {
+		c.Logf("test %d: %s", i, test.endpoint)
+		cfg, err := suite.bootstrapConfig(c, test.endpoint)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(cfg.UnknownAttrs()["maas-server"], gc.Equals, test.server)
+	}
+}
+
+func (suite *EnvironProviderSuite) TestMAASServerFromEndpointNoHost(c *gc.C) {
+	_, err := suite.bootstrapConfig(c, "http:///MAAS/")
+	c.Assert(err, gc.ErrorMatches, `MAAS server "http:///MAAS/" with no host not valid`)
+}
+
+func (suite *EnvironProviderSuite) bootstrapConfig(c *gc.C, endpoint string) (*config.Config, error) {
 	attrs := testing.FakeConfig().Merge(testing.Attrs{
 		"type": "maas",
 	})
 	config, err := config.New(config.NoDefaults, attrs)
 	c.Assert(err, jc.ErrorIsNil)
 
-	cfg, err := providerInstance.BootstrapConfig(environs.BootstrapConfigParams{
+	return providerInstance.BootstrapConfig(environs.BootstrapConfigParams{
 		Config:        config,
-		CloudEndpoint: "maas.testing",
+		CloudEndpoint: endpoint,
 		Credentials: cloud.NewCredential(
 			cloud.OAuth1AuthType,
 			map[string]string{
@@ -112,10 +175,6 @@ func (suite *EnvironProviderSuite) TestMAASServerFromEndpoint(c *gc.C) {
 			},
 		),
 	})
-	c.Assert(err, jc.ErrorIsNil)
-
-	unknownAttrs := cfg.UnknownAttrs()
-	c.Assert(unknownAttrs["maas-server"], gc.Equals, "http://maas.testing/MAAS")
 }
 
 func (suite *EnvironProviderSuite) TestPrepareSetsAgentName(c *gc.C) {
@@ -197,3 +256,12 @@ func (suite *EnvironProviderSuite) TestOpenReturnsNilInterfaceUponFailure(c *gc.
 	c.Check(env, gc.Equals, nil)
 	c.Check(err, gc.ErrorMatches, ".*malformed maas-oauth.*")
 }
+
+func (suite *EnvironProviderSuite) TestCapabilities(c *gc.C) {
+	capabilities := environs.Capabilities(providerInstance)
+	c.Assert(capabilities, gc.Equals, environs.ProviderCapabilities{
+		Spaces:            true,
+		SpaceDiscovery:    true,
+		AvailabilityZones: true,
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 
 	"github.com/juju/errors"
 	"github.com/juju/gomaasapi"
@@ -176,6 +178,49 @@ func (suite *maas2EnvironSuite) TestAvailabilityZonesError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "a bad thing")
 }
 
+func (suite *maas2EnvironSuite) TestVerifyCredentials(c *gc.C) {
+	controller := &fakeController{
+		zones: []gomaasapi.Zone{&fakeZone{name: "mossack"}},
+	}
+	env := suite.makeEnviron(c, controller)
+	err := verifyCredentials(env)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (suite *maas2EnvironSuite) TestVerifyCredentialsUnauthorized(c *gc.C) {
+	controller := &fakeController{
+		zonesError: unauthorizedError(c),
+	}
+	env := suite.makeEnviron(c, controller)
+	err := verifyCredentials(env)
+	c.Assert(err, gc.ErrorMatches, `authentication failed.
+
+Please ensure the credentials are correct.`)
+}
+
+func (suite *maas2EnvironSuite) TestVerifyCredentialsOtherError(c *gc.C) {
+	controller := &fakeController{
+		zonesError: errors.New("connection refused"),
+	}
+	env := suite.makeEnviron(c, controller)
+	err := verifyCredentials(env)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// unauthorizedError returns the error the maas2 controller reports
+// when MAAS rejects its credentials.
+func unauthorizedError(c *gc.C) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Authorization Error: 'Expired timestamp'", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client, err := gomaasapi.NewAnonymousClient(server.URL, "2.0")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Get(&url.URL{Path: "zones/"}, "", nil)
+	c.Assert(err, gc.NotNil)
+	return gomaasapi.NewUnexpectedError(errors.Trace(err))
+}
+
 func (suite *maas2EnvironSuite) TestSpaces(c *gc.C) {
 	controller := &fakeController{
 		spaces: []gomaasapi.Space{
@@ -211,6 +256,31 @@ func (suite *maas2EnvironSuite) TestSpaces(c *gc.C) {
 	c.Assert(subnets[1].SpaceProviderId, gc.Equals, network.Id("4567"))
 }
 
+func (suite *maas2EnvironSuite) TestSpacesTwoSpaces(c *gc.C) {
+	env := suite.makeEnviron(c, &fakeController{spaces: getTwoSpaces()})
+	result, err := env.Spaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []network.SpaceInfo{{
+		Name:       "foo",
+		ProviderId: "2",
+		Subnets: []network.SubnetInfo{{
+			ProviderId:      "99",
+			VLANTag:         66,
+			CIDR:            "192.168.10.0/24",
+			SpaceProviderId: "2",
+		}},
+	}, {
+		Name:       "bar",
+		ProviderId: "3",
+		Subnets: []network.SubnetInfo{{
+			ProviderId:      "100",
+			VLANTag:         66,
+			CIDR:            "192.168.11.0/24",
+			SpaceProviderId: "3",
+		}},
+	}})
+}
+
 func (suite *maas2EnvironSuite) TestSpacesError(c *gc.C) {
 	controller := &fakeController{
 		spacesError: errors.New("Joe Manginiello"),
@@ -220,6 +290,98 @@ func (suite *maas2EnvironSuite) TestSpacesError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "Joe Manginiello")
 }
 
+func (suite *maas2EnvironSuite) TestCheckNetworkAvailability(c *gc.C) {
+	controller := &fakeController{
+		spaces: []gomaasapi.Space{
+			fakeSpace{
+				name: "freckles",
+				id:   4567,
+				subnets: []gomaasapi.Subnet{
+					fakeSubnet{id: 99, vlan: fakeVLAN{vid: 66}, cidr: "192.168.10.0/24"},
+					fakeSubnet{id: 98, vlan: fakeVLAN{vid: 67}, cidr: "192.168.11.0/24"},
+				},
+			},
+		},
+	}
+	env := suite.makeEnviron(c, controller)
+
+	err := env.CheckNetworkAvailability("192.168.12.0/24")
+	c.Check(err, jc.ErrorIsNil)
+
+	err = env.CheckNetworkAvailability("192.168.0.0/16")
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Check(err, gc.ErrorMatches, `CIDR "192.168.0.0/16" conflicts with existing network "192.168.10.0/24"`)
+
+	err = env.CheckNetworkAvailability("192.168.11.128/25")
+	c.Check(err, gc.ErrorMatches, `CIDR "192.168.11.128/25" conflicts with existing network "192.168.11.0/24"`)
+}
+
+func (suite *maas2EnvironSuite) TestCheckNetworkAvailabilityError(c *gc.C) {
+	controller := &fakeController{
+		spacesError: errors.New("Joe Manginiello"),
+	}
+	env := suite.makeEnviron(c, controller)
+	err := env.CheckNetworkAvailability("192.168.12.0/24")
+	c.Assert(err, gc.ErrorMatches, "getting subnets: Joe Manginiello")
+}
+
+func (suite *maas2EnvironSuite) TestProviderStatusHealthy(c *gc.C) {
+	env := suite.makeEnviron(c, &fakeController{})
+	health, err := env.ProviderStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, environs.ProviderHealth{Status: environs.HealthHealthy})
+}
+
+func (suite *maas2EnvironSuite) TestProviderStatusUnavailable(c *gc.C) {
+	env := suite.makeEnviron(c, &fakeController{
+		zonesError: errors.New("connection refused"),
+	})
+	health, err := env.ProviderStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, environs.ProviderHealth{
+		Status:   environs.HealthUnavailable,
+		Messages: []string{"querying MAAS: connection refused"},
+	})
+}
+
+func (suite *maas2EnvironSuite) TestCacheImagesAllImported(c *gc.C) {
+	env := suite.makeEnviron(c, &fakeController{
+		bootResources: []gomaasapi.BootResource{
+			&fakeBootResource{name: "ubuntu/trusty", architecture: "amd64/generic"},
+			&fakeBootResource{name: "ubuntu/xenial", architecture: "amd64/hwe-x"},
+		},
+	})
+	err := env.CacheImages([]string{"trusty", "xenial"}, "amd64")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (suite *maas2EnvironSuite) TestCacheImagesMissing(c *gc.C) {
+	env := suite.makeEnviron(c, &fakeController{
+		bootResources: []gomaasapi.BootResource{
+			&fakeBootResource{name: "ubuntu/trusty", architecture: "amd64/generic"},
+			&fakeBootResource{name: "ubuntu/xenial", architecture: "arm64/generic"},
+		},
+	})
+	err := env.CacheImages([]string{"trusty", "xenial"}, "amd64")
+	c.Assert(err, gc.ErrorMatches, "MAAS boot images for xenial/amd64 not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (suite *maas2EnvironSuite) TestCacheImagesError(c *gc.C) {
+	env := suite.makeEnviron(c, &fakeController{
+		bootResourcesError: errors.New("boom"),
+	})
+	err := env.CacheImages([]string{"xenial"}, "amd64")
+	c.Assert(err, gc.ErrorMatches, "querying boot images: boom")
+}
+
+func (suite *maas2EnvironSuite) TestInstanceConsoleLogNotSupported(c *gc.C) {
+	env := suite.makeEnviron(c, &fakeController{})
+	_, err := env.InstanceConsoleLog("node0")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "node event logs with MAAS 2 not supported")
+}
+
 func collectReleaseArgs(controller *fakeController) []gomaasapi.ReleaseMachinesArgs {
 	args := []gomaasapi.ReleaseMachinesArgs{}
 	for _, call := range controller.Stub.Calls() {
@@ -1631,6 +1793,19 @@ func (suite *maas2EnvironSuite) TestBootstrapFailsIfNoNodes(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, ".*cannot run instances.*")
 }
 
+func (suite *maas2EnvironSuite) TestSelectNodeNoMatchingTags(c *gc.C) {
+	controller := newFakeController()
+	controller.allocateMachineError = gomaasapi.NewNoMatchError("no matching node")
+	env := suite.makeEnviron(c, controller)
+
+	_, err := env.selectNode2(selectNodeArgs{
+		AvailabilityZones: []string{"foo"},
+		Constraints:       constraints.Value{Tags: stringslicep("gpu", "^virtual")},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot run instance: no available node matches constraints "tags=gpu,\^virtual": no matching node`)
+	c.Assert(err, jc.Satisfies, gomaasapi.IsNoMatchError)
+}
+
 func (suite *maas2EnvironSuite) TestGetToolsMetadataSources(c *gc.C) {
 	// Add a dummy file to storage so we can use that to check the
 	// obtained source later.
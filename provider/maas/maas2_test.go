@@ -4,6 +4,8 @@
 package maas
 
 import (
+	"net/http"
+
 	"github.com/juju/errors"
 	"github.com/juju/gomaasapi"
 	"github.com/juju/testing"
@@ -21,7 +23,7 @@ type maas2Suite struct {
 }
 
 func (suite *maas2Suite) injectController(controller gomaasapi.Controller) {
-	mockGetController := func(maasServer, apiKey string) (gomaasapi.Controller, error) {
+	mockGetController := func(maasServer, apiKey string, httpClient *http.Client) (gomaasapi.Controller, error) {
 		return controller, nil
 	}
 	suite.PatchValue(&GetMAAS2Controller, mockGetController)
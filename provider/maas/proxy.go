@@ -0,0 +1,43 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maas
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// parseHTTPProxy parses and checks the value of maas-http-proxy.
+func parseHTTPProxy(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Annotatef(err, "malformed maas-http-proxy URL %q", proxy)
+	}
+	if proxyURL.Scheme != "http" && proxyURL.Scheme != "https" {
+		return nil, errors.NotValidf("maas-http-proxy URL %q with scheme %q", proxy, proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.NotValidf("maas-http-proxy URL %q with no host", proxy)
+	}
+	return proxyURL, nil
+}
+
+// newMAASHTTPClient returns the HTTP client with which an environ
+// talks to its MAAS server. Each environ gets its own transport, so
+// that maas-http-proxy affects only the requests for that environ. If
+// proxy is empty, the proxy is taken from the environment as usual.
+func newMAASHTTPClient(proxy string) (*http.Client, error) {
+	transport := utils.NewHttpTLSTransport(nil)
+	if proxy != "" {
+		proxyURL, err := parseHTTPProxy(proxy)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{Transport: transport}, nil
+}
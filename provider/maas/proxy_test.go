@@ -0,0 +1,129 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/juju/gomaasapi"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type proxySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&proxySuite{})
+
+const proxiedMAASServer = "http://maas.proxied.invalid/MAAS/"
+
+func (s *proxySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	mockCapabilities := func(client *gomaasapi.MAASObject) (set.Strings, error) {
+		return set.NewStrings("network-deployment-ubuntu"), nil
+	}
+	s.PatchValue(&GetCapabilities, mockCapabilities)
+	mockGetController := func(maasServer, apiKey string, httpClient *http.Client) (gomaasapi.Controller, error) {
+		return nil, gomaasapi.NewUnsupportedVersionError("oops")
+	}
+	s.PatchValue(&GetMAAS2Controller, mockGetController)
+}
+
+func (s *proxySuite) TestClientUsesProxy(c *gc.C) {
+	var requested []*url.URL
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer proxy.Close()
+
+	attrs := testing.FakeConfig().Merge(testing.Attrs{
+		"type":            "maas",
+		"maas-server":     proxiedMAASServer,
+		"maas-oauth":      "consumer-key:resource-token:resource-secret",
+		"maas-http-proxy": proxy.URL,
+	})
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := environs.New(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spaces, err := env.(*maasEnviron).Spaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, gc.HasLen, 0)
+	c.Assert(requested, gc.HasLen, 1)
+	c.Assert(requested[0].Host, gc.Equals, "maas.proxied.invalid")
+	c.Assert(requested[0].Path, gc.Matches, "/MAAS/api/1.0/spaces/?")
+}
+
+func (s *proxySuite) TestNewMAASHTTPClient(c *gc.C) {
+	client, err := newMAASHTTPClient("http://squid.invalid:3128/")
+	c.Assert(err, jc.ErrorIsNil)
+	transport, ok := client.Transport.(*http.Transport)
+	c.Assert(ok, jc.IsTrue)
+	// The environ's transport is its own, so the proxy doesn't affect
+	// other HTTP requests.
+	c.Assert(transport, gc.Not(gc.Equals), http.DefaultTransport)
+
+	req, err := http.NewRequest("GET", "http://maas.proxied.invalid/MAAS/api/2.0/version/", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	proxyURL, err := transport.Proxy(req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(proxyURL.String(), gc.Equals, "http://squid.invalid:3128/")
+}
+
+func (s *proxySuite) TestNewMAASHTTPClientNoProxy(c *gc.C) {
+	client, err := newMAASHTTPClient("")
+	c.Assert(err, jc.ErrorIsNil)
+	transport, ok := client.Transport.(*http.Transport)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(transport, gc.Not(gc.Equals), http.DefaultTransport)
+	// The proxy is taken from the environment.
+	c.Check(transport.Proxy, gc.NotNil)
+}
+
+func (s *proxySuite) TestNewMAASHTTPClientBadProxy(c *gc.C) {
+	_, err := newMAASHTTPClient("socks5://squid.invalid:1080")
+	c.Assert(err, gc.ErrorMatches, `maas-http-proxy URL "socks5://squid.invalid:1080" with scheme "socks5" not valid`)
+}
+
+func (s *proxySuite) TestParseHTTPProxy(c *gc.C) {
+	for _, test := range []struct {
+		proxy string
+		err   string
+	}{{
+		proxy: "http://squid.invalid:3128/",
+	}, {
+		proxy: "https://squid.invalid/",
+	}, {
+		proxy: "squid.invalid:3128",
+		err:   `maas-http-proxy URL "squid.invalid:3128" with scheme "squid.invalid" not valid`,
+	}, {
+		proxy: "socks5://squid.invalid:1080",
+		err:   `maas-http-proxy URL "socks5://squid.invalid:1080" with scheme "socks5" not valid`,
+	}, {
+		proxy: "http:///",
+		err:   `maas-http-proxy URL "http:///" with no host not valid`,
+	}, {
+		proxy: "http://squid.invalid:3128/%zz",
+		err:   `malformed maas-http-proxy URL .*`,
+	}} {
+		c.Logf("proxy %q", test.proxy)
+		_, err := parseHTTPProxy(test.proxy)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
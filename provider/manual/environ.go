@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/names"
@@ -259,6 +260,12 @@ func (e *manualEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes is defined on the Environs interface. Manual machines
+// are provisioned by the user, so there are no instance types.
+func (e *manualEnviron) InstanceTypes(constraints.Value) ([]instances.InstanceType, error) {
+	return nil, errors.NotSupportedf("instance types")
+}
+
 func (e *manualEnviron) OpenPorts(ports []network.PortRange) error {
 	return nil
 }
@@ -438,6 +438,34 @@ func (e *Environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes is defined on the Environs interface. The instance
+// types are constructed from the flavors supported by the deployment.
+func (e *Environ) InstanceTypes(cons constraints.Value) ([]instances.InstanceType, error) {
+	supportedArches, err := e.supportedArchitectures()
+	if err != nil {
+		return nil, err
+	}
+	flavors, err := e.nova().ListFlavorsDetail()
+	if err != nil {
+		return nil, err
+	}
+	// As in findInstanceSpec, flavors don't say which architectures
+	// or virtualisation types they support, so we assume all of them.
+	allInstanceTypes := make([]instances.InstanceType, len(flavors))
+	for i, flavor := range flavors {
+		allInstanceTypes[i] = instances.InstanceType{
+			Id:       flavor.Id,
+			Name:     flavor.Name,
+			Arches:   supportedArches,
+			Mem:      uint64(flavor.RAM),
+			CpuCores: uint64(flavor.VCPUs),
+			RootDisk: uint64(flavor.Disk * 1024),
+			VirtType: cons.VirtType,
+		}
+	}
+	return instances.FilterInstanceTypes(allInstanceTypes, cons), nil
+}
+
 func (e *Environ) supportedArchitectures() ([]string, error) {
 	e.archMutex.Lock()
 	defer e.archMutex.Unlock()
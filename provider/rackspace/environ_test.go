@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
@@ -143,6 +144,11 @@ func (e *fakeEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	return nil, nil
 }
 
+func (e *fakeEnviron) InstanceTypes(cons constraints.Value) ([]instances.InstanceType, error) {
+	e.Push("InstanceTypes", cons)
+	return nil, nil
+}
+
 func (e *fakeEnviron) SetConfig(cfg *config.Config) error {
 	e.config = cfg
 	return nil
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 )
 
@@ -90,6 +91,12 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// InstanceTypes is specified in the environs.Environ interface.
+// vSphere machines are sized by constraints, not by instance types.
+func (env *environ) InstanceTypes(constraints.Value) ([]instances.InstanceType, error) {
+	return nil, errors.NotSupportedf("instance types")
+}
+
 // SupportNetworks returns whether the environment has support to
 // specify networks for applications and machines.
 func (env *environ) SupportNetworks() bool {
@@ -128,6 +128,10 @@ func allCollections() collectionSchema {
 		// migration minions.
 		migrationsMinionSyncC: {global: true},
 
+		// This collection records operator requests for model
+		// migrations to be aborted.
+		migrationsAbortC: {global: true},
+
 		// This collection holds user information that's not specific to any
 		// one model.
 		usersC: {
@@ -411,6 +415,7 @@ const (
 	metricsC                 = "metrics"
 	metricsManagerC          = "metricsmanager"
 	minUnitsC                = "minunits"
+	migrationsAbortC         = "migrations.abort"
 	migrationsActiveC        = "migrations.active"
 	migrationsC              = "migrations"
 	migrationsMinionSyncC    = "migrations.minionsync"
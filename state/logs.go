@@ -225,6 +225,13 @@ func NewDbLogger(st ModelSessioner, entity names.Tag, ver version.Number) *DbLog
 
 // Log writes a log message to the database.
 func (logger *DbLogger) Log(t time.Time, module string, location string, level loggo.Level, msg string) error {
+	return logger.LogFor(logger.entity, t, module, location, level, msg)
+}
+
+// LogFor writes a log message to the database, attributed to the
+// given entity rather than the DbLogger's own. It's used to store log
+// records which were originally written by another controller.
+func (logger *DbLogger) LogFor(entity string, t time.Time, module string, location string, level loggo.Level, msg string) error {
 	// TODO(ericsnow) Use a controller-global int sequence for Id.
 
 	// UnixNano() returns the "absolute" (UTC) number of nanoseconds
@@ -234,7 +241,7 @@ func (logger *DbLogger) Log(t time.Time, module string, location string, level l
 		Id:        bson.NewObjectId(),
 		Time:      unixEpochNanoUTC,
 		ModelUUID: logger.modelUUID,
-		Entity:    logger.entity,
+		Entity:    entity,
 		Version:   logger.version,
 		Module:    module,
 		Location:  location,
@@ -250,6 +257,22 @@ func (logger *DbLogger) Close() {
 	}
 }
 
+// LatestLogTime returns the time of the most recent log record for
+// the model, or the zero time if the model has no log records.
+func LatestLogTime(st ModelSessioner) (time.Time, error) {
+	session, logsColl := initLogsSession(st)
+	defer session.Close()
+
+	var doc logDoc
+	err := logsColl.Find(bson.M{"e": st.ModelUUID()}).Sort("-e", "-t").One(&doc)
+	if err == mgo.ErrNotFound {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, errors.Annotate(err, "cannot find latest log record")
+	}
+	return time.Unix(0, doc.Time).In(time.UTC), nil
+}
+
 // LogTailer allows for retrieval of Juju's logs from MongoDB. It
 // first returns any matching already recorded logs and then waits for
 // additional matching logs as they appear.
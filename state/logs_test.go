@@ -197,6 +197,42 @@ func (s *LogsSuite) TestDbLogger(c *gc.C) {
 	c.Assert(docs[1]["x"], gc.Equals, "oh noes")
 }
 
+func (s *LogsSuite) TestDbLoggerLogFor(c *gc.C) {
+	logger := state.NewDbLogger(s.State, names.NewMachineTag("22"), jujuversion.Current)
+	defer logger.Close()
+	t0 := time.Now().Truncate(time.Millisecond) // MongoDB only stores timestamps with ms precision.
+	err := logger.LogFor("unit-mysql-0", t0, "some.where", "foo.go:99", loggo.INFO, "all is well")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []bson.M
+	err = s.logsColl.Find(nil).All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 1)
+	c.Assert(docs[0]["t"], gc.Equals, t0.UnixNano())
+	c.Assert(docs[0]["e"], gc.Equals, s.State.ModelUUID())
+	c.Assert(docs[0]["n"], gc.Equals, "unit-mysql-0")
+	c.Assert(docs[0]["x"], gc.Equals, "all is well")
+}
+
+func (s *LogsSuite) TestLatestLogTime(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	t0 := time.Date(2016, 11, 30, 10, 1, 0, 0, time.UTC)
+	s.generateLogs(c, st, t0, 3)
+	s.generateLogs(c, s.State, t0.Add(time.Hour), 1)
+
+	latest, err := state.LatestLogTime(st)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(latest, gc.Equals, t0)
+}
+
+func (s *LogsSuite) TestLatestLogTimeNoLogs(c *gc.C) {
+	latest, err := state.LatestLogTime(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(latest.IsZero(), jc.IsTrue)
+}
+
 func (s *LogsSuite) TestPruneLogsByTime(c *gc.C) {
 	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("22"), jujuversion.Current)
 	defer dbLogger.Close()
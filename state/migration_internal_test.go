@@ -102,6 +102,7 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		migrationsStatusC,
 		migrationsActiveC,
 		migrationsMinionSyncC,
+		migrationsAbortC,
 
 		// The container ref document is primarily there to keep track
 		// of a particular machine's containers. The migration format
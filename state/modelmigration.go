@@ -44,7 +44,7 @@ type ModelMigration interface {
 	SuccessTime() time.Time
 
 	// EndTime returns the time when the migration reached DONE or
-	// REAPFAILED.
+	// ABORTDONE.
 	EndTime() time.Time
 
 	// Phase returns the migration's phase.
@@ -58,6 +58,16 @@ type ModelMigration interface {
 	// progress of the migration.
 	StatusMessage() string
 
+	// ApprovalRef returns the change ticket reference recorded when
+	// the migration was approved, or an empty string if it hasn't
+	// been approved.
+	ApprovalRef() string
+
+	// ReapRetryRequested returns whether an operator has asked for
+	// the removal of the migrated model to be retried since the
+	// migration last entered the REAPFAILED phase.
+	ReapRetryRequested() bool
+
 	// InitiatedBy returns username the initiated the migration.
 	InitiatedBy() string
 
@@ -74,16 +84,59 @@ type ModelMigration interface {
 	// current progress of the migration.
 	SetStatusMessage(text string) error
 
+	// MinionWaitProgress returns the counts of migration minions
+	// which had reported for a migration phase, as last recorded by
+	// SetMinionWaitProgress.
+	MinionWaitProgress() MinionWaitProgress
+
+	// SetMinionWaitProgress records the counts of migration minions
+	// which have reported so far for a migration phase, while the
+	// migrationmaster waits for the rest.
+	SetMinionWaitProgress(progress MinionWaitProgress) error
+
+	// Approve records external approval for a migration which is
+	// being held in the HOLD phase, along with a reference to the
+	// change ticket that authorised it. An error is returned if the
+	// migration isn't in the HOLD phase.
+	Approve(ticketRef string) error
+
+	// RequestAbort records an operator's request that the migration
+	// be aborted. The migrationmaster worker decides whether it's
+	// still possible to abort. Repeated requests are ignored. An
+	// error is returned if the migration has already ended.
+	RequestAbort() error
+
+	// RequestReapRetry records an operator's request that the removal
+	// of the migrated model from the source controller be attempted
+	// again. The request is cleared when the migration moves back to
+	// the REAP phase. An error is returned if the migration isn't in
+	// the REAPFAILED phase.
+	RequestReapRetry() error
+
+	// WatchForAbort returns a notify watcher which triggers when an
+	// abort of the migration is requested.
+	WatchForAbort() (NotifyWatcher, error)
+
 	// MinionReport records a report from a migration minion worker
 	// about the success or failure to complete its actions for a
 	// given migration phase.
 	MinionReport(tag names.Tag, phase migration.Phase, success bool) error
 
+	// MinionReportFailure records a report from a migration minion
+	// worker that it failed to complete its actions for a given
+	// migration phase, along with a description of the failure.
+	MinionReportFailure(tag names.Tag, phase migration.Phase, message string) error
+
 	// GetMinionReports returns details of the minions that have
 	// reported success or failure for the current migration phase, as
 	// well as those which are yet to report.
 	GetMinionReports() (*MinionReports, error)
 
+	// MinionFailureDetails returns the failure descriptions reported
+	// by the minions which failed the given migration phase, keyed by
+	// agent tag.
+	MinionFailureDetails(phase migration.Phase) (map[names.Tag]string, error)
+
 	// WatchMinionReports returns a notify watcher which triggers when
 	// a migration minion has reported back about the success or failure
 	// of its actions for the current migration phase.
@@ -102,6 +155,20 @@ type MinionReports struct {
 	Succeeded []names.Tag
 	Failed    []names.Tag
 	Unknown   []names.Tag
+
+	// FailureDetails holds the failure descriptions reported by
+	// failed agents which gave one.
+	FailureDetails map[names.Tag]string
+}
+
+// MinionWaitProgress holds the counts of migration minions which had
+// reported for a migration phase when last recorded by the
+// migrationmaster.
+type MinionWaitProgress struct {
+	Phase        migration.Phase
+	SuccessCount int
+	FailedCount  int
+	UnknownCount int
 }
 
 // modelMigration is an implementation of ModelMigration.
@@ -181,6 +248,26 @@ type modelMigStatusDoc struct {
 	// StatusMessage holds a human readable message about the
 	// migration's progress.
 	StatusMessage string `bson:"status-message"`
+
+	// ApprovalRef holds the reference of the change ticket which
+	// approved the migration while it was held in the HOLD phase.
+	ApprovalRef string `bson:"approval-ref,omitempty"`
+
+	// ReapRetryRequested records whether an operator has asked for
+	// the removal of the migrated model to be retried while the
+	// migration is in the REAPFAILED phase.
+	ReapRetryRequested bool `bson:"reap-retry-requested,omitempty"`
+
+	// MinionWaitPhase holds the migration phase which the minion
+	// counts below relate to.
+	MinionWaitPhase string `bson:"minion-wait-phase,omitempty"`
+
+	// MinionSuccessCount, MinionFailedCount and MinionUnknownCount
+	// hold the counts of minions which had reported success, had
+	// reported failure, and were yet to report for MinionWaitPhase.
+	MinionSuccessCount int `bson:"minion-success-count,omitempty"`
+	MinionFailedCount  int `bson:"minion-failed-count,omitempty"`
+	MinionUnknownCount int `bson:"minion-unknown-count,omitempty"`
 }
 
 type modelMigMinionSyncDoc struct {
@@ -190,6 +277,14 @@ type modelMigMinionSyncDoc struct {
 	EntityKey   string `bson:"entity-key"`
 	Time        int64  `bson:"time"`
 	Success     bool   `bson:"success"`
+	Error       string `bson:"error,omitempty"`
+}
+
+// modelMigAbortDoc records that an abort of the model migration with
+// the same id has been requested.
+type modelMigAbortDoc struct {
+	Id   string `bson:"_id"`
+	Time int64  `bson:"time"`
 }
 
 // Id implements ModelMigration.
@@ -246,6 +341,16 @@ func (mig *modelMigration) StatusMessage() string {
 	return mig.statusDoc.StatusMessage
 }
 
+// ApprovalRef implements ModelMigration.
+func (mig *modelMigration) ApprovalRef() string {
+	return mig.statusDoc.ApprovalRef
+}
+
+// ReapRetryRequested implements ModelMigration.
+func (mig *modelMigration) ReapRetryRequested() bool {
+	return mig.statusDoc.ReapRetryRequested
+}
+
 // InitiatedBy implements ModelMigration.
 func (mig *modelMigration) InitiatedBy() string {
 	return mig.doc.InitiatedBy
@@ -293,10 +398,16 @@ func (mig *modelMigration) SetPhase(nextPhase migration.Phase) error {
 		nextDoc.SuccessTime = now
 		update["success-time"] = now
 	}
+	if phase == migration.REAPFAILED {
+		// Any retry request has now been acted on.
+		nextDoc.ReapRetryRequested = false
+		update["reap-retry-requested"] = false
+	}
 	var ops []txn.Op
 
-	// If the migration aborted, make the model active again.
-	if nextPhase == migration.ABORTDONE {
+	// If the migration aborted, or was only a dry run, make the
+	// model active again.
+	if nextPhase == migration.ABORTDONE || nextPhase == migration.DRYRUNDONE {
 		ops = append(ops, txn.Op{
 			C:      modelsC,
 			Id:     mig.doc.ModelUUID,
@@ -353,8 +464,141 @@ func (mig *modelMigration) SetStatusMessage(text string) error {
 	return nil
 }
 
+// MinionWaitProgress implements ModelMigration.
+func (mig *modelMigration) MinionWaitProgress() MinionWaitProgress {
+	phase, _ := migration.ParsePhase(mig.statusDoc.MinionWaitPhase)
+	return MinionWaitProgress{
+		Phase:        phase,
+		SuccessCount: mig.statusDoc.MinionSuccessCount,
+		FailedCount:  mig.statusDoc.MinionFailedCount,
+		UnknownCount: mig.statusDoc.MinionUnknownCount,
+	}
+}
+
+// SetMinionWaitProgress implements ModelMigration.
+func (mig *modelMigration) SetMinionWaitProgress(progress MinionWaitProgress) error {
+	ops := []txn.Op{{
+		C:  migrationsStatusC,
+		Id: mig.statusDoc.Id,
+		Update: bson.M{"$set": bson.M{
+			"minion-wait-phase":    progress.Phase.String(),
+			"minion-success-count": progress.SuccessCount,
+			"minion-failed-count":  progress.FailedCount,
+			"minion-unknown-count": progress.UnknownCount,
+		}},
+		Assert: txn.DocExists,
+	}}
+	if err := mig.st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "failed to set minion wait progress")
+	}
+	mig.statusDoc.MinionWaitPhase = progress.Phase.String()
+	mig.statusDoc.MinionSuccessCount = progress.SuccessCount
+	mig.statusDoc.MinionFailedCount = progress.FailedCount
+	mig.statusDoc.MinionUnknownCount = progress.UnknownCount
+	return nil
+}
+
+// Approve implements ModelMigration.
+func (mig *modelMigration) Approve(ticketRef string) error {
+	if ticketRef == "" {
+		return errors.NotValidf("empty ticket reference")
+	}
+	phase, err := mig.Phase()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if phase != migration.HOLD {
+		return errors.Errorf("migration is not being held (phase is %s)", phase)
+	}
+	ops := []txn.Op{{
+		C:      migrationsStatusC,
+		Id:     mig.statusDoc.Id,
+		Update: bson.M{"$set": bson.M{"approval-ref": ticketRef}},
+		// Ensure the migration hasn't moved on underneath us.
+		Assert: bson.M{"phase": mig.statusDoc.Phase},
+	}}
+	if err := mig.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("phase already changed")
+	} else if err != nil {
+		return errors.Annotate(err, "failed to approve migration")
+	}
+	mig.statusDoc.ApprovalRef = ticketRef
+	return nil
+}
+
+// RequestAbort implements ModelMigration.
+func (mig *modelMigration) RequestAbort() error {
+	phase, err := mig.Phase()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if phase.IsTerminal() {
+		return errors.Errorf("migration has ended (phase is %s)", phase)
+	}
+	doc := modelMigAbortDoc{
+		Id:   mig.Id(),
+		Time: GetClock().Now().UnixNano(),
+	}
+	ops := []txn.Op{{
+		C:      migrationsAbortC,
+		Id:     doc.Id,
+		Insert: doc,
+		Assert: txn.DocMissing,
+	}}
+	if err := mig.st.runTransaction(ops); err == txn.ErrAborted {
+		// Abort has already been requested.
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "failed to request abort")
+	}
+	return nil
+}
+
+// RequestReapRetry implements ModelMigration.
+func (mig *modelMigration) RequestReapRetry() error {
+	phase, err := mig.Phase()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if phase != migration.REAPFAILED {
+		return errors.Errorf("migration has not failed to remove the model (phase is %s)", phase)
+	}
+	ops := []txn.Op{{
+		C:      migrationsStatusC,
+		Id:     mig.statusDoc.Id,
+		Update: bson.M{"$set": bson.M{"reap-retry-requested": true}},
+		// Ensure the migration hasn't moved on underneath us.
+		Assert: bson.M{"phase": mig.statusDoc.Phase},
+	}}
+	if err := mig.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("phase already changed")
+	} else if err != nil {
+		return errors.Annotate(err, "failed to request reap retry")
+	}
+	mig.statusDoc.ReapRetryRequested = true
+	return nil
+}
+
+// WatchForAbort implements ModelMigration.
+func (mig *modelMigration) WatchForAbort() (NotifyWatcher, error) {
+	filter := func(rawId interface{}) bool {
+		id, ok := rawId.(string)
+		return ok && id == mig.Id()
+	}
+	return newNotifyCollWatcher(mig.st, migrationsAbortC, filter), nil
+}
+
 // MinionReport implements ModelMigration.
 func (mig *modelMigration) MinionReport(tag names.Tag, phase migration.Phase, success bool) error {
+	return mig.minionReport(tag, phase, success, "")
+}
+
+// MinionReportFailure implements ModelMigration.
+func (mig *modelMigration) MinionReportFailure(tag names.Tag, phase migration.Phase, message string) error {
+	return mig.minionReport(tag, phase, false, message)
+}
+
+func (mig *modelMigration) minionReport(tag names.Tag, phase migration.Phase, success bool, message string) error {
 	globalKey, err := agentTagToGlobalKey(tag)
 	if err != nil {
 		return errors.Trace(err)
@@ -367,6 +611,7 @@ func (mig *modelMigration) MinionReport(tag names.Tag, phase migration.Phase, su
 		EntityKey:   globalKey,
 		Time:        GetClock().Now().UnixNano(),
 		Success:     success,
+		Error:       message,
 	}
 	ops := []txn.Op{{
 		C:      migrationsMinionSyncC,
@@ -414,6 +659,7 @@ func (mig *modelMigration) GetMinionReports() (*MinionReports, error) {
 	query = query.Select(bson.M{
 		"entity-key": 1,
 		"success":    1,
+		"error":      1,
 	})
 	var docs []bson.M
 	if err := query.All(&docs); err != nil {
@@ -422,6 +668,7 @@ func (mig *modelMigration) GetMinionReports() (*MinionReports, error) {
 
 	succeeded := set.NewTags()
 	failed := set.NewTags()
+	failureDetails := make(map[names.Tag]string)
 	for _, doc := range docs {
 		entityKey, ok := doc["entity-key"].(string)
 		if !ok {
@@ -439,6 +686,9 @@ func (mig *modelMigration) GetMinionReports() (*MinionReports, error) {
 			succeeded.Add(tag)
 		} else {
 			failed.Add(tag)
+			if message, _ := doc["error"].(string); message != "" {
+				failureDetails[tag] = message
+			}
 		}
 	}
 
@@ -448,9 +698,37 @@ func (mig *modelMigration) GetMinionReports() (*MinionReports, error) {
 		Succeeded: succeeded.Values(),
 		Failed:    failed.Values(),
 		Unknown:   unknown.Values(),
+
+		FailureDetails: failureDetails,
 	}, nil
 }
 
+// MinionFailureDetails implements ModelMigration.
+func (mig *modelMigration) MinionFailureDetails(phase migration.Phase) (map[names.Tag]string, error) {
+	coll, closer := mig.st.getCollection(migrationsMinionSyncC)
+	defer closer()
+	query := coll.Find(bson.M{
+		"_id": bson.M{
+			"$regex": "^" + mig.minionReportId(phase, ".+"),
+		},
+		"success": false,
+	})
+	var docs []modelMigMinionSyncDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "retrieving minion reports")
+	}
+
+	details := make(map[names.Tag]string)
+	for _, doc := range docs {
+		tag, err := globalKeyToAgentTag(doc.EntityKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		details[tag] = doc.Error
+	}
+	return details, nil
+}
+
 // WatchMinionReports implements ModelMigration.
 func (mig *modelMigration) WatchMinionReports() (NotifyWatcher, error) {
 	phase, err := mig.Phase()
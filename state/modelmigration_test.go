@@ -360,10 +360,36 @@ func (s *ModelMigrationSuite) TestABORTCleanup(c *gc.C) {
 	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeActive)
 }
 
-func (s *ModelMigrationSuite) TestREAPFAILEDCleanup(c *gc.C) {
+func (s *ModelMigrationSuite) TestDRYRUNDONECleanup(c *gc.C) {
 	mig, err := s.State2.CreateModelMigration(s.stdSpec)
 	c.Assert(err, jc.ErrorIsNil)
 
+	phases := []migration.Phase{
+		migration.READONLY,
+		migration.PRECHECK,
+		migration.DRYRUNDONE,
+	}
+	for _, phase := range phases {
+		s.clock.Advance(time.Millisecond)
+		c.Assert(mig.SetPhase(phase), jc.ErrorIsNil)
+	}
+
+	s.assertMigrationCleanedUp(c, mig)
+
+	// Model should be set back to active.
+	model, err := s.State2.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeActive)
+}
+
+func (s *ModelMigrationSuite) TestREAPFAILEDRetry(c *gc.C) {
+	mig, err := s.State2.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A retry can only be requested once reaping has failed.
+	err = mig.RequestReapRetry()
+	c.Assert(err, gc.ErrorMatches, "migration has not failed to remove the model \\(phase is QUIESCE\\)")
+
 	// Advance the migration to REAPFAILED.
 	phases := []migration.Phase{
 		migration.READONLY,
@@ -380,9 +406,64 @@ func (s *ModelMigrationSuite) TestREAPFAILEDCleanup(c *gc.C) {
 		c.Assert(mig.SetPhase(phase), jc.ErrorIsNil)
 	}
 
+	// The migration remains active so that reaping can be retried.
+	c.Assert(mig.EndTime().IsZero(), jc.IsTrue)
+	assertMigrationActive(c, s.State2)
+	c.Assert(mig.ReapRetryRequested(), jc.IsFalse)
+
+	c.Assert(mig.RequestReapRetry(), jc.ErrorIsNil)
+	c.Assert(mig.ReapRetryRequested(), jc.IsTrue)
+	mig2, err := s.State2.LatestModelMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig2.ReapRetryRequested(), jc.IsTrue)
+
+	// Moving back to REAP clears the request.
+	s.clock.Advance(time.Millisecond)
+	c.Assert(mig.SetPhase(migration.REAP), jc.ErrorIsNil)
+	c.Assert(mig.ReapRetryRequested(), jc.IsFalse)
+	mig2, err = s.State2.LatestModelMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig2.ReapRetryRequested(), jc.IsFalse)
+
+	s.clock.Advance(time.Millisecond)
+	c.Assert(mig.SetPhase(migration.DONE), jc.ErrorIsNil)
 	s.assertMigrationCleanedUp(c, mig)
 }
 
+func (s *ModelMigrationSuite) TestApprove(c *gc.C) {
+	mig, err := s.State2.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig.ApprovalRef(), gc.Equals, "")
+
+	// Approval is only possible while the migration is held.
+	err = mig.Approve("CHG-1234")
+	c.Assert(err, gc.ErrorMatches, "migration is not being held \\(phase is QUIESCE\\)")
+
+	phases := []migration.Phase{
+		migration.READONLY,
+		migration.PRECHECK,
+		migration.IMPORT,
+		migration.VALIDATION,
+		migration.HOLD,
+	}
+	for _, phase := range phases {
+		c.Assert(mig.SetPhase(phase), jc.ErrorIsNil)
+	}
+
+	err = mig.Approve("")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	c.Assert(mig.Approve("CHG-1234"), jc.ErrorIsNil)
+	c.Assert(mig.ApprovalRef(), gc.Equals, "CHG-1234")
+
+	// The reference is persisted, and retained after the migration
+	// moves on for audit purposes.
+	c.Assert(mig.SetPhase(migration.SUCCESS), jc.ErrorIsNil)
+	mig2, err := s.State2.LatestModelMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig2.ApprovalRef(), gc.Equals, "CHG-1234")
+}
+
 func (s *ModelMigrationSuite) assertMigrationCleanedUp(c *gc.C, mig state.ModelMigration) {
 	c.Assert(mig.PhaseChangedTime(), gc.Equals, s.clock.Now())
 	c.Assert(mig.EndTime(), gc.Equals, s.clock.Now())
@@ -437,6 +518,29 @@ func (s *ModelMigrationSuite) TestStatusMessage(c *gc.C) {
 	c.Check(mig2.StatusMessage(), gc.Equals, "foo bar")
 }
 
+func (s *ModelMigrationSuite) TestMinionWaitProgress(c *gc.C) {
+	mig, err := s.State2.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	mig2, err := s.State2.LatestModelMigration()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(mig.MinionWaitProgress(), gc.Equals, state.MinionWaitProgress{})
+
+	progress := state.MinionWaitProgress{
+		Phase:        migration.QUIESCE,
+		SuccessCount: 3,
+		FailedCount:  1,
+		UnknownCount: 2,
+	}
+	err = mig.SetMinionWaitProgress(progress)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mig.MinionWaitProgress(), gc.Equals, progress)
+
+	c.Assert(mig2.Refresh(), jc.ErrorIsNil)
+	c.Check(mig2.MinionWaitProgress(), gc.Equals, progress)
+}
+
 func (s *ModelMigrationSuite) TestWatchForModelMigration(c *gc.C) {
 	// Start watching for migration.
 	w, wc := s.createMigrationWatcher(c, s.State2)
@@ -596,6 +700,44 @@ func (s *ModelMigrationSuite) TestMinionReports(c *gc.C) {
 	c.Check(reports.Unknown, jc.SameContents, []names.Tag{m2.Tag()})
 }
 
+func (s *ModelMigrationSuite) TestMinionReportFailureDetails(c *gc.C) {
+	factory2 := factory.NewFactory(s.State2)
+	m0 := factory2.MakeMachine(c, nil)
+	u0 := factory2.MakeUnit(c, &factory.UnitParams{Machine: m0})
+	m1 := factory2.MakeMachine(c, nil)
+	m2 := factory2.MakeMachine(c, nil)
+
+	mig, err := s.State2.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	const phase = migration.QUIESCE
+	c.Assert(mig.MinionReport(m0.Tag(), phase, true), jc.ErrorIsNil)
+	c.Assert(mig.MinionReportFailure(m1.Tag(), phase, "disk full"), jc.ErrorIsNil)
+	c.Assert(mig.MinionReportFailure(u0.Tag(), phase, "hook failed"), jc.ErrorIsNil)
+	c.Assert(mig.MinionReport(m2.Tag(), phase, false), jc.ErrorIsNil)
+
+	reports, err := mig.GetMinionReports()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(reports.Failed, jc.SameContents, []names.Tag{m1.Tag(), u0.Tag(), m2.Tag()})
+	c.Check(reports.FailureDetails, jc.DeepEquals, map[names.Tag]string{
+		m1.Tag(): "disk full",
+		u0.Tag(): "hook failed",
+	})
+
+	details, err := mig.MinionFailureDetails(phase)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details, jc.DeepEquals, map[names.Tag]string{
+		m1.Tag(): "disk full",
+		u0.Tag(): "hook failed",
+		m2.Tag(): "",
+	})
+
+	// Other phases are unaffected.
+	details, err = mig.MinionFailureDetails(migration.IMPORT)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details, gc.HasLen, 0)
+}
+
 func (s *ModelMigrationSuite) TestDuplicateMinionReportsSameSuccess(c *gc.C) {
 	// It should be OK for a minion report to arrive more than once
 	// for the same migration, agent and phase as long as the value of
@@ -708,6 +850,56 @@ func (s *ModelMigrationSuite) TestWatchMinionReportsMultiModel(c *gc.C) {
 	wc3.AssertOneChange()
 }
 
+func (s *ModelMigrationSuite) TestWatchForAbort(c *gc.C) {
+	mig, err := s.State2.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	w, err := mig.WatchForAbort()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State2, w)
+	wc.AssertOneChange() // initial event
+
+	c.Assert(mig.RequestAbort(), jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Repeated requests are ignored.
+	c.Assert(mig.RequestAbort(), jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Other changes to the migration don't trigger the watcher.
+	c.Assert(mig.SetStatusMessage("foo"), jc.ErrorIsNil)
+	c.Assert(mig.SetPhase(migration.READONLY), jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *ModelMigrationSuite) TestWatchForAbortMultiModel(c *gc.C) {
+	mig, err := s.State2.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	w, err := mig.WatchForAbort()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State2, w)
+	wc.AssertOneChange() // initial event
+
+	State3 := s.Factory.MakeModel(c, nil)
+	s.AddCleanup(func(*gc.C) { State3.Close() })
+	mig3, err := State3.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(mig3.RequestAbort(), jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *ModelMigrationSuite) TestRequestAbortEnded(c *gc.C) {
+	mig, err := s.State2.CreateModelMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig.SetPhase(migration.ABORT), jc.ErrorIsNil)
+	c.Assert(mig.SetPhase(migration.ABORTDONE), jc.ErrorIsNil)
+
+	err = mig.RequestAbort()
+	c.Assert(err, gc.ErrorMatches, "migration has ended \\(phase is ABORTDONE\\)")
+}
+
 func (s *ModelMigrationSuite) createStatusWatcher(c *gc.C, st *state.State) (
 	state.NotifyWatcher, statetesting.NotifyWatcherC,
 ) {
@@ -4,6 +4,12 @@
 package upgrades
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/state"
@@ -34,8 +40,27 @@ type Context interface {
 	// APIContext returns a new Context suitable for API-based upgrade
 	// steps.
 	APIContext() Context
+
+	// StepCompleted reports whether the upgrade step with the given
+	// description, for the given target version, has been recorded
+	// as completed.
+	StepCompleted(vers version.Number, description string) bool
+
+	// SetStepCompleted records that the upgrade step with the given
+	// description, for the given target version, has completed, so
+	// that it is not run again if the upgrade is retried, even after
+	// the agent restarts.
+	SetStepCompleted(vers version.Number, description string) error
+
+	// ClearCompletedSteps forgets all completed upgrade steps. It is
+	// called once all of the steps of an upgrade have completed.
+	ClearCompletedSteps()
 }
 
+// completedStepsKey is the agent config key under which the
+// completed upgrade steps are recorded, one per line.
+const completedStepsKey = "completed-upgrade-steps"
+
 // NewContext returns a new upgrade context.
 func NewContext(agentConfig agent.ConfigSetter, api api.Connection, st *state.State) Context {
 	return &upgradeContext{
@@ -92,3 +117,51 @@ func (c *upgradeContext) APIContext() Context {
 		api:         c.api,
 	}
 }
+
+// StepCompleted is defined on the Context interface.
+func (c *upgradeContext) StepCompleted(vers version.Number, description string) bool {
+	entry := completedStepEntry(vers, description)
+	for _, completed := range c.completedSteps() {
+		if completed == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStepCompleted is defined on the Context interface.
+//
+// Completed steps are recorded in the agent config, so that they are
+// shared by the Contexts returned by StateContext and APIContext. The
+// agent config is written straight away if it can be, since otherwise
+// it is only written once all of the upgrade steps have succeeded.
+func (c *upgradeContext) SetStepCompleted(vers version.Number, description string) error {
+	if c.StepCompleted(vers, description) {
+		return nil
+	}
+	completed := append(c.completedSteps(), completedStepEntry(vers, description))
+	c.agentConfig.SetValue(completedStepsKey, strings.Join(completed, "\n"))
+	if writer, ok := c.agentConfig.(agent.ConfigWriter); ok {
+		if err := writer.Write(); err != nil {
+			return errors.Annotate(err, "writing agent config")
+		}
+	}
+	return nil
+}
+
+// ClearCompletedSteps is defined on the Context interface.
+func (c *upgradeContext) ClearCompletedSteps() {
+	c.agentConfig.SetValue(completedStepsKey, "")
+}
+
+func (c *upgradeContext) completedSteps() []string {
+	value := c.agentConfig.Value(completedStepsKey)
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, "\n")
+}
+
+func completedStepEntry(vers version.Number, description string) string {
+	return fmt.Sprintf("%v %s", vers, description)
+}
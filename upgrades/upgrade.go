@@ -6,14 +6,16 @@ package upgrades
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/version"
 )
 
 var logger = loggo.GetLogger("juju.upgrade")
 
-// Step defines an idempotent operation that is run to perform
-// a specific upgrade step.
+// Step defines an operation that is run to perform a specific upgrade
+// step. Once a step has completed, it is not run again if the upgrade
+// is retried, unless it is an IdempotentStep.
 type Step interface {
 	// Description is a human readable description of what the upgrade step does.
 	Description() string
@@ -25,6 +27,17 @@ type Step interface {
 	Run(Context) error
 }
 
+// IdempotentStep is implemented by upgrade steps which are safe to run
+// more than once. If Idempotent returns true, the step is run on every
+// attempt at an upgrade, even if it has already completed.
+type IdempotentStep interface {
+	Step
+
+	// Idempotent reports whether the step may be run again after it
+	// has completed.
+	Idempotent() bool
+}
+
 // Operation defines what steps to perform to upgrade to a target version.
 type Operation interface {
 	// The Juju version for which this operation is applicable.
@@ -104,6 +117,7 @@ func PerformUpgrade(from version.Number, targets []Target, context Context) erro
 	if err := runUpgradeSteps(ops, targets, context.APIContext()); err != nil {
 		return err
 	}
+	context.ClearCompletedSteps()
 
 	logger.Infof("All upgrade steps completed successfully")
 	return nil
@@ -118,31 +132,84 @@ func hasStateTarget(targets []Target) bool {
 	return false
 }
 
+// StepsFor returns the upgrade steps, in the order they are run, for
+// upgrading a machine of the given target type from one version of
+// Juju to another. Each step is registered in the upgrade operations
+// for the version it targets, and declares the types of machine it
+// applies to; state-based steps are only returned for controllers,
+// and are returned before all API-based steps.
+func StepsFor(from, to version.Number, target Target) []Step {
+	targets := []Target{target}
+	var steps []Step
+	if hasStateTarget(targets) {
+		ops := newOpsIterator(from, to, stateUpgradeOperations())
+		steps = append(steps, stepsFor(ops, targets)...)
+	}
+	ops := newOpsIterator(from, to, upgradeOperations())
+	return append(steps, stepsFor(ops, targets)...)
+}
+
+// stepsFor returns the steps of the upgrade operations given which
+// are relevant to the targets given.
+func stepsFor(ops *opsIterator, targets []Target) []Step {
+	var steps []Step
+	for ops.Next() {
+		steps = append(steps, operationSteps(ops.Get(), targets)...)
+	}
+	return steps
+}
+
+// operationSteps returns the steps of the upgrade operation given
+// which are relevant to the targets given.
+func operationSteps(op Operation, targets []Target) []Step {
+	var steps []Step
+	for _, step := range op.Steps() {
+		if targetsMatch(targets, step.Targets()) {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
 // runUpgradeSteps finds all the upgrade operations relevant to
 // the targets given and runs the associated upgrade steps.
 //
 // As soon as any error is encountered, the operation is aborted since
 // subsequent steps may required successful completion of earlier
-// ones. The steps must be idempotent so that the entire upgrade
-// operation can be retried.
+// ones. Each step that completes is recorded in the context, so that
+// when the upgrade operation is retried, only the steps which have not
+// completed, and those which are idempotent, are run again.
 func runUpgradeSteps(ops *opsIterator, targets []Target, context Context) error {
 	for ops.Next() {
-		for _, step := range ops.Get().Steps() {
-			if targetsMatch(targets, step.Targets()) {
-				logger.Infof("running upgrade step: %v", step.Description())
-				if err := step.Run(context); err != nil {
-					logger.Errorf("upgrade step %q failed: %v", step.Description(), err)
-					return &upgradeError{
-						description: step.Description(),
-						err:         err,
-					}
+		targetVersion := ops.Get().TargetVersion()
+		for _, step := range operationSteps(ops.Get(), targets) {
+			if context.StepCompleted(targetVersion, step.Description()) && !isIdempotent(step) {
+				logger.Infof("skipping completed upgrade step: %v", step.Description())
+				continue
+			}
+			logger.Infof("running upgrade step: %v", step.Description())
+			if err := step.Run(context); err != nil {
+				logger.Errorf("upgrade step %q failed: %v", step.Description(), err)
+				return &upgradeError{
+					description: step.Description(),
+					err:         err,
 				}
 			}
+			if err := context.SetStepCompleted(targetVersion, step.Description()); err != nil {
+				return errors.Annotatef(err, "recording completion of upgrade step %q", step.Description())
+			}
 		}
 	}
 	return nil
 }
 
+// isIdempotent reports whether the step may be run again after it
+// has completed.
+func isIdempotent(step Step) bool {
+	idempotent, ok := step.(IdempotentStep)
+	return ok && idempotent.Idempotent()
+}
+
 // targetsMatch returns true if any machineTargets match any of
 // stepTargets.
 func targetsMatch(machineTargets []Target, stepTargets []Target) bool {
@@ -161,9 +228,10 @@ type upgradeStep struct {
 	description string
 	targets     []Target
 	run         func(Context) error
+	idempotent  bool
 }
 
-var _ Step = (*upgradeStep)(nil)
+var _ IdempotentStep = (*upgradeStep)(nil)
 
 // Description is defined on the Step interface.
 func (step *upgradeStep) Description() string {
@@ -179,3 +247,8 @@ func (step *upgradeStep) Targets() []Target {
 func (step *upgradeStep) Run(context Context) error {
 	return step.run(context)
 }
+
+// Idempotent is defined on the IdempotentStep interface.
+func (step *upgradeStep) Idempotent() bool {
+	return step.idempotent
+}
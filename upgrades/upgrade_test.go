@@ -126,6 +126,7 @@ type mockContext struct {
 	realAgentConfig agent.ConfigSetter
 	apiState        api.Connection
 	state           *state.State
+	completed       map[string]bool
 }
 
 func (c *mockContext) APIState() api.Connection {
@@ -151,6 +152,22 @@ func (c *mockContext) APIContext() upgrades.Context {
 	return c
 }
 
+func (c *mockContext) StepCompleted(vers version.Number, description string) bool {
+	return c.completed[vers.String()+" "+description]
+}
+
+func (c *mockContext) SetStepCompleted(vers version.Number, description string) error {
+	if c.completed == nil {
+		c.completed = make(map[string]bool)
+	}
+	c.completed[vers.String()+" "+description] = true
+	return nil
+}
+
+func (c *mockContext) ClearCompletedSteps() {
+	c.completed = nil
+}
+
 type mockAgentConfig struct {
 	agent.ConfigSetter
 	dataDir      string
@@ -560,6 +577,60 @@ func (s *upgradeSuite) TestPerformUpgrade(c *gc.C) {
 	}
 }
 
+func (s *upgradeSuite) TestStepsFor(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.22.0"))
+	fromVersion := version.MustParse("1.16.0")
+
+	for i, test := range []struct {
+		target        upgrades.Target
+		expectedSteps []string
+	}{{
+		target: upgrades.HostMachine,
+		expectedSteps: []string{
+			"step 1 - 1.17.0",
+			"step 1 - 1.17.1",
+			"step 1 - 1.18.0",
+			"step 1 - 1.20.0", "step 2 - 1.20.0",
+			"step 1 - 1.21.0",
+			"step 1 - 1.22.0", "step 2 - 1.22.0",
+		},
+	}, {
+		target: upgrades.Controller,
+		expectedSteps: []string{
+			"state step 2 - 1.21.0",
+			"state step 2 - 1.22.0",
+			"step 2 - 1.17.1",
+			"step 2 - 1.18.0",
+			"step 1 - 1.20.0", "step 3 - 1.20.0",
+			"step 1 - 1.21.0",
+			"step 1 - 1.22.0", "step 2 - 1.22.0",
+		},
+	}, {
+		target: upgrades.DatabaseMaster,
+		expectedSteps: []string{
+			"state step 1 - 1.21.0",
+			"state step 1 - 1.22.0",
+			"step 1 - 1.20.0",
+			"step 1 - 1.21.0",
+			"step 2 - 1.22.0",
+		},
+	}} {
+		c.Logf("%d: %s", i, test.target)
+		steps := upgrades.StepsFor(fromVersion, jujuversion.Current, test.target)
+		assertExpectedSteps(c, steps, test.expectedSteps)
+	}
+}
+
+func (s *upgradeSuite) TestStepsForSameVersion(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	vers := version.MustParse("1.22.0")
+	steps := upgrades.StepsFor(vers, vers, upgrades.Controller)
+	assertExpectedSteps(c, steps, nil)
+}
+
 type contextStep struct {
 	useAPI bool
 }
@@ -615,14 +686,205 @@ func (s *upgradeSuite) TestApiStepsGetRestrictedContext(c *gc.C) {
 
 func (s *upgradeSuite) checkContextRestriction(c *gc.C, expectedPanic string) {
 	fromVersion := version.MustParse("1.20.0")
-	type fakeAgentConfigSetter struct{ agent.ConfigSetter }
-	ctx := upgrades.NewContext(fakeAgentConfigSetter{}, nil, new(state.State))
+	ctx := upgrades.NewContext(newValuesAgentConfig(), nil, new(state.State))
 	c.Assert(
 		func() { upgrades.PerformUpgrade(fromVersion, targets(upgrades.Controller), ctx) },
 		gc.PanicMatches, expectedPanic,
 	)
 }
 
+// valuesAgentConfig is an agent.ConfigSetter which only supports
+// getting and setting values.
+type valuesAgentConfig struct {
+	agent.ConfigSetter
+	values map[string]string
+}
+
+func newValuesAgentConfig() *valuesAgentConfig {
+	return &valuesAgentConfig{values: make(map[string]string)}
+}
+
+func (c *valuesAgentConfig) Value(key string) string {
+	return c.values[key]
+}
+
+func (c *valuesAgentConfig) SetValue(key, value string) {
+	if value == "" {
+		delete(c.values, key)
+	} else {
+		c.values[key] = value
+	}
+}
+
+// writingAgentConfig is a valuesAgentConfig which records how many
+// times it is written.
+type writingAgentConfig struct {
+	*valuesAgentConfig
+	writes   int
+	writeErr error
+}
+
+func (c *writingAgentConfig) Write() error {
+	c.writes++
+	return c.writeErr
+}
+
+// flakyStep is an upgrade step which fails the first time it is run.
+type flakyStep struct {
+	description string
+	idempotent  bool
+	runs        int
+}
+
+func (s *flakyStep) Description() string {
+	return s.description
+}
+
+func (s *flakyStep) Targets() []upgrades.Target {
+	return []upgrades.Target{upgrades.AllMachines}
+}
+
+func (s *flakyStep) Run(upgrades.Context) error {
+	s.runs++
+	if s.runs == 1 {
+		return errors.New("flaked")
+	}
+	return nil
+}
+
+func (s *flakyStep) Idempotent() bool {
+	return s.idempotent
+}
+
+func (s *upgradeSuite) TestRetriedUpgradeSkipsCompletedSteps(c *gc.C) {
+	completedStep := newUpgradeStep("step 1 - 1.21.0", upgrades.AllMachines)
+	failingStep := &flakyStep{description: "step 2 - 1.21.0"}
+	s.PatchValue(upgrades.StateUpgradeOperations,
+		func() []upgrades.Operation { return nil })
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps:         []upgrades.Step{completedStep, failingStep},
+			},
+		}
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+	fromVersion := version.MustParse("1.20.0")
+	ctx := &mockContext{}
+
+	err := upgrades.PerformUpgrade(fromVersion, targets(upgrades.HostMachine), ctx)
+	c.Assert(err, gc.ErrorMatches, "step 2 - 1.21.0: flaked")
+	c.Assert(ctx.messages, jc.DeepEquals, []string{"step 1 - 1.21.0"})
+	c.Assert(failingStep.runs, gc.Equals, 1)
+
+	// The completed step is not run again, but the failed step is.
+	err = upgrades.PerformUpgrade(fromVersion, targets(upgrades.HostMachine), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.messages, jc.DeepEquals, []string{"step 1 - 1.21.0"})
+	c.Assert(failingStep.runs, gc.Equals, 2)
+
+	// Once the upgrade has completed, the record of the completed
+	// steps is cleared.
+	c.Assert(ctx.completed, gc.HasLen, 0)
+}
+
+func (s *upgradeSuite) TestRetriedUpgradeRerunsIdempotentSteps(c *gc.C) {
+	idempotentStep := &flakyStep{description: "step 1 - 1.21.0", idempotent: true}
+	failingStep := &flakyStep{description: "step 2 - 1.21.0"}
+	s.PatchValue(upgrades.StateUpgradeOperations,
+		func() []upgrades.Operation { return nil })
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps:         []upgrades.Step{idempotentStep, failingStep},
+			},
+		}
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+	fromVersion := version.MustParse("1.20.0")
+	ctx := &mockContext{}
+
+	// The idempotent step fails on the first attempt, and succeeds
+	// on the second, when the failing step then fails.
+	err := upgrades.PerformUpgrade(fromVersion, targets(upgrades.HostMachine), ctx)
+	c.Assert(err, gc.ErrorMatches, "step 1 - 1.21.0: flaked")
+	err = upgrades.PerformUpgrade(fromVersion, targets(upgrades.HostMachine), ctx)
+	c.Assert(err, gc.ErrorMatches, "step 2 - 1.21.0: flaked")
+	c.Assert(ctx.StepCompleted(version.MustParse("1.21.0"), "step 1 - 1.21.0"), jc.IsTrue)
+
+	// The idempotent step is run again, even though it has completed.
+	err = upgrades.PerformUpgrade(fromVersion, targets(upgrades.HostMachine), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(idempotentStep.runs, gc.Equals, 3)
+	c.Assert(failingStep.runs, gc.Equals, 2)
+}
+
+func (s *upgradeSuite) TestContextRecordsCompletedSteps(c *gc.C) {
+	agentConfig := newValuesAgentConfig()
+	ctx := upgrades.NewContext(agentConfig, nil, nil)
+	vers := version.MustParse("1.21.0")
+	c.Assert(ctx.StepCompleted(vers, "step 1"), jc.IsFalse)
+
+	// Steps completed in the state and API contexts are recorded in
+	// the shared agent config.
+	c.Assert(ctx.StateContext().SetStepCompleted(vers, "step 1"), jc.ErrorIsNil)
+	c.Assert(ctx.APIContext().SetStepCompleted(vers, "step 2"), jc.ErrorIsNil)
+	c.Assert(ctx.APIContext().SetStepCompleted(vers, "step 2"), jc.ErrorIsNil)
+	c.Assert(agentConfig.values, gc.HasLen, 1)
+
+	ctx = upgrades.NewContext(agentConfig, nil, nil)
+	c.Check(ctx.StepCompleted(vers, "step 1"), jc.IsTrue)
+	c.Check(ctx.StepCompleted(vers, "step 2"), jc.IsTrue)
+	c.Check(ctx.StepCompleted(vers, "step 3"), jc.IsFalse)
+	c.Check(ctx.StepCompleted(version.MustParse("1.22.0"), "step 1"), jc.IsFalse)
+
+	ctx.ClearCompletedSteps()
+	c.Check(ctx.StepCompleted(vers, "step 1"), jc.IsFalse)
+	c.Check(agentConfig.values, gc.HasLen, 0)
+}
+
+func (s *upgradeSuite) TestContextWritesCompletedSteps(c *gc.C) {
+	agentConfig := &writingAgentConfig{valuesAgentConfig: newValuesAgentConfig()}
+	ctx := upgrades.NewContext(agentConfig, nil, nil)
+	vers := version.MustParse("1.21.0")
+
+	// The agent config is written as each step completes, so that
+	// the record survives an agent restart.
+	c.Assert(ctx.SetStepCompleted(vers, "step 1"), jc.ErrorIsNil)
+	c.Assert(agentConfig.writes, gc.Equals, 1)
+	c.Assert(ctx.SetStepCompleted(vers, "step 2"), jc.ErrorIsNil)
+	c.Assert(agentConfig.writes, gc.Equals, 2)
+
+	// Steps already recorded aren't written again.
+	c.Assert(ctx.SetStepCompleted(vers, "step 2"), jc.ErrorIsNil)
+	c.Assert(agentConfig.writes, gc.Equals, 2)
+}
+
+func (s *upgradeSuite) TestUpgradeFailsIfCompletedStepNotWritten(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations,
+		func() []upgrades.Operation { return nil })
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				// The step has already flaked, so it succeeds.
+				steps: []upgrades.Step{&flakyStep{description: "step 1 - 1.21.0", runs: 1}},
+			},
+		}
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+	agentConfig := &writingAgentConfig{
+		valuesAgentConfig: newValuesAgentConfig(),
+		writeErr:          errors.New("disk full"),
+	}
+	ctx := upgrades.NewContext(agentConfig, nil, nil)
+
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.HostMachine), ctx)
+	c.Assert(err, gc.ErrorMatches, `recording completion of upgrade step "step 1 - 1.21.0": writing agent config: disk full`)
+}
+
 func (s *upgradeSuite) TestStateStepsNotAttemptedWhenNoStateTarget(c *gc.C) {
 	stateCount := 0
 	stateUpgradeOperations := func() []upgrades.Operation {
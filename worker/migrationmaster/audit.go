@@ -0,0 +1,78 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationmaster
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+
+	coremigration "github.com/juju/juju/core/migration"
+)
+
+// MinionAuditRecord is written as a line of JSON to the minion report
+// audit file for each set of minion reports processed while waiting
+// for minions.
+type MinionAuditRecord struct {
+	Time                time.Time         `json:"time"`
+	MigrationId         string            `json:"migration-id"`
+	Phase               string            `json:"phase"`
+	SuccessCount        int               `json:"success-count"`
+	UnknownCount        int               `json:"unknown-count"`
+	SomeUnknownMachines []string          `json:"some-unknown-machines,omitempty"`
+	SomeUnknownUnits    []string          `json:"some-unknown-units,omitempty"`
+	FailedMachines      []string          `json:"failed-machines,omitempty"`
+	FailedUnits         []string          `json:"failed-units,omitempty"`
+	FailureDetails      map[string]string `json:"failure-details,omitempty"`
+}
+
+// minionAudit appends minion reports to an audit file.
+type minionAudit struct {
+	file *os.File
+}
+
+// openMinionAudit opens the audit file at path for appending,
+// creating it if necessary.
+func openMinionAudit(path string) (*minionAudit, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &minionAudit{file: file}, nil
+}
+
+// record appends the reports to the audit file, and syncs it so that
+// the record survives the controller going away. It is safe to call
+// on a nil minionAudit, which does nothing.
+func (a *minionAudit) record(now time.Time, reports coremigration.MinionReports) error {
+	if a == nil {
+		return nil
+	}
+	line, err := json.Marshal(MinionAuditRecord{
+		Time:                now.UTC(),
+		MigrationId:         reports.MigrationId,
+		Phase:               reports.Phase.String(),
+		SuccessCount:        reports.SuccessCount,
+		UnknownCount:        reports.UnknownCount,
+		SomeUnknownMachines: reports.SomeUnknownMachines,
+		SomeUnknownUnits:    reports.SomeUnknownUnits,
+		FailedMachines:      reports.FailedMachines,
+		FailedUnits:         reports.FailedUnits,
+		FailureDetails:      reports.FailureDetails,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(a.file.Sync())
+}
+
+// Close closes the audit file.
+func (a *minionAudit) Close() error {
+	return errors.Trace(a.file.Close())
+}
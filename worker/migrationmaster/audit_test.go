@@ -0,0 +1,83 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationmaster_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/migrationmaster"
+	"github.com/juju/juju/worker/workertest"
+)
+
+func readAuditRecords(c *gc.C, path string) []migrationmaster.MinionAuditRecord {
+	file, err := os.Open(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer file.Close()
+	var records []migrationmaster.MinionAuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record migrationmaster.MinionAuditRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		c.Assert(err, jc.ErrorIsNil)
+		records = append(records, record)
+	}
+	c.Assert(scanner.Err(), jc.ErrorIsNil)
+	return records
+}
+
+func (s *Suite) TestMinionReportAudit(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "minions.jsonl")
+	// Records are appended to any already in the file.
+	err := ioutil.WriteFile(path, []byte(`{"phase":"QUIESCE"}`+"\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	s.config.MinionReportAuditFile = path
+	s.masterFacade.minionReports.SuccessCount = 4
+	s.masterFacade.minionReports.FailedUnits = []string{"foo/2"}
+	s.masterFacade.minionReports.FailureDetails = map[string]string{"foo/2": "hook failed"}
+
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.masterFacade.status.Phase = coremigration.SUCCESS
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+
+	records := readAuditRecords(c, path)
+	c.Assert(records, gc.HasLen, 2)
+	c.Check(records[0].Phase, gc.Equals, "QUIESCE")
+	record := records[1]
+	c.Check(record.Time.Equal(s.clock.Now()), jc.IsTrue)
+	record.Time = time.Time{}
+	c.Check(record, jc.DeepEquals, migrationmaster.MinionAuditRecord{
+		MigrationId:    "model-uuid:2",
+		Phase:          "SUCCESS",
+		SuccessCount:   4,
+		FailedUnits:    []string{"foo/2"},
+		FailureDetails: map[string]string{"foo/2": "hook failed"},
+	})
+}
+
+func (s *Suite) TestMinionReportAuditBadPath(c *gc.C) {
+	s.config.MinionReportAuditFile = filepath.Join(c.MkDir(), "missing", "minions.jsonl")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.ErrorMatches, "opening minion report audit file: .*no such file or directory")
+}
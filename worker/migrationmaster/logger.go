@@ -0,0 +1,61 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationmaster
+
+import (
+	"fmt"
+
+	"github.com/juju/loggo"
+
+	coremigration "github.com/juju/juju/core/migration"
+)
+
+// shortUUIDLen is the number of characters of a model's UUID used to
+// name its migration logger.
+const shortUUIDLen = 6
+
+// migrationLogger logs the messages for a single model migration to
+// a logger named for the model, prefixing each with the model's name
+// so that concurrent migrations can be told apart in the logs.
+type migrationLogger struct {
+	logger loggo.Logger
+	prefix string
+}
+
+// newMigrationLogger returns a migrationLogger for the migration with
+// the given status.
+func newMigrationLogger(status coremigration.MigrationStatus) migrationLogger {
+	shortUUID := status.ModelUUID
+	if len(shortUUID) > shortUUIDLen {
+		shortUUID = shortUUID[:shortUUIDLen]
+	}
+	name := status.ModelName
+	if name == "" {
+		name = status.ModelUUID
+	}
+	return migrationLogger{
+		logger: loggo.GetLogger("juju.worker.migrationmaster." + shortUUID),
+		prefix: fmt.Sprintf("[%s] ", name),
+	}
+}
+
+// Debugf logs a message at DEBUG level.
+func (l migrationLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debugf(l.prefix+format, args...)
+}
+
+// Infof logs a message at INFO level.
+func (l migrationLogger) Infof(format string, args ...interface{}) {
+	l.logger.Infof(l.prefix+format, args...)
+}
+
+// Warningf logs a message at WARNING level.
+func (l migrationLogger) Warningf(format string, args ...interface{}) {
+	l.logger.Warningf(l.prefix+format, args...)
+}
+
+// Errorf logs a message at ERROR level.
+func (l migrationLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf(l.prefix+format, args...)
+}
@@ -24,6 +24,12 @@ type ManifoldConfig struct {
 	Clock     clock.Clock
 	NewFacade func(base.APICaller) (Facade, error)
 	NewWorker func(Config) (worker.Worker, error)
+
+	// BinaryDownloadConcurrency, BinaryUploadConcurrency and
+	// BinaryUploadRateLimit are passed on to the worker's Config.
+	BinaryDownloadConcurrency int
+	BinaryUploadConcurrency   int
+	BinaryUploadRateLimit     int64
 }
 
 // validate is called by start to check for bad configuration.
@@ -72,6 +78,11 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		CharmDownloader: apiClient,
 		ToolsDownloader: apiClient,
 		Clock:           config.Clock,
+
+		ValidationSettleDelay:     DefaultValidationSettleDelay,
+		BinaryDownloadConcurrency: config.BinaryDownloadConcurrency,
+		BinaryUploadConcurrency:   config.BinaryUploadConcurrency,
+		BinaryUploadRateLimit:     config.BinaryUploadRateLimit,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
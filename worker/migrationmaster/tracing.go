@@ -0,0 +1,31 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationmaster
+
+// Tracer is implemented by tracing backends which record the phases
+// of a migration as spans. It is deliberately minimal so that any
+// tracing system, such as OpenTelemetry, can be adapted to it.
+type Tracer interface {
+	// StartSpan starts a new span with the given name.
+	StartSpan(name string) Span
+}
+
+// Span records a single operation started by a Tracer.
+type Span interface {
+	// SetAttribute records a key/value attribute on the span.
+	SetAttribute(key string, value interface{})
+
+	// End finishes the span. If err is non-nil, the operation the
+	// span records failed.
+	End(err error)
+}
+
+// nopSpan is the Span used when the worker has no Tracer.
+type nopSpan struct{}
+
+// SetAttribute is part of the Span interface.
+func (nopSpan) SetAttribute(string, interface{}) {}
+
+// End is part of the Span interface.
+func (nopSpan) End(error) {}
@@ -0,0 +1,134 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationmaster_test
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/migrationmaster"
+	"github.com/juju/juju/worker/workertest"
+)
+
+// recordingTracer is a migrationmaster.Tracer which records the spans
+// it starts.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) StartSpan(name string) migrationmaster.Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordingSpan{
+		name:       name,
+		attributes: make(map[string]interface{}),
+	}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+func (t *recordingTracer) names() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var names []string
+	for _, span := range t.spans {
+		names = append(names, span.name)
+	}
+	return names
+}
+
+func (t *recordingTracer) span(c *gc.C, name string) *recordingSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, span := range t.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	c.Fatalf("no span named %q", name)
+	return nil
+}
+
+type recordingSpan struct {
+	name       string
+	attributes map[string]interface{}
+	ended      bool
+	err        error
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *recordingSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+func (s *Suite) TestTracerSpans(c *gc.C) {
+	tracer := &recordingTracer{}
+	s.config.Tracer = tracer
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	c.Assert(tracer.names(), jc.DeepEquals, []string{
+		"migration.QUIESCE",
+		"migration.READONLY",
+		"migration.PRECHECK",
+		"migration.IMPORT",
+		"migration.VALIDATION",
+		"migration.SUCCESS",
+		"migration.LOGTRANSFER",
+		"migration.REAP",
+	})
+	for _, span := range tracer.spans {
+		c.Check(span.ended, jc.IsTrue)
+		c.Check(span.err, jc.ErrorIsNil)
+		c.Check(span.attributes["model-uuid"], gc.Equals, "model-uuid")
+	}
+
+	importSpan := tracer.span(c, "migration.IMPORT")
+	c.Check(importSpan.attributes["phase"], gc.Equals, "IMPORT")
+	c.Check(importSpan.attributes["next-phase"], gc.Equals, "VALIDATION")
+	c.Check(importSpan.attributes["model-bytes"], gc.Equals, len(fakeModelBytes))
+
+	successSpan := tracer.span(c, "migration.SUCCESS")
+	c.Check(successSpan.attributes["minions-succeeded"], gc.Equals, 5)
+	c.Check(successSpan.attributes["minions-failed"], gc.Equals, 0)
+	c.Check(successSpan.attributes["minions-unknown"], gc.Equals, 0)
+}
+
+func (s *Suite) TestTracerSpanFailure(c *gc.C) {
+	tracer := &recordingTracer{}
+	s.config.Tracer = tracer
+	s.masterFacade.exportErr = errors.New("boom")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	importSpan := tracer.span(c, "migration.IMPORT")
+	c.Check(importSpan.attributes["next-phase"], gc.Equals, "ABORT")
+	c.Check(importSpan.err, gc.ErrorMatches, "migration moving to ABORT")
+
+	abortSpan := tracer.span(c, "migration.ABORT")
+	c.Check(abortSpan.attributes["next-phase"], gc.Equals, "ABORTDONE")
+	c.Check(abortSpan.err, jc.ErrorIsNil)
+}
@@ -4,6 +4,8 @@
 package migrationmaster_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -11,6 +13,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/migrationmaster"
@@ -69,6 +72,132 @@ func (*ValidateSuite) TestMissingClock(c *gc.C) {
 	checkNotValid(c, config, "nil Clock not valid")
 }
 
+func (*ValidateSuite) TestNegativeExportTimeout(c *gc.C) {
+	config := validConfig()
+	config.ExportTimeout = -time.Second
+	checkNotValid(c, config, "negative ExportTimeout not valid")
+}
+
+func (*ValidateSuite) TestNegativeHoldTimeout(c *gc.C) {
+	config := validConfig()
+	config.HoldTimeout = -time.Second
+	checkNotValid(c, config, "negative HoldTimeout not valid")
+}
+
+func (*ValidateSuite) TestNegativeMinionReportWorkers(c *gc.C) {
+	config := validConfig()
+	config.MinionReportWorkers = -1
+	checkNotValid(c, config, "negative MinionReportWorkers not valid")
+}
+
+func (*ValidateSuite) TestBadProgressWebhookURL(c *gc.C) {
+	config := validConfig()
+	config.ProgressWebhook = migrationmaster.WebhookConfig{URL: "ftp://example.com/hook"}
+	checkNotValid(c, config, `webhook URL "ftp://example.com/hook" not valid`)
+}
+
+func (*ValidateSuite) TestProgressWebhookAuthorizationWithoutURL(c *gc.C) {
+	config := validConfig()
+	config.ProgressWebhook = migrationmaster.WebhookConfig{Authorization: "Bearer sekrit"}
+	checkNotValid(c, config, "webhook Authorization without URL not valid")
+}
+
+func (*ValidateSuite) TestNegativeValidationSettleDelay(c *gc.C) {
+	config := validConfig()
+	config.ValidationSettleDelay = -time.Second
+	checkNotValid(c, config, "negative ValidationSettleDelay not valid")
+}
+
+func (*ValidateSuite) TestNegativeLogTransferBatchSize(c *gc.C) {
+	config := validConfig()
+	config.LogTransferBatchSize = -1
+	checkNotValid(c, config, "negative LogTransferBatchSize not valid")
+}
+
+func (*ValidateSuite) TestNegativeBinaryDownloadConcurrency(c *gc.C) {
+	config := validConfig()
+	config.BinaryDownloadConcurrency = -1
+	checkNotValid(c, config, "negative BinaryDownloadConcurrency not valid")
+}
+
+func (*ValidateSuite) TestNegativeBinaryUploadConcurrency(c *gc.C) {
+	config := validConfig()
+	config.BinaryUploadConcurrency = -1
+	checkNotValid(c, config, "negative BinaryUploadConcurrency not valid")
+}
+
+func (*ValidateSuite) TestNegativeBinaryUploadRateLimit(c *gc.C) {
+	config := validConfig()
+	config.BinaryUploadRateLimit = -1
+	checkNotValid(c, config, "negative BinaryUploadRateLimit not valid")
+}
+
+func (*ValidateSuite) TestNegativeReapTimeout(c *gc.C) {
+	config := validConfig()
+	config.ReapTimeout = -time.Second
+	checkNotValid(c, config, "negative ReapTimeout not valid")
+}
+
+func (*ValidateSuite) TestNegativeReapAttempts(c *gc.C) {
+	config := validConfig()
+	config.ReapAttempts = -1
+	checkNotValid(c, config, "negative ReapAttempts not valid")
+}
+
+func (*ValidateSuite) TestNegativeAPIOpenAttempts(c *gc.C) {
+	config := validConfig()
+	config.APIOpenAttempts = -1
+	checkNotValid(c, config, "negative APIOpenAttempts not valid")
+}
+
+func (*ValidateSuite) TestPhasePlan(c *gc.C) {
+	config := validConfig()
+	config.PhasePlan = []coremigration.Phase{
+		coremigration.QUIESCE,
+		coremigration.READONLY,
+		coremigration.PRECHECK,
+		coremigration.IMPORT,
+		coremigration.VALIDATION,
+	}
+	c.Check(config.Validate(), jc.ErrorIsNil)
+}
+
+func (*ValidateSuite) TestPhasePlanBadStart(c *gc.C) {
+	config := validConfig()
+	config.PhasePlan = []coremigration.Phase{
+		coremigration.READONLY,
+		coremigration.PRECHECK,
+		coremigration.IMPORT,
+		coremigration.VALIDATION,
+	}
+	checkNotValid(c, config, "PhasePlan starting with READONLY not valid")
+}
+
+func (*ValidateSuite) TestPhasePlanBadEnd(c *gc.C) {
+	// Phases from VALIDATION on can't be planned.
+	config := validConfig()
+	config.PhasePlan = []coremigration.Phase{
+		coremigration.QUIESCE,
+		coremigration.READONLY,
+		coremigration.PRECHECK,
+		coremigration.IMPORT,
+		coremigration.VALIDATION,
+		coremigration.SUCCESS,
+	}
+	checkNotValid(c, config, "PhasePlan ending with SUCCESS not valid")
+}
+
+func (*ValidateSuite) TestPhasePlanInvalidTransition(c *gc.C) {
+	config := validConfig()
+	config.PhasePlan = []coremigration.Phase{
+		coremigration.QUIESCE,
+		coremigration.PRECHECK,
+		coremigration.IMPORT,
+		coremigration.VALIDATION,
+	}
+	checkNotValid(c, config, "PhasePlan transition from QUIESCE to PRECHECK not valid")
+}
+
 func validConfig() migrationmaster.Config {
 	return migrationmaster.Config{
 		Guard:           struct{ fortress.Guard }{},
@@ -0,0 +1,147 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationmaster
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// webhookQueueSize is the number of progress events which may be
+	// waiting to be sent to the progress webhook. Further events are
+	// dropped until the queue drains.
+	webhookQueueSize = 16
+
+	// webhookTimeout bounds the time spent sending a single progress
+	// event to the progress webhook.
+	webhookTimeout = 10 * time.Second
+)
+
+// WebhookConfig holds the details of an HTTP endpoint which is sent
+// migration progress events.
+type WebhookConfig struct {
+	// URL is the http or https address which events are POSTed to.
+	// If it is empty, no events are sent.
+	URL string
+
+	// Authorization, if set, is sent as the value of the
+	// Authorization header of each request, for example
+	// "Bearer <token>".
+	Authorization string
+}
+
+// Validate returns an error if the webhook config is not valid.
+func (c WebhookConfig) Validate() error {
+	if c.URL == "" {
+		if c.Authorization != "" {
+			return errors.NotValidf("webhook Authorization without URL")
+		}
+		return nil
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.NotValidf("webhook URL %q", c.URL)
+	}
+	return nil
+}
+
+// ProgressEvent is the JSON document POSTed to the progress webhook
+// when the migration phase changes, and periodically while waiting
+// for the model's agents.
+type ProgressEvent struct {
+	ModelUUID string    `json:"model-uuid"`
+	Phase     string    `json:"phase"`
+	Progress  string    `json:"progress,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// progressNotifier sends progress events to a webhook from its own
+// goroutine, so that a slow or failing endpoint never holds up a
+// migration.
+type progressNotifier struct {
+	config WebhookConfig
+	client *http.Client
+	events chan ProgressEvent
+}
+
+// newProgressNotifier starts a progressNotifier which sends events
+// until stop is closed. Events which are already queued when stop is
+// closed are still sent, so that the final phase change isn't lost.
+func newProgressNotifier(config WebhookConfig, stop <-chan struct{}) *progressNotifier {
+	n := &progressNotifier{
+		config: config,
+		client: &http.Client{Timeout: webhookTimeout},
+		events: make(chan ProgressEvent, webhookQueueSize),
+	}
+	go n.loop(stop)
+	return n
+}
+
+// notify queues an event to be sent. It never blocks: if the queue is
+// full, the event is dropped. It is safe to call on a nil
+// progressNotifier, which does nothing.
+func (n *progressNotifier) notify(event ProgressEvent) {
+	if n == nil {
+		return
+	}
+	select {
+	case n.events <- event:
+	default:
+		logger.Warningf("dropping migration progress event for phase %s: webhook queue is full", event.Phase)
+	}
+}
+
+func (n *progressNotifier) loop(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			for {
+				select {
+				case event := <-n.events:
+					n.send(event)
+				default:
+					return
+				}
+			}
+		case event := <-n.events:
+			n.send(event)
+		}
+	}
+}
+
+func (n *progressNotifier) send(event ProgressEvent) {
+	if err := n.post(event); err != nil {
+		logger.Warningf("failed to send migration progress event: %v", err)
+	}
+}
+
+func (n *progressNotifier) post(event ProgressEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.config.Authorization != "" {
+		req.Header.Set("Authorization", n.config.Authorization)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationmaster_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/migrationmaster"
+	"github.com/juju/juju/worker/workertest"
+)
+
+// startWebhookServer starts an HTTP server which decodes the progress
+// events POSTed to it and passes them on the returned channel. Each
+// request is answered with the given status code.
+func startWebhookServer(c *gc.C, code int) (*httptest.Server, <-chan migrationmaster.ProgressEvent) {
+	events := make(chan migrationmaster.ProgressEvent, 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, gc.Equals, "POST")
+		c.Check(r.Header.Get("Content-Type"), gc.Equals, "application/json")
+		c.Check(r.Header.Get("Authorization"), gc.Equals, "Bearer sekrit")
+		var event migrationmaster.ProgressEvent
+		err := json.NewDecoder(r.Body).Decode(&event)
+		c.Check(err, jc.ErrorIsNil)
+		w.WriteHeader(code)
+		events <- event
+	}))
+	return server, events
+}
+
+// waitForPhases collects the phases of the events received until the
+// DONE phase is seen.
+func waitForPhases(c *gc.C, events <-chan migrationmaster.ProgressEvent) []string {
+	var phases []string
+	for len(phases) == 0 || phases[len(phases)-1] != "DONE" {
+		select {
+		case event := <-events:
+			c.Check(event.ModelUUID, gc.Equals, "model-uuid")
+			phases = append(phases, event.Phase)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for progress events, got %v", phases)
+		}
+	}
+	return phases
+}
+
+func (s *Suite) TestProgressWebhook(c *gc.C) {
+	server, events := startWebhookServer(c, http.StatusOK)
+	defer server.Close()
+	s.config.ProgressWebhook = migrationmaster.WebhookConfig{
+		URL:           server.URL,
+		Authorization: "Bearer sekrit",
+	}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	c.Assert(waitForPhases(c, events), jc.DeepEquals, []string{
+		"READONLY", "PRECHECK", "IMPORT", "VALIDATION",
+		"SUCCESS", "LOGTRANSFER", "REAP", "DONE",
+	})
+}
+
+func (s *Suite) TestProgressWebhookFailure(c *gc.C) {
+	server, events := startWebhookServer(c, http.StatusInternalServerError)
+	defer server.Close()
+	s.config.ProgressWebhook = migrationmaster.WebhookConfig{
+		URL:           server.URL,
+		Authorization: "Bearer sekrit",
+	}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	// The migration completes regardless of the webhook failing.
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	waitForPhases(c, events)
+
+	const expect = "failed to send migration progress event: webhook returned 500 Internal Server Error"
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if strings.Contains(c.GetTestLog(), expect) {
+			return
+		}
+	}
+	c.Fatalf("webhook failure not logged")
+}
@@ -11,13 +11,18 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/migrationmaster"
 	"github.com/juju/juju/api/migrationtarget"
 	"github.com/juju/juju/apiserver/params"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
 	"github.com/juju/juju/worker/dependency"
@@ -42,6 +47,59 @@ const (
 	// messages, while the migrationmaster is waiting for reports from
 	// minions.
 	minionWaitLogInterval = 30 * time.Second
+
+	// reapRetryDelay is the time that the migrationmaster waits
+	// before trying again to remove a migrated model from the source
+	// controller after a previous attempt failed.
+	reapRetryDelay = 5 * time.Minute
+
+	// reapPollInterval is how often the migrationmaster checks
+	// whether a reaped model's documents have all been removed, when
+	// Config.ReapTimeout is set.
+	reapPollInterval = 10 * time.Second
+
+	// apiOpenRetryDelay is how long the migrationmaster waits after
+	// a first failed attempt to connect to the target controller.
+	// The delay doubles after each further failure.
+	apiOpenRetryDelay = time.Second
+
+	// holdPollInterval is how often the migrationmaster checks
+	// whether a migration in the HOLD phase has been approved.
+	holdPollInterval = time.Minute
+
+	// reapRetryPollInterval is how often the migrationmaster checks
+	// whether an operator has asked for the removal of a migrated
+	// model to be retried, while in the REAPFAILED phase.
+	reapRetryPollInterval = time.Minute
+
+	// DefaultExportTimeout is the maximum time that the
+	// migrationmaster will wait for the model to be exported if
+	// Config.ExportTimeout isn't set.
+	DefaultExportTimeout = time.Hour
+
+	// DefaultMinionReportWorkers is the number of goroutines used to
+	// process minion reports if Config.MinionReportWorkers isn't set.
+	DefaultMinionReportWorkers = 1
+
+	// DefaultValidationSettleDelay is the time that the manifold
+	// configures the migrationmaster to wait for the target model's
+	// agents to start before validating an imported model.
+	DefaultValidationSettleDelay = 10 * time.Second
+
+	// DefaultLogTransferBatchSize is the maximum number of log
+	// records sent to the target controller in each message during
+	// LOGTRANSFER if Config.LogTransferBatchSize isn't set.
+	DefaultLogTransferBatchSize = 1000
+
+	// DefaultAPIOpenAttempts is the number of times the
+	// migrationmaster tries to connect to the target controller
+	// before giving up if Config.APIOpenAttempts isn't set.
+	DefaultAPIOpenAttempts = 3
+
+	// DefaultReapAttempts is the number of times the migrationmaster
+	// tries to remove a migrated model from the source controller
+	// before moving to REAPFAILED if Config.ReapAttempts isn't set.
+	DefaultReapAttempts = 3
 )
 
 // Facade exposes controller functionality to a Worker.
@@ -54,14 +112,36 @@ type Facade interface {
 	// latest model migration.
 	GetMigrationStatus() (coremigration.MigrationStatus, error)
 
+	// WatchForAbort returns a watcher which reports when an operator
+	// requests that the active migration be aborted. As usual, an
+	// initial event is sent when the watcher starts.
+	WatchForAbort() (watcher.NotifyWatcher, error)
+
 	// SetPhase updates the phase of the currently active model
 	// migration.
 	SetPhase(coremigration.Phase) error
 
+	// SetStatusMessage sets a human readable message describing the
+	// progress of the currently active model migration.
+	SetStatusMessage(string) error
+
+	// ModelInfo returns basic information about the model to be
+	// migrated.
+	ModelInfo() (coremigration.ModelInfo, error)
+
 	// Export returns a serialized representation of the model
 	// associated with the API connection.
 	Export() (coremigration.SerializedModel, error)
 
+	// ModelCredential returns the cloud credential used by the model
+	// associated with the API connection. A zero CloudCredential is
+	// returned if the model doesn't use a credential.
+	ModelCredential() (coremigration.CloudCredential, error)
+
+	// StoragePools returns the storage pools which are in use by the
+	// model associated with the API connection.
+	StoragePools() ([]coremigration.StoragePool, error)
+
 	// Reap removes all documents of the model associated with the API
 	// connection.
 	Reap() error
@@ -73,6 +153,22 @@ type Facade interface {
 	// GetMinionReports returns details of the reports made by migration
 	// minions to the controller for the current migration phase.
 	GetMinionReports() (coremigration.MinionReports, error)
+
+	// SetMinionWaitProgress records the minion reports received so
+	// far for the current migration phase, so that progress can be
+	// shown while waiting for minions.
+	SetMinionWaitProgress(coremigration.MinionReports) error
+
+	// StreamModelLog returns a channel which yields the log records
+	// of the model associated with the API connection from the given
+	// start time onwards. The channel is closed once the records
+	// logged before the call have been sent; if the stream fails, the
+	// final result holds the error. Closing stop abandons the stream.
+	StreamModelLog(start time.Time, stop <-chan struct{}) (<-chan migrationmaster.LogRecordResult, error)
+
+	// ReapComplete returns whether all the documents of the model
+	// associated with the API connection have been removed.
+	ReapComplete() (bool, error)
 }
 
 // Config defines the operation of a Worker.
@@ -84,6 +180,122 @@ type Config struct {
 	CharmDownloader migration.CharmDownloader
 	ToolsDownloader migration.ToolsDownloader
 	Clock           clock.Clock
+
+	// ExportTimeout bounds the time spent waiting for the model to
+	// be exported. DefaultExportTimeout is used if it is zero.
+	ExportTimeout time.Duration
+
+	// AgentStream, if set, names the agent stream (e.g. "released" or
+	// "proposed") that the target controller is expected to serve
+	// tools from. The migration is aborted during PRECHECK if the
+	// target doesn't support it. If empty, no check is made.
+	AgentStream string
+
+	// HoldTimeout, if non-zero, causes migrations to be held in the
+	// HOLD phase after VALIDATION until they are approved by an
+	// external system. A migration which isn't approved within
+	// HoldTimeout of entering HOLD is aborted.
+	HoldTimeout time.Duration
+
+	// VerifyImport, if true, causes the model to be exported from
+	// the target controller during VALIDATION and compared with the
+	// source model. The migration is aborted if anything other than
+	// cosmetic differences are found.
+	VerifyImport bool
+
+	// MinionReportWorkers bounds the number of minion report fetches
+	// which may be processed concurrently while waiting for minions.
+	// Processing happens away from the worker's main loop so that it
+	// stays responsive to being killed. DefaultMinionReportWorkers is
+	// used if it is zero.
+	MinionReportWorkers int
+
+	// ProgressWebhook, if its URL is set, names an HTTP endpoint
+	// which is sent a JSON ProgressEvent on each phase change and
+	// periodically while waiting for minions. Failures to send
+	// events are logged but never affect the migration.
+	ProgressWebhook WebhookConfig
+
+	// ValidationSettleDelay is how long the worker waits, once a
+	// model has been imported, before starting to validate it. This
+	// gives the target controller's agents time to start up, so that
+	// they don't eat into the time allowed for validation. There is
+	// no delay if it is zero.
+	ValidationSettleDelay time.Duration
+
+	// Tracer, if not nil, is used to record each phase of the
+	// migration as a span.
+	Tracer Tracer
+
+	// MinionReportAuditFile, if set, is the path of a file to which
+	// each set of minion reports processed while waiting for minions
+	// is appended, as a line of JSON holding a MinionAuditRecord.
+	MinionReportAuditFile string
+
+	// PhasePlan, if set, overrides the order in which the phases up
+	// to VALIDATION are run. It must start with QUIESCE and end with
+	// VALIDATION, and each step must be a valid phase transition.
+	// VALIDATION and the phases after it always run in their usual
+	// order, and a phase may still move to ABORT. If empty, the
+	// default order is used.
+	PhasePlan []coremigration.Phase
+
+	// LogTransferBatchSize bounds the number of log records sent to
+	// the target controller in each message during LOGTRANSFER.
+	// DefaultLogTransferBatchSize is used if it is zero.
+	LogTransferBatchSize int
+
+	// BinaryDownloadConcurrency bounds the number of charms and tools
+	// downloaded from the source controller at once during IMPORT.
+	// migration.DefaultDownloadConcurrency is used if it is zero.
+	BinaryDownloadConcurrency int
+
+	// BinaryUploadConcurrency bounds the number of charms and tools
+	// uploaded to the target controller at once during IMPORT. One
+	// binary is uploaded at a time if it is zero.
+	BinaryUploadConcurrency int
+
+	// BinaryUploadRateLimit limits the rate, in bytes per second, at
+	// which each binary is uploaded to the target controller during
+	// IMPORT. Zero means unlimited.
+	BinaryUploadRateLimit int64
+
+	// ReapTimeout, if non-zero, causes the worker to confirm that
+	// the source model's documents have all been removed after
+	// reaping, by polling Facade.ReapComplete, before reporting the
+	// migration DONE. If the removal isn't confirmed within
+	// ReapTimeout, the attempt to reap the model has failed.
+	ReapTimeout time.Duration
+
+	// ReapAttempts bounds the number of times the worker tries to
+	// remove the migrated model from the source controller, waiting
+	// a few minutes between attempts, before giving up and moving
+	// the migration to REAPFAILED. DefaultReapAttempts is used if it
+	// is zero.
+	ReapAttempts int
+
+	// APIOpenAttempts bounds the number of times the worker tries to
+	// connect to the target controller before giving up, so that a
+	// brief network problem doesn't cause a migration to be aborted.
+	// DefaultAPIOpenAttempts is used if it is zero.
+	APIOpenAttempts int
+
+	// DryRun, if true, causes migrations to stop once PRECHECK has
+	// passed, so that operators can check that a model could be
+	// migrated without committing to it. Nothing is written to the
+	// target controller: once the prechecks pass the migration moves
+	// to DRYRUNDONE, leaving the model in place on the source
+	// controller. A failed dry run is aborted as usual.
+	DryRun bool
+
+	// OnPhaseChange, if not nil, is called on the worker's goroutine
+	// each time the worker sets the migration's phase, with the
+	// phases moved from and to, and any error from setting the
+	// phase. It's normally called once the phase has been set, with
+	// a nil error; if setting the phase fails it is called with the
+	// error and the worker then exits. It should return promptly. A
+	// panic in OnPhaseChange is logged and otherwise ignored.
+	OnPhaseChange func(from, to coremigration.Phase, err error)
 }
 
 // Validate returns an error if config cannot drive a Worker.
@@ -109,6 +321,66 @@ func (config Config) Validate() error {
 	if config.Clock == nil {
 		return errors.NotValidf("nil Clock")
 	}
+	if config.ExportTimeout < 0 {
+		return errors.NotValidf("negative ExportTimeout")
+	}
+	if config.HoldTimeout < 0 {
+		return errors.NotValidf("negative HoldTimeout")
+	}
+	if config.MinionReportWorkers < 0 {
+		return errors.NotValidf("negative MinionReportWorkers")
+	}
+	if config.ValidationSettleDelay < 0 {
+		return errors.NotValidf("negative ValidationSettleDelay")
+	}
+	if config.LogTransferBatchSize < 0 {
+		return errors.NotValidf("negative LogTransferBatchSize")
+	}
+	if config.BinaryDownloadConcurrency < 0 {
+		return errors.NotValidf("negative BinaryDownloadConcurrency")
+	}
+	if config.BinaryUploadConcurrency < 0 {
+		return errors.NotValidf("negative BinaryUploadConcurrency")
+	}
+	if config.BinaryUploadRateLimit < 0 {
+		return errors.NotValidf("negative BinaryUploadRateLimit")
+	}
+	if config.ReapTimeout < 0 {
+		return errors.NotValidf("negative ReapTimeout")
+	}
+	if config.ReapAttempts < 0 {
+		return errors.NotValidf("negative ReapAttempts")
+	}
+	if config.APIOpenAttempts < 0 {
+		return errors.NotValidf("negative APIOpenAttempts")
+	}
+	if err := config.ProgressWebhook.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validatePhasePlan(config.PhasePlan); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// validatePhasePlan returns an error if plan can't be used as a
+// Config.PhasePlan.
+func validatePhasePlan(plan []coremigration.Phase) error {
+	if len(plan) == 0 {
+		return nil
+	}
+	if first := plan[0]; first != coremigration.QUIESCE {
+		return errors.NotValidf("PhasePlan starting with %s", first)
+	}
+	if last := plan[len(plan)-1]; last != coremigration.VALIDATION {
+		return errors.NotValidf("PhasePlan ending with %s", last)
+	}
+	for i := 1; i < len(plan); i++ {
+		from, to := plan[i-1], plan[i]
+		if !from.CanTransitionTo(to) {
+			return errors.NotValidf("PhasePlan transition from %s to %s", from, to)
+		}
+	}
 	return nil
 }
 
@@ -117,8 +389,22 @@ func New(config Config) (*Worker, error) {
 	if err := config.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if config.ExportTimeout == 0 {
+		config.ExportTimeout = DefaultExportTimeout
+	}
+	if config.MinionReportWorkers == 0 {
+		config.MinionReportWorkers = DefaultMinionReportWorkers
+	}
+	if config.LogTransferBatchSize == 0 {
+		config.LogTransferBatchSize = DefaultLogTransferBatchSize
+	}
+	if config.APIOpenAttempts == 0 {
+		config.APIOpenAttempts = DefaultAPIOpenAttempts
+	}
 	w := &Worker{
 		config: config,
+		logger: migrationLogger{logger: logger},
+		span:   nopSpan{},
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
@@ -135,6 +421,15 @@ func New(config Config) (*Worker, error) {
 type Worker struct {
 	catacomb catacomb.Catacomb
 	config   Config
+	notifier *progressNotifier
+	audit    *minionAudit
+
+	// logger is used for messages about the migration being run,
+	// once it is known.
+	logger migrationLogger
+
+	// span records the phase currently being run.
+	span Span
 }
 
 // Kill implements worker.Worker.
@@ -160,34 +455,59 @@ func (w *Worker) run() error {
 		return errors.Trace(err)
 	}
 
-	// TODO(mjs) - log messages should indicate the model name and
-	// UUID. Independent logger per migration instance?
+	w.logger = newMigrationLogger(status)
+
+	abortChanges, err := w.watchForAbort()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if w.config.ProgressWebhook.URL != "" {
+		w.notifier = newProgressNotifier(w.config.ProgressWebhook, w.catacomb.Dying())
+	}
+	if w.config.MinionReportAuditFile != "" {
+		w.audit, err = openMinionAudit(w.config.MinionReportAuditFile)
+		if err != nil {
+			return errors.Annotate(err, "opening minion report audit file")
+		}
+		defer func() {
+			if err := w.audit.Close(); err != nil {
+				w.logger.Warningf("failed to close minion report audit file: %v", err)
+			}
+		}()
+	}
 
 	phase := status.Phase
 	for {
+		w.startPhaseSpan(status)
 		var err error
 		switch phase {
 		case coremigration.QUIESCE:
-			phase, err = w.doQUIESCE()
+			phase, err = w.doQUIESCE(status)
 		case coremigration.READONLY:
 			phase, err = w.doREADONLY()
 		case coremigration.PRECHECK:
-			phase, err = w.doPRECHECK()
+			phase, err = w.doPRECHECK(status.TargetInfo)
 		case coremigration.IMPORT:
 			phase, err = w.doIMPORT(status.TargetInfo, status.ModelUUID)
 		case coremigration.VALIDATION:
 			phase, err = w.doVALIDATION(status.TargetInfo, status.ModelUUID)
+		case coremigration.HOLD:
+			phase, err = w.doHOLD(abortChanges, status.TargetInfo, status.ModelUUID)
 		case coremigration.SUCCESS:
 			phase, err = w.doSUCCESS(status)
 		case coremigration.LOGTRANSFER:
-			phase, err = w.doLOGTRANSFER()
+			phase, err = w.doLOGTRANSFER(status.TargetInfo, status.ModelUUID)
 		case coremigration.REAP:
 			phase, err = w.doREAP()
+		case coremigration.REAPFAILED:
+			phase, err = w.doREAPFAILED()
 		case coremigration.ABORT:
 			phase, err = w.doABORT(status.TargetInfo, status.ModelUUID)
 		default:
-			return errors.Errorf("unknown phase: %v [%d]", phase.String(), phase)
+			err = errors.Errorf("unknown phase: %v [%d]", phase.String(), phase)
 		}
+		w.endPhaseSpan(phase, err)
 
 		if err != nil {
 			// A phase handler should only return an error if the
@@ -197,16 +517,21 @@ func (w *Worker) run() error {
 			// i.e. ABORT or REAPFAILED)
 			return errors.Trace(err)
 		}
+		phase = w.plannedPhase(status.Phase, phase)
+		phase = w.checkForAbort(abortChanges, status.Phase, phase)
 
 		if w.killed() {
 			return w.catacomb.ErrDying()
 		}
 
-		logger.Infof("setting migration phase to %s", phase)
-		if err := w.config.Facade.SetPhase(phase); err != nil {
+		w.logger.Infof("setting migration phase to %s", phase)
+		err = w.config.Facade.SetPhase(phase)
+		w.phaseChanged(status.Phase, phase, err)
+		if err != nil {
 			return errors.Annotate(err, "failed to set phase")
 		}
 		status.Phase = phase
+		w.notifyProgress(status, "")
 
 		if modelHasMigrated(phase) {
 			// TODO(mjs) - use manifold Filter so that the dep engine
@@ -219,6 +544,90 @@ func (w *Worker) run() error {
 	}
 }
 
+// phaseChanged reports a phase change to the configured
+// OnPhaseChange callback, if any, recovering from any panic so that
+// a broken callback can't take down the migration.
+func (w *Worker) phaseChanged(from, to coremigration.Phase, err error) {
+	if w.config.OnPhaseChange == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Errorf("phase change callback panicked: %v", r)
+		}
+	}()
+	w.config.OnPhaseChange(from, to, err)
+}
+
+// plannedPhase returns the phase to move to from current, given the
+// phase chosen by current's handler. The configured PhasePlan wins
+// unless the handler chose to abort the migration or to end a dry
+// run.
+func (w *Worker) plannedPhase(current, next coremigration.Phase) coremigration.Phase {
+	if next == coremigration.ABORT || next == coremigration.DRYRUNDONE {
+		return next
+	}
+	plan := w.config.PhasePlan
+	for i := 0; i < len(plan)-1; i++ {
+		if plan[i] == current {
+			return plan[i+1]
+		}
+	}
+	return next
+}
+
+// watchForAbort returns a channel which receives a value when an
+// operator requests that the migration be aborted. If the controller
+// can't report abort requests, the returned channel is nil, so no
+// abort is ever requested.
+func (w *Worker) watchForAbort() (watcher.NotifyChannel, error) {
+	abortWatcher, err := w.config.Facade.WatchForAbort()
+	if params.IsCodeNotImplemented(err) {
+		w.logger.Infof("controller can't report abort requests, not watching for them")
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "watching for abort requests")
+	}
+	if err := w.catacomb.Add(abortWatcher); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Discard the initial event; only later ones are requests.
+	select {
+	case <-w.catacomb.Dying():
+		return nil, w.catacomb.ErrDying()
+	case <-abortWatcher.Changes():
+	}
+	return abortWatcher.Changes(), nil
+}
+
+// checkForAbort returns ABORT in place of next if an operator has
+// requested that the migration be aborted, so long as it's not too
+// late: once SUCCESS is reached there's no turning back. current is
+// the phase which has just been run.
+func (w *Worker) checkForAbort(
+	abortChanges watcher.NotifyChannel, current, next coremigration.Phase,
+) coremigration.Phase {
+	select {
+	case _, ok := <-abortChanges:
+		if !ok {
+			// The watcher has stopped; the catacomb deals with it.
+			return next
+		}
+	default:
+		return next
+	}
+	switch {
+	case current == coremigration.ABORT || next == coremigration.ABORT:
+		return next
+	case current.CanTransitionTo(coremigration.ABORT):
+		w.logger.Infof("abort requested in phase %s, aborting migration", current)
+		return coremigration.ABORT
+	default:
+		w.logger.Errorf("abort requested in phase %s rejected: migration can't be aborted after SUCCESS", current)
+		return next
+	}
+}
+
 func (w *Worker) killed() bool {
 	select {
 	case <-w.catacomb.Dying():
@@ -228,9 +637,20 @@ func (w *Worker) killed() bool {
 	}
 }
 
-func (w *Worker) doQUIESCE() (coremigration.Phase, error) {
-	// TODO(mjs) - Wait for all agents to report back.
-	return coremigration.READONLY, nil
+func (w *Worker) doQUIESCE(status coremigration.MigrationStatus) (coremigration.Phase, error) {
+	// Wait for all agents to report back.
+	err := w.waitForMinions(status, waitForAll)
+	switch errors.Cause(err) {
+	case nil:
+		return coremigration.READONLY, nil
+	case errMinionReportFailed, errMinionReportTimeout:
+		// Nothing has been imported into the target controller yet,
+		// so it's safe to abort.
+		w.logger.Errorf("model agents failed to quiesce: %v", err)
+		return coremigration.ABORT, nil
+	default:
+		return coremigration.QUIESCE, errors.Trace(err)
+	}
 }
 
 func (w *Worker) doREADONLY() (coremigration.Phase, error) {
@@ -238,64 +658,380 @@ func (w *Worker) doREADONLY() (coremigration.Phase, error) {
 	return coremigration.PRECHECK, nil
 }
 
-func (w *Worker) doPRECHECK() (coremigration.Phase, error) {
-	// TODO(mjs) - To be implemented.
+func (w *Worker) doPRECHECK(targetInfo coremigration.TargetInfo) (coremigration.Phase, error) {
+	if err := w.checkStorageCompatibility(targetInfo); err != nil {
+		w.logger.Errorf("storage precheck failed: %v", err)
+		return coremigration.ABORT, nil
+	}
+	if err := w.checkAgentStream(targetInfo); err != nil {
+		w.logger.Errorf("agent stream precheck failed: %v", err)
+		return coremigration.ABORT, nil
+	}
+	if err := w.checkTarget(targetInfo); err != nil {
+		w.logger.Errorf("target precheck failed: %v", err)
+		return coremigration.ABORT, nil
+	}
+	if w.config.DryRun {
+		w.logger.Infof("prechecks passed, ending dry run")
+		if err := w.config.Facade.SetStatusMessage(dryRunSucceededMessage); err != nil {
+			w.logger.Warningf("failed to record dry run result: %v", err)
+		}
+		return coremigration.DRYRUNDONE, nil
+	}
 	return coremigration.IMPORT, nil
 }
 
-func (w *Worker) doIMPORT(targetInfo coremigration.TargetInfo, modelUUID string) (coremigration.Phase, error) {
-	logger.Infof("exporting model")
-	serialized, err := w.config.Facade.Export()
+// dryRunSucceededMessage is recorded as the migration's status
+// message when a dry run gets through PRECHECK.
+const dryRunSucceededMessage = "dry run succeeded: model can be migrated"
+
+// checkTarget returns an error if the target controller reports that
+// it can't accept the model.
+func (w *Worker) checkTarget(targetInfo coremigration.TargetInfo) error {
+	model, err := w.config.Facade.ModelInfo()
+	if err != nil {
+		return errors.Annotate(err, "retrieving model info")
+	}
+	conn, err := w.openAPIConn(targetInfo)
+	if err != nil {
+		return errors.Annotate(err, "connecting to target controller")
+	}
+	defer conn.Close()
+	targetClient := migrationtarget.NewClient(conn)
+	return errors.Trace(targetClient.Prechecks(model))
+}
+
+// checkAgentStream returns an error if an agent stream has been
+// configured for the migration and the target controller can't
+// serve tools from it.
+func (w *Worker) checkAgentStream(targetInfo coremigration.TargetInfo) error {
+	stream := w.config.AgentStream
+	if stream == "" {
+		return nil
+	}
+
+	conn, err := w.openAPIConn(targetInfo)
 	if err != nil {
-		logger.Errorf("model export failed: %v", err)
+		return errors.Annotate(err, "connecting to target controller")
+	}
+	defer conn.Close()
+	targetClient := migrationtarget.NewClient(conn)
+	streams, err := targetClient.AgentStreams()
+	if err != nil {
+		return errors.Annotate(err, "retrieving target agent streams")
+	}
+	if !set.NewStrings(streams...).Contains(stream) {
+		return errors.Errorf("agent stream %q not supported by the target controller (supported: %s)",
+			stream, strings.Join(streams, ", "))
+	}
+	return nil
+}
+
+// checkStorageCompatibility returns an error if the model uses
+// storage pools whose providers aren't supported by the target
+// controller's cloud. Pools backed by the generic providers
+// available on every cloud are always considered compatible, so the
+// target is only consulted if the model uses provider-specific
+// storage.
+func (w *Worker) checkStorageCompatibility(targetInfo coremigration.TargetInfo) error {
+	pools, err := w.config.Facade.StoragePools()
+	if err != nil {
+		return errors.Annotate(err, "retrieving model storage pools")
+	}
+	common := provider.CommonProviders()
+	var specific []coremigration.StoragePool
+	for _, pool := range pools {
+		if _, ok := common[storage.ProviderType(pool.Provider)]; !ok {
+			specific = append(specific, pool)
+		}
+	}
+	if len(specific) == 0 {
+		return nil
+	}
+
+	conn, err := w.openAPIConn(targetInfo)
+	if err != nil {
+		return errors.Annotate(err, "connecting to target controller")
+	}
+	defer conn.Close()
+	targetClient := migrationtarget.NewClient(conn)
+	providerTypes, err := targetClient.StorageProviderTypes()
+	if err != nil {
+		return errors.Annotate(err, "retrieving target storage providers")
+	}
+
+	supported := set.NewStrings(providerTypes...)
+	var incompatible []string
+	for _, pool := range specific {
+		if !supported.Contains(pool.Provider) {
+			incompatible = append(incompatible, pool.String())
+		}
+	}
+	if len(incompatible) > 0 {
+		return errors.Errorf("storage pools not supported by the target cloud: %s",
+			strings.Join(incompatible, ", "))
+	}
+	return nil
+}
+
+func (w *Worker) doIMPORT(targetInfo coremigration.TargetInfo, modelUUID string) (coremigration.Phase, error) {
+	w.logger.Infof("exporting model")
+	serialized, err := w.exportModel()
+	if w.killed() {
+		return coremigration.IMPORT, w.catacomb.ErrDying()
+	} else if err != nil {
+		w.logger.Errorf("model export failed: %v", err)
+		return coremigration.ABORT, nil
+	}
+	if err := coremigration.CheckCharmOrigins(serialized.Charms, serialized.CharmOrigins); err != nil {
+		w.logger.Errorf("cannot migrate model charms: %v", err)
 		return coremigration.ABORT, nil
 	}
 
-	logger.Infof("opening API connection to target controller")
+	w.logger.Infof("opening API connection to target controller")
 	conn, err := w.openAPIConn(targetInfo)
 	if err != nil {
-		logger.Errorf("failed to connect to target controller: %v", err)
+		w.logger.Errorf("failed to connect to target controller: %v", err)
 		return coremigration.ABORT, nil
 	}
 	defer conn.Close()
-
-	logger.Infof("importing model into target controller")
 	targetClient := migrationtarget.NewClient(conn)
+
+	w.logger.Infof("transferring model credential to target controller")
+	if err := w.transferCredential(targetClient); err != nil {
+		w.logger.Errorf("failed to transfer model credential: %v", err)
+		return coremigration.ABORT, nil
+	}
+
+	w.logger.Infof("importing model into target controller")
+	w.span.SetAttribute("model-bytes", len(serialized.Bytes))
 	err = targetClient.Import(serialized.Bytes)
 	if err != nil {
-		logger.Errorf("failed to import model into target controller: %v", err)
+		w.logger.Errorf("failed to import model into target controller: %v", err)
 		return coremigration.ABORT, nil
 	}
 
-	logger.Infof("opening API connection for target model")
+	binaries := migration.UploadBinariesConfig{
+		Charms:       serialized.Charms,
+		CharmOrigins: serialized.CharmOrigins,
+		CharmSHA256s: serialized.CharmSHA256s,
+		Tools:        serialized.Tools,
+		ToolsSHA256s: serialized.ToolsSHA256s,
+	}
+	if err := w.completeImport(
+		targetClient, targetInfo, modelUUID, binaries, serialized.FirewallRules,
+	); err != nil {
+		w.logger.Errorf("%v", err)
+		return coremigration.ABORT, nil
+	}
+	return coremigration.VALIDATION, nil
+}
+
+// completeImport recreates the imported model's firewall rules in
+// the target controller, and uploads the model's charms and tools.
+// The binaries to upload, and the sums to verify them against, are
+// taken from binaries; the worker supplies the means of transfer.
+func (w *Worker) completeImport(
+	targetClient migrationtarget.Client,
+	targetInfo coremigration.TargetInfo,
+	modelUUID string,
+	binaries migration.UploadBinariesConfig,
+	rules []coremigration.FirewallRule,
+) error {
+	if len(rules) > 0 {
+		w.logger.Infof("applying firewall rules in target controller")
+		err := w.applyFirewallRules(targetClient, modelUUID, rules)
+		if err != nil {
+			return errors.Annotate(err, "failed to apply firewall rules in target controller")
+		}
+	}
+
+	w.logger.Infof("opening API connection for target model")
 	targetModelConn, err := w.openAPIConnForModel(targetInfo, modelUUID)
 	if err != nil {
-		logger.Errorf("failed to open connection to target model: %v", err)
-		return coremigration.ABORT, nil
+		return errors.Annotate(err, "failed to open connection to target model")
 	}
 	defer targetModelConn.Close()
 	targetModelClient := targetModelConn.Client()
 
-	logger.Infof("uploading binaries into target model")
-	err = w.config.UploadBinaries(migration.UploadBinariesConfig{
-		Charms:          serialized.Charms,
-		CharmDownloader: w.config.CharmDownloader,
-		CharmUploader:   targetModelClient,
-		Tools:           serialized.Tools,
-		ToolsDownloader: w.config.ToolsDownloader,
-		ToolsUploader:   targetModelClient,
+	w.logger.Infof("uploading binaries into target model")
+	binaries.CharmDownloader = w.config.CharmDownloader
+	binaries.CharmUploader = targetModelClient
+	binaries.ToolsDownloader = w.config.ToolsDownloader
+	binaries.ToolsUploader = targetModelClient
+	binaries.BinaryVerifier = &binaryVerifier{targetClient, modelUUID, w.logger}
+	binaries.DownloadConcurrency = w.config.BinaryDownloadConcurrency
+	binaries.Concurrency = w.config.BinaryUploadConcurrency
+	binaries.UploadRateLimit = w.config.BinaryUploadRateLimit
+	binaries.Clock = w.config.Clock
+	binaries.Abort = w.catacomb.Dying()
+	err = w.config.UploadBinaries(binaries)
+	return errors.Annotate(err, "failed migration binaries")
+}
+
+// binaryVerifier adapts a target controller client to the
+// migration.BinaryVerifier interface for a single model.
+type binaryVerifier struct {
+	client    migrationtarget.Client
+	modelUUID string
+	logger    migrationLogger
+}
+
+// VerifyBinaries implements migration.BinaryVerifier. Target
+// controllers which can't verify binaries are trusted to have stored
+// them intact.
+func (v *binaryVerifier) VerifyBinaries(charms map[string]string, tools map[version.Binary]string) error {
+	err := v.client.VerifyBinaries(v.modelUUID, charms, tools)
+	if params.IsCodeNotImplemented(err) {
+		v.logger.Warningf("target controller can't verify uploaded binaries, skipping verification")
+		return nil
+	}
+	return err
+}
+
+var errExportTimeout = errors.New("model export timed out")
+
+// exportModel retrieves the serialized model from the controller,
+// giving up if the export takes longer than the configured
+// ExportTimeout or the worker is killed.
+func (w *Worker) exportModel() (coremigration.SerializedModel, error) {
+	var serialized coremigration.SerializedModel
+	err := w.runExport(func() (err error) {
+		serialized, err = w.config.Facade.Export()
+		return err
 	})
 	if err != nil {
-		logger.Errorf("failed migration binaries: %v", err)
-		return coremigration.ABORT, nil
+		return coremigration.SerializedModel{}, errors.Trace(err)
 	}
+	return serialized, nil
+}
 
-	return coremigration.VALIDATION, nil
+// runExport runs the given export function, giving up if it takes
+// longer than the configured ExportTimeout or the worker is killed.
+// The export function's results must only be used if runExport
+// returns nil.
+//
+// Giving up doesn't stop the export itself: API calls can't be
+// cancelled once made, and the controller exports the model in a
+// single pass over the database which can't be interrupted. The
+// export is left to finish in the background and its result is
+// discarded.
+func (w *Worker) runExport(export func() error) error {
+	// The channel is buffered so that the goroutine can finish even
+	// if the result is no longer wanted.
+	results := make(chan error, 1)
+	go func() {
+		results <- export()
+	}()
+
+	timeout := w.config.Clock.After(w.config.ExportTimeout)
+	select {
+	case <-w.catacomb.Dying():
+		return w.catacomb.ErrDying()
+	case <-timeout:
+		return errors.Trace(errExportTimeout)
+	case err := <-results:
+		return errors.Trace(err)
+	}
+}
+
+// transferCredential ensures that the target controller has the cloud
+// credential used by the model. If the target already has an
+// identical credential (for example, because an earlier migration
+// attempt transferred it) nothing is sent.
+func (w *Worker) transferCredential(targetClient migrationtarget.Client) error {
+	cred, err := w.config.Facade.ModelCredential()
+	if err != nil {
+		return errors.Annotate(err, "retrieving model credential")
+	}
+	if cred.IsZero() {
+		w.logger.Debugf("model has no credential, nothing to transfer")
+		return nil
+	}
+
+	targetHash, err := targetClient.CredentialHash(cred)
+	switch {
+	case params.IsCodeNotFound(err):
+		// The target doesn't have the credential - send it.
+	case err != nil:
+		return errors.Annotate(err, "checking target credential")
+	case targetHash == cred.Hash():
+		w.logger.Infof("target controller already has credential %q, skipping transfer", cred.Name)
+		return nil
+	default:
+		return errors.Errorf(
+			"target controller has a different credential named %q for cloud %q owned by %q; "+
+				"remove or rename that credential on the target and retry the migration",
+			cred.Name, cred.Cloud, cred.Owner.Id())
+	}
+
+	err = targetClient.UploadCredential(cred)
+	return errors.Annotate(err, "uploading credential")
+}
+
+// applyFirewallRules asks the target controller to recreate the
+// model's firewall rules. Rules which the target's provider can't
+// recreate (because of provider differences) don't stop the
+// migration but are reported as warnings.
+func (w *Worker) applyFirewallRules(
+	targetClient migrationtarget.Client,
+	modelUUID string,
+	rules []coremigration.FirewallRule,
+) error {
+	untranslated, err := targetClient.ApplyFirewallRules(modelUUID, rules)
+	if params.IsCodeNotImplemented(err) {
+		untranslated = make([]coremigration.UntranslatedFirewallRule, len(rules))
+		for i, rule := range rules {
+			untranslated[i] = coremigration.UntranslatedFirewallRule{
+				Rule:   rule,
+				Reason: "not supported by target controller",
+			}
+		}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if len(untranslated) > 0 {
+		w.logger.Warningf(formatUntranslatedFirewallRules(untranslated))
+	}
+	return nil
+}
+
+func formatUntranslatedFirewallRules(untranslated []coremigration.UntranslatedFirewallRule) string {
+	descs := make([]string, len(untranslated))
+	for i, u := range untranslated {
+		descs[i] = fmt.Sprintf("%s (%s)", u.Rule, u.Reason)
+	}
+	return fmt.Sprintf("%d firewall rule(s) could not be recreated by the target controller: %s",
+		len(untranslated), strings.Join(descs, "; "))
 }
 
 func (w *Worker) doVALIDATION(targetInfo coremigration.TargetInfo, modelUUID string) (coremigration.Phase, error) {
+	if delay := w.config.ValidationSettleDelay; delay > 0 {
+		w.logger.Infof("waiting %s for target agents to settle", delay)
+		select {
+		case <-w.catacomb.Dying():
+			return coremigration.VALIDATION, w.catacomb.ErrDying()
+		case <-w.config.Clock.After(delay):
+		}
+	}
+
 	// TODO(mjs) - Wait for all agents to report back.
 
+	if w.config.VerifyImport {
+		err := w.verifyImport(targetInfo, modelUUID)
+		if w.killed() {
+			return coremigration.VALIDATION, w.catacomb.ErrDying()
+		} else if err != nil {
+			w.logger.Errorf("imported model verification failed: %v", err)
+			return coremigration.ABORT, nil
+		}
+	}
+
+	if w.config.HoldTimeout > 0 {
+		// Don't activate the model until the migration is approved.
+		return coremigration.HOLD, nil
+	}
+
 	// Once all agents have validated, activate the model.
 	err := w.activateModel(targetInfo, modelUUID)
 	if err != nil {
@@ -304,6 +1040,108 @@ func (w *Worker) doVALIDATION(targetInfo coremigration.TargetInfo, modelUUID str
 	return coremigration.SUCCESS, nil
 }
 
+// verifyImport compares the source model with the target
+// controller's copy of it, returning an error if they differ in
+// anything other than cosmetic details.
+func (w *Worker) verifyImport(targetInfo coremigration.TargetInfo, modelUUID string) error {
+	w.logger.Infof("exporting model for verification")
+	serialized, err := w.exportModel()
+	if err != nil {
+		return errors.Annotate(err, "exporting source model")
+	}
+
+	conn, err := w.openAPIConn(targetInfo)
+	if err != nil {
+		return errors.Annotate(err, "connecting to target controller")
+	}
+	defer conn.Close()
+	targetClient := migrationtarget.NewClient(conn)
+	targetBytes, err := targetClient.Export(modelUUID)
+	if err != nil {
+		return errors.Annotate(err, "exporting target model")
+	}
+
+	differences, err := migration.CompareModels(serialized.Bytes, targetBytes)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	critical := migration.CriticalDifferences(differences)
+	for _, d := range critical {
+		w.logger.Errorf("imported model differs: %s", d)
+	}
+	if len(critical) > 0 {
+		return errors.Errorf("found %d difference(s) between source and target models", len(critical))
+	}
+	w.logger.Infof("imported model matches source model")
+	return nil
+}
+
+func (w *Worker) doHOLD(
+	abortChanges watcher.NotifyChannel, targetInfo coremigration.TargetInfo, modelUUID string,
+) (coremigration.Phase, error) {
+	approvalRef, err := w.waitForApproval(abortChanges)
+	switch errors.Cause(err) {
+	case nil:
+	case errApprovalTimeout:
+		w.logger.Errorf("migration was not approved within %s", w.config.HoldTimeout)
+		return coremigration.ABORT, nil
+	case errApprovalAborted:
+		w.logger.Infof("abort requested while migration held, aborting migration")
+		return coremigration.ABORT, nil
+	default:
+		return coremigration.HOLD, errors.Trace(err)
+	}
+	w.logger.Infof("migration approved (ticket %s)", approvalRef)
+
+	err = w.activateModel(targetInfo, modelUUID)
+	if err != nil {
+		return coremigration.ABORT, nil
+	}
+	return coremigration.SUCCESS, nil
+}
+
+var errApprovalTimeout = errors.New("migration approval timed out")
+var errApprovalAborted = errors.New("migration aborted while awaiting approval")
+
+// waitForApproval polls the migration status until the migration has
+// been approved, returning the approval's ticket reference. The wait
+// is measured from when the migration entered the HOLD phase, so that
+// a restarted worker doesn't extend it. An abort request received
+// on abortChanges ends the wait.
+func (w *Worker) waitForApproval(abortChanges watcher.NotifyChannel) (string, error) {
+	clk := w.config.Clock
+	w.logger.Infof("holding migration until it is approved (will wait up to %s)", w.config.HoldTimeout)
+	for {
+		status, err := w.config.Facade.GetMigrationStatus()
+		if err != nil {
+			return "", errors.Annotate(err, "checking for migration approval")
+		}
+		if status.ApprovalRef != "" {
+			return status.ApprovalRef, nil
+		}
+
+		remaining := status.PhaseChangedTime.Add(w.config.HoldTimeout).Sub(clk.Now())
+		if remaining <= 0 {
+			return "", errors.Trace(errApprovalTimeout)
+		}
+		wait := holdPollInterval
+		if remaining < wait {
+			wait = remaining
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return "", w.catacomb.ErrDying()
+		case _, ok := <-abortChanges:
+			if ok {
+				return "", errors.Trace(errApprovalAborted)
+			}
+			// The watcher has stopped; the catacomb deals with it.
+			abortChanges = nil
+		case <-clk.After(wait):
+		}
+	}
+}
+
 func (w *Worker) activateModel(targetInfo coremigration.TargetInfo, modelUUID string) error {
 	conn, err := w.openAPIConn(targetInfo)
 	if err != nil {
@@ -330,24 +1168,214 @@ func (w *Worker) doSUCCESS(status coremigration.MigrationStatus) (coremigration.
 	}
 }
 
-func (w *Worker) doLOGTRANSFER() (coremigration.Phase, error) {
-	// TODO(mjs) - To be implemented.
+func (w *Worker) doLOGTRANSFER(targetInfo coremigration.TargetInfo, modelUUID string) (coremigration.Phase, error) {
+	// The model's agents are now using the target controller, so
+	// leave a hint in the migration status telling users where to
+	// find the model. Failing to do so mustn't hold up the migration.
+	if err := w.config.Facade.SetStatusMessage(targetModelHint(targetInfo, modelUUID)); err != nil {
+		w.logger.Warningf("failed to record target model location: %v", err)
+	}
+
+	// A failed transfer leaves the migration in LOGTRANSFER, so that
+	// it is resumed when the worker restarts.
+	if err := w.transferLogs(targetInfo, modelUUID); err != nil {
+		return coremigration.LOGTRANSFER, errors.Annotate(err, "transferring logs")
+	}
 	return coremigration.REAP, nil
 }
 
-func (w *Worker) doREAP() (coremigration.Phase, error) {
-	err := w.config.Facade.Reap()
+// transferLogs sends the model's log records to the target
+// controller in batches. Records which the target already holds,
+// from an earlier interrupted transfer, aren't sent again.
+func (w *Worker) transferLogs(targetInfo coremigration.TargetInfo, modelUUID string) error {
+	conn, err := w.openAPIConn(targetInfo)
+	if err != nil {
+		return errors.Annotate(err, "connecting to target controller")
+	}
+	defer conn.Close()
+	targetClient := migrationtarget.NewClient(conn)
+
+	latest, err := targetClient.LatestLogTime(modelUUID)
+	if err != nil {
+		return errors.Annotate(err, "retrieving latest log time from target")
+	}
+	stream, err := targetClient.OpenLogTransferStream(modelUUID)
 	if err != nil {
-		return coremigration.REAPFAILED, errors.Trace(err)
+		return errors.Trace(err)
+	}
+	defer stream.Close()
+	records, err := w.config.Facade.StreamModelLog(latest, w.catacomb.Dying())
+	if err != nil {
+		return errors.Annotate(err, "streaming model logs")
+	}
+
+	batch := make([]params.LogRecord, 0, w.config.LogTransferBatchSize)
+	sent := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := stream.WriteJSON(batch); err != nil {
+			return errors.Annotate(err, "sending logs to target")
+		}
+		sent += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case result, ok := <-records:
+			if !ok {
+				if err := flush(); err != nil {
+					return errors.Trace(err)
+				}
+				w.logger.Infof("transferred %d log records to target", sent)
+				return nil
+			}
+			if result.Error != nil {
+				return errors.Annotate(result.Error, "streaming model logs")
+			}
+			// The stream starts with records logged at the
+			// latest time the target already has.
+			if !result.Record.Time.After(latest) {
+				continue
+			}
+			batch = append(batch, result.Record)
+			if len(batch) == w.config.LogTransferBatchSize {
+				if err := flush(); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
+}
+
+// targetModelHint returns a status message describing how to reach
+// the migrated model on the target controller.
+func targetModelHint(targetInfo coremigration.TargetInfo, modelUUID string) string {
+	return fmt.Sprintf("model now available at %s (model UUID %s)",
+		strings.Join(targetInfo.Addrs, ", "), modelUUID)
+}
+
+func (w *Worker) doREAP() (coremigration.Phase, error) {
+	attempts := w.config.ReapAttempts
+	if attempts == 0 {
+		attempts = DefaultReapAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		err := w.reap()
+		if err == nil {
+			return coremigration.DONE, nil
+		}
+		if errors.Cause(err) == w.catacomb.ErrDying() {
+			return coremigration.REAP, errors.Trace(err)
+		}
+		w.logger.Errorf("%v", err)
+		if attempt >= attempts {
+			break
+		}
+		// Wait a while and try again, rather than leaving the
+		// migrated model stranded after a transient failure.
+		w.logger.Infof("will retry removal of migrated model in %s", reapRetryDelay)
+		select {
+		case <-w.catacomb.Dying():
+			return coremigration.REAP, w.catacomb.ErrDying()
+		case <-w.config.Clock.After(reapRetryDelay):
+		}
+	}
+	w.logger.Errorf("giving up on removal of migrated model after %d attempts", attempts)
+	return coremigration.REAPFAILED, nil
+}
+
+// doREAPFAILED waits for an operator to request that the removal of
+// the migrated model be retried, and then returns the migration to
+// the REAP phase.
+func (w *Worker) doREAPFAILED() (coremigration.Phase, error) {
+	w.logger.Infof("waiting for a request to retry removal of migrated model")
+	for {
+		status, err := w.config.Facade.GetMigrationStatus()
+		if err != nil {
+			return coremigration.REAPFAILED, errors.Annotate(err, "checking for reap retry request")
+		}
+		if status.ReapRetryRequested {
+			w.logger.Infof("retrying removal of migrated model")
+			return coremigration.REAP, nil
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return coremigration.REAPFAILED, w.catacomb.ErrDying()
+		case <-w.config.Clock.After(reapRetryPollInterval):
+		}
+	}
+}
+
+// reap removes the migrated model from the source controller, and
+// then waits for the removal to complete if Config.ReapTimeout is
+// set.
+func (w *Worker) reap() error {
+	if err := w.config.Facade.Reap(); err != nil {
+		return errors.Annotate(err, "failed to remove migrated model")
+	}
+	if w.config.ReapTimeout == 0 {
+		return nil
+	}
+	err := w.waitForReap()
+	switch errors.Cause(err) {
+	case nil:
+		return nil
+	case errReapTimeout:
+		return errors.Errorf("migrated model was not removed within %s", w.config.ReapTimeout)
+	case w.catacomb.ErrDying():
+		return errors.Trace(err)
+	default:
+		return errors.Annotate(err, "failed to confirm removal of migrated model")
+	}
+}
+
+var errReapTimeout = errors.New("reap completion timed out")
+
+// waitForReap polls until the reaped model's documents have all been
+// removed, or Config.ReapTimeout has passed.
+func (w *Worker) waitForReap() error {
+	clk := w.config.Clock
+	deadline := clk.Now().Add(w.config.ReapTimeout)
+	for {
+		complete, err := w.config.Facade.ReapComplete()
+		if err != nil {
+			return errors.Annotate(err, "checking model removal")
+		}
+		if complete {
+			return nil
+		}
+
+		remaining := deadline.Sub(clk.Now())
+		if remaining <= 0 {
+			return errors.Trace(errReapTimeout)
+		}
+		w.logger.Debugf("migrated model not yet removed, will check again")
+		wait := reapPollInterval
+		if remaining < wait {
+			wait = remaining
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-clk.After(wait):
+		}
 	}
-	return coremigration.DONE, nil
 }
 
 func (w *Worker) doABORT(targetInfo coremigration.TargetInfo, modelUUID string) (coremigration.Phase, error) {
+	if w.config.DryRun {
+		// Nothing is ever imported during a dry run.
+		return coremigration.ABORTDONE, nil
+	}
 	if err := w.removeImportedModel(targetInfo, modelUUID); err != nil {
 		// This isn't fatal. Removing the imported model is a best
 		// efforts attempt.
-		logger.Errorf("failed to reverse model import: %v", err)
+		w.logger.Errorf("failed to reverse model import: %v", err)
 	}
 	return coremigration.ABORTDONE, nil
 }
@@ -412,7 +1440,7 @@ func (w *Worker) waitForMinions(status coremigration.MigrationStatus, waitPolicy
 	clk := w.config.Clock
 	maxWait := maxMinionWait - clk.Now().Sub(status.PhaseChangedTime)
 	timeout := clk.After(maxWait)
-	logger.Infof("waiting for minions to report back for migration phase %s (will wait up to %s)",
+	w.logger.Infof("waiting for minions to report back for migration phase %s (will wait up to %s)",
 		status.Phase, truncDuration(maxWait))
 
 	watch, err := w.config.Facade.WatchMinionReports()
@@ -425,57 +1453,212 @@ func (w *Worker) waitForMinions(status coremigration.MigrationStatus, waitPolicy
 
 	logProgress := clk.After(minionWaitLogInterval)
 
-	var reports coremigration.MinionReports
+	// Reports are fetched, validated and formatted by a bounded pool
+	// of goroutines so that large reports don't hold up this loop.
+	// Results are applied in the order they were requested in, so
+	// the outcome is the same as processing them inline.
+	jobs := make(chan int)
+	results := make(chan minionReportResult)
+	stop := make(chan struct{})
+	defer close(stop)
+	for i := 0; i < w.config.MinionReportWorkers; i++ {
+		go w.processMinionReports(status, jobs, results, stop)
+	}
+
+	var (
+		reports  coremigration.MinionReports
+		changed  bool
+		nextJob  int
+		nextDone int
+		finished = make(map[int]minionReportResult)
+	)
+	defer func() {
+		w.span.SetAttribute("minions-succeeded", reports.SuccessCount)
+		w.span.SetAttribute("minions-failed", len(reports.FailedMachines)+len(reports.FailedUnits))
+		w.span.SetAttribute("minions-unknown", reports.UnknownCount)
+	}()
 	for {
+		var sendJob chan<- int
+		if changed {
+			sendJob = jobs
+		}
 		select {
 		case <-w.catacomb.Dying():
 			return w.catacomb.ErrDying()
 
 		case <-timeout:
-			logger.Errorf(formatMinionTimeout(reports, status))
+			w.logger.Errorf(formatMinionTimeout(reports, status))
 			return errors.Trace(errMinionReportTimeout)
 
 		case <-watch.Changes():
-			var err error
-			reports, err = w.config.Facade.GetMinionReports()
-			if err != nil {
-				return errors.Trace(err)
-			}
-			if err := validateMinionReports(reports, status); err != nil {
-				return errors.Trace(err)
-			}
-			failures := len(reports.FailedMachines) + len(reports.FailedUnits)
-			if failures > 0 {
-				logger.Errorf(formatMinionFailure(reports))
-				if waitPolicy == failFast {
-					return errors.Trace(errMinionReportFailed)
+			changed = true
+
+		case sendJob <- nextJob:
+			changed = false
+			nextJob++
+
+		case result := <-results:
+			finished[result.job] = result
+			for {
+				result, ok := finished[nextDone]
+				if !ok {
+					break
 				}
-			}
-			if reports.UnknownCount == 0 {
-				logger.Infof(formatMinionWaitDone(reports))
-				if failures > 0 {
-					return errors.Trace(errMinionReportFailed)
+				delete(finished, nextDone)
+				nextDone++
+				reports = result.reports
+				if result.err == nil {
+					if err := w.audit.record(clk.Now(), reports); err != nil {
+						w.logger.Warningf("failed to record minion reports for audit: %v", err)
+					}
+					w.setMinionWaitProgress(reports)
+				}
+				if done, err := w.applyMinionReports(result, waitPolicy); done {
+					return errors.Trace(err)
 				}
-				return nil
 			}
 
 		case <-logProgress:
-			logger.Infof(formatMinionWaitUpdate(reports, status))
+			progress := formatMinionWaitUpdate(reports, status)
+			w.logger.Infof(progress)
+			w.notifyProgress(status, progress)
+			if !reports.IsZero() {
+				w.setMinionWaitProgress(reports)
+			}
 			logProgress = clk.After(minionWaitLogInterval)
 		}
 	}
 }
 
+// setMinionWaitProgress passes the minion reports received so far on
+// to the controller. Failing to do so is logged, but doesn't affect
+// the migration.
+func (w *Worker) setMinionWaitProgress(reports coremigration.MinionReports) {
+	if err := w.config.Facade.SetMinionWaitProgress(reports); err != nil {
+		w.logger.Warningf("failed to record minion wait progress: %v", err)
+	}
+}
+
+// minionReportResult holds the outcome of fetching and checking the
+// minion reports for a migration phase.
+type minionReportResult struct {
+	job        int
+	reports    coremigration.MinionReports
+	err        error
+	failures   int
+	failureMsg string
+	doneMsg    string
+}
+
+// processMinionReports fetches and checks minion reports for each
+// job received, until stop is closed.
+func (w *Worker) processMinionReports(
+	status coremigration.MigrationStatus,
+	jobs <-chan int,
+	results chan<- minionReportResult,
+	stop <-chan struct{},
+) {
+	for {
+		var job int
+		select {
+		case <-stop:
+			return
+		case job = <-jobs:
+		}
+
+		result := minionReportResult{job: job}
+		result.reports, result.err = w.config.Facade.GetMinionReports()
+		if result.err == nil {
+			result.err = validateMinionReports(result.reports, status)
+		}
+		if result.err == nil {
+			reports := result.reports
+			result.failures = len(reports.FailedMachines) + len(reports.FailedUnits)
+			if result.failures > 0 {
+				result.failureMsg = formatMinionFailure(reports)
+			}
+			if reports.UnknownCount == 0 {
+				result.doneMsg = formatMinionWaitDone(reports)
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case results <- result:
+		}
+	}
+}
+
+// applyMinionReports acts on the outcome of processing a set of
+// minion reports. It returns true if the wait for minions is over,
+// along with the wait's result.
+func (w *Worker) applyMinionReports(result minionReportResult, waitPolicy bool) (bool, error) {
+	if result.err != nil {
+		return true, errors.Trace(result.err)
+	}
+	if result.failures > 0 {
+		w.logger.Errorf(result.failureMsg)
+		if waitPolicy == failFast {
+			return true, errors.Trace(errMinionReportFailed)
+		}
+	}
+	if result.reports.UnknownCount == 0 {
+		w.logger.Infof(result.doneMsg)
+		if result.failures > 0 {
+			return true, errors.Trace(errMinionReportFailed)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// startPhaseSpan starts a span recording the running of the current
+// migration phase.
+func (w *Worker) startPhaseSpan(status coremigration.MigrationStatus) {
+	if w.config.Tracer == nil {
+		w.span = nopSpan{}
+		return
+	}
+	w.span = w.config.Tracer.StartSpan("migration." + status.Phase.String())
+	w.span.SetAttribute("model-uuid", status.ModelUUID)
+	w.span.SetAttribute("phase", status.Phase.String())
+}
+
+// endPhaseSpan ends the span for the current phase, recording the
+// phase that the migration moves to next. Phase handlers log problems
+// rather than returning them, so moving to an error phase is recorded
+// as a failure.
+func (w *Worker) endPhaseSpan(next coremigration.Phase, err error) {
+	if err == nil {
+		w.span.SetAttribute("next-phase", next.String())
+		if next == coremigration.ABORT || next == coremigration.REAPFAILED {
+			err = errors.Errorf("migration moving to %s", next)
+		}
+	}
+	w.span.End(err)
+	w.span = nopSpan{}
+}
+
+// notifyProgress sends a progress event for the migration to the
+// progress webhook, if one is configured.
+func (w *Worker) notifyProgress(status coremigration.MigrationStatus, progress string) {
+	w.notifier.notify(ProgressEvent{
+		ModelUUID: status.ModelUUID,
+		Phase:     status.Phase.String(),
+		Progress:  progress,
+		Time:      w.config.Clock.Now(),
+	})
+}
+
 func truncDuration(d time.Duration) time.Duration {
 	return (d / time.Second) * time.Second
 }
 
 func validateMinionReports(reports coremigration.MinionReports, status coremigration.MigrationStatus) error {
-	// TODO(mjs) the migration id should be part of the status response.
-	migId := fmt.Sprintf("%s:%d", status.ModelUUID, status.Attempt)
-	if reports.MigrationId != migId {
+	if reports.MigrationId != status.MigrationId {
 		return errors.Errorf("unexpected migration id in minion reports, got %v, expected %v",
-			reports.MigrationId, migId)
+			reports.MigrationId, status.MigrationId)
 	}
 	if reports.Phase != status.Phase {
 		return errors.Errorf("minion reports phase (%s) does not match migration phase (%s)",
@@ -502,14 +1685,29 @@ func formatMinionTimeout(reports coremigration.MinionReports, status coremigrati
 func formatMinionFailure(reports coremigration.MinionReports) string {
 	msg := fmt.Sprintf("some agents failed %s: ", reports.Phase)
 	if len(reports.FailedMachines) > 0 {
-		msg += fmt.Sprintf("failed machines: %s; ", strings.Join(reports.FailedMachines, ", "))
+		msg += fmt.Sprintf("failed machines: %s; ", formatFailedAgents(reports.FailedMachines, reports.FailureDetails))
 	}
 	if len(reports.FailedUnits) > 0 {
-		msg += fmt.Sprintf("failed units: %s", strings.Join(reports.FailedUnits, ", "))
+		msg += fmt.Sprintf("failed units: %s", formatFailedAgents(reports.FailedUnits, reports.FailureDetails))
 	}
 	return msg
 }
 
+// formatFailedAgents returns a comma separated list of the given
+// agent ids, each followed by the failure the agent reported, if it
+// reported one.
+func formatFailedAgents(ids []string, details map[string]string) string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		if message := details[id]; message != "" {
+			out[i] = fmt.Sprintf("%s (%s)", id, message)
+		} else {
+			out[i] = id
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
 func formatMinionWaitUpdate(reports coremigration.MinionReports, status coremigration.MigrationStatus) string {
 	if reports.IsZero() {
 		return fmt.Sprintf("no reports from minions yet for %s", status.Phase)
@@ -541,12 +1739,30 @@ func (w *Worker) openAPIConnForModel(targetInfo coremigration.TargetInfo, modelU
 		Password: targetInfo.Password,
 		ModelTag: names.NewModelTag(modelUUID),
 	}
-	// Use zero DialOpts (no retries) because the worker must stay
-	// responsive to Kill requests. We don't want it to be blocked by
-	// a long set of retry attempts.
-	return w.config.APIOpen(apiInfo, api.DialOpts{})
+	delay := apiOpenRetryDelay
+	for attempt := 1; ; attempt++ {
+		// Use zero DialOpts (no retries) because the worker must stay
+		// responsive to Kill requests. We don't want it to be blocked
+		// by a long set of retry attempts, so retries are made here
+		// instead, checking for Kill in between.
+		conn, err := w.config.APIOpen(apiInfo, api.DialOpts{})
+		if err == nil {
+			return conn, nil
+		}
+		if attempt >= w.config.APIOpenAttempts {
+			return nil, errors.Trace(err)
+		}
+		w.logger.Warningf("failed to connect to target controller (attempt %d of %d), retrying in %s: %v",
+			attempt, w.config.APIOpenAttempts, delay, err)
+		select {
+		case <-w.catacomb.Dying():
+			return nil, w.catacomb.ErrDying()
+		case <-w.config.Clock.After(delay):
+		}
+		delay *= 2
+	}
 }
 
 func modelHasMigrated(phase coremigration.Phase) bool {
-	return phase == coremigration.DONE || phase == coremigration.REAPFAILED
+	return phase == coremigration.DONE
 }
@@ -4,6 +4,9 @@
 package migrationmaster_test
 
 import (
+	"fmt"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -14,9 +17,13 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	masterapi "github.com/juju/juju/api/migrationmaster"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/description"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
+	"github.com/juju/juju/network"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker"
@@ -28,12 +35,13 @@ import (
 
 type Suite struct {
 	coretesting.BaseSuite
-	clock         *coretesting.Clock
-	stub          *jujutesting.Stub
-	connection    *stubConnection
-	connectionErr error
-	masterFacade  *stubMasterFacade
-	config        migrationmaster.Config
+	clock          *coretesting.Clock
+	stub           *jujutesting.Stub
+	connection     *stubConnection
+	connectionErr  error
+	connectionErrs []error
+	masterFacade   *stubMasterFacade
+	config         migrationmaster.Config
 }
 
 var _ = gc.Suite(&Suite{})
@@ -81,6 +89,17 @@ var (
 			params.ModelArgs{ModelTag: modelTagString},
 		},
 	}
+	prechecksCall = jujutesting.StubCall{
+		"APICall:MigrationTarget.Prechecks",
+		[]interface{}{
+			params.MigrationModelInfo{
+				UUID:         "model-uuid",
+				Name:         "mymodel",
+				OwnerTag:     names.NewUserTag("owner").String(),
+				AgentVersion: version.MustParse("2.1.0"),
+			},
+		},
+	}
 	connCloseCall = jujutesting.StubCall{"Connection.Close", nil}
 	abortCall     = jujutesting.StubCall{
 		"APICall:MigrationTarget.Abort",
@@ -88,6 +107,28 @@ var (
 			params.ModelArgs{ModelTag: modelTagString},
 		},
 	}
+	setTargetHintCall = jujutesting.StubCall{
+		"masterFacade.SetStatusMessage",
+		[]interface{}{"model now available at 1.2.3.4:5 (model UUID model-uuid)"},
+	}
+	latestLogTimeCall = jujutesting.StubCall{
+		"APICall:MigrationTarget.LatestLogTime",
+		[]interface{}{
+			params.ModelArgs{ModelTag: modelTagString},
+		},
+	}
+	openLogStreamCall = jujutesting.StubCall{
+		"ConnectStream",
+		[]interface{}{
+			"/migrate/logtransfer",
+			url.Values{"model-uuid": {"model-uuid"}},
+		},
+	}
+	streamModelLogCall = jujutesting.StubCall{
+		"masterFacade.StreamModelLog",
+		[]interface{}{time.Time{}},
+	}
+	logStreamCloseCall = jujutesting.StubCall{"Stream.Close", nil}
 )
 
 func (s *Suite) SetUpTest(c *gc.C) {
@@ -95,8 +136,12 @@ func (s *Suite) SetUpTest(c *gc.C) {
 
 	s.clock = coretesting.NewClock(time.Now())
 	s.stub = new(jujutesting.Stub)
-	s.connection = &stubConnection{stub: s.stub}
+	s.connection = &stubConnection{
+		stub:      s.stub,
+		logStream: &stubLogStream{stub: s.stub},
+	}
 	s.connectionErr = nil
+	s.connectionErrs = nil
 
 	s.masterFacade = newStubMasterFacade(s.stub, s.clock.Now())
 
@@ -115,6 +160,11 @@ func (s *Suite) SetUpTest(c *gc.C) {
 
 func (s *Suite) apiOpen(info *api.Info, dialOpts api.DialOpts) (api.Connection, error) {
 	s.stub.AddCall("apiOpen", info, dialOpts)
+	if len(s.connectionErrs) > 0 {
+		err := s.connectionErrs[0]
+		s.connectionErrs = s.connectionErrs[1:]
+		return nil, err
+	}
 	if s.connectionErr != nil {
 		return nil, s.connectionErr
 	}
@@ -130,6 +180,14 @@ func (s *Suite) triggerMigration() {
 
 }
 
+func (s *Suite) triggerAbort() {
+	select {
+	case s.masterFacade.abortChanges <- struct{}{}:
+	default:
+		panic("abort watcher channel unexpectedly closed")
+	}
+}
+
 func (s *Suite) triggerMinionReports() {
 	select {
 	case s.masterFacade.minionReportsChanges <- struct{}{}:
@@ -145,6 +203,7 @@ func (s *Suite) TestSuccessfulMigration(c *gc.C) {
 	defer workertest.DirtyKill(c, worker)
 	s.triggerMigration()
 	s.triggerMinionReports()
+	s.triggerMinionReports()
 
 	err = workertest.CheckKilled(c, worker)
 	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
@@ -156,15 +215,24 @@ func (s *Suite) TestSuccessfulMigration(c *gc.C) {
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
 		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
 		{"masterFacade.Export", nil},
 		apiOpenCallController,
+		{"masterFacade.ModelCredential", nil},
 		importCall,
 		apiOpenCallModel,
 		{"UploadBinaries", []interface{}{
-			[]string{"charm0", "charm1"},
+			[]string{"cs:charm0", "cs:charm1"},
 			fakeCharmDownloader,
 			map[version.Binary]string{
 				version.MustParseBinary("2.1.0-trusty-amd64"): "/tools/0",
@@ -181,12 +249,273 @@ func (s *Suite) TestSuccessfulMigration(c *gc.C) {
 		{"masterFacade.WatchMinionReports", nil},
 		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+}
+
+type phaseChange struct {
+	from, to coremigration.Phase
+	err      error
+}
+
+// recordPhaseChanges configures the worker to record the phase
+// changes it makes.
+func (s *Suite) recordPhaseChanges() *[]phaseChange {
+	var changes []phaseChange
+	s.config.OnPhaseChange = func(from, to coremigration.Phase, err error) {
+		changes = append(changes, phaseChange{from, to, err})
+	}
+	return &changes
+}
+
+func (s *Suite) TestOnPhaseChange(c *gc.C) {
+	s.config.UploadBinaries = makeStubUploadBinaries(s.stub)
+	changes := s.recordPhaseChanges()
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Assert(*changes, jc.DeepEquals, []phaseChange{
+		{coremigration.QUIESCE, coremigration.READONLY, nil},
+		{coremigration.READONLY, coremigration.PRECHECK, nil},
+		{coremigration.PRECHECK, coremigration.IMPORT, nil},
+		{coremigration.IMPORT, coremigration.VALIDATION, nil},
+		{coremigration.VALIDATION, coremigration.SUCCESS, nil},
+		{coremigration.SUCCESS, coremigration.LOGTRANSFER, nil},
+		{coremigration.LOGTRANSFER, coremigration.REAP, nil},
+		{coremigration.REAP, coremigration.DONE, nil},
+	})
+}
+
+func (s *Suite) TestOnPhaseChangeAborted(c *gc.C) {
+	s.connection.prechecksErr = errors.New("boom")
+	changes := s.recordPhaseChanges()
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+	c.Assert(*changes, jc.DeepEquals, []phaseChange{
+		{coremigration.QUIESCE, coremigration.READONLY, nil},
+		{coremigration.READONLY, coremigration.PRECHECK, nil},
+		{coremigration.PRECHECK, coremigration.ABORT, nil},
+		{coremigration.ABORT, coremigration.ABORTDONE, nil},
+	})
+}
+
+func (s *Suite) TestOnPhaseChangePanic(c *gc.C) {
+	s.connection.prechecksErr = errors.New("boom")
+	var calls int
+	s.config.OnPhaseChange = func(from, to coremigration.Phase, err error) {
+		calls++
+		panic("kaboom")
+	}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	// The migration carries on regardless.
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+	c.Check(calls, gc.Equals, 4)
+	c.Check(c.GetTestLog(), jc.Contains, "phase change callback panicked: kaboom")
+}
+
+func (s *Suite) TestTargetHintFailure(c *gc.C) {
+	// Failing to record where the model went isn't fatal.
+	s.masterFacade.setStatusMessageErr = errors.New("boom")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.masterFacade.status.Phase = coremigration.LOGTRANSFER
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "failed to record target model location: boom")
+}
+
+// makeLogRecords returns n log records logged a second apart from
+// start.
+func makeLogRecords(start time.Time, n int) []params.LogRecord {
+	records := make([]params.LogRecord, n)
+	for i := range records {
+		records[i] = params.LogRecord{
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Module:  "juju.worker",
+			Level:   "INFO",
+			Message: fmt.Sprintf("message %d", i),
+			Entity:  "machine-0",
+		}
+	}
+	return records
+}
+
+func (s *Suite) TestLogTransferBatches(c *gc.C) {
+	records := makeLogRecords(s.clock.Now(), 5)
+	s.masterFacade.logRecords = records
+	s.masterFacade.status.Phase = coremigration.LOGTRANSFER
+	s.config.LogTransferBatchSize = 2
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		{"Stream.WriteJSON", []interface{}{records[0:2]}},
+		{"Stream.WriteJSON", []interface{}{records[2:4]}},
+		{"Stream.WriteJSON", []interface{}{records[4:5]}},
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "transferred 5 log records to target")
+}
+
+func (s *Suite) TestLogTransferResume(c *gc.C) {
+	// The target already holds the records up to the third, from an
+	// earlier attempt, so only the remaining ones are sent.
+	records := makeLogRecords(s.clock.Now(), 5)
+	s.masterFacade.logRecords = records
+	s.masterFacade.status.Phase = coremigration.LOGTRANSFER
+	s.connection.latestLogTime = records[2].Time
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		{"masterFacade.StreamModelLog", []interface{}{records[2].Time}},
+		{"Stream.WriteJSON", []interface{}{records[3:5]}},
+		logStreamCloseCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
 		{"masterFacade.Reap", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
 	})
 }
 
+func (s *Suite) TestLogTransferFailure(c *gc.C) {
+	// A failed transfer leaves the migration in LOGTRANSFER, to be
+	// resumed when the worker is restarted.
+	records := makeLogRecords(s.clock.Now(), 3)
+	s.masterFacade.logRecords = records
+	s.masterFacade.status.Phase = coremigration.LOGTRANSFER
+	s.config.LogTransferBatchSize = 2
+	s.stub.SetErrors(nil, errors.New("boom"))
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.ErrorMatches, "transferring logs: sending logs to target: boom")
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		{"Stream.WriteJSON", []interface{}{records[0:2]}},
+		{"Stream.WriteJSON", []interface{}{records[2:3]}},
+		logStreamCloseCall,
+		connCloseCall,
+	})
+}
+
+func (s *Suite) TestLogTransferStreamFailure(c *gc.C) {
+	// A failure reading the source logs leaves the migration in
+	// LOGTRANSFER, after sending the records read so far.
+	records := makeLogRecords(s.clock.Now(), 3)
+	s.masterFacade.logRecords = records
+	s.masterFacade.logStreamErr = errors.New("boom")
+	s.masterFacade.status.Phase = coremigration.LOGTRANSFER
+	s.config.LogTransferBatchSize = 2
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.ErrorMatches, "transferring logs: streaming model logs: boom")
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		{"Stream.WriteJSON", []interface{}{records[0:2]}},
+		logStreamCloseCall,
+		connCloseCall,
+	})
+}
+
 func (s *Suite) TestMigrationResume(c *gc.C) {
 	// Test that a partially complete migration can be resumed.
 	worker, err := migrationmaster.New(s.config)
@@ -203,9 +532,17 @@ func (s *Suite) TestMigrationResume(c *gc.C) {
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
 		{"masterFacade.WatchMinionReports", nil},
 		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
 		{"masterFacade.Reap", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
@@ -326,6 +663,7 @@ func (s *Suite) TestExportFailure(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.DirtyKill(c, worker)
 	s.triggerMigration()
+	s.triggerMinionReports()
 
 	err = workertest.CheckKilled(c, worker)
 	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
@@ -334,8 +672,16 @@ func (s *Suite) TestExportFailure(c *gc.C) {
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
 		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
 		{"masterFacade.Export", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
@@ -346,52 +692,143 @@ func (s *Suite) TestExportFailure(c *gc.C) {
 	})
 }
 
-func (s *Suite) TestAPIOpenFailure(c *gc.C) {
+func (s *Suite) TestExportUnknownCharmOrigin(c *gc.C) {
+	s.masterFacade.exportCharmOrigins = map[string]coremigration.CharmOrigin{
+		"cs:charm0": coremigration.CharmStoreOrigin,
+	}
 	worker, err := migrationmaster.New(s.config)
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.DirtyKill(c, worker)
-	s.connectionErr = errors.New("boom")
 	s.triggerMigration()
+	s.triggerMinionReports()
 
 	err = workertest.CheckKilled(c, worker)
 	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
 
+	// The model isn't imported into the target controller at all.
 	s.stub.CheckCalls(c, []jujutesting.StubCall{
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
 		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
 		{"masterFacade.Export", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
 		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+}
+
+func (s *Suite) TestMigrationLogger(c *gc.C) {
+	s.masterFacade.exportErr = errors.New("boom")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	// Messages about the migration are logged to a logger for the
+	// model, and name it.
+	log := c.GetTestLog()
+	c.Check(log, jc.Contains, "juju.worker.migrationmaster.model- ")
+	c.Check(log, jc.Contains, "[mymodel] model export failed: boom")
+}
+
+func (s *Suite) TestAbortDuringQUIESCE(c *gc.C) {
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerAbort()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	// The migration is aborted once QUIESCE is complete, instead of
+	// moving on to READONLY.
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
 		apiOpenCallController,
+		abortCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
 	})
 }
 
-func (s *Suite) TestImportFailure(c *gc.C) {
+func (s *Suite) TestAbortDuringIMPORT(c *gc.C) {
+	s.config.UploadBinaries = makeStubUploadBinaries(s.stub)
+	s.masterFacade.exportStarted = make(chan struct{})
+	s.masterFacade.exportBlock = make(chan struct{})
 	worker, err := migrationmaster.New(s.config)
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.DirtyKill(c, worker)
-	s.connection.importErr = errors.New("boom")
 	s.triggerMigration()
+	s.triggerMinionReports()
+
+	select {
+	case <-s.masterFacade.exportStarted:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for export")
+	}
+	s.triggerAbort()
+	close(s.masterFacade.exportBlock)
 
 	err = workertest.CheckKilled(c, worker)
 	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
 
+	// IMPORT runs to completion, but the migration is then aborted
+	// instead of moving on to VALIDATION.
 	s.stub.CheckCalls(c, []jujutesting.StubCall{
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
 		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
 		{"masterFacade.Export", nil},
 		apiOpenCallController,
+		{"masterFacade.ModelCredential", nil},
 		importCall,
-		connCloseCall,
+		apiOpenCallModel,
+		{"UploadBinaries", []interface{}{
+			[]string{"cs:charm0", "cs:charm1"},
+			fakeCharmDownloader,
+			map[version.Binary]string{
+				version.MustParseBinary("2.1.0-trusty-amd64"): "/tools/0",
+			},
+			fakeToolsDownloader,
+		}},
+		connCloseCall, // for target model
+		connCloseCall, // for target controller
 		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
 		apiOpenCallController,
 		abortCall,
@@ -400,6 +837,1371 @@ func (s *Suite) TestImportFailure(c *gc.C) {
 	})
 }
 
+func (s *Suite) TestAbortAfterSUCCESSRejected(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.SUCCESS
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerAbort()
+	s.triggerMinionReports()
+
+	// The migration completes regardless.
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Check(c.GetTestLog(), jc.Contains,
+		"abort requested in phase SUCCESS rejected: migration can't be aborted after SUCCESS")
+}
+
+func (s *Suite) TestWatchForAbortNotImplemented(c *gc.C) {
+	// Controllers which can't report abort requests don't stop the
+	// migration.
+	s.masterFacade.status.Phase = coremigration.REAP
+	s.masterFacade.abortWatchErr = &params.Error{Code: params.CodeNotImplemented}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+}
+
+func (s *Suite) TestWatchForAbortError(c *gc.C) {
+	s.masterFacade.abortWatchErr = errors.New("boom")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.ErrorMatches, "watching for abort requests: boom")
+}
+
+func (s *Suite) TestExportTimeout(c *gc.C) {
+	s.config.ExportTimeout = time.Minute
+	s.masterFacade.exportStarted = make(chan struct{})
+	s.masterFacade.exportBlock = make(chan struct{})
+	s.masterFacade.exportDone = make(chan struct{})
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	select {
+	case <-s.masterFacade.exportStarted:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for export")
+	}
+	// Two alarms are set while waiting for the minions to quiesce and
+	// another for the export timeout.
+	for i := 0; i < 3; i++ {
+		select {
+		case <-s.clock.Alarms():
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out waiting for clock.After call")
+		}
+	}
+	s.clock.Advance(time.Minute)
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	expectedCalls := []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
+		{"masterFacade.Export", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	}
+	s.stub.CheckCalls(c, expectedCalls)
+	c.Check(c.GetTestLog(), jc.Contains, "model export failed: model export timed out")
+
+	// The abandoned export can't be cancelled, but is left to finish
+	// and its result is ignored.
+	close(s.masterFacade.exportBlock)
+	select {
+	case <-s.masterFacade.exportDone:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for abandoned export to finish")
+	}
+	// No further calls are made.
+	s.stub.CheckCalls(c, expectedCalls)
+}
+
+func (s *Suite) TestAPIOpenFailure(c *gc.C) {
+	s.config.APIOpenAttempts = 1
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.connectionErr = errors.New("boom")
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		apiOpenCallController,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"target precheck failed: connecting to target controller: boom")
+}
+
+func (s *Suite) TestAPIOpenRetry(c *gc.C) {
+	// Transient failures to connect to the target controller during
+	// IMPORT don't cause the migration to be aborted.
+	s.masterFacade.status.Phase = coremigration.IMPORT
+	s.config.UploadBinaries = makeStubUploadBinaries(s.stub)
+	s.connectionErrs = []error{errors.New("boom"), errors.New("splat")}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// The first alarm is for the export timeout. The worker then
+	// backs off for a second, and then for two seconds.
+	s.waitForClockAlarm(c)
+	s.waitForClockAlarm(c)
+	s.clock.Advance(time.Second)
+	s.waitForClockAlarm(c)
+	s.clock.Advance(2 * time.Second)
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Export", nil},
+		apiOpenCallController,
+		apiOpenCallController,
+		apiOpenCallController,
+		{"masterFacade.ModelCredential", nil},
+		importCall,
+		apiOpenCallModel,
+		{"UploadBinaries", []interface{}{
+			[]string{"cs:charm0", "cs:charm1"},
+			fakeCharmDownloader,
+			map[version.Binary]string{
+				version.MustParseBinary("2.1.0-trusty-amd64"): "/tools/0",
+			},
+			fakeToolsDownloader,
+		}},
+		connCloseCall, // for target model
+		connCloseCall, // for target controller
+		{"masterFacade.SetPhase", []interface{}{coremigration.VALIDATION}},
+		apiOpenCallController,
+		activateCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.SUCCESS}},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"failed to connect to target controller (attempt 2 of 3), retrying in 2s: splat")
+}
+
+func (s *Suite) TestAPIOpenRetryDying(c *gc.C) {
+	// The worker can be killed while waiting to retry a connection,
+	// without the migration being aborted.
+	s.masterFacade.status.Phase = coremigration.IMPORT
+	s.connectionErr = errors.New("boom")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c) // export timeout
+	s.waitForClockAlarm(c) // retry delay
+	workertest.CleanKill(c, worker)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Export", nil},
+		apiOpenCallController,
+	})
+}
+
+func (s *Suite) TestImportFailure(c *gc.C) {
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.connection.importErr = errors.New("boom")
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
+		{"masterFacade.Export", nil},
+		apiOpenCallController,
+		{"masterFacade.ModelCredential", nil},
+		importCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+}
+
+func (s *Suite) TestCredentialTransfer(c *gc.C) {
+	s.masterFacade.credential = testCredential
+	s.connection.credentialHashErr = &params.Error{Code: params.CodeNotFound}
+	s.connection.importErr = errors.New("stop here")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCall(c, 16, "masterFacade.ModelCredential")
+	s.stub.CheckCall(c, 17, "APICall:MigrationTarget.CredentialHash", params.MigrationCredential{
+		OwnerTag: "user-bob",
+		Cloud:    "aws",
+		Name:     "default",
+	})
+	s.stub.CheckCall(c, 18, "APICall:MigrationTarget.UploadCredential", params.MigrationCredential{
+		OwnerTag:   "user-bob",
+		Cloud:      "aws",
+		Name:       "default",
+		AuthType:   "access-key",
+		Attributes: map[string]string{"secret-key": "sekrit"},
+		Hash:       testCredential.Hash(),
+	})
+	s.stub.CheckCall(c, 19, "APICall:MigrationTarget.Import", params.SerializedModel{Bytes: fakeModelBytes})
+}
+
+func (s *Suite) TestCredentialTransferSkippedWhenIdentical(c *gc.C) {
+	s.masterFacade.credential = testCredential
+	s.connection.credentialHash = testCredential.Hash()
+	s.connection.importErr = errors.New("stop here")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCallNames(c,
+		"masterFacade.Watch",
+		"masterFacade.GetMigrationStatus",
+		"guard.Lockdown",
+		"masterFacade.WatchForAbort",
+		"masterFacade.WatchMinionReports",
+		"masterFacade.GetMinionReports",
+		"masterFacade.SetPhase",
+		"masterFacade.SetPhase",
+		"masterFacade.StoragePools",
+		"apiOpen",
+		"APICall:MigrationTarget.Prechecks",
+		"Connection.Close",
+		"masterFacade.SetPhase",
+		"masterFacade.Export",
+		"apiOpen",
+		"masterFacade.ModelCredential",
+		"APICall:MigrationTarget.CredentialHash",
+		"APICall:MigrationTarget.Import",
+		"Connection.Close",
+		"masterFacade.SetPhase",
+		"apiOpen",
+		"APICall:MigrationTarget.Abort",
+		"Connection.Close",
+		"masterFacade.SetPhase",
+	)
+}
+
+func (s *Suite) TestCredentialConflict(c *gc.C) {
+	s.masterFacade.credential = testCredential
+	s.connection.credentialHash = "something-else"
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
+		{"masterFacade.Export", nil},
+		apiOpenCallController,
+		{"masterFacade.ModelCredential", nil},
+		{"APICall:MigrationTarget.CredentialHash", []interface{}{params.MigrationCredential{
+			OwnerTag: "user-bob",
+			Cloud:    "aws",
+			Name:     "default",
+		}}},
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+}
+
+func (s *Suite) TestFirewallRulesPartiallyTranslated(c *gc.C) {
+	s.config.UploadBinaries = makeStubUploadBinaries(s.stub)
+	s.masterFacade.firewallRules = []coremigration.FirewallRule{{
+		Ports: network.MustParsePortRange("80/tcp"),
+	}, {
+		Ports:       network.MustParsePortRange("53/udp"),
+		SourceCIDRs: []string{"10.0.0.0/8"},
+	}}
+	s.connection.untranslatedRules = []params.UntranslatedFirewallRule{{
+		Rule: params.FirewallRule{
+			Ports:       params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+			SourceCIDRs: []string{"10.0.0.0/8"},
+		},
+		Reason: "source CIDRs not supported",
+	}}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	// Untranslatable rules don't stop the migration.
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCall(c, 18, "APICall:MigrationTarget.ApplyFirewallRules", params.ApplyFirewallRulesArgs{
+		ModelTag: modelTagString,
+		Rules: []params.FirewallRule{{
+			Ports: params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		}, {
+			Ports:       params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+			SourceCIDRs: []string{"10.0.0.0/8"},
+		}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"1 firewall rule(s) could not be recreated by the target controller: "+
+			"53/udp from 10.0.0.0/8 (source CIDRs not supported)")
+}
+
+func (s *Suite) TestFirewallRulesNotSupportedByTarget(c *gc.C) {
+	s.config.UploadBinaries = makeStubUploadBinaries(s.stub)
+	s.masterFacade.firewallRules = []coremigration.FirewallRule{{
+		Ports: network.MustParsePortRange("80/tcp"),
+	}}
+	s.connection.applyFirewallErr = &params.Error{Code: params.CodeNotImplemented}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Check(c.GetTestLog(), jc.Contains,
+		"80/tcp (not supported by target controller)")
+}
+
+func (s *Suite) TestFirewallRulesError(c *gc.C) {
+	s.masterFacade.firewallRules = []coremigration.FirewallRule{{
+		Ports: network.MustParsePortRange("80/tcp"),
+	}}
+	s.connection.applyFirewallErr = errors.New("boom")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+}
+
+func (s *Suite) TestVerifyBinariesFailure(c *gc.C) {
+	s.config.UploadBinaries = func(config migration.UploadBinariesConfig) error {
+		return config.BinaryVerifier.VerifyBinaries(config.CharmSHA256s, config.ToolsSHA256s)
+	}
+	s.connection.verifyBinariesErr = errors.New("tools 2.1.0-trusty-amd64 truncated")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCall(c, 19, "APICall:MigrationTarget.VerifyBinaries", params.VerifyBinariesArgs{
+		ModelTag: names.NewModelTag("model-uuid").String(),
+		Charms: map[string]string{
+			"cs:charm0": "charm0-sha256",
+			"cs:charm1": "charm1-sha256",
+		},
+		Tools: []params.SerializedModelTools{{
+			Version: "2.1.0-trusty-amd64",
+			SHA256:  "tools-sha256",
+		}},
+	})
+	s.stub.CheckCall(c, 22, "masterFacade.SetPhase", coremigration.ABORT)
+	c.Check(c.GetTestLog(), jc.Contains, "tools 2.1.0-trusty-amd64 truncated")
+}
+
+func (s *Suite) TestVerifyBinariesNotImplemented(c *gc.C) {
+	// Target controllers which can't verify binaries don't stop the
+	// migration.
+	s.config.UploadBinaries = func(config migration.UploadBinariesConfig) error {
+		return config.BinaryVerifier.VerifyBinaries(config.CharmSHA256s, config.ToolsSHA256s)
+	}
+	s.connection.verifyBinariesErr = &params.Error{Code: params.CodeNotImplemented}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Check(c.GetTestLog(), jc.Contains,
+		"target controller can't verify uploaded binaries, skipping verification")
+}
+
+func (s *Suite) TestUploadBinariesSettings(c *gc.C) {
+	s.config.BinaryDownloadConcurrency = 2
+	s.config.BinaryUploadConcurrency = 3
+	s.config.BinaryUploadRateLimit = 1024
+	var binaries migration.UploadBinariesConfig
+	s.config.UploadBinaries = func(config migration.UploadBinariesConfig) error {
+		binaries = config
+		return nil
+	}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Check(binaries.DownloadConcurrency, gc.Equals, 2)
+	c.Check(binaries.Concurrency, gc.Equals, 3)
+	c.Check(binaries.UploadRateLimit, gc.Equals, int64(1024))
+	c.Check(binaries.Clock, gc.Equals, s.config.Clock)
+	c.Check(binaries.Abort, gc.NotNil)
+}
+
+func (s *Suite) TestStorageCompatible(c *gc.C) {
+	s.masterFacade.storagePools = []coremigration.StoragePool{
+		{Name: "rootfs", Provider: "rootfs"},
+		{Name: "ebs-ssd", Provider: "ebs"},
+	}
+	s.connection.storageProviderTypes = []string{"ebs", "loop"}
+	s.connection.importErr = errors.New("stop here")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		apiOpenCallController,
+		{"APICall:MigrationTarget.StorageProviderTypes", []interface{}{nil}},
+		connCloseCall,
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.IMPORT}},
+		{"masterFacade.Export", nil},
+		apiOpenCallController,
+		{"masterFacade.ModelCredential", nil},
+		importCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+}
+
+func (s *Suite) TestStorageGenericOnly(c *gc.C) {
+	s.masterFacade.storagePools = []coremigration.StoragePool{
+		{Name: "rootfs", Provider: "rootfs"},
+		{Name: "tmpfs", Provider: "tmpfs"},
+		{Name: "loop", Provider: "loop"},
+	}
+	s.connection.importErr = errors.New("stop here")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	// The target isn't consulted when only generic storage is used.
+	s.stub.CheckCall(c, 8, "masterFacade.StoragePools")
+	s.stub.CheckCall(c, 13, "masterFacade.SetPhase", coremigration.IMPORT)
+}
+
+func (s *Suite) TestStorageIncompatible(c *gc.C) {
+	s.masterFacade.storagePools = []coremigration.StoragePool{
+		{Name: "rootfs", Provider: "rootfs"},
+		{Name: "ebs-ssd", Provider: "ebs"},
+		{Name: "fast", Provider: "cinder"},
+		{Name: "azure-premium", Provider: "azure"},
+	}
+	s.connection.storageProviderTypes = []string{"cinder"}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		apiOpenCallController,
+		{"APICall:MigrationTarget.StorageProviderTypes", []interface{}{nil}},
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"storage precheck failed: storage pools not supported by the target cloud: "+
+			"ebs-ssd (ebs), azure-premium (azure)")
+}
+
+func (s *Suite) TestAgentStreamSupported(c *gc.C) {
+	s.config.AgentStream = "proposed"
+	s.connection.agentStreams = []string{"released", "proposed"}
+	s.connection.importErr = errors.New("stop here")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCall(c, 8, "masterFacade.StoragePools")
+	s.stub.CheckCall(c, 9, "apiOpen", apiOpenCallController.Args...)
+	s.stub.CheckCall(c, 10, "APICall:MigrationTarget.AgentStreams", nil)
+	s.stub.CheckCall(c, 16, "masterFacade.SetPhase", coremigration.IMPORT)
+}
+
+func (s *Suite) TestAgentStreamNotSupported(c *gc.C) {
+	s.config.AgentStream = "devel"
+	s.connection.agentStreams = []string{"released", "proposed"}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		apiOpenCallController,
+		{"APICall:MigrationTarget.AgentStreams", []interface{}{nil}},
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		`agent stream precheck failed: agent stream "devel" not supported `+
+			`by the target controller (supported: released, proposed)`)
+}
+
+func (s *Suite) TestTargetPrechecks(c *gc.C) {
+	s.connection.importErr = errors.New("stop here")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCall(c, 8, "masterFacade.StoragePools")
+	s.stub.CheckCall(c, 9, "masterFacade.ModelInfo")
+	s.stub.CheckCall(c, 10, "apiOpen", apiOpenCallController.Args...)
+	s.stub.CheckCall(c, 11, "APICall:MigrationTarget.Prechecks", prechecksCall.Args...)
+	s.stub.CheckCall(c, 12, "Connection.Close")
+	s.stub.CheckCall(c, 13, "masterFacade.SetPhase", coremigration.IMPORT)
+}
+
+func (s *Suite) TestTargetPrechecksFailed(c *gc.C) {
+	s.connection.prechecksErr = errors.New("model with same name exists")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"target precheck failed: model with same name exists")
+}
+
+func (s *Suite) TestDryRun(c *gc.C) {
+	s.config.DryRun = true
+	s.config.UploadBinaries = makeStubUploadBinaries(s.stub)
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	// The migration ends once the prechecks pass, without anything
+	// being exported, imported or activated.
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
+		{"masterFacade.SetStatusMessage", []interface{}{"dry run succeeded: model can be migrated"}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DRYRUNDONE}},
+	})
+}
+
+func (s *Suite) TestDryRunPrechecksFailed(c *gc.C) {
+	s.config.DryRun = true
+	s.connection.prechecksErr = errors.New("model with same name exists")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.READONLY}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.PRECHECK}},
+		{"masterFacade.StoragePools", nil},
+		{"masterFacade.ModelInfo", nil},
+		apiOpenCallController,
+		prechecksCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"target precheck failed: model with same name exists")
+}
+
+func (s *Suite) TestReapRetrySucceeds(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.REAP
+	s.masterFacade.reapErrs = []error{errors.New("boom")}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c)
+	s.clock.Advance(5 * time.Minute)
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+}
+
+func (s *Suite) TestReapVerified(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.REAP
+	s.masterFacade.reapComplete = []bool{false, true}
+	s.config.ReapTimeout = time.Minute
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c)
+	s.clock.Advance(10 * time.Second)
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.ReapComplete", nil},
+		{"masterFacade.ReapComplete", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+}
+
+func (s *Suite) TestReapIncomplete(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.REAP
+	s.masterFacade.reapComplete = []bool{false, false, false}
+	s.config.ReapTimeout = 15 * time.Second
+	s.config.ReapAttempts = 1
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// The worker polls every 10 seconds, and then again once the
+	// timeout expires.
+	s.waitForClockAlarm(c)
+	s.clock.Advance(10 * time.Second)
+	s.waitForClockAlarm(c)
+	s.clock.Advance(5 * time.Second)
+
+	// The worker then waits for the reap to be retried.
+	s.waitForClockAlarm(c)
+	workertest.CleanKill(c, worker)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.ReapComplete", nil},
+		{"masterFacade.ReapComplete", nil},
+		{"masterFacade.ReapComplete", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAPFAILED}},
+		{"masterFacade.GetMigrationStatus", nil},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "migrated model was not removed within 15s")
+}
+
+func (s *Suite) TestReapCompleteError(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.REAP
+	s.masterFacade.reapCompleteErr = errors.New("boom")
+	s.config.ReapTimeout = time.Minute
+	s.config.ReapAttempts = 1
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c)
+	workertest.CleanKill(c, worker)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.ReapComplete", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAPFAILED}},
+		{"masterFacade.GetMigrationStatus", nil},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"failed to confirm removal of migrated model: checking model removal: boom")
+}
+
+func (s *Suite) TestReapRetriesExhausted(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.REAP
+	s.masterFacade.reapErrs = []error{errors.New("boom"), errors.New("boom")}
+	s.config.ReapAttempts = 2
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c)
+	s.clock.Advance(5 * time.Minute)
+
+	// Once the attempts are used up the worker waits in REAPFAILED
+	// for an operator to ask for the reap to be retried.
+	s.waitForClockAlarm(c)
+	workertest.CleanKill(c, worker)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAPFAILED}},
+		{"masterFacade.GetMigrationStatus", nil},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "failed to remove migrated model: boom")
+	c.Check(c.GetTestLog(), jc.Contains,
+		"giving up on removal of migrated model after 2 attempts")
+}
+
+func (s *Suite) TestRetryReapSucceeds(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.REAPFAILED
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// Ask for the reap to be retried while the worker is waiting.
+	s.waitForClockAlarm(c)
+	s.masterFacade.requestReapRetry()
+	s.clock.Advance(time.Minute)
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+}
+
+func (s *Suite) TestRetryReapFailsAgain(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.REAPFAILED
+	s.masterFacade.reapRetryRequested = true
+	s.masterFacade.reapErrs = []error{errors.New("boom")}
+	s.config.ReapAttempts = 1
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// The retry fails, so the worker returns to REAPFAILED and waits
+	// for another request.
+	s.waitForClockAlarm(c)
+	workertest.CleanKill(c, worker)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAPFAILED}},
+		{"masterFacade.GetMigrationStatus", nil},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "failed to remove migrated model: boom")
+}
+
+func (s *Suite) TestVerifyImport(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.VALIDATION
+	s.masterFacade.exportBytes = makeModelBytes(c, "default")
+	s.connection.targetModelBytes = makeModelBytes(c, "default")
+	s.config.VerifyImport = true
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.Export", nil},
+		apiOpenCallController,
+		{"APICall:MigrationTarget.Export", []interface{}{params.ModelArgs{ModelTag: modelTagString}}},
+		connCloseCall,
+		apiOpenCallController,
+		activateCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.SUCCESS}},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "imported model matches source model")
+}
+
+func (s *Suite) TestVerifyImportDifferences(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.VALIDATION
+	s.masterFacade.exportBytes = makeModelBytes(c, "default")
+	s.connection.targetModelBytes = makeModelBytes(c, "other")
+	s.config.VerifyImport = true
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCallNames(c,
+		"masterFacade.Watch",
+		"masterFacade.GetMigrationStatus",
+		"guard.Lockdown",
+		"masterFacade.WatchForAbort",
+		"masterFacade.Export",
+		"apiOpen",
+		"APICall:MigrationTarget.Export",
+		"Connection.Close",
+		"masterFacade.SetPhase",
+		"apiOpen",
+		"APICall:MigrationTarget.Abort",
+		"Connection.Close",
+		"masterFacade.SetPhase",
+	)
+	c.Check(c.GetTestLog(), jc.Contains, `imported model differs: model config "name": default != other`)
+}
+
+func makeModelBytes(c *gc.C, name string) []byte {
+	model := description.NewModel(description.ModelArgs{
+		Owner:  names.NewUserTag("admin"),
+		Config: map[string]interface{}{"name": name},
+	})
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+	return bytes
+}
+
+func (s *Suite) TestHoldAfterValidation(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.VALIDATION
+	s.masterFacade.approve("CHG-1234")
+	s.config.HoldTimeout = time.Hour
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	// The model is only activated once the migration is approved.
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.HOLD}},
+		{"masterFacade.GetMigrationStatus", nil},
+		apiOpenCallController,
+		activateCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.SUCCESS}},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "migration approved (ticket CHG-1234)")
+}
+
+func (s *Suite) TestHoldApproved(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.HOLD
+	s.config.HoldTimeout = time.Hour
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// Approve while the worker is waiting.
+	s.waitForClockAlarm(c)
+	s.masterFacade.approve("CHG-1234")
+	s.clock.Advance(time.Minute)
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		apiOpenCallController,
+		activateCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.SUCCESS}},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+}
+
+func (s *Suite) TestHoldTimeout(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.HOLD
+	s.config.HoldTimeout = 90 * time.Second
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// The worker polls once a minute, and then again once the
+	// timeout expires.
+	s.waitForClockAlarm(c)
+	s.clock.Advance(time.Minute)
+	s.waitForClockAlarm(c)
+	s.clock.Advance(30 * time.Second)
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "migration was not approved within 1m30s")
+}
+
+func (s *Suite) TestHoldAbort(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.HOLD
+	s.config.HoldTimeout = time.Hour
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// Abort while the worker is waiting for approval, without
+	// waiting for the hold to time out.
+	s.waitForClockAlarm(c)
+	s.triggerAbort()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "abort requested while migration held, aborting migration")
+}
+
+func (s *Suite) TestHoldDying(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.HOLD
+	s.config.HoldTimeout = time.Hour
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c)
+	workertest.CleanKill(c, worker)
+
+	// The phase is left alone so that the hold is resumed when the
+	// worker restarts.
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+	})
+}
+
+func (s *Suite) TestValidationSettleDelay(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.VALIDATION
+	s.config.ValidationSettleDelay = 30 * time.Second
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// Nothing happens until the delay has passed.
+	s.waitForClockAlarm(c)
+	s.clock.Advance(29 * time.Second)
+	s.stub.CheckCallNames(c,
+		"masterFacade.Watch",
+		"masterFacade.GetMigrationStatus",
+		"guard.Lockdown",
+		"masterFacade.WatchForAbort",
+	)
+	s.clock.Advance(time.Second)
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		apiOpenCallController,
+		activateCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.SUCCESS}},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
+		{"masterFacade.Reap", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
+	})
+}
+
+func (s *Suite) TestValidationSettleDelayDying(c *gc.C) {
+	s.masterFacade.status.Phase = coremigration.VALIDATION
+	s.config.ValidationSettleDelay = 30 * time.Second
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c)
+	workertest.CleanKill(c, worker)
+
+	// The phase is left alone so that validation is retried when
+	// the worker restarts.
+	s.stub.CheckCallNames(c,
+		"masterFacade.Watch",
+		"masterFacade.GetMigrationStatus",
+		"guard.Lockdown",
+		"masterFacade.WatchForAbort",
+	)
+}
+
+func (s *Suite) waitForClockAlarm(c *gc.C) {
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for clock.After call")
+	}
+}
+
 func (s *Suite) TestMinionWaitWatchError(c *gc.C) {
 	worker, err := migrationmaster.New(s.config)
 	c.Assert(err, jc.ErrorIsNil)
@@ -425,6 +2227,36 @@ func (s *Suite) TestMinionWaitGetError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "boom")
 }
 
+func (s *Suite) TestMinionWaitKilledWhileProcessing(c *gc.C) {
+	s.masterFacade.minionReportsStarted = make(chan struct{})
+	s.masterFacade.minionReportsBlock = make(chan struct{})
+	defer close(s.masterFacade.minionReportsBlock)
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.masterFacade.status.Phase = coremigration.SUCCESS
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	select {
+	case <-s.masterFacade.minionReportsStarted:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for minion reports to be fetched")
+	}
+
+	// The worker must stop promptly even though the reports are
+	// still being processed.
+	workertest.CleanKill(c, worker)
+	s.stub.CheckCallNames(c,
+		"masterFacade.Watch",
+		"masterFacade.GetMigrationStatus",
+		"guard.Lockdown",
+		"masterFacade.WatchForAbort",
+		"masterFacade.WatchMinionReports",
+		"masterFacade.GetMinionReports",
+	)
+}
+
 func (s *Suite) TestMinionWaitSUCCESSFailedMachine(c *gc.C) {
 	// With the SUCCESS phase the master should wait for all reports,
 	// continuing even if some minions report failure.
@@ -444,9 +2276,17 @@ func (s *Suite) TestMinionWaitSUCCESSFailedMachine(c *gc.C) {
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
 		{"masterFacade.WatchMinionReports", nil},
 		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
 		{"masterFacade.Reap", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
@@ -471,15 +2311,44 @@ func (s *Suite) TestMinionWaitSUCCESSFailedUnit(c *gc.C) {
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
 		{"masterFacade.WatchMinionReports", nil},
 		{"masterFacade.GetMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
 		{"masterFacade.Reap", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
 	})
 }
 
+func (s *Suite) TestMinionWaitFailureDetails(c *gc.C) {
+	s.masterFacade.minionReports.FailedMachines = []string{"42", "43"}
+	s.masterFacade.minionReports.FailedUnits = []string{"foo/2"}
+	s.masterFacade.minionReports.FailureDetails = map[string]string{
+		"42":    "disk full",
+		"foo/2": "hook failed",
+	}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.masterFacade.status.Phase = coremigration.SUCCESS
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	c.Check(c.GetTestLog(), jc.Contains,
+		"some agents failed SUCCESS: failed machines: 42 (disk full), 43; "+
+			"failed units: foo/2 (hook failed)")
+}
+
 func (s *Suite) TestMinionWaitSUCCESSTimeout(c *gc.C) {
 	// The SUCCESS phase is special in that even if some minions fail
 	// to report the migration should continue. There's no turning
@@ -507,14 +2376,101 @@ func (s *Suite) TestMinionWaitSUCCESSTimeout(c *gc.C) {
 		{"masterFacade.Watch", nil},
 		{"masterFacade.GetMigrationStatus", nil},
 		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
 		{"masterFacade.WatchMinionReports", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+		setTargetHintCall,
+		apiOpenCallController,
+		latestLogTimeCall,
+		openLogStreamCall,
+		streamModelLogCall,
+		logStreamCloseCall,
+		connCloseCall,
 		{"masterFacade.SetPhase", []interface{}{coremigration.REAP}},
 		{"masterFacade.Reap", nil},
 		{"masterFacade.SetPhase", []interface{}{coremigration.DONE}},
 	})
 }
 
+func (s *Suite) TestMinionWaitProgress(c *gc.C) {
+	s.masterFacade.minionReports.SuccessCount = 3
+	s.masterFacade.minionReports.UnknownCount = 2
+	s.masterFacade.minionReports.SomeUnknownMachines = []string{"3"}
+	s.masterFacade.minionReports.SomeUnknownUnits = []string{"foo/0"}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// Wait for the minion wait timeout and progress log timers.
+	s.waitForClockAlarm(c)
+	s.waitForClockAlarm(c)
+	s.triggerMinionReports()
+	s.waitForMinionWaitProgress(c, 1)
+
+	// Progress is pushed again on each periodic update.
+	s.clock.Advance(30 * time.Second)
+	s.waitForClockAlarm(c)
+	s.waitForMinionWaitProgress(c, 2)
+	workertest.CleanKill(c, worker)
+
+	expected := coremigration.MinionReports{
+		MigrationId:         "model-uuid:2",
+		Phase:               coremigration.QUIESCE,
+		SuccessCount:        3,
+		UnknownCount:        2,
+		SomeUnknownMachines: []string{"3"},
+		SomeUnknownUnits:    []string{"foo/0"},
+	}
+	c.Assert(s.masterFacade.minionWaitProgress, jc.DeepEquals,
+		[]coremigration.MinionReports{expected, expected})
+}
+
+func (s *Suite) TestMinionWaitProgressNoReports(c *gc.C) {
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	// There's nothing to push until the first reports are received.
+	s.waitForClockAlarm(c)
+	s.waitForClockAlarm(c)
+	s.clock.Advance(30 * time.Second)
+	s.waitForClockAlarm(c)
+	workertest.CleanKill(c, worker)
+	c.Assert(s.masterFacade.minionWaitProgress, gc.HasLen, 0)
+}
+
+func (s *Suite) TestMinionWaitProgressError(c *gc.C) {
+	s.masterFacade.minionWaitProgressErr = errors.New("boom")
+	s.config.UploadBinaries = makeStubUploadBinaries(s.stub)
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	// The migration carries on regardless.
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Check(s.masterFacade.minionWaitProgress, gc.HasLen, 2)
+	c.Check(c.GetTestLog(), jc.Contains, "failed to record minion wait progress: boom")
+}
+
+// waitForMinionWaitProgress waits until minion wait progress has
+// been set count times.
+func (s *Suite) waitForMinionWaitProgress(c *gc.C, count int) {
+	timeout := time.After(coretesting.LongWait)
+	for s.masterFacade.minionWaitProgressCount() < count {
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for minion wait progress %d", count)
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+}
+
 func (s *Suite) TestMinionWaitWrongPhase(c *gc.C) {
 	worker, err := migrationmaster.New(s.config)
 	c.Assert(err, jc.ErrorIsNil)
@@ -550,6 +2506,156 @@ func (s *Suite) TestMinionWaitMigrationIdChanged(c *gc.C) {
 		"unexpected migration id in minion reports, got blah, expected model-uuid:2")
 }
 
+func (s *Suite) TestQuiesceMinionsSucceed(c *gc.C) {
+	s.masterFacade.exportErr = errors.New("stop here")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	// READONLY is only reached once all minions have quiesced.
+	s.stub.CheckCall(c, 4, "masterFacade.WatchMinionReports")
+	s.stub.CheckCall(c, 5, "masterFacade.GetMinionReports")
+	s.stub.CheckCall(c, 6, "masterFacade.SetPhase", coremigration.READONLY)
+}
+
+func (s *Suite) TestQuiesceMinionFailure(c *gc.C) {
+	// If any minion fails to quiesce the migration is aborted.
+	s.masterFacade.minionReports.FailedMachines = []string{"42"}
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.GetMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains, "some agents failed QUIESCE: failed machines: 42")
+}
+
+func (s *Suite) TestQuiesceMinionTimeout(c *gc.C) {
+	// Nothing has been imported yet so a timeout aborts the migration.
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+
+	s.waitForClockAlarm(c)
+	s.clock.Advance(15 * time.Minute)
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"masterFacade.Watch", nil},
+		{"masterFacade.GetMigrationStatus", nil},
+		{"guard.Lockdown", nil},
+		{"masterFacade.WatchForAbort", nil},
+		{"masterFacade.WatchMinionReports", nil},
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORT}},
+		apiOpenCallController,
+		abortCall,
+		connCloseCall,
+		{"masterFacade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+	})
+	c.Check(c.GetTestLog(), jc.Contains,
+		"no agents reported in time for migration phase QUIESCE")
+}
+
+var testPhasePlan = []coremigration.Phase{
+	coremigration.QUIESCE,
+	coremigration.READONLY,
+	coremigration.PRECHECK,
+	coremigration.IMPORT,
+	coremigration.VALIDATION,
+}
+
+func (s *Suite) TestPhasePlan(c *gc.C) {
+	s.config.PhasePlan = testPhasePlan
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Check(s.phasesSet(), jc.DeepEquals, []coremigration.Phase{
+		coremigration.READONLY,
+		coremigration.PRECHECK,
+		coremigration.IMPORT,
+		coremigration.VALIDATION,
+		coremigration.SUCCESS,
+		coremigration.LOGTRANSFER,
+		coremigration.REAP,
+		coremigration.DONE,
+	})
+}
+
+func (s *Suite) TestPhasePlanAbort(c *gc.C) {
+	// A phase plan doesn't stop a failing phase from aborting.
+	s.config.PhasePlan = testPhasePlan
+	s.masterFacade.exportErr = errors.New("boom")
+	worker, err := migrationmaster.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, worker)
+	s.triggerMigration()
+	s.triggerMinionReports()
+
+	err = workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.Equals, migrationmaster.ErrDoneForNow)
+	c.Check(s.phasesSet(), jc.DeepEquals, []coremigration.Phase{
+		coremigration.READONLY,
+		coremigration.PRECHECK,
+		coremigration.IMPORT,
+		coremigration.ABORT,
+		coremigration.ABORTDONE,
+	})
+}
+
+func (s *Suite) TestInvalidPhasePlan(c *gc.C) {
+	s.config.PhasePlan = []coremigration.Phase{
+		coremigration.QUIESCE,
+		coremigration.IMPORT,
+		coremigration.VALIDATION,
+	}
+	worker, err := migrationmaster.New(s.config)
+	c.Check(worker, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "PhasePlan transition from QUIESCE to IMPORT not valid")
+	s.stub.CheckNoCalls(c)
+}
+
+// phasesSet returns the phases passed to the facade's SetPhase, in
+// order.
+func (s *Suite) phasesSet() []coremigration.Phase {
+	var phases []coremigration.Phase
+	for _, call := range s.stub.Calls() {
+		if call.FuncName == "masterFacade.SetPhase" {
+			phases = append(phases, call.Args[0].(coremigration.Phase))
+		}
+	}
+	return phases
+}
+
 func newStubGuard(stub *jujutesting.Stub) *stubGuard {
 	return &stubGuard{stub: stub}
 }
@@ -571,11 +2677,17 @@ func (g *stubGuard) Unlock() error {
 }
 
 func newStubMasterFacade(stub *jujutesting.Stub, now time.Time) *stubMasterFacade {
+	// The abort watcher starts with its initial event queued.
+	abortChanges := make(chan struct{}, 999)
+	abortChanges <- struct{}{}
 	return &stubMasterFacade{
 		stub:           stub,
 		watcherChanges: make(chan struct{}, 999),
+		abortChanges:   abortChanges,
 		status: coremigration.MigrationStatus{
+			MigrationId:      "model-uuid:2",
 			ModelUUID:        "model-uuid",
+			ModelName:        "mymodel",
 			Attempt:          2,
 			Phase:            coremigration.QUIESCE,
 			PhaseChangedTime: now,
@@ -592,10 +2704,10 @@ func newStubMasterFacade(stub *jujutesting.Stub, now time.Time) *stubMasterFacad
 		// support waits at a number of phases.
 		minionReportsChanges: make(chan struct{}, 999),
 
-		// Default to happy state. Test may wish to tweak.
+		// Default to happy state. Test may wish to tweak. If Phase
+		// isn't set the reports are for the current migration phase.
 		minionReports: coremigration.MinionReports{
 			MigrationId:  "model-uuid:2",
-			Phase:        coremigration.SUCCESS,
 			SuccessCount: 5,
 			UnknownCount: 0,
 		},
@@ -612,12 +2724,55 @@ type stubMasterFacade struct {
 	status         coremigration.MigrationStatus
 	statusErr      error
 
-	exportErr error
+	abortChanges  chan struct{}
+	abortWatchErr error
+
+	// approvalRef and reapRetryRequested may be set by a test while
+	// the worker is running, so they are guarded by mu. phase records
+	// the last phase set by the worker and is also guarded by mu.
+	mu                 sync.Mutex
+	approvalRef        string
+	reapRetryRequested bool
+	phase              coremigration.Phase
+
+	exportBytes        []byte
+	exportCharmOrigins map[string]coremigration.CharmOrigin
+	exportErr          error
+	exportStarted      chan struct{}
+	exportBlock        chan struct{}
+	exportDone         chan struct{}
+	firewallRules      []coremigration.FirewallRule
+
+	credential    coremigration.CloudCredential
+	credentialErr error
+
+	storagePools    []coremigration.StoragePool
+	storagePoolsErr error
+
+	setStatusMessageErr error
+
+	reapErrs []error
+
+	// reapComplete holds the successive results of ReapComplete,
+	// which reports true once they are used up.
+	reapComplete    []bool
+	reapCompleteErr error
 
 	minionReportsChanges  chan struct{}
 	minionReportsWatchErr error
 	minionReports         coremigration.MinionReports
 	minionReportsErr      error
+	minionReportsStarted  chan struct{}
+	minionReportsBlock    chan struct{}
+
+	// minionWaitProgress records the reports passed to
+	// SetMinionWaitProgress, and is guarded by mu.
+	minionWaitProgress    []coremigration.MinionReports
+	minionWaitProgressErr error
+
+	logRecords        []params.LogRecord
+	logStreamErr      error
+	streamModelLogErr error
 }
 
 func (c *stubMasterFacade) Watch() (watcher.NotifyWatcher, error) {
@@ -628,12 +2783,37 @@ func (c *stubMasterFacade) Watch() (watcher.NotifyWatcher, error) {
 	return newMockWatcher(c.watcherChanges), nil
 }
 
+func (c *stubMasterFacade) WatchForAbort() (watcher.NotifyWatcher, error) {
+	c.stub.AddCall("masterFacade.WatchForAbort")
+	if c.abortWatchErr != nil {
+		return nil, c.abortWatchErr
+	}
+	return newMockWatcher(c.abortChanges), nil
+}
+
 func (c *stubMasterFacade) GetMigrationStatus() (coremigration.MigrationStatus, error) {
 	c.stub.AddCall("masterFacade.GetMigrationStatus")
 	if c.statusErr != nil {
 		return coremigration.MigrationStatus{}, c.statusErr
 	}
-	return c.status, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := c.status
+	status.ApprovalRef = c.approvalRef
+	status.ReapRetryRequested = c.reapRetryRequested
+	return status, nil
+}
+
+func (c *stubMasterFacade) approve(ticketRef string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.approvalRef = ticketRef
+}
+
+func (c *stubMasterFacade) requestReapRetry() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reapRetryRequested = true
 }
 
 func (c *stubMasterFacade) WatchMinionReports() (watcher.NotifyWatcher, error) {
@@ -641,41 +2821,176 @@ func (c *stubMasterFacade) WatchMinionReports() (watcher.NotifyWatcher, error) {
 	if c.minionReportsWatchErr != nil {
 		return nil, c.minionReportsWatchErr
 	}
-	return newMockWatcher(c.minionReportsChanges), nil
+	return newMinionReportsWatcher(c.minionReportsChanges), nil
 }
 
 func (c *stubMasterFacade) GetMinionReports() (coremigration.MinionReports, error) {
 	c.stub.AddCall("masterFacade.GetMinionReports")
+	if c.minionReportsBlock != nil {
+		close(c.minionReportsStarted)
+		<-c.minionReportsBlock
+	}
 	if c.minionReportsErr != nil {
 		return coremigration.MinionReports{}, c.minionReportsErr
 	}
-	return c.minionReports, nil
+	reports := c.minionReports
+	if reports.Phase == coremigration.UNKNOWN {
+		reports.Phase = c.currentPhase()
+	}
+	return reports, nil
+}
+
+func (c *stubMasterFacade) SetMinionWaitProgress(reports coremigration.MinionReports) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.minionWaitProgress = append(c.minionWaitProgress, reports)
+	return c.minionWaitProgressErr
+}
+
+// minionWaitProgressCount returns the number of times minion wait
+// progress has been set.
+func (c *stubMasterFacade) minionWaitProgressCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.minionWaitProgress)
+}
+
+// currentPhase returns the phase most recently set by the worker, or
+// the phase of the initial migration status if none has been set.
+func (c *stubMasterFacade) currentPhase() coremigration.Phase {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.phase != coremigration.UNKNOWN {
+		return c.phase
+	}
+	return c.status.Phase
+}
+
+func (c *stubMasterFacade) StreamModelLog(start time.Time, stop <-chan struct{}) (<-chan masterapi.LogRecordResult, error) {
+	c.stub.AddCall("masterFacade.StreamModelLog", start)
+	if c.streamModelLogErr != nil {
+		return nil, c.streamModelLogErr
+	}
+	results := make(chan masterapi.LogRecordResult, len(c.logRecords)+1)
+	for _, record := range c.logRecords {
+		if !record.Time.Before(start) {
+			results <- masterapi.LogRecordResult{Record: record}
+		}
+	}
+	if c.logStreamErr != nil {
+		results <- masterapi.LogRecordResult{Error: c.logStreamErr}
+	}
+	close(results)
+	return results, nil
+}
+
+func (c *stubMasterFacade) ModelInfo() (coremigration.ModelInfo, error) {
+	c.stub.AddCall("masterFacade.ModelInfo")
+	return coremigration.ModelInfo{
+		UUID:         "model-uuid",
+		Name:         "mymodel",
+		Owner:        names.NewUserTag("owner"),
+		AgentVersion: version.MustParse("2.1.0"),
+	}, nil
 }
 
 func (c *stubMasterFacade) Export() (coremigration.SerializedModel, error) {
 	c.stub.AddCall("masterFacade.Export")
+	if c.exportBlock != nil {
+		close(c.exportStarted)
+		<-c.exportBlock
+	}
+	if c.exportDone != nil {
+		defer close(c.exportDone)
+	}
 	if c.exportErr != nil {
 		return coremigration.SerializedModel{}, c.exportErr
 	}
+	bytes := fakeModelBytes
+	if c.exportBytes != nil {
+		bytes = c.exportBytes
+	}
+	charmOrigins := map[string]coremigration.CharmOrigin{
+		"cs:charm0": coremigration.CharmStoreOrigin,
+		"cs:charm1": coremigration.CharmStoreOrigin,
+	}
+	if c.exportCharmOrigins != nil {
+		charmOrigins = c.exportCharmOrigins
+	}
 	return coremigration.SerializedModel{
-		Bytes:  fakeModelBytes,
-		Charms: []string{"charm0", "charm1"},
+		Bytes:        bytes,
+		Charms:       []string{"cs:charm0", "cs:charm1"},
+		CharmOrigins: charmOrigins,
+		CharmSHA256s: map[string]string{
+			"cs:charm0": "charm0-sha256",
+			"cs:charm1": "charm1-sha256",
+		},
 		Tools: map[version.Binary]string{
 			version.MustParseBinary("2.1.0-trusty-amd64"): "/tools/0",
 		},
+		ToolsSHA256s: map[version.Binary]string{
+			version.MustParseBinary("2.1.0-trusty-amd64"): "tools-sha256",
+		},
+		FirewallRules: c.firewallRules,
 	}, nil
 }
 
+func (c *stubMasterFacade) ModelCredential() (coremigration.CloudCredential, error) {
+	c.stub.AddCall("masterFacade.ModelCredential")
+	if c.credentialErr != nil {
+		return coremigration.CloudCredential{}, c.credentialErr
+	}
+	return c.credential, nil
+}
+
+func (c *stubMasterFacade) StoragePools() ([]coremigration.StoragePool, error) {
+	c.stub.AddCall("masterFacade.StoragePools")
+	if c.storagePoolsErr != nil {
+		return nil, c.storagePoolsErr
+	}
+	return c.storagePools, nil
+}
+
 func (c *stubMasterFacade) SetPhase(phase coremigration.Phase) error {
 	c.stub.AddCall("masterFacade.SetPhase", phase)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.phase = phase
+	if phase == coremigration.REAP {
+		// As in state, returning to REAP clears a retry request.
+		c.reapRetryRequested = false
+	}
 	return nil
 }
 
+func (c *stubMasterFacade) SetStatusMessage(message string) error {
+	c.stub.AddCall("masterFacade.SetStatusMessage", message)
+	return c.setStatusMessageErr
+}
+
 func (c *stubMasterFacade) Reap() error {
 	c.stub.AddCall("masterFacade.Reap")
+	if len(c.reapErrs) > 0 {
+		err := c.reapErrs[0]
+		c.reapErrs = c.reapErrs[1:]
+		return err
+	}
 	return nil
 }
 
+func (c *stubMasterFacade) ReapComplete() (bool, error) {
+	c.stub.AddCall("masterFacade.ReapComplete")
+	if c.reapCompleteErr != nil {
+		return false, c.reapCompleteErr
+	}
+	if len(c.reapComplete) > 0 {
+		complete := c.reapComplete[0]
+		c.reapComplete = c.reapComplete[1:]
+		return complete, nil
+	}
+	return true, nil
+}
+
 func newMockWatcher(changes chan struct{}) *mockWatcher {
 	return &mockWatcher{
 		Worker:  workertest.NewErrorWorker(nil),
@@ -692,25 +3007,106 @@ func (w *mockWatcher) Changes() watcher.NotifyChannel {
 	return w.changes
 }
 
+// newMinionReportsWatcher returns a watcher which passes on at most
+// one change from source. Each wait for minion reports starts its own
+// watcher, so this stops an earlier phase's wait from consuming the
+// changes triggered by a test for later phases.
+func newMinionReportsWatcher(source <-chan struct{}) *mockWatcher {
+	changes := make(chan struct{}, 1)
+	w := newMockWatcher(changes)
+	stopped := make(chan struct{})
+	go func() {
+		w.Wait()
+		close(stopped)
+	}()
+	go func() {
+		select {
+		case <-source:
+			changes <- struct{}{}
+		case <-stopped:
+		}
+	}()
+	return w
+}
+
 type stubConnection struct {
 	api.Connection
-	stub      *jujutesting.Stub
-	importErr error
+	stub         *jujutesting.Stub
+	prechecksErr error
+	importErr    error
+
+	credentialHash      string
+	credentialHashErr   *params.Error
+	uploadCredentialErr error
+
+	untranslatedRules []params.UntranslatedFirewallRule
+	applyFirewallErr  error
+
+	verifyBinariesErr error
+
+	storageProviderTypes    []string
+	storageProviderTypesErr error
+
+	agentStreams []string
+
+	targetModelBytes []byte
+
+	latestLogTime time.Time
+	logStream     *stubLogStream
 }
 
 func (c *stubConnection) BestFacadeVersion(string) int {
 	return 1
 }
 
-func (c *stubConnection) APICall(objType string, version int, id, request string, params, response interface{}) error {
-	c.stub.AddCall("APICall:"+objType+"."+request, params)
+func (c *stubConnection) APICall(objType string, version int, id, request string, args, response interface{}) error {
+	c.stub.AddCall("APICall:"+objType+"."+request, args)
 
 	if objType == "MigrationTarget" {
 		switch request {
+		case "Prechecks":
+			return c.prechecksErr
 		case "Import":
 			return c.importErr
 		case "Activate":
 			return nil
+		case "CredentialHash":
+			result := response.(*params.StringResult)
+			if c.credentialHashErr != nil {
+				result.Error = c.credentialHashErr
+				return nil
+			}
+			result.Result = c.credentialHash
+			return nil
+		case "UploadCredential":
+			return c.uploadCredentialErr
+		case "ApplyFirewallRules":
+			if c.applyFirewallErr != nil {
+				return c.applyFirewallErr
+			}
+			result := response.(*params.ApplyFirewallRulesResult)
+			result.Untranslated = c.untranslatedRules
+			return nil
+		case "StorageProviderTypes":
+			if c.storageProviderTypesErr != nil {
+				return c.storageProviderTypesErr
+			}
+			result := response.(*params.StringsResult)
+			result.Result = c.storageProviderTypes
+			return nil
+		case "AgentStreams":
+			result := response.(*params.StringsResult)
+			result.Result = c.agentStreams
+			return nil
+		case "VerifyBinaries":
+			return c.verifyBinariesErr
+		case "Export":
+			result := response.(*params.SerializedModel)
+			result.Bytes = c.targetModelBytes
+			return nil
+		case "LatestLogTime":
+			*(response.(*time.Time)) = c.latestLogTime
+			return nil
 		}
 	}
 	return errors.New("unexpected API call")
@@ -722,11 +3118,34 @@ func (c *stubConnection) Client() *api.Client {
 	return new(api.Client)
 }
 
+func (c *stubConnection) ConnectStream(path string, attrs url.Values) (base.Stream, error) {
+	c.stub.AddCall("ConnectStream", path, attrs)
+	return c.logStream, nil
+}
+
 func (c *stubConnection) Close() error {
 	c.stub.AddCall("Connection.Close")
 	return nil
 }
 
+// stubLogStream records the batches of log records written to it.
+type stubLogStream struct {
+	base.Stream
+	stub *jujutesting.Stub
+}
+
+func (s *stubLogStream) WriteJSON(v interface{}) error {
+	// Copy the batch, as the worker reuses its buffer.
+	batch := append([]params.LogRecord(nil), v.([]params.LogRecord)...)
+	s.stub.AddCall("Stream.WriteJSON", batch)
+	return s.stub.NextErr()
+}
+
+func (s *stubLogStream) Close() error {
+	s.stub.AddCall("Stream.Close")
+	return nil
+}
+
 func makeStubUploadBinaries(stub *jujutesting.Stub) func(migration.UploadBinariesConfig) error {
 	return func(config migration.UploadBinariesConfig) error {
 		stub.AddCall(
@@ -749,3 +3168,11 @@ func nullUploadBinaries(migration.UploadBinariesConfig) error {
 var fakeCharmDownloader = struct{ migration.CharmDownloader }{}
 
 var fakeToolsDownloader = struct{ migration.ToolsDownloader }{}
+
+var testCredential = coremigration.CloudCredential{
+	Owner:      names.NewUserTag("bob"),
+	Cloud:      "aws",
+	Name:       "default",
+	AuthType:   "access-key",
+	Attributes: map[string]string{"secret-key": "sekrit"},
+}
@@ -22,6 +22,7 @@ var logger = loggo.GetLogger("juju.worker.migrationminion")
 type Facade interface {
 	Watch() (watcher.MigrationStatusWatcher, error)
 	Report(migrationId string, phase migration.Phase, success bool) error
+	ReportFailure(migrationId string, phase migration.Phase, message string) error
 }
 
 // Config defines the operation of a Worker.
@@ -128,7 +129,7 @@ func (w *Worker) handle(status watcher.MigrationStatus) error {
 
 func (w *Worker) callAndReport(f func(watcher.MigrationStatus) error, status watcher.MigrationStatus) error {
 	callErr := f(status)
-	reportErr := w.report(status, callErr == nil)
+	reportErr := w.report(status, callErr)
 
 	// The error from the call is more important than the failure to
 	// report.
@@ -152,8 +153,16 @@ func (w *Worker) doSUCCESS(status watcher.MigrationStatus) error {
 	return errors.Annotate(err, "setting agent config")
 }
 
-func (w *Worker) report(status watcher.MigrationStatus, success bool) error {
-	err := w.config.Facade.Report(status.MigrationId, status.Phase, success)
+// report tells the controller whether the minion's activities for
+// the migration phase succeeded. If they failed, the cause is
+// included so that it's visible to the migration master.
+func (w *Worker) report(status watcher.MigrationStatus, callErr error) error {
+	var err error
+	if callErr == nil {
+		err = w.config.Facade.Report(status.MigrationId, status.Phase, true)
+	} else {
+		err = w.config.Facade.ReportFailure(status.MigrationId, status.Phase, callErr.Error())
+	}
 	return errors.Trace(err)
 }
 
@@ -168,6 +168,27 @@ func (s *Suite) TestSUCCESS(c *gc.C) {
 	s.stub.CheckCall(c, 2, "Report", "id", migration.SUCCESS, true)
 }
 
+func (s *Suite) TestSUCCESSFailure(c *gc.C) {
+	s.client.watcher.changes <- watcher.MigrationStatus{
+		MigrationId:    "id",
+		Phase:          migration.SUCCESS,
+		TargetAPIAddrs: []string{"1.1.1.1:1"},
+		TargetCACert:   "top secret",
+	}
+	s.agent.changeConfigErr = errors.New("disk full")
+	w, err := migrationminion.New(migrationminion.Config{
+		Facade: s.client,
+		Guard:  s.guard,
+		Agent:  s.agent,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "setting agent config: disk full")
+	s.stub.CheckCallNames(c, "Watch", "Lockdown", "ReportFailure")
+	s.stub.CheckCall(c, 2, "ReportFailure", "id", migration.SUCCESS, "setting agent config: disk full")
+}
+
 func newStubGuard(stub *jujutesting.Stub) *stubGuard {
 	return &stubGuard{stub: stub}
 }
@@ -214,6 +235,11 @@ func (c *stubMinionClient) Report(id string, phase migration.Phase, success bool
 	return nil
 }
 
+func (c *stubMinionClient) ReportFailure(id string, phase migration.Phase, message string) error {
+	c.stub.MethodCall(c, "ReportFailure", id, phase, message)
+	return nil
+}
+
 func newStubWatcher() *stubWatcher {
 	return &stubWatcher{
 		Worker:  workertest.NewErrorWorker(nil),
@@ -238,8 +264,9 @@ func newStubAgent() *stubAgent {
 
 type stubAgent struct {
 	agent.Agent
-	configChanged chan bool
-	conf          stubConfig
+	configChanged   chan bool
+	conf            stubConfig
+	changeConfigErr error
 }
 
 func (ma *stubAgent) CurrentConfig() agent.Config {
@@ -248,6 +275,9 @@ func (ma *stubAgent) CurrentConfig() agent.Config {
 
 func (ma *stubAgent) ChangeConfig(f agent.ConfigMutator) error {
 	defer close(ma.configChanged)
+	if ma.changeConfigErr != nil {
+		return ma.changeConfigErr
+	}
 	return f(&ma.conf)
 }
 